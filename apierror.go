@@ -0,0 +1,274 @@
+package responsehelper
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error that carries everything needed to render an error
+// envelope, so the service layer can decide the response and the handler
+// only has to call RespondAPIError.
+//
+// Example:
+//
+//	return nil, responsehelper.NewNotFoundError("User not found")
+type APIError struct {
+	// Status is the HTTP status code, eg: http.StatusNotFound.
+	Status int
+	// Code is an optional business error code rendered as "errorCode".
+	Code string
+	// Message is the user facing message. When empty the text of Err is used,
+	// or the status text when error sanitization is enabled.
+	Message string
+	// Details is rendered as "details". When nil the text of Err is used instead.
+	Details interface{}
+	// FieldErrors are rendered as "errors", eg: field violations of a request.
+	FieldErrors []FieldError
+	// Headers are set on the response before the body is written.
+	Headers map[string]string
+	// Err is the underlying error, reachable through errors.Is and errors.As.
+	Err error
+
+	// base is the error this one was derived from with one of the With*
+	// methods, so errors.Is(err, ErrNotFound) holds for ErrNotFound.WithMessage(...).
+	base *APIError
+}
+
+// Sentinel errors for the common cases. Derive the error to return with the
+// With* methods, errors.Is keeps matching the sentinel.
+//
+// Example:
+//
+//	return nil, responsehelper.ErrNotFound.WithMessage("user not found")
+//
+//	if errors.Is(err, responsehelper.ErrNotFound) { ... }
+var (
+	ErrBadRequest   = &APIError{Status: http.StatusBadRequest}
+	ErrUnauthorized = &APIError{Status: http.StatusUnauthorized}
+	ErrForbidden    = &APIError{Status: http.StatusForbidden}
+	ErrNotFound     = &APIError{Status: http.StatusNotFound}
+	ErrConflict     = &APIError{Status: http.StatusConflict}
+	ErrInternal     = &APIError{Status: http.StatusInternalServerError}
+)
+
+// NewAPIError creates an APIError with the given status, message and underlying error.
+func NewAPIError(status int, message string, err error) *APIError {
+	return &APIError{Status: status, Message: message, Err: err}
+}
+
+// NewBadRequestError creates a 400 Bad Request APIError.
+func NewBadRequestError(message string, details interface{}) *APIError {
+	return &APIError{Status: http.StatusBadRequest, Message: message, Details: details}
+}
+
+// NewUnauthorizedError creates a 401 Unauthorized APIError.
+func NewUnauthorizedError(message string) *APIError {
+	return &APIError{Status: http.StatusUnauthorized, Message: message}
+}
+
+// NewForbiddenError creates a 403 Forbidden APIError.
+func NewForbiddenError(message string) *APIError {
+	return &APIError{Status: http.StatusForbidden, Message: message}
+}
+
+// NewNotFoundError creates a 404 Not Found APIError.
+func NewNotFoundError(message string) *APIError {
+	return &APIError{Status: http.StatusNotFound, Message: message}
+}
+
+// NewConflictError creates a 409 Conflict APIError.
+func NewConflictError(message string, err error) *APIError {
+	return &APIError{Status: http.StatusConflict, Message: message, Err: err}
+}
+
+// NewAlreadyExistsError creates a 409 Conflict APIError for a resource that already exists.
+func NewAlreadyExistsError(resource string, err error) *APIError {
+	return NewConflictError(resource+" already exists", err)
+}
+
+// NewInternalError creates a 500 Internal Server Error APIError.
+func NewInternalError(message string, err error) *APIError {
+	return &APIError{Status: http.StatusInternalServerError, Message: message, Err: err}
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.status())
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the underlying error so errors.Is and errors.As can see through an APIError.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether e was derived from target with one of the With* methods.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	for base := e.base; base != nil; base = base.base {
+		if base == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WithMessage returns a copy of e with the given message.
+func (e *APIError) WithMessage(message string) *APIError {
+	derived := e.derive()
+	derived.Message = message
+	return derived
+}
+
+// WithDetails returns a copy of e with the given details.
+func (e *APIError) WithDetails(details interface{}) *APIError {
+	derived := e.derive()
+	derived.Details = details
+	return derived
+}
+
+// WithCode returns a copy of e with the given business error code.
+func (e *APIError) WithCode(code string) *APIError {
+	derived := e.derive()
+	derived.Code = code
+	return derived
+}
+
+// WithError returns a copy of e wrapping err.
+func (e *APIError) WithError(err error) *APIError {
+	derived := e.derive()
+	derived.Err = err
+	return derived
+}
+
+// derive returns a copy of e that still matches the error it was derived from.
+func (e *APIError) derive() *APIError {
+	derived := *e
+	derived.base = e
+	return &derived
+}
+
+// status returns the HTTP status of the error, falling back to 500 when it is
+// not a valid error status.
+func (e *APIError) status() int {
+	return errorStatus(e.Status)
+}
+
+func (r *Core) RespondAPIError(c Exchange, err *APIError, opts ...ResponseOption) {
+	if err == nil {
+		err = NewInternalError("", nil)
+	}
+	opts = helperCall(opts, "RespondAPIError", err)
+	status := r.validErrorStatus(err.Status, "RespondAPIError")
+	message := err.Message
+	messageFromErr := false
+	if message == "" {
+		// fall back to the underlying error, unless it must not reach the client
+		if details, ok := r.errorDetails(err.Err); ok && details != "" {
+			message, messageFromErr = details, true
+		} else {
+			message = http.StatusText(status)
+		}
+	}
+
+	errorBody := ErrorBody{
+		Code:      status,
+		Status:    statusText(status),
+		Message:   message,
+		ErrorCode: err.Code,
+	}
+	if len(err.FieldErrors) > 0 {
+		errorBody.Errors = err.FieldErrors
+	}
+	if err.Details != nil {
+		errorBody.Details = err.Details
+	} else if details, ok := r.errorDetails(err.Err); ok && !messageFromErr {
+		errorBody.Details = details
+	}
+
+	r.addErrorCauses(&errorBody, err.Err)
+
+	for key, value := range err.Headers {
+		setHeader(c, key, value)
+	}
+	r.respondError(c, status, errorBody, opts...)
+}
+
+func (r *Core) Respond(c Exchange, err error, data interface{}, opts ...ResponseOption) {
+	if err == nil {
+		r.renderSuccess(c, "Respond", http.StatusOK, SuccessEnvelope{
+			Data:    nullable(data),
+			Success: true,
+		}, opts...)
+		return
+	}
+	opts = helperCall(opts, "Respond", err)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		r.RespondAPIError(c, apiErr, opts...)
+		return
+	}
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		r.RespondAPIError(c, statusCoderError(coder.StatusCode(), err), opts...)
+		return
+	}
+	r.InternalError(c, "An unexpected error occurred", err, opts...)
+}
+
+// StatusCoder is implemented by errors carrying the HTTP status they should
+// be sent with, eg: the errors of other libraries. Respond, Wrap and
+// ErrorHandler send them with that status.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// statusCoderError returns the APIError err is sent as with status. The text
+// of err is the message of a 4xx error, a 5xx error gets the generic message
+// of InternalError.
+func statusCoderError(status int, err error) *APIError {
+	if errorStatus(status) >= http.StatusInternalServerError {
+		return NewAPIError(status, "An unexpected error occurred", err)
+	}
+	return NewAPIError(status, "", err)
+}
+
+// errorStatus returns status when it is a valid error status and 500 otherwise.
+func errorStatus(status int) int {
+	if status < 400 || status > 599 {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// statusText returns the status name used in the error envelope, eg: 404 -> "NOT_FOUND".
+func statusText(code int) string {
+	text := strings.ToUpper(http.StatusText(code))
+	text = strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text)
+	if text == "" {
+		return "UNKNOWN"
+	}
+	return text
+}
+
+// ErrorItem is a single entry of the "errors" array rendered by Errors.
+type ErrorItem struct {
+	// Code is an optional machine readable error code.
+	Code string `json:"code,omitempty" example:"DUPLICATE_EMAIL"`
+	// Message describes the problem.
+	Message string `json:"message" example:"The email is already registered"`
+	// Field is the request field the error refers to, if any.
+	Field string `json:"field,omitempty" example:"email"`
+	// Details holds any additional information about the error.
+	Details interface{} `json:"details,omitempty"`
+}
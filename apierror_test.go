@@ -0,0 +1,154 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// constraintError is an error type of a storage layer, found through an
+// APIError with errors.As.
+type constraintError struct {
+	constraint string
+}
+
+func (e *constraintError) Error() string {
+	return "violates " + e.constraint
+}
+
+func TestAPIErrorHeaders(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/imports")
+	err := responsehelper.NewAPIError(http.StatusTooManyRequests, "Slow down", nil)
+	err.Headers = map[string]string{"Retry-After": "30", "X-RateLimit-Remaining": "0"}
+	responsehelper.NewResponseHelper().RespondAPIError(c, err)
+
+	assertError(t, w, http.StatusTooManyRequests, "Slow down")
+	for key, want := range err.Headers {
+		if got := w.Header().Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestAPIErrorUnwrap(t *testing.T) {
+	errNoRows := errors.New("no rows")
+	constraint := &constraintError{constraint: "users_email_key"}
+	for name, cause := range map[string]error{
+		"sentinel": fmt.Errorf("loading user: %w", errNoRows),
+		"type":     constraint,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := fmt.Errorf("service: %w", responsehelper.NewConflictError("Email already registered", cause))
+
+			var apiErr *responsehelper.APIError
+			if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+				t.Fatalf("errors.As did not find the APIError in %v", err)
+			}
+			if got := errors.Is(err, errNoRows); got != (name == "sentinel") {
+				t.Errorf("errors.Is(err, errNoRows) = %t", got)
+			}
+			var target *constraintError
+			if got := errors.As(err, &target); got != (name == "type") || (got && target != constraint) {
+				t.Errorf("errors.As(err, *constraintError) = %t, %v", got, target)
+			}
+		})
+	}
+	if errors.Unwrap(responsehelper.NewNotFoundError("User not found")) != nil {
+		t.Error("an APIError without Err unwraps to an error")
+	}
+}
+
+func TestAPIErrorDetailsOfErr(t *testing.T) {
+	err := responsehelper.NewInternalError("Could not load the user", errors.New("dial tcp 10.0.0.7:5432: connection refused"))
+
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper().RespondAPIError(c, err)
+	assertError(t, w, http.StatusInternalServerError, "Could not load the user")
+	assertField(t, w, "error.details", "dial tcp 10.0.0.7:5432: connection refused")
+
+	c, w = newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)).RespondAPIError(c, err)
+	assertError(t, w, http.StatusInternalServerError, "Could not load the user")
+	if details, ok := lookup(w, "error.details"); ok {
+		t.Errorf("error.details = %v, want the wrapped error left out", details)
+	}
+	if strings.Contains(w.Body.String(), "10.0.0.7") {
+		t.Errorf("the body leaks the wrapped error: %s", w.Body)
+	}
+}
+
+func TestAPIErrorDetailsWinOverErr(t *testing.T) {
+	err := responsehelper.NewAPIError(http.StatusUnprocessableEntity, "Invalid user", errors.New("pq: check constraint"))
+	err.Details = map[string]string{"age": "must be positive"}
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().RespondAPIError(c, err)
+
+	assertError(t, w, http.StatusUnprocessableEntity, "Invalid user")
+	assertField(t, w, "error.details", map[string]string{"age": "must be positive"})
+}
+
+func TestAPIErrorInvalidStatus(t *testing.T) {
+	for _, status := range []int{0, http.StatusOK, 600} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		responsehelper.NewResponseHelper().RespondAPIError(c, &responsehelper.APIError{Status: status, Message: "went wrong"})
+
+		assertError(t, w, http.StatusInternalServerError, "went wrong")
+	}
+}
+
+func TestSentinelsMatchDerivedErrors(t *testing.T) {
+	err := fmt.Errorf("loading user: %w", responsehelper.ErrNotFound.WithMessage("user not found").WithDetails("id 42"))
+
+	if !errors.Is(err, responsehelper.ErrNotFound) {
+		t.Error("errors.Is(err, ErrNotFound) = false")
+	}
+	if errors.Is(err, responsehelper.ErrConflict) {
+		t.Error("errors.Is(err, ErrConflict) = true")
+	}
+	if responsehelper.ErrNotFound.Message != "" {
+		t.Errorf("WithMessage changed the sentinel: %q", responsehelper.ErrNotFound.Message)
+	}
+}
+
+func TestRespondSuccess(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper().Respond(c, nil, map[string]int{"id": 42})
+
+	assertSuccess(t, w)
+	assertField(t, w, "data.id", 42)
+}
+
+func TestRespondSentinels(t *testing.T) {
+	for _, tc := range []struct {
+		sentinel *responsehelper.APIError
+		status   int
+	}{
+		{responsehelper.ErrBadRequest, http.StatusBadRequest},
+		{responsehelper.ErrUnauthorized, http.StatusUnauthorized},
+		{responsehelper.ErrForbidden, http.StatusForbidden},
+		{responsehelper.ErrNotFound, http.StatusNotFound},
+		{responsehelper.ErrConflict, http.StatusConflict},
+		{responsehelper.ErrInternal, http.StatusInternalServerError},
+	} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		err := fmt.Errorf("service: %w", tc.sentinel.WithMessage("went wrong").WithCode("SOME_CODE"))
+		responsehelper.NewResponseHelper().Respond(c, err, nil)
+
+		assertError(t, w, tc.status, "went wrong")
+		assertField(t, w, "error.errorCode", "SOME_CODE")
+	}
+}
+
+func TestRespondOtherErrors(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)).Respond(c, errors.New("pq: connection refused"), nil)
+
+	assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+	if details, ok := decodeBody(t, w)["error"].(map[string]interface{})["details"]; ok {
+		t.Errorf("error.details = %v, want no details", details)
+	}
+}
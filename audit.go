@@ -0,0 +1,167 @@
+package responsehelper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultAuditBufferSize is the number of audit entries buffered for a slow sink.
+const DefaultAuditBufferSize = 1024
+
+// AuditEntry records an error response together with who made the request.
+type AuditEntry struct {
+	Timestamp time.Time
+	Path      string
+	Method    string
+	Status    int
+	ErrorCode string
+	Message   string
+	ClientIP  string
+	UserID    string
+	RequestID string
+}
+
+// AuditSink receives an AuditEntry for every error response. Entries are
+// delivered one at a time from a single goroutine, in the order of the responses.
+type AuditSink interface {
+	RecordError(ctx context.Context, entry AuditEntry)
+}
+
+// AuditOption configures WithAuditSink.
+type AuditOption func(*auditConfig)
+
+type auditConfig struct {
+	// bufferSize is the number of entries buffered before entries are dropped.
+	bufferSize int
+	// userKey is the gin context key holding the user ID.
+	userKey string
+}
+
+// WithAuditBufferSize replaces DefaultAuditBufferSize.
+func WithAuditBufferSize(size int) AuditOption {
+	return func(cfg *auditConfig) {
+		if size > 0 {
+			cfg.bufferSize = size
+		}
+	}
+}
+
+// WithAuditUserKey sets the gin context key the user ID is read from, eg:
+// the key the authentication middleware stores it under. Defaults to "userId".
+func WithAuditUserKey(key string) AuditOption {
+	return func(cfg *auditConfig) {
+		cfg.userKey = key
+	}
+}
+
+// WithAuditSink sends an AuditEntry to sink for every 4xx and 5xx response.
+// Success responses are never audited. The entries are buffered so a slow
+// sink never blocks a request, entries arriving while the buffer is full are
+// dropped and counted, see AuditDropped.
+//
+// Example:
+//
+//	responsehelper.WithAuditSink(auditLog, responsehelper.WithAuditUserKey("userID"))
+func WithAuditSink(sink AuditSink, opts ...AuditOption) Option {
+	cfg := auditConfig{bufferSize: DefaultAuditBufferSize, userKey: "userId"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *config) {
+		if sink == nil {
+			c.audit = nil
+			return
+		}
+		c.audit = newAuditQueue(sink, cfg)
+	}
+}
+
+// AuditDropped returns the number of audit entries h dropped because the
+// buffer of the sink was full.
+func AuditDropped(h ResponseHelper) uint64 {
+	helper, ok := h.(*responseHelper)
+	if !ok || helper.audit == nil {
+		return 0
+	}
+	return helper.audit.dropped.Load()
+}
+
+// auditQueue delivers audit entries to the sink from its own goroutine.
+type auditQueue struct {
+	cfg     auditConfig
+	entries chan auditItem
+	dropped atomic.Uint64
+}
+
+type auditItem struct {
+	ctx   context.Context
+	entry AuditEntry
+}
+
+func newAuditQueue(sink AuditSink, cfg auditConfig) *auditQueue {
+	queue := &auditQueue{cfg: cfg, entries: make(chan auditItem, cfg.bufferSize)}
+	go func() {
+		for item := range queue.entries {
+			sink.RecordError(item.ctx, item.entry)
+		}
+	}()
+	return queue
+}
+
+// record queues the entry of an error response, or drops it when the buffer is full.
+func (q *auditQueue) record(c Exchange, status int, errorCode, message string) {
+	entry := AuditEntry{
+		Timestamp: time.Now().UTC(),
+		Path:      requestPath(c),
+		Status:    status,
+		ErrorCode: errorCode,
+		Message:   message,
+		RequestID: requestID(c),
+	}
+	ctx := context.Background()
+	if c.Request() != nil {
+		entry.Method = c.Request().Method
+		entry.ClientIP = clientIP(c)
+		// the request context is canceled once the response is sent
+		ctx = context.WithoutCancel(c.Request().Context())
+	}
+	if user, ok := c.Get(q.cfg.userKey); ok && user != nil {
+		entry.UserID = fmt.Sprint(user)
+	}
+	select {
+	case q.entries <- auditItem{ctx: ctx, entry: entry}:
+	default:
+		q.dropped.Add(1)
+	}
+}
+
+// recordAudit queues an audit entry for an error response when a sink is configured.
+func (cfg *config) recordAudit(c Exchange, status int, errorCode, message string) {
+	if cfg.audit == nil || status < 400 || detached(c) {
+		return
+	}
+	cfg.audit.record(c, status, errorCode, message)
+}
+
+// MemoryAuditSink keeps the audit entries in memory, eg: for tests. The zero
+// value is ready to use.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (s *MemoryAuditSink) RecordError(_ context.Context, entry AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+}
+
+// Entries returns a copy of the recorded entries in the order they were recorded.
+func (s *MemoryAuditSink) Entries() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AuditEntry(nil), s.entries...)
+}
@@ -0,0 +1,99 @@
+package responsehelper_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// waitForEntries waits until sink recorded n entries.
+func waitForEntries(t *testing.T, sink *responsehelper.MemoryAuditSink, n int) []responsehelper.AuditEntry {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		entries := sink.Entries()
+		if len(entries) >= n || time.Now().After(deadline) {
+			if len(entries) != n {
+				t.Fatalf("%d audit entries, want %d: %+v", len(entries), n, entries)
+			}
+			return entries
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAuditSink(t *testing.T) {
+	sink := &responsehelper.MemoryAuditSink{}
+	h := responsehelper.NewResponseHelper(responsehelper.WithAuditSink(sink, responsehelper.WithAuditUserKey("userID")))
+
+	c, _ := newContext(http.MethodDelete, "/users/42")
+	c.Set("userID", 7)
+	c.Set(responsehelper.RequestIDKey, "req-1")
+	h.Forbidden(c, "Not yours")
+	c, _ = newContext(http.MethodGet, "/users")
+	h.Success(c, nil)
+	c, _ = newContext(http.MethodGet, "/users/43")
+	h.RespondAPIError(c, responsehelper.ErrNotFound.WithCode("USER_NOT_FOUND").WithMessage("User not found"))
+
+	entries := waitForEntries(t, sink, 2)
+	first := entries[0]
+	if first.Status != http.StatusForbidden || first.Method != http.MethodDelete || first.Path != "/users/42" ||
+		first.Message != "Not yours" || first.UserID != "7" || first.RequestID != "req-1" || first.ClientIP != "192.0.2.1" || first.Timestamp.IsZero() {
+		t.Errorf("entries[0] = %+v", first)
+	}
+	if second := entries[1]; second.Status != http.StatusNotFound || second.ErrorCode != "USER_NOT_FOUND" || second.UserID != "" {
+		t.Errorf("entries[1] = %+v", second)
+	}
+}
+
+// blockingSink blocks on every entry until release is closed.
+type blockingSink struct {
+	received chan responsehelper.AuditEntry
+	release  chan struct{}
+}
+
+func (s *blockingSink) RecordError(_ context.Context, entry responsehelper.AuditEntry) {
+	s.received <- entry
+	<-s.release
+}
+
+func TestAuditSinkDropsWhenFull(t *testing.T) {
+	sink := &blockingSink{received: make(chan responsehelper.AuditEntry, 10), release: make(chan struct{})}
+	h := responsehelper.NewResponseHelper(responsehelper.WithAuditSink(sink, responsehelper.WithAuditBufferSize(1)))
+	notFound := func(path string) {
+		c, _ := newContext(http.MethodGet, path)
+		h.NotFound(c, "missing")
+	}
+
+	notFound("/1")
+	<-sink.received // the sink is busy with /1
+	notFound("/2")  // buffered
+	start := time.Now()
+	notFound("/3") // dropped
+	notFound("/4") // dropped
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("a full buffer blocked the request for %v", elapsed)
+	}
+	if got := responsehelper.AuditDropped(h); got != 2 {
+		t.Errorf("AuditDropped = %d, want 2", got)
+	}
+
+	close(sink.release)
+	if entry := <-sink.received; entry.Path != "/2" {
+		t.Errorf("next entry is %q, want %q", entry.Path, "/2")
+	}
+	select {
+	case entry := <-sink.received:
+		t.Errorf("the dropped entry %q was delivered", entry.Path)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestAuditDroppedWithoutSink(t *testing.T) {
+	if got := responsehelper.AuditDropped(responsehelper.NewResponseHelper()); got != 0 {
+		t.Errorf("AuditDropped = %d, want 0", got)
+	}
+}
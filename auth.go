@@ -0,0 +1,163 @@
+package responsehelper
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Error codes of the Bearer authentication scheme (RFC 6750).
+const (
+	BearerErrorInvalidRequest    = "invalid_request"
+	BearerErrorInvalidToken      = "invalid_token"
+	BearerErrorInsufficientScope = "insufficient_scope"
+)
+
+// WWWAuthenticateHeader is the header carrying the authentication challenge of a 401 response.
+const WWWAuthenticateHeader = "WWW-Authenticate"
+
+// AuthFailureReason tells clients why their credentials were rejected, eg:
+// to refresh an expired token silently rather than asking to log in again.
+type AuthFailureReason string
+
+// Reasons of UnauthorizedReason, rendered as "error.reason".
+const (
+	// TokenExpired is sent for a valid token past its expiry, clients can
+	// refresh it.
+	TokenExpired AuthFailureReason = "TOKEN_EXPIRED"
+	// TokenInvalid is sent for a malformed token or a bad signature.
+	TokenInvalid AuthFailureReason = "TOKEN_INVALID"
+	// TokenMissing is sent for requests without credentials.
+	TokenMissing AuthFailureReason = "TOKEN_MISSING"
+	// SessionRevoked is sent for a token whose session was ended, eg: by a
+	// logout on another device, clients must log in again.
+	SessionRevoked AuthFailureReason = "SESSION_REVOKED"
+)
+
+// authFailureDescriptions are the error_description of the Bearer challenge
+// of each reason. TokenMissing has none, as RFC 6750 sends no error code for
+// requests without credentials.
+var authFailureDescriptions = map[AuthFailureReason]string{
+	TokenExpired:   "The access token expired",
+	TokenInvalid:   "The access token is invalid",
+	SessionRevoked: "The session was revoked",
+}
+
+// FormatChallenge formats an authentication challenge as defined by RFC 9110,
+// eg: `Bearer realm="api", error="invalid_token"`. The realm comes first and
+// the remaining parameters are sorted by name. Parameter values are quoted
+// and escaped, parameters with an invalid name are skipped.
+func FormatChallenge(scheme, realm string, params map[string]string) string {
+	var b strings.Builder
+	b.WriteString(scheme)
+	separator := " "
+	writeParam := func(name, value string) {
+		b.WriteString(separator)
+		b.WriteString(name)
+		b.WriteString("=")
+		b.WriteString(quoteString(value))
+		separator = ", "
+	}
+	if realm != "" {
+		writeParam("realm", realm)
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "realm" && isToken(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeParam(name, params[name])
+	}
+	return b.String()
+}
+
+// quoteString returns value as an RFC 9110 quoted-string, escaping '"' and
+// '\' and dropping control characters which are not allowed.
+func quoteString(value string) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch == '\t' || (ch >= 0x20 && ch != 0x7f):
+			b.WriteByte(ch)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isToken reports whether s is an RFC 9110 token.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' {
+			continue
+		}
+		if !strings.ContainsRune("!#$%&'*+-.^_`|~", rune(ch)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Core) UnauthorizedWithChallenge(c Exchange, message, scheme, realm string, params map[string]string, opts ...ResponseOption) {
+	opts = helperCall(opts, "UnauthorizedWithChallenge", nil)
+	setHeader(c, WWWAuthenticateHeader, FormatChallenge(scheme, realm, params))
+	r.respondError(c, http.StatusUnauthorized, ErrorBody{
+		Code:    401,
+		Status:  "UNAUTHORIZED",
+		Message: message,
+	}, opts...)
+}
+
+func (r *Core) UnauthorizedReason(c Exchange, reason AuthFailureReason, message string, opts ...ResponseOption) {
+	opts = helperCall(opts, "UnauthorizedReason", nil)
+	var params map[string]string
+	if reason != TokenMissing {
+		params = map[string]string{"error": BearerErrorInvalidToken}
+		if description, ok := authFailureDescriptions[reason]; ok {
+			params["error_description"] = description
+		}
+	}
+	setHeader(c, WWWAuthenticateHeader, FormatChallenge("Bearer", r.bearerRealm, params))
+	r.respondError(c, http.StatusUnauthorized, ErrorBody{
+		Code:    401,
+		Status:  "UNAUTHORIZED",
+		Message: message,
+		Reason:  string(reason),
+	}, opts...)
+}
+
+func (r *Core) ForbiddenScope(c Exchange, message string, required []string, granted []string, opts ...ResponseOption) {
+	opts = helperCall(opts, "ForbiddenScope", nil)
+	errorBody := ErrorBody{
+		Code:    403,
+		Status:  "FORBIDDEN",
+		Message: message,
+	}
+	if len(required) > 0 {
+		errorBody.RequiredPermissions = required
+	}
+	if len(granted) > 0 {
+		errorBody.GrantedPermissions = granted
+	}
+	if r.bearerChallenge {
+		params := map[string]string{"error": BearerErrorInsufficientScope}
+		if len(required) > 0 {
+			params["scope"] = strings.Join(required, " ")
+		}
+		setHeader(c, WWWAuthenticateHeader, FormatChallenge("Bearer", r.bearerRealm, params))
+	}
+	r.respondError(c, http.StatusForbidden, errorBody, opts...)
+}
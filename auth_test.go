@@ -0,0 +1,120 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestFormatChallenge(t *testing.T) {
+	for _, tc := range []struct {
+		scheme, realm string
+		params        map[string]string
+		want          string
+	}{
+		{"Bearer", "api", map[string]string{"error": responsehelper.BearerErrorInvalidToken}, `Bearer realm="api", error="invalid_token"`},
+		{"Bearer", "api", map[string]string{
+			"error_description": "The access token expired",
+			"error":             responsehelper.BearerErrorInvalidToken,
+		}, `Bearer realm="api", error="invalid_token", error_description="The access token expired"`},
+		{"Basic", `my "quoted" \realm`, nil, `Basic realm="my \"quoted\" \\realm"`},
+		{"Bearer", "", map[string]string{"scope": "a b", "bad name": "x", "realm": "ignored"}, `Bearer scope="a b"`},
+		{"Bearer", "api", map[string]string{"error": "line\r\nbreak"}, `Bearer realm="api", error="linebreak"`},
+	} {
+		if got := responsehelper.FormatChallenge(tc.scheme, tc.realm, tc.params); got != tc.want {
+			t.Errorf("FormatChallenge(%q, %q, %v) = %s, want %s", tc.scheme, tc.realm, tc.params, got, tc.want)
+		}
+	}
+}
+
+func TestUnauthorizedWithChallenge(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/me")
+	responsehelper.NewResponseHelper().UnauthorizedWithChallenge(c, "Invalid token", "Bearer", "api", map[string]string{
+		"error": responsehelper.BearerErrorInvalidToken,
+	})
+
+	assertError(t, w, http.StatusUnauthorized, "Invalid token")
+	if got := w.Header().Get(responsehelper.WWWAuthenticateHeader); got != `Bearer realm="api", error="invalid_token"` {
+		t.Errorf("%s = %s", responsehelper.WWWAuthenticateHeader, got)
+	}
+}
+
+func TestUnauthorizedReason(t *testing.T) {
+	for _, tc := range []struct {
+		reason responsehelper.AuthFailureReason
+		want   string
+	}{
+		{responsehelper.TokenExpired, `Bearer realm="api", error="invalid_token", error_description="The access token expired"`},
+		{responsehelper.TokenInvalid, `Bearer realm="api", error="invalid_token", error_description="The access token is invalid"`},
+		{responsehelper.SessionRevoked, `Bearer realm="api", error="invalid_token", error_description="The session was revoked"`},
+		{responsehelper.TokenMissing, `Bearer realm="api"`},
+		{"ACCOUNT_LOCKED", `Bearer realm="api", error="invalid_token"`},
+	} {
+		t.Run(string(tc.reason), func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/me")
+			responsehelper.NewResponseHelper(responsehelper.WithBearerChallenge("api")).UnauthorizedReason(c, tc.reason, "Please log in")
+
+			assertError(t, w, http.StatusUnauthorized, "Please log in")
+			assertField(t, w, "error.reason", string(tc.reason))
+			if got := w.Header().Get(responsehelper.WWWAuthenticateHeader); got != tc.want {
+				t.Errorf("%s = %s, want %s", responsehelper.WWWAuthenticateHeader, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnauthorizedHasNoReason(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/me")
+	responsehelper.NewResponseHelper().Unauthorized(c, "Please log in")
+
+	if _, ok := lookup(w, "error.reason"); ok {
+		t.Errorf("Unauthorized sent a reason: %s", w.Body)
+	}
+}
+
+func TestUnauthorizedReasonIsNotPrecomputedAway(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	h.PrecomputeError(http.StatusUnauthorized, "Please log in")
+	for _, reason := range []responsehelper.AuthFailureReason{responsehelper.TokenExpired, responsehelper.TokenInvalid, responsehelper.TokenExpired} {
+		c, w := newContext(http.MethodGet, "/me")
+		h.UnauthorizedReason(c, reason, "Please log in")
+
+		assertField(t, w, "error.reason", string(reason))
+	}
+}
+
+func TestForbiddenScope(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/invoices")
+	responsehelper.NewResponseHelper().ForbiddenScope(c, "Missing role", []string{"billing.write"}, []string{"billing.read"})
+
+	assertError(t, w, http.StatusForbidden, "Missing role")
+	assertField(t, w, "error.requiredPermissions", []string{"billing.write"})
+	assertField(t, w, "error.grantedPermissions", []string{"billing.read"})
+	if got := w.Header().Get(responsehelper.WWWAuthenticateHeader); got != "" {
+		t.Errorf("%s = %s without a bearer flow", responsehelper.WWWAuthenticateHeader, got)
+	}
+}
+
+func TestForbiddenScopeBearerChallenge(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/invoices")
+	responsehelper.NewResponseHelper(responsehelper.WithBearerChallenge("api")).
+		ForbiddenScope(c, "Missing role", []string{"billing.write", "billing.read"}, nil)
+
+	want := `Bearer realm="api", error="insufficient_scope", scope="billing.write billing.read"`
+	if got := w.Header().Get(responsehelper.WWWAuthenticateHeader); got != want {
+		t.Errorf("%s = %s, want %s", responsehelper.WWWAuthenticateHeader, got, want)
+	}
+}
+
+func TestForbiddenScopeOmitsEmptySlices(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/invoices")
+	responsehelper.NewResponseHelper().ForbiddenScope(c, "No", []string{}, nil)
+
+	errorBody := decodeBody(t, w)["error"].(map[string]interface{})
+	for _, member := range []string{"requiredPermissions", "grantedPermissions"} {
+		if value, ok := errorBody[member]; ok {
+			t.Errorf("error.%s = %v, want no member", member, value)
+		}
+	}
+}
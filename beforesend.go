@@ -0,0 +1,78 @@
+package responsehelper
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// beforeSendFailedKey marks a request whose WithBeforeSend hook failed, so
+// the 500 replacing its response does not run the hooks again.
+const beforeSendFailedKey = "responsehelper.beforeSendFailed"
+
+// OutgoingResponse is a response about to be marshalled, given to the
+// WithBeforeSend hooks to modify.
+type OutgoingResponse struct {
+	// Status is the status the response is sent with. Hooks may change it
+	// within its class, eg: 200 to 203, other changes are ignored with a
+	// warning.
+	Status int
+	// Success is the envelope of a success response, nil for an error.
+	Success *SuccessEnvelope
+	// Error is the envelope of an error response, nil for a success.
+	Error *ErrorEnvelope
+
+	c Exchange
+}
+
+// SetHeader sets the header name of the response to value, removing it when
+// value is empty.
+func (r *OutgoingResponse) SetHeader(name, value string) {
+	setHeader(r.c, name, value)
+}
+
+// BeforeSendHook modifies a response before it is marshalled. Returning an
+// error replaces the response with a 500 Internal Server Error.
+type BeforeSendHook func(c *gin.Context, response *OutgoingResponse) error
+
+// WithBeforeSend adds a hook called with every success and error envelope
+// before it is marshalled, eg: to add a tenant watermark to the meta or
+// redact data. Hooks run in the order they were added, each seeing the
+// changes of the previous ones. When one returns an error the response is
+// replaced by a 500 Internal Server Error, which the hooks do not see. The
+// bodies of SuccessCSV, SuccessLarge, Problem and OAuthError are not passed
+// to the hooks.
+//
+// Example:
+//
+//	responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+//		response.SetHeader("X-Tenant", c.GetString("tenant"))
+//		return nil
+//	})
+func WithBeforeSend(hook BeforeSendHook) Option {
+	return func(cfg *config) {
+		if hook != nil {
+			cfg.beforeSendHooks = append(cfg.beforeSendHooks, hook)
+		}
+	}
+}
+
+// runBeforeSend runs the WithBeforeSend hooks on response and returns its
+// status, or sends a 500 and reports false when a hook failed.
+func (r *Core) runBeforeSend(c Exchange, response *OutgoingResponse) (int, bool) {
+	status := response.Status
+	if len(r.beforeSendHooks) == 0 || getBool(c, beforeSendFailedKey) {
+		return status, true
+	}
+	response.c = c
+	for _, hook := range r.beforeSendHooks {
+		if err := hook(ginContextOf(c), response); err != nil {
+			c.Set(beforeSendFailedKey, true)
+			r.InternalError(c, "An unexpected error occurred", err)
+			return status, false
+		}
+	}
+	if response.Status/100 != status/100 {
+		r.warnf("before send hook changed the status %d to %d, keeping %d", status, response.Status, status)
+		return status, true
+	}
+	return response.Status, true
+}
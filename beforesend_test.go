@@ -0,0 +1,136 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBeforeSendModifiesTheSuccess(t *testing.T) {
+	var order []string
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+			order = append(order, "watermark")
+			response.Success.Meta = gin.H{"tenant": c.GetString("tenant")}
+			response.SetHeader("X-Tenant", c.GetString("tenant"))
+			return nil
+		}),
+		responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+			order = append(order, "redact")
+			data := response.Success.Data.(gin.H)
+			delete(data, "password")
+			if meta, ok := response.Success.Meta.(gin.H); !ok || meta["tenant"] != "acme" {
+				t.Errorf("the second hook got the meta %v, want the one of the first", response.Success.Meta)
+			}
+			response.Status = http.StatusNonAuthoritativeInfo
+			return nil
+		}),
+	)
+	c, w := newContext(http.MethodGet, "/users/42")
+	c.Set("tenant", "acme")
+	h.Success(c, gin.H{"id": 42, "password": "secret"})
+
+	if strings.Join(order, ",") != "watermark,redact" {
+		t.Errorf("hooks ran in the order %v", order)
+	}
+	if w.Code != http.StatusNonAuthoritativeInfo || w.Header().Get("X-Tenant") != "acme" {
+		t.Errorf("status = %d, X-Tenant = %q", w.Code, w.Header().Get("X-Tenant"))
+	}
+	body := decodeBody(t, w)
+	if data, _ := body["data"].(map[string]interface{}); data["password"] != nil || data["id"] != float64(42) {
+		t.Errorf("data = %v", body["data"])
+	}
+	if meta, _ := body["meta"].(map[string]interface{}); meta["tenant"] != "acme" {
+		t.Errorf("meta = %v", body["meta"])
+	}
+}
+
+func TestBeforeSendModifiesTheError(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+		if response.Success != nil || response.Error == nil {
+			t.Errorf("an error was given as %+v", response)
+			return nil
+		}
+		response.Error.Error.Details = nil
+		response.Status = http.StatusUnprocessableEntity
+		return nil
+	}))
+	c, w := newContext(http.MethodGet, "/users/42")
+	h.BadRequest(c, "Invalid input", "name is required")
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", w.Code)
+	}
+	if errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{}); errorBody["details"] != nil || errorBody["code"] != float64(http.StatusUnprocessableEntity) {
+		t.Errorf("error = %v, want the details dropped and the code following the status", errorBody)
+	}
+}
+
+func TestBeforeSendKeepsTheStatusClass(t *testing.T) {
+	logs := &captureHandler{level: slog.LevelWarn}
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithLogger(slog.New(logs)),
+		responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+			response.Status = http.StatusBadRequest
+			return nil
+		}),
+	)
+	c, w := newContext(http.MethodGet, "/users/42")
+	h.Success(c, gin.H{"id": 42})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 kept", w.Code)
+	}
+	if len(logs.records) == 0 || !strings.Contains(logs.records[0].Message, "changed the status 200 to 400") {
+		t.Errorf("logs = %v, want a warning", logs.records)
+	}
+}
+
+func TestBeforeSendErrorBecomesAnInternalError(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"success": func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 42}) },
+		"error":   func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "User not found") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls, later := 0, 0
+			failure := errors.New("redaction policy unavailable")
+			var reports []report
+			h := reportingHelper(&reports,
+				responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+					calls++
+					return failure
+				}),
+				responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+					later++
+					return nil
+				}),
+			)
+			c, w := newContext(http.MethodGet, "/users/42")
+			respond(h, c)
+
+			if calls != 1 || later != 0 {
+				t.Errorf("the failing hook ran %d times and the next one %d times, want 1 and 0", calls, later)
+			}
+			if w.Code != http.StatusInternalServerError || strings.Count(w.Body.String(), `"success"`) != 1 {
+				t.Errorf("status = %d, body: %s, want a single 500", w.Code, w.Body)
+			}
+			if len(reports) != 1 || reports[0].err != failure {
+				t.Errorf("reports = %v, want the error of the hook", reports)
+			}
+		})
+	}
+}
+
+func TestBeforeSendNil(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithBeforeSend(nil)).Success(c, gin.H{"id": 42})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
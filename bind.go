@@ -0,0 +1,155 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BindJSON binds the JSON body of the request into dst. When binding fails it
+// sends the 400 response describing the failure and returns false, so
+// handlers can stop right away:
+//
+//	var req CreateUserRequest
+//	if !responsehelper.BindJSON(c, h.responseHelper, &req) {
+//		return
+//	}
+//
+// A wrong JSON type is reported as a field error, eg: "expected number, got
+// string", a syntax error with its offset, an empty body as "empty body" and
+// validation errors the same way as ValidationFailed.
+func BindJSON(c *gin.Context, h ResponseHelper, dst interface{}) bool {
+	return bindResult(c, h, dst, c.ShouldBindJSON(dst))
+}
+
+// BindQuery binds the query string into dst like BindJSON.
+func BindQuery(c *gin.Context, h ResponseHelper, dst interface{}) bool {
+	return bindResult(c, h, dst, c.ShouldBindQuery(dst))
+}
+
+// BindUri binds the path parameters into dst like BindJSON.
+func BindUri(c *gin.Context, h ResponseHelper, dst interface{}) bool {
+	return bindResult(c, h, dst, c.ShouldBindUri(dst))
+}
+
+// bindResult sends the response for a failed bind into dst and reports
+// whether the bind succeeded. Type and validation errors both name their
+// field the way FieldErrors does, eg: "items[2].name".
+func bindResult(c *gin.Context, h ResponseHelper, dst interface{}, err error) bool {
+	if err == nil {
+		return true
+	}
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		h.RespondAPIError(c, &APIError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			FieldErrors: []FieldError{{
+				Field:   typeErrorPath(typeErr.Field, reflect.TypeOf(dst)),
+				Tag:     "type",
+				Message: fmt.Sprintf("expected %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value),
+			}},
+		})
+	case errors.As(err, &syntaxErr):
+		h.BadRequest(c, "Invalid request body", fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		h.BadRequest(c, "Invalid request body", "malformed JSON, unexpected end of body")
+	case errors.Is(err, io.EOF):
+		h.BadRequest(c, "Invalid request body", "empty body")
+	default:
+		h.ValidationFailed(c, err, BoundTo(dst))
+	}
+	return false
+}
+
+// typeErrorPath converts the dotted path of a json.UnmarshalTypeError, eg:
+// "items.2.name", to the path used for validation errors, eg: "items[2].name",
+// by walking root. Indexes of slices, arrays and maps become brackets.
+func typeErrorPath(field string, root reflect.Type) string {
+	if field == "" {
+		return ""
+	}
+	t := derefType(root)
+	var path strings.Builder
+	for _, segment := range strings.Split(field, ".") {
+		index := false
+		switch {
+		case t == nil:
+			_, err := strconv.Atoi(segment)
+			index = err == nil
+		case t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map:
+			index, t = true, derefType(t.Elem())
+		case t.Kind() == reflect.Struct:
+			t = jsonFieldType(t, segment)
+		default:
+			t = nil
+		}
+		if index {
+			path.WriteString("[" + segment + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(segment)
+	}
+	return path.String()
+}
+
+// jsonFieldType returns the type of the field of t decoded from the JSON
+// member name, looking into embedded structs like encoding/json, or nil.
+func jsonFieldType(t reflect.Type, name string) reflect.Type {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tagName == "-" {
+			continue
+		}
+		if field.Anonymous && tagName == "" {
+			if embedded := derefType(field.Type); embedded.Kind() == reflect.Struct {
+				if found := jsonFieldType(embedded, name); found != nil {
+					return found
+				}
+			}
+			continue
+		}
+		if tagName == name || tagName == "" && strings.EqualFold(field.Name, name) {
+			return derefType(field.Type)
+		}
+	}
+	return nil
+}
+
+// jsonTypeName returns the JSON type a Go type is decoded from.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,87 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestBindJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		body    string
+		details string
+		field   string
+		message string
+	}{
+		{name: "empty body", body: "", details: "empty body"},
+		{name: "syntax error", body: `{"customer_name": }`, details: "malformed JSON at offset 19"},
+		{name: "truncated", body: `{"customer_name": "arun"`, details: "malformed JSON, unexpected end of body"},
+		{name: "type error", body: `{"customer_name": 42}`, field: "customer_name", message: "expected string, got number"},
+		{name: "nested type error", body: `{"items": [{"name": "pen", "qty": 1}, {"name": "ink", "qty": "two"}]}`, field: "items[1].qty", message: "expected number, got string"},
+		{name: "embedded type error", body: `{"created_by": true}`, field: "created_by", message: "expected string, got bool"},
+		{name: "validation error", body: orderBody, field: "items[2].name", message: "items[2].name is required"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodPost, "/orders")
+			c.Request = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tc.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			var req createOrder
+			if responsehelper.BindJSON(c, responsehelper.NewResponseHelper(), &req) {
+				t.Fatal("BindJSON succeeded")
+			}
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d\nbody: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+			if tc.details != "" {
+				assertField(t, w, "error.details", tc.details)
+				return
+			}
+			errs, _ := decodeBody(t, w)["error"].(map[string]interface{})["errors"].([]interface{})
+			for _, e := range errs {
+				fieldError := e.(map[string]interface{})
+				if fieldError["field"] == tc.field {
+					if fieldError["message"] != tc.message {
+						t.Errorf("message = %v, want %q", fieldError["message"], tc.message)
+					}
+					return
+				}
+			}
+			t.Errorf("no error for %q\nbody: %s", tc.field, w.Body)
+		})
+	}
+}
+
+func TestBindJSONSucceeds(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/orders")
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"customer_name":"arun","created_by":"arun","shipping_address":{"city":"Kochi"},"items":[{"name":"pen","qty":1}]}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	var req createOrder
+	if !responsehelper.BindJSON(c, responsehelper.NewResponseHelper(), &req) {
+		t.Fatalf("BindJSON failed\nbody: %s", w.Body)
+	}
+	if c.Writer.Written() {
+		t.Error("BindJSON wrote a response")
+	}
+	if req.Items[0].Name != "pen" {
+		t.Errorf("req = %+v", req)
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	var query struct {
+		Page int    `form:"page" binding:"min=1"`
+		Sort string `form:"sort"`
+	}
+	c, w := newContext(http.MethodGet, "/orders?page=0")
+	if responsehelper.BindQuery(c, responsehelper.NewResponseHelper(), &query) {
+		t.Fatal("BindQuery succeeded")
+	}
+
+	assertError(t, w, http.StatusBadRequest, "Validation failed")
+	assertField(t, w, "error.errors.0.field", "page")
+}
@@ -0,0 +1,122 @@
+package responsehelper
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// noStore is the Cache-Control of the error responses.
+const noStore = "no-store"
+
+// CachePolicy is the Cache-Control of a response, see WithCache.
+type CachePolicy struct {
+	// Private restricts caching to the client, shared caches may store the
+	// response otherwise.
+	Private bool
+	// MaxAge is how long the response stays fresh, rendered as max-age in
+	// whole seconds.
+	MaxAge time.Duration
+	// SharedMaxAge overrides MaxAge for shared caches, eg: CDNs, rendered as
+	// s-maxage when positive. It is left out of private policies.
+	SharedMaxAge time.Duration
+	// StaleWhileRevalidate is how long a stale response may be served while
+	// it is revalidated, rendered as stale-while-revalidate when positive.
+	StaleWhileRevalidate time.Duration
+	// NoStore forbids storing the response at all, the other fields are
+	// ignored.
+	NoStore bool
+}
+
+// String returns the Cache-Control header value of the policy, eg:
+// "public, max-age=60, s-maxage=300, stale-while-revalidate=30".
+func (p CachePolicy) String() string {
+	if p.NoStore {
+		return noStore
+	}
+	directives := []string{"public"}
+	if p.Private {
+		directives[0] = "private"
+	}
+	directives = append(directives, "max-age="+cacheSeconds(p.MaxAge))
+	if p.SharedMaxAge > 0 && !p.Private {
+		directives = append(directives, "s-maxage="+cacheSeconds(p.SharedMaxAge))
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+cacheSeconds(p.StaleWhileRevalidate))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// cacheSeconds formats d as the whole seconds of a Cache-Control directive.
+func cacheSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(max(d, 0)/time.Second), 10)
+}
+
+// WithCache sets the Cache-Control of the response to policy, overriding
+// WithDefaultCache on success responses and no-store on error responses.
+//
+// Example:
+//
+//	h.responseHelper.Success(c, catalog, responsehelper.WithCache(responsehelper.CachePolicy{
+//		MaxAge:               time.Minute,
+//		SharedMaxAge:         5 * time.Minute,
+//		StaleWhileRevalidate: 30 * time.Second,
+//	}))
+func WithCache(policy CachePolicy) ResponseOption {
+	return func(options *responseOptions) {
+		options.cache = &policy
+	}
+}
+
+// CachePublic lets clients and shared caches store the response for maxAge,
+// eg: "Cache-Control: public, max-age=60".
+func CachePublic(maxAge time.Duration) ResponseOption {
+	return WithCache(CachePolicy{MaxAge: maxAge})
+}
+
+// CachePrivate lets only the client store the response for maxAge, eg:
+// "Cache-Control: private, max-age=60".
+func CachePrivate(maxAge time.Duration) ResponseOption {
+	return WithCache(CachePolicy{Private: true, MaxAge: maxAge})
+}
+
+// NoStore forbids storing the response, "Cache-Control: no-store".
+func NoStore() ResponseOption {
+	return WithCache(CachePolicy{NoStore: true})
+}
+
+// WithDefaultCache sets the Cache-Control of the success responses sent
+// without WithCache or a Cache-Control set by the handler. Without it they
+// get none. Error responses are sent with no-store whatever it says.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithDefaultCache(responsehelper.CachePolicy{Private: true}))
+func WithDefaultCache(policy CachePolicy) Option {
+	return func(cfg *config) {
+		cfg.defaultCache = &policy
+	}
+}
+
+// setCacheHeader sets the Cache-Control of a success response to policy, or
+// to WithDefaultCache unless the handler set one.
+func (cfg *config) setCacheHeader(c Exchange, policy *CachePolicy) {
+	if policy == nil {
+		if cfg.defaultCache == nil || c.Header().Get("Cache-Control") != "" {
+			return
+		}
+		policy = cfg.defaultCache
+	}
+	setHeader(c, "Cache-Control", policy.String())
+}
+
+// setErrorCacheHeader sets the Cache-Control of an error response to
+// policy, or to no-store.
+func setErrorCacheHeader(c Exchange, policy *CachePolicy) {
+	value := noStore
+	if policy != nil {
+		value = policy.String()
+	}
+	setHeader(c, "Cache-Control", value)
+}
@@ -0,0 +1,138 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCachePolicyString(t *testing.T) {
+	for _, tt := range []struct {
+		policy responsehelper.CachePolicy
+		want   string
+	}{
+		{responsehelper.CachePolicy{}, "public, max-age=0"},
+		{responsehelper.CachePolicy{MaxAge: time.Minute}, "public, max-age=60"},
+		{responsehelper.CachePolicy{MaxAge: 1500 * time.Millisecond}, "public, max-age=1"},
+		{responsehelper.CachePolicy{MaxAge: -time.Minute}, "public, max-age=0"},
+		{responsehelper.CachePolicy{Private: true, MaxAge: time.Minute}, "private, max-age=60"},
+		{responsehelper.CachePolicy{MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute}, "public, max-age=60, s-maxage=300"},
+		{responsehelper.CachePolicy{Private: true, MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute}, "private, max-age=60"},
+		{responsehelper.CachePolicy{MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second}, "public, max-age=60, stale-while-revalidate=30"},
+		{responsehelper.CachePolicy{Private: true, MaxAge: time.Minute, StaleWhileRevalidate: 30 * time.Second}, "private, max-age=60, stale-while-revalidate=30"},
+		{
+			responsehelper.CachePolicy{MaxAge: time.Minute, SharedMaxAge: 5 * time.Minute, StaleWhileRevalidate: 30 * time.Second},
+			"public, max-age=60, s-maxage=300, stale-while-revalidate=30",
+		},
+		{responsehelper.CachePolicy{MaxAge: time.Minute, SharedMaxAge: -time.Second, StaleWhileRevalidate: -time.Second}, "public, max-age=60"},
+		{responsehelper.CachePolicy{NoStore: true, Private: true, MaxAge: time.Minute}, "no-store"},
+	} {
+		if got := tt.policy.String(); got != tt.want {
+			t.Errorf("%+v: %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context, opt responsehelper.ResponseOption)
+	}{
+		{"Success", func(h responsehelper.ResponseHelper, c *gin.Context, opt responsehelper.ResponseOption) {
+			h.Success(c, gin.H{"id": 1}, opt)
+		}},
+		{"SuccessWithPagination", func(h responsehelper.ResponseHelper, c *gin.Context, opt responsehelper.ResponseOption) {
+			h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 1, 1), opt)
+		}},
+		{"Respond", func(h responsehelper.ResponseHelper, c *gin.Context, opt responsehelper.ResponseOption) {
+			h.Respond(c, nil, gin.H{"id": 1}, opt)
+		}},
+	} {
+		for _, option := range []struct {
+			opt  responsehelper.ResponseOption
+			want string
+		}{
+			{responsehelper.CachePublic(time.Minute), "public, max-age=60"},
+			{responsehelper.CachePrivate(10 * time.Second), "private, max-age=10"},
+			{responsehelper.NoStore(), "no-store"},
+		} {
+			t.Run(tt.name+"/"+option.want, func(t *testing.T) {
+				c, w := newContext(http.MethodGet, "/catalog")
+				tt.respond(responsehelper.NewResponseHelper(), c, option.opt)
+
+				if got := w.Header().Get("Cache-Control"); got != option.want {
+					t.Errorf("Cache-Control = %q, want %q", got, option.want)
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultCache(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithDefaultCache(responsehelper.CachePolicy{Private: true, MaxAge: time.Minute}))
+	for _, tt := range []struct {
+		name    string
+		respond func(c *gin.Context)
+		want    string
+	}{
+		{"default", func(c *gin.Context) { h.Success(c, 1) }, "private, max-age=60"},
+		{"option", func(c *gin.Context) { h.Success(c, 1, responsehelper.CachePublic(time.Hour)) }, "public, max-age=3600"},
+		{"handler", func(c *gin.Context) {
+			c.Header("Cache-Control", "max-age=5")
+			h.Success(c, 1)
+		}, "max-age=5"},
+		{"option over handler", func(c *gin.Context) {
+			c.Header("Cache-Control", "max-age=5")
+			h.Success(c, 1, responsehelper.NoStore())
+		}, "no-store"},
+		{"error", func(c *gin.Context) { h.NotFound(c, "missing") }, "no-store"},
+	} {
+		c, w := newContext(http.MethodGet, "/catalog")
+		tt.respond(c)
+		if got := w.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	c, w := newContext(http.MethodGet, "/catalog")
+	responsehelper.NewResponseHelper().Success(c, 1)
+	if got, ok := w.Header()["Cache-Control"]; ok {
+		t.Errorf("Cache-Control = %q without a default", got)
+	}
+}
+
+func TestErrorsAreNotStored(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		want    string
+	}{
+		{"NotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "missing") }, "no-store"},
+		{"InternalError", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.InternalError(c, "Oops", errors.New("db down"))
+		}, "no-store"},
+		{"Respond", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Respond(c, errors.New("db down"), nil, responsehelper.CachePublic(time.Minute))
+		}, "public, max-age=60"},
+		{"Problem", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Problem(c, http.StatusConflict, "", "Conflict", "", nil)
+		}, "no-store"},
+		{"handler header", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			c.Header("Cache-Control", "public, max-age=600")
+			h.BadRequest(c, "Invalid input", "")
+		}, "no-store"},
+		{"overridden", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.NotFound(c, "missing", responsehelper.CachePublic(30*time.Second))
+		}, "public, max-age=30"},
+	} {
+		c, w := newContext(http.MethodGet, "/catalog")
+		tt.respond(responsehelper.NewResponseHelper(responsehelper.WithDefaultCache(responsehelper.CachePolicy{MaxAge: time.Hour})), c)
+		if got := w.Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
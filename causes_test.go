@@ -0,0 +1,52 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// threeDeep wraps a driver error twice.
+var threeDeep = fmt.Errorf("creating account: %w", fmt.Errorf("saving user: %w", errors.New("pq: duplicate key")))
+
+func TestErrorCausesInDebugMode(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/accounts")
+	responsehelper.NewResponseHelper(responsehelper.WithDebug(true)).InternalError(c, "oops", threeDeep)
+
+	assertField(t, w, "error.causes", []string{"saving user: pq: duplicate key", "pq: duplicate key"})
+}
+
+func TestErrorCausesDepth(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/accounts")
+	responsehelper.NewResponseHelper(responsehelper.WithDebug(true), responsehelper.WithErrorCauseDepth(1)).InternalError(c, "oops", threeDeep)
+
+	assertField(t, w, "error.causes", []string{"saving user: pq: duplicate key"})
+}
+
+func TestErrorCausesWithoutDebug(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/accounts")
+	responsehelper.NewResponseHelper().InternalError(c, "oops", threeDeep)
+
+	assertNoCauses(t, decodeBody(t, w))
+}
+
+func TestErrorCausesNeverInReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+
+	c, w := newContext(http.MethodPost, "/accounts")
+	responsehelper.NewResponseHelper(responsehelper.WithDebug(true)).InternalError(c, "oops", threeDeep)
+
+	assertNoCauses(t, decodeBody(t, w))
+}
+
+func assertNoCauses(t *testing.T, body map[string]interface{}) {
+	t.Helper()
+	if causes, ok := body["error"].(map[string]interface{})["causes"]; ok {
+		t.Errorf("error.causes = %v, want no causes", causes)
+	}
+}
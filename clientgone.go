@@ -0,0 +1,35 @@
+package responsehelper
+
+import (
+	"context"
+	"errors"
+)
+
+// WithSkipOnClientGone skips the responses of requests whose client already
+// disconnected, their context being canceled, so no time is spent encoding
+// bodies nobody reads. The response hooks still run, with
+// ResponseInfo.Skipped set, and the 5xx errors are still logged and
+// reported. Requests whose context hit a deadline, eg: with Timeout, are
+// answered as usual.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithSkipOnClientGone(true))
+func WithSkipOnClientGone(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.skipOnClientGone = enabled
+	}
+}
+
+// clientGone reports whether the response must be skipped because the
+// client disconnected.
+func (cfg *config) clientGone(c Exchange) bool {
+	return cfg.skipOnClientGone && c.Request() != nil && errors.Is(c.Request().Context().Err(), context.Canceled)
+}
+
+// skipResponse runs the hooks of a response skipped by WithSkipOnClientGone.
+func (cfg *config) skipResponse(c Exchange, info ResponseInfo) {
+	c.Set(RespondedKey, true)
+	info.Skipped = true
+	cfg.runResponseHooks(c, info, c.Size())
+}
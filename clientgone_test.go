@@ -0,0 +1,140 @@
+package responsehelper_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// hookRecords collects the ResponseInfo of every response.
+type hookRecords struct {
+	mu    sync.Mutex
+	infos []responsehelper.ResponseInfo
+}
+
+func (h *hookRecords) hook(_ *gin.Context, info responsehelper.ResponseInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.infos = append(h.infos, info)
+}
+
+// goneEngine returns an engine whose handler cancels the request context,
+// as net/http does when the client disconnects, before responding.
+func goneEngine(h responsehelper.ResponseHelper, respond func(h responsehelper.ResponseHelper, c *gin.Context)) (*gin.Engine, *http.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+	engine := gin.New()
+	engine.GET("/users", func(c *gin.Context) {
+		cancel()
+		respond(h, c)
+	})
+	return engine, httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx)
+}
+
+func TestSkipOnClientGone(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		status  int
+		method  string
+	}{
+		{"Success", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, make([]int, 1000)) }, http.StatusOK, "Success"},
+		{"NotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "missing") }, http.StatusNotFound, "NotFound"},
+		{"SuccessLarge", func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessLarge(c, []int{1}) }, http.StatusOK, "SuccessLarge"},
+		{"SuccessCSV", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+		}, http.StatusOK, "SuccessCSV"},
+		{"Problem", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Problem(c, http.StatusConflict, "", "Conflict", "", nil)
+		}, http.StatusConflict, "Problem"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &hookRecords{}
+			h := responsehelper.NewResponseHelper(responsehelper.WithSkipOnClientGone(true), responsehelper.WithOnResponse(hooks.hook))
+			engine, r := goneEngine(h, tt.respond)
+			w := serve(engine, r)
+
+			if w.Body.Len() != 0 {
+				t.Errorf("wrote %q to a gone client", w.Body)
+			}
+			if len(hooks.infos) != 1 {
+				t.Fatalf("the hook ran %d times, want once", len(hooks.infos))
+			}
+			info := hooks.infos[0]
+			if !info.Skipped || info.Status != tt.status || info.Method != tt.method || info.BytesWritten != 0 {
+				t.Errorf("ResponseInfo = %+v", info)
+			}
+		})
+	}
+}
+
+func TestSkipOnClientGoneStillReports5xx(t *testing.T) {
+	var reported []error
+	logs := &captureHandler{}
+	hooks := &hookRecords{}
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithSkipOnClientGone(true),
+		responsehelper.WithOnResponse(hooks.hook),
+		responsehelper.WithLogger(slog.New(logs)),
+		responsehelper.WithErrorReporter(func(_ context.Context, err error, _ map[string]interface{}) {
+			reported = append(reported, err)
+		}),
+	)
+	dbDown := errors.New("db down")
+	engine, r := goneEngine(h, func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.InternalError(c, "Oops", dbDown)
+	})
+	w := serve(engine, r)
+
+	if w.Body.Len() != 0 {
+		t.Errorf("wrote %q to a gone client", w.Body)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], dbDown) {
+		t.Errorf("reported %v, want the error", reported)
+	}
+	if records := logs.responseRecords(); len(records) != 1 || records[0]["status"] != int64(http.StatusInternalServerError) {
+		t.Errorf("logged %v, want the 500", records)
+	}
+	if len(hooks.infos) != 1 || !hooks.infos[0].Skipped || !errors.Is(hooks.infos[0].Err, dbDown) {
+		t.Errorf("hooks = %+v", hooks.infos)
+	}
+}
+
+func TestSkipOnClientGoneAnswersOtherRequests(t *testing.T) {
+	for name, ctx := range map[string]func() (context.Context, context.CancelFunc){
+		"deadline": func() (context.Context, context.CancelFunc) {
+			return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		},
+		"live": func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := ctx()
+			defer cancel()
+			hooks := &hookRecords{}
+			c, w := newContext(http.MethodGet, "/users")
+			c.Request = c.Request.WithContext(ctx)
+			responsehelper.NewResponseHelper(responsehelper.WithSkipOnClientGone(true), responsehelper.WithOnResponse(hooks.hook)).
+				Success(c, gin.H{"id": 1})
+
+			if w.Body.Len() == 0 || len(hooks.infos) != 1 || hooks.infos[0].Skipped {
+				t.Errorf("body %q, hooks = %+v", w.Body, hooks.infos)
+			}
+		})
+	}
+}
+
+func TestClientGoneWithoutTheOption(t *testing.T) {
+	engine, r := goneEngine(responsehelper.NewResponseHelper(), func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, gin.H{"id": 1})
+	})
+	if w := serve(engine, r); w.Body.Len() == 0 {
+		t.Error("skipped a response without WithSkipOnClientGone")
+	}
+}
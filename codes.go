@@ -0,0 +1,59 @@
+package responsehelper
+
+import (
+	"net/http"
+	"sync"
+)
+
+// registeredCode is a business error code registered with RegisterCode.
+type registeredCode struct {
+	status         int
+	defaultMessage string
+}
+
+// codeRegistry holds the registered business error codes, safe for concurrent use.
+type codeRegistry struct {
+	mu    sync.RWMutex
+	codes map[string]registeredCode
+}
+
+func (reg *codeRegistry) register(code string, status int, defaultMessage string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if reg.codes == nil {
+		reg.codes = make(map[string]registeredCode)
+	}
+	reg.codes[code] = registeredCode{status: errorStatus(status), defaultMessage: defaultMessage}
+}
+
+func (reg *codeRegistry) lookup(code string) (registeredCode, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	registered, ok := reg.codes[code]
+	return registered, ok
+}
+
+func (r *Core) RegisterCode(code string, status int, defaultMessage string) {
+	r.codes.register(code, r.validErrorStatus(status, "RegisterCode"), defaultMessage)
+}
+
+func (r *Core) RespondCode(c Exchange, code string, args ...interface{}) {
+	opts := helperCall(nil, "RespondCode", nil)
+	registered, ok := r.codes.lookup(code)
+	if !ok {
+		r.warnf("unknown error code %q", code)
+		r.respondError(c, http.StatusInternalServerError, ErrorBody{
+			Code:      500,
+			Status:    "INTERNAL_SERVER_ERROR",
+			Message:   "An unexpected error occurred",
+			ErrorCode: code,
+		}, opts...)
+		return
+	}
+	r.respondError(c, registered.status, ErrorBody{
+		Code:      registered.status,
+		Status:    statusText(registered.status),
+		Message:   formatMessage(registered.defaultMessage, args...),
+		ErrorCode: code,
+	}, opts...)
+}
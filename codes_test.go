@@ -0,0 +1,78 @@
+package responsehelper_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestRespondCode(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	h.RegisterCode("USER_SUSPENDED", http.StatusForbidden, "User %s is suspended")
+
+	c, w := newContext(http.MethodGet, "/users/arun")
+	h.RespondCode(c, "USER_SUSPENDED", "arun")
+
+	assertError(t, w, http.StatusForbidden, "User arun is suspended")
+	assertField(t, w, "error.errorCode", "USER_SUSPENDED")
+}
+
+func TestRespondCodeUnknown(t *testing.T) {
+	var logs bytes.Buffer
+	h := responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	c, w := newContext(http.MethodGet, "/users/arun")
+	h.RespondCode(c, "NO_SUCH_CODE")
+
+	assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+	assertField(t, w, "error.errorCode", "NO_SUCH_CODE")
+	if !strings.Contains(logs.String(), `unknown error code \"NO_SUCH_CODE\"`) {
+		t.Errorf("no warning about the unknown code, logs:\n%s", logs.String())
+	}
+}
+
+func TestRegisterCodeInvalidStatus(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	h.RegisterCode("WEIRD", http.StatusOK, "Weird")
+
+	c, w := newContext(http.MethodGet, "/")
+	h.RespondCode(c, "WEIRD")
+
+	assertError(t, w, http.StatusInternalServerError, "Weird")
+}
+
+// TestRegisterCodeConcurrently is meant for go test -race.
+func TestRegisterCodeConcurrently(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				h.RegisterCode(fmt.Sprintf("CODE_%d_%d", i, j), http.StatusConflict, "Conflict %d")
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				c, w := newContext(http.MethodGet, "/")
+				h.RespondCode(c, fmt.Sprintf("CODE_%d_%d", i, j), j)
+				if w.Code != http.StatusConflict && w.Code != http.StatusInternalServerError {
+					t.Errorf("status = %d", w.Code)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	c, w := newContext(http.MethodGet, "/")
+	h.RespondCode(c, "CODE_7_49", 49)
+	assertError(t, w, http.StatusConflict, "Conflict 49")
+}
@@ -0,0 +1,282 @@
+package responsehelper
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// Exchange is the request being answered and its response, as seen by the
+// Core. The gin methods of a ResponseHelper render to the *gin.Context, the
+// adapters of the other frameworks, eg: the stdlib package, implement it for
+// theirs.
+//
+// The http.ResponseWriter methods follow net/http: WriteHeader sends the
+// status line and the headers, which are frozen from then on, and Write sends
+// them with 200 when WriteHeader was not called.
+type Exchange interface {
+	http.ResponseWriter
+	// Request returns the request being answered, nil when there is none.
+	Request() *http.Request
+	// Get returns the value stored under key for the request, the way a
+	// gin handler reads the values set by middleware with c.Get.
+	Get(key string) (value interface{}, exists bool)
+	// Set stores value under key for the request.
+	Set(key string, value interface{})
+	// Status returns the status of the response, 200 until one is written.
+	Status() int
+	// Size returns the number of body bytes written, -1 before the headers
+	// are sent.
+	Size() int
+	// Written reports whether the headers were sent.
+	Written() bool
+}
+
+// JSONWriter is implemented by the Exchanges of frameworks with their own
+// JSON rendering, eg: the one of Fiber. The Core hands them the JSON
+// envelopes to marshal and write with status and contentType, the headers
+// included, unless WithJSONEncoder is set or the data is raw JSON. An error
+// is answered with the 500 Internal Server Error sent when an envelope cannot
+// be marshalled, so nothing may be written when one is returned.
+type JSONWriter interface {
+	WriteJSON(status int, contentType string, v interface{}) error
+}
+
+// DetachedExchange is implemented by the Exchanges of the responses rendered
+// outside of an HTTP exchange, eg: by the render package. The Core runs no
+// response hooks, audit, error reporting or response logging for them, and
+// passes MarshalFailed the error of an envelope it could not render, answered
+// with the 500 Internal Server Error sent when an envelope cannot be
+// marshalled, instead of logging it.
+type DetachedExchange interface {
+	Exchange
+	MarshalFailed(err error)
+}
+
+// detached reports whether c is a DetachedExchange.
+func detached(c Exchange) bool {
+	_, ok := c.(DetachedExchange)
+	return ok
+}
+
+// Core renders the envelopes to an Exchange. It has the methods of
+// ResponseHelper, documented there, taking an Exchange instead of a
+// *gin.Context: the gin methods of a ResponseHelper call them, so every
+// framework shares the same envelopes, headers and configuration.
+//
+// Callbacks taking a *gin.Context, eg: a TranslatorFunc, a ResponseHook or a
+// BeforeSendHook, receive nil for the responses of an Exchange that is not a
+// gin context.
+type Core struct {
+	config
+	codes       codeRegistry
+	precomputed precomputedErrors
+}
+
+// NewCore creates a Core configured with opts, the options of
+// NewResponseHelper.
+func NewCore(opts ...Option) *Core {
+	r := &Core{}
+	for _, opt := range opts {
+		opt(&r.config)
+	}
+	return r
+}
+
+// CoreOf returns the Core h renders with, so other frameworks share the
+// configuration, the registered codes and the hooks of h. It returns nil
+// when h was not created by NewResponseHelper or New.
+func CoreOf(h ResponseHelper) *Core {
+	if helper, ok := h.(*responseHelper); ok {
+		return helper.Core
+	}
+	return nil
+}
+
+// ginExchange is the Exchange of a gin context. Holding only the pointer, it
+// is stored in an Exchange without allocating.
+type ginExchange struct {
+	c *gin.Context
+}
+
+// exchangeOf returns the Exchange of c.
+func exchangeOf(c *gin.Context) Exchange {
+	return ginExchange{c}
+}
+
+// ginContextOf returns the gin context of c, and nil when c is not one.
+func ginContextOf(c Exchange) *gin.Context {
+	if x, ok := c.(ginExchange); ok {
+		return x.c
+	}
+	return nil
+}
+
+func (x ginExchange) Request() *http.Request {
+	return x.c.Request
+}
+
+func (x ginExchange) Get(key string) (interface{}, bool) {
+	return x.c.Get(key)
+}
+
+func (x ginExchange) Set(key string, value interface{}) {
+	x.c.Set(key, value)
+}
+
+func (x ginExchange) Header() http.Header {
+	return x.c.Writer.Header()
+}
+
+func (x ginExchange) WriteHeader(status int) {
+	x.c.Writer.WriteHeader(status)
+	x.c.Writer.WriteHeaderNow()
+}
+
+func (x ginExchange) Write(data []byte) (int, error) {
+	return x.c.Writer.Write(data)
+}
+
+func (x ginExchange) WriteString(s string) (int, error) {
+	return x.c.Writer.WriteString(s)
+}
+
+func (x ginExchange) Flush() {
+	// the writer of gin panics when the one it wraps cannot flush
+	if w, ok := x.c.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		if _, ok := w.Unwrap().(http.Flusher); !ok {
+			return
+		}
+	}
+	x.c.Writer.Flush()
+}
+
+func (x ginExchange) Status() int {
+	return x.c.Writer.Status()
+}
+
+func (x ginExchange) Size() int {
+	return x.c.Writer.Size()
+}
+
+func (x ginExchange) Written() bool {
+	return x.c.Writer.Written()
+}
+
+// setHeader sets the response header key to value, removing it when value
+// is empty, like c.Header of gin.
+func setHeader(c Exchange, key, value string) {
+	if value == "" {
+		c.Header().Del(key)
+		return
+	}
+	c.Header().Set(key, value)
+}
+
+// getString returns the string stored under key, "" when there is none.
+func getString(c Exchange, key string) string {
+	value, _ := c.Get(key)
+	s, _ := value.(string)
+	return s
+}
+
+// getBool returns the bool stored under key, false when there is none.
+func getBool(c Exchange, key string) bool {
+	value, _ := c.Get(key)
+	b, _ := value.(bool)
+	return b
+}
+
+// requestHeader returns the request header key, "" without a request.
+func requestHeader(c Exchange, key string) string {
+	if c.Request() == nil {
+		return ""
+	}
+	return c.Request().Header.Get(key)
+}
+
+// query returns the query parameter key of the request, "" without a request.
+func query(c Exchange, key string) string {
+	if gc := ginContextOf(c); gc != nil {
+		// gin caches the parsed query
+		return gc.Query(key)
+	}
+	if c.Request() == nil || c.Request().URL == nil {
+		return ""
+	}
+	return c.Request().URL.Query().Get(key)
+}
+
+// clientIP returns the IP of the client, resolved by gin with its trusted
+// proxies, or the address of the peer for other exchanges.
+func clientIP(c Exchange) string {
+	if gc := ginContextOf(c); gc != nil {
+		return gc.ClientIP()
+	}
+	if c.Request() == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		return c.Request().RemoteAddr
+	}
+	return host
+}
+
+// renderTo writes r with status the way c.Render of gin does: the headers
+// are sent with the first byte of the body, so r can still set them, and
+// only the content type is sent when the status allows no body.
+func renderTo(c Exchange, status int, r render.Render) error {
+	if !bodyAllowedForStatus(status) {
+		r.WriteContentType(c)
+		c.WriteHeader(status)
+		return nil
+	}
+	w := &deferredHeader{Exchange: c, status: status}
+	err := r.Render(w)
+	if !w.sent {
+		c.WriteHeader(status)
+	}
+	return err
+}
+
+// deferredHeader sends the headers of an Exchange with status on the first
+// write.
+type deferredHeader struct {
+	Exchange
+	status int
+	sent   bool
+}
+
+func (w *deferredHeader) Write(data []byte) (int, error) {
+	if !w.sent {
+		w.sent = true
+		w.Exchange.WriteHeader(w.status)
+	}
+	return w.Exchange.Write(data)
+}
+
+// writeData writes body with status and contentType, like c.Data of gin.
+func writeData(c Exchange, status int, contentType string, body []byte) {
+	header := c.Header()
+	if len(header["Content-Type"]) == 0 {
+		header["Content-Type"] = []string{contentType}
+	}
+	c.WriteHeader(status)
+	if bodyAllowedForStatus(status) {
+		_, _ = c.Write(body)
+	}
+}
+
+// bodyAllowedForStatus reports whether a response with status may have a body.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
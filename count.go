@@ -0,0 +1,60 @@
+package responsehelper
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// CountPlacement is where SuccessWithCount sends the number of items.
+type CountPlacement int
+
+const (
+	// CountInMeta sends the count as "meta.count", the default.
+	CountInMeta CountPlacement = iota
+	// CountTopLevel sends the count as "count", next to "data".
+	CountTopLevel
+)
+
+// WithCountPlacement sets where SuccessWithCount sends the number of items.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithCountPlacement(responsehelper.CountTopLevel))
+func WithCountPlacement(placement CountPlacement) Option {
+	return func(cfg *config) {
+		cfg.countPlacement = placement
+	}
+}
+
+func (r *Core) SuccessWithCount(c Exchange, data interface{}) {
+	envelope := SuccessEnvelope{
+		Data:    nullable(data),
+		Success: true,
+	}
+	if count, ok := collectionLen(data); ok {
+		if r.countPlacement == CountTopLevel {
+			envelope.Count = &count
+		} else {
+			envelope.Meta = metaWithField(r.successMeta(c, "SuccessWithCount"), "count", count)
+		}
+	}
+	r.renderSuccess(c, "SuccessWithCount", http.StatusOK, envelope)
+}
+
+// collectionLen returns the number of items of a slice or an array, or of
+// the one a pointer points to. It reports false for other values, byte
+// slices included as they are rendered as strings or raw JSON.
+func collectionLen(data interface{}) (int, bool) {
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return 0, false
+		}
+		return value.Len(), true
+	}
+	return 0, false
+}
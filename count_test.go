@@ -0,0 +1,90 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+type countedUser struct {
+	Name string `json:"name"`
+}
+
+func TestSuccessWithCount(t *testing.T) {
+	var nilUsers []countedUser
+	array := [3]int{1, 2, 3}
+	for _, tc := range []struct {
+		name  string
+		data  interface{}
+		count int
+	}{
+		{"slice of structs", []countedUser{{"arun"}, {"anu"}}, 2},
+		{"slice of pointers", []*countedUser{{"arun"}}, 1},
+		{"slice of interfaces", []interface{}{1, "two", nil}, 3},
+		{"slice of strings", []string{"a", "b", "c", "d"}, 4},
+		{"array", array, 3},
+		{"pointer to an array", &array, 3},
+		{"pointer to a slice", &[]int{1, 2}, 2},
+		{"empty slice", []countedUser{}, 0},
+		{"nil slice", nilUsers, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			responsehelper.NewResponseHelper().SuccessWithCount(c, tc.data)
+
+			assertSuccess(t, w)
+			assertField(t, w, "meta.count", float64(tc.count))
+		})
+	}
+}
+
+func TestSuccessWithCountFallsBackToSuccess(t *testing.T) {
+	for name, data := range map[string]interface{}{
+		"struct": countedUser{"arun"},
+		"map":    map[string]int{"a": 1},
+		"bytes":  []byte("abc"),
+		"nil":    nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			responsehelper.NewResponseHelper().SuccessWithCount(c, data)
+
+			assertSuccess(t, w)
+			if count, ok := lookup(w, "meta.count"); ok {
+				t.Errorf("meta.count = %v for %T", count, data)
+			}
+		})
+	}
+}
+
+func TestSuccessWithCountTopLevel(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithCountPlacement(responsehelper.CountTopLevel)).
+		SuccessWithCount(c, []int{1, 2})
+
+	assertField(t, w, "count", float64(2))
+	if count, ok := lookup(w, "meta.count"); ok {
+		t.Errorf("meta.count = %v with CountTopLevel", count)
+	}
+}
+
+func TestSuccessWithCountOfANilSliceWithEmptyCollections(t *testing.T) {
+	var users []countedUser
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.New(responsehelper.WithCountPlacement(responsehelper.CountTopLevel)).SuccessWithCount(c, users)
+
+	if got := rawData(t, w.Body); got != "[]" {
+		t.Errorf("data = %s, want []", got)
+	}
+	assertField(t, w, "count", float64(0))
+}
+
+func TestSuccessWithCountKeepsTheMeta(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1"})
+	responsehelper.NewResponseHelper().SuccessWithCount(c, []int{1})
+
+	assertField(t, w, "meta.requestId", "req-1")
+	assertField(t, w, "meta.count", float64(1))
+}
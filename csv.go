@@ -0,0 +1,262 @@
+package responsehelper
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// errCSVRows is returned for rows SuccessCSV cannot render.
+var errCSVRows = errors.New("responsehelper: CSV rows must be a slice of structs or of maps with string keys")
+
+func (r *Core) SuccessCSV(c Exchange, filename string, rows interface{}) {
+	if r.secondResponse(c, "SuccessCSV") {
+		return
+	}
+	response := sentResponse{status: http.StatusOK, options: responseOptions{method: "SuccessCSV"}, streamed: true}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	table, err := newCSVTable(rows)
+	if err != nil {
+		r.InternalError(c, "An unexpected error occurred", err, helperCall(nil, "SuccessCSV", err)...)
+		return
+	}
+	setHeader(c, "Content-Type", "text/csv; charset=utf-8")
+	if filename != "" {
+		setHeader(c, "Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	r.writeResponse(c, response, func(c Exchange) {
+		c.WriteHeader(http.StatusOK)
+		if err := table.write(c); err != nil {
+			// the header is sent, so the error can only be logged
+			r.warnf("writing CSV response: %v", err)
+		}
+	})
+}
+
+// csvTable writes a slice of structs or maps as CSV.
+type csvTable struct {
+	rows    reflect.Value
+	header  []string
+	columns []csvColumn
+}
+
+// csvColumn locates the value of a column in a row.
+type csvColumn struct {
+	// index is the field index path of a struct row.
+	index []int
+	// key is the key of a map row.
+	key reflect.Value
+}
+
+func newCSVTable(rows interface{}) (*csvTable, error) {
+	value := reflect.ValueOf(rows)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, errCSVRows
+	}
+	table := &csvTable{rows: value}
+	elem := value.Type().Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	switch {
+	case elem.Kind() == reflect.Struct:
+		table.addStructColumns(elem, "", nil, map[reflect.Type]bool{})
+	case elem.Kind() == reflect.Map && elem.Key().Kind() == reflect.String:
+		table.addMapColumns(elem)
+	default:
+		return nil, errCSVRows
+	}
+	return table, nil
+}
+
+// addStructColumns adds a column per exported field of typ. Nested structs
+// are flattened into dotted headers, eg: "address.city", structs nested in
+// themselves are skipped.
+func (t *csvTable) addStructColumns(typ reflect.Type, prefix string, index []int, visiting map[reflect.Type]bool) {
+	visiting[typ] = true
+	defer delete(visiting, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, tagged := csvFieldName(field)
+		if name == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && !csvScalar(fieldType) {
+			switch {
+			case visiting[fieldType]:
+			case field.Anonymous && !tagged:
+				// embedded fields are promoted like encoding/json does
+				t.addStructColumns(fieldType, prefix, fieldIndex, visiting)
+			default:
+				t.addStructColumns(fieldType, prefix+name+".", fieldIndex, visiting)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		t.header = append(t.header, prefix+name)
+		t.columns = append(t.columns, csvColumn{index: fieldIndex})
+	}
+}
+
+// addMapColumns adds a column per key found in the rows, sorted.
+func (t *csvTable) addMapColumns(typ reflect.Type) {
+	seen := map[string]bool{}
+	for i := 0; i < t.rows.Len(); i++ {
+		row := reflect.Indirect(t.rows.Index(i))
+		if !row.IsValid() {
+			continue
+		}
+		for _, key := range row.MapKeys() {
+			seen[key.String()] = true
+		}
+	}
+	for key := range seen {
+		t.header = append(t.header, key)
+	}
+	sort.Strings(t.header)
+	for _, key := range t.header {
+		t.columns = append(t.columns, csvColumn{key: reflect.ValueOf(key).Convert(typ.Key())})
+	}
+}
+
+// write writes the header and the rows to w, one row at a time.
+func (t *csvTable) write(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.header); err != nil {
+		return err
+	}
+	record := make([]string, len(t.columns))
+	for i := 0; i < t.rows.Len(); i++ {
+		row := t.rows.Index(i)
+		for row.Kind() == reflect.Pointer && !row.IsNil() {
+			row = row.Elem()
+		}
+		for j, column := range t.columns {
+			record[j] = csvValue(column.value(row))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// value returns the value of the column in row, invalid when it has none.
+func (column csvColumn) value(row reflect.Value) reflect.Value {
+	if row.Kind() == reflect.Pointer {
+		// a nil row
+		return reflect.Value{}
+	}
+	if column.key.IsValid() {
+		return row.MapIndex(column.key)
+	}
+	for _, i := range column.index {
+		for row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				return reflect.Value{}
+			}
+			row = row.Elem()
+		}
+		row = row.Field(i)
+	}
+	return row
+}
+
+// csvFieldName returns the header of a struct field from its csv tag, its
+// json tag or its name, and whether it came from a tag.
+func csvFieldName(field reflect.StructField) (string, bool) {
+	for _, key := range []string{"csv", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name, true
+			}
+		}
+	}
+	return field.Name, false
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// csvScalar reports whether a struct type is rendered as a single value, eg: time.Time.
+func csvScalar(typ reflect.Type) bool {
+	return typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType)
+}
+
+// csvValue formats a field for a CSV cell. Slices, maps and other structs
+// are rendered as JSON.
+func csvValue(value reflect.Value) string {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return ""
+	}
+	if value.CanInterface() {
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return ""
+			}
+			return string(text)
+		}
+		if value.CanAddr() {
+			if marshaler, ok := value.Addr().Interface().(encoding.TextMarshaler); ok {
+				text, err := marshaler.MarshalText()
+				if err != nil {
+					return ""
+				}
+				return string(text)
+			}
+		}
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.Struct:
+		if (value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.IsNil() {
+			return ""
+		}
+		if value.CanInterface() {
+			if body, err := json.Marshal(value.Interface()); err == nil {
+				return string(body)
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,179 @@
+package responsehelper_test
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type csvAddress struct {
+	City string `json:"city"`
+	Zip  string `csv:"postcode"`
+}
+
+type csvAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type csvUser struct {
+	csvAudit
+	ID       int        `csv:"id" json:"userId"`
+	Name     string     `json:"name"`
+	Email    string     `json:"-"`
+	Address  csvAddress `json:"address"`
+	Joined   time.Time
+	Tags     []string `json:"tags"`
+	Manager  *csvUser `json:"manager"`
+	internal string
+}
+
+// sendCSV sends rows with SuccessCSV and returns the response.
+func sendCSV(rows interface{}) *httptest.ResponseRecorder {
+	c, w := newContext(http.MethodGet, "/users.csv")
+	responsehelper.NewResponseHelper().SuccessCSV(c, "users.csv", rows)
+	return w
+}
+
+func readCSV(t *testing.T, w *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV body: %v\nbody: %s", err, w.Body)
+	}
+	return records
+}
+
+func TestSuccessCSVStructs(t *testing.T) {
+	joined := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := sendCSV([]csvUser{{
+		csvAudit: csvAudit{CreatedBy: "admin"},
+		ID:       1,
+		Name:     "Arun, C S",
+		Email:    "arun@example.com",
+		Address:  csvAddress{City: "Kochi", Zip: "682001"},
+		Joined:   joined,
+		Tags:     []string{"a", "b"},
+		internal: "secret",
+	}})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=users.csv` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	want := [][]string{
+		{"createdBy", "id", "name", "address.city", "address.postcode", "Joined", "tags"},
+		{"admin", "1", "Arun, C S", "Kochi", "682001", "2024-05-01T12:00:00Z", `["a","b"]`},
+	}
+	if got := readCSV(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %q\nwant %q", got, want)
+	}
+}
+
+func TestSuccessCSVMaps(t *testing.T) {
+	w := sendCSV([]map[string]interface{}{
+		{"name": "arun", "id": 1},
+		{"id": 2, "email": "anu@example.com"},
+	})
+
+	want := [][]string{
+		{"email", "id", "name"},
+		{"", "1", "arun"},
+		{"anu@example.com", "2", ""},
+	}
+	if got := readCSV(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %q\nwant %q", got, want)
+	}
+}
+
+func TestSuccessCSVQuoting(t *testing.T) {
+	w := sendCSV([]map[string]string{{"note": "a, b\n\"quoted\" line"}})
+
+	if want := "note\n\"a, b\n\"\"quoted\"\" line\"\n"; w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body, want)
+	}
+	if got := readCSV(t, w)[1][0]; got != "a, b\n\"quoted\" line" {
+		t.Errorf("cell = %q after a round trip", got)
+	}
+}
+
+func TestSuccessCSVRejectsRows(t *testing.T) {
+	for _, rows := range []interface{}{
+		[]int{1, 2},
+		map[string]string{"name": "arun"},
+		csvUser{},
+		[]map[int]string{{1: "a"}},
+		nil,
+	} {
+		t.Run(fmt.Sprintf("%T", rows), func(t *testing.T) {
+			w := sendCSV(rows)
+
+			assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+			if got := w.Header().Get("Content-Disposition"); got != "" {
+				t.Errorf("Content-Disposition = %q on an error", got)
+			}
+		})
+	}
+}
+
+// countingWriter counts the writes reaching the client.
+type countingWriter struct {
+	gin.ResponseWriter
+	writes  int
+	largest int
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	w.writes++
+	w.largest = max(w.largest, len(data))
+	return w.ResponseWriter.Write(data)
+}
+
+func TestSuccessCSVStreams(t *testing.T) {
+	type row struct {
+		ID   int    `csv:"id"`
+		Note string `csv:"note"`
+	}
+	rows := make([]row, 10000)
+	for i := range rows {
+		rows[i] = row{ID: i, Note: fmt.Sprintf("line %d, with a comma\nand a newline", i)}
+	}
+	c, w := newContext(http.MethodGet, "/report.csv")
+	counter := &countingWriter{ResponseWriter: c.Writer}
+	c.Writer = counter
+	responsehelper.NewResponseHelper().SuccessCSV(c, "report.csv", rows)
+
+	if counter.writes < 100 {
+		t.Errorf("%d writes for %d bytes, want the rows streamed", counter.writes, w.Body.Len())
+	}
+	if counter.largest > 64<<10 {
+		t.Errorf("a write of %d bytes, want the rows streamed", counter.largest)
+	}
+	records := readCSV(t, w)
+	if len(records) != len(rows)+1 {
+		t.Fatalf("%d records, want %d", len(records), len(rows)+1)
+	}
+	if got := records[9999+1]; got[0] != "9999" || got[1] != "line 9999, with a comma\nand a newline" {
+		t.Errorf("last record = %q", got)
+	}
+}
+
+func TestSuccessCSVEmpty(t *testing.T) {
+	w := sendCSV([]csvAddress{})
+
+	if got := strings.TrimSpace(w.Body.String()); got != "city,postcode" {
+		t.Errorf("body = %q, want only the header", got)
+	}
+}
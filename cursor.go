@@ -0,0 +1,98 @@
+package responsehelper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for cursors that were not made
+// by EncodeCursor, or that do not decode into the destination.
+var ErrInvalidCursor = errors.New("responsehelper: invalid cursor")
+
+// CursorPagination is the "pagination" object of SuccessWithCursor. Empty
+// cursors are omitted from the response.
+type CursorPagination struct {
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJpZCI6NDJ9"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	PageSize   int    `json:"pageSize" example:"20"`
+	HasMore    bool   `json:"hasMore" example:"true"`
+}
+
+func (r *Core) SuccessWithCursor(c Exchange, data interface{}, cur CursorPagination) {
+	r.renderSuccess(c, "SuccessWithCursor", http.StatusOK, SuccessEnvelope{
+		Data:       nullable(data),
+		Pagination: cur,
+		Success:    true,
+	})
+}
+
+// EncodeCursor returns v as an opaque cursor, its JSON encoded as unpadded
+// base64url so it can be used in a query string as it is. It returns "" when
+// v cannot be encoded as JSON.
+//
+// Example:
+//
+//	next := responsehelper.EncodeCursor(map[string]interface{}{"id": last.ID, "createdAt": last.CreatedAt})
+func EncodeCursor(v interface{}) string {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// DecodeCursor decodes a cursor made by EncodeCursor into dst. The error
+// wraps ErrInvalidCursor when s is malformed.
+//
+// Example:
+//
+//	var after struct{ ID int64 `json:"id"` }
+//	if err := responsehelper.DecodeCursor(c.Query("cursor"), &after); err != nil {
+//		...
+//	}
+func DecodeCursor(s string, dst interface{}) error {
+	body, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return nil
+}
+
+// BindCursor decodes the cursor in the query parameter param into dst like
+// BindJSON. It sends a 400 response with a field error for param and returns
+// false when the cursor is malformed. dst is left untouched when the
+// parameter is missing, eg: for the first page.
+//
+// Example:
+//
+//	var after struct{ ID int64 `json:"id"` }
+//	if !responsehelper.BindCursor(c, h.responseHelper, "cursor", &after) {
+//		return
+//	}
+func BindCursor(c *gin.Context, h ResponseHelper, param string, dst interface{}) bool {
+	cursor := c.Query(param)
+	if cursor == "" {
+		return true
+	}
+	if err := DecodeCursor(cursor, dst); err != nil {
+		h.RespondAPIError(c, &APIError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid cursor",
+			FieldErrors: []FieldError{{
+				Field:   param,
+				Tag:     "cursor",
+				Message: "malformed cursor",
+			}},
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type cursorKey struct {
+	ID        int64  `json:"id"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func TestSuccessWithCursorOmitsEmptyCursors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cur      responsehelper.CursorPagination
+		want     []string
+		wantLeft []string
+	}{
+		{"first page", responsehelper.CursorPagination{NextCursor: "n1", PageSize: 20, HasMore: true},
+			[]string{`"nextCursor":"n1"`, `"pageSize":20`, `"hasMore":true`}, []string{"prevCursor"}},
+		{"last page", responsehelper.CursorPagination{PrevCursor: "p1", PageSize: 20},
+			[]string{`"prevCursor":"p1"`, `"hasMore":false`}, []string{"nextCursor"}},
+		{"single page", responsehelper.CursorPagination{PageSize: 20},
+			[]string{`"pageSize":20`, `"hasMore":false`}, []string{"nextCursor", "prevCursor"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/events")
+			responsehelper.NewResponseHelper().SuccessWithCursor(c, []int{1, 2}, tc.cur)
+
+			assertSuccess(t, w)
+			for _, want := range tc.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("body has no %s: %s", want, w.Body)
+				}
+			}
+			for _, left := range tc.wantLeft {
+				if strings.Contains(w.Body.String(), left) {
+					t.Errorf("body has %s: %s", left, w.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := cursorKey{ID: 42, CreatedAt: "2024-05-01T12:00:00Z"}
+	cursor := responsehelper.EncodeCursor(want)
+
+	if strings.ContainsAny(cursor, "+/=") {
+		t.Errorf("cursor %q is not unpadded base64url", cursor)
+	}
+	var got cursorKey
+	if err := responsehelper.DecodeCursor(cursor, &got); err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if got != want {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestEncodeCursorUnencodable(t *testing.T) {
+	if cursor := responsehelper.EncodeCursor(make(chan int)); cursor != "" {
+		t.Errorf("EncodeCursor(chan) = %q, want empty", cursor)
+	}
+}
+
+func TestDecodeCursorFailures(t *testing.T) {
+	for name, cursor := range map[string]string{
+		"not base64":      "!!!",
+		"padded":          responsehelper.EncodeCursor(cursorKey{ID: 1}) + "==",
+		"not json":        "bm90IGpzb24",
+		"wrong type":      responsehelper.EncodeCursor(map[string]string{"id": "abc"}),
+		"standard base64": "eyJpZCI6NDJ9+/",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got cursorKey
+			if err := responsehelper.DecodeCursor(cursor, &got); !errors.Is(err, responsehelper.ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", cursor, err)
+			}
+		})
+	}
+}
+
+func cursorEngine() *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.GET("/events", func(c *gin.Context) {
+		var after cursorKey
+		if !responsehelper.BindCursor(c, h, "cursor", &after) {
+			return
+		}
+		h.SuccessWithCursor(c, gin.H{"after": after.ID}, responsehelper.CursorPagination{PageSize: 20})
+	})
+	return engine
+}
+
+func TestBindCursor(t *testing.T) {
+	w := serve(cursorEngine(), httptest.NewRequest(http.MethodGet, "/events?cursor="+responsehelper.EncodeCursor(cursorKey{ID: 42}), nil))
+
+	assertField(t, w, "data.after", float64(42))
+}
+
+func TestBindCursorWithoutCursor(t *testing.T) {
+	w := serve(cursorEngine(), httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assertField(t, w, "data.after", float64(0))
+}
+
+func TestBindCursorMalformed(t *testing.T) {
+	w := serve(cursorEngine(), httptest.NewRequest(http.MethodGet, "/events?cursor=not-a-cursor!", nil))
+
+	assertError(t, w, http.StatusBadRequest, "Invalid cursor")
+	assertField(t, w, "error.errors.0.field", "cursor")
+	assertField(t, w, "error.errors.0.tag", "cursor")
+}
@@ -0,0 +1,181 @@
+package responsehelper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DBErrorMessage replaces the text of the database driver errors scrubbed
+// from the responses, see WithDBErrorScrubbing.
+const DBErrorMessage = "A database error occurred"
+
+// The kinds of database errors, rendered as the "kind" of a scrubbed error.
+const (
+	DBErrorUniqueViolation      = "unique_violation"
+	DBErrorForeignKeyViolation  = "foreign_key_violation"
+	DBErrorNotNullViolation     = "not_null_violation"
+	DBErrorCheckViolation       = "check_violation"
+	DBErrorDeadlock             = "deadlock"
+	DBErrorSerializationFailure = "serialization_failure"
+	DBErrorUndefinedTable       = "undefined_table"
+	DBErrorUndefinedColumn      = "undefined_column"
+	DBErrorSyntax               = "syntax_error"
+	DBErrorConnection           = "connection_failure"
+	DBErrorQueryCanceled        = "query_canceled"
+	DBErrorNoRows               = "no_rows"
+	// DBErrorOther is the kind of the driver errors of no other kind.
+	DBErrorOther = "database_error"
+)
+
+var (
+	// sqlstatePattern matches the SQLSTATE of an error, eg: "(SQLSTATE 23505)"
+	// of pgx.
+	sqlstatePattern = regexp.MustCompile(`SQLSTATE[ =\[]?([0-9A-Z]{5})`)
+	// mysqlPattern matches the error number of the MySQL driver, eg: "Error 1062:".
+	mysqlPattern = regexp.MustCompile(`\bError (\d{4})(?: \([0-9A-Z]{5}\))?:`)
+)
+
+// sqlstateKinds are the kinds of the SQLSTATE codes, by code or class.
+var sqlstateKinds = map[string]string{
+	"23505": DBErrorUniqueViolation,
+	"23503": DBErrorForeignKeyViolation,
+	"23502": DBErrorNotNullViolation,
+	"23514": DBErrorCheckViolation,
+	"40P01": DBErrorDeadlock,
+	"40001": DBErrorSerializationFailure,
+	"42P01": DBErrorUndefinedTable,
+	"42703": DBErrorUndefinedColumn,
+	"42601": DBErrorSyntax,
+	"57014": DBErrorQueryCanceled,
+	"08":    DBErrorConnection,
+}
+
+// mysqlKinds are the kinds of the MySQL error numbers.
+var mysqlKinds = map[string]string{
+	"1062": DBErrorUniqueViolation,
+	"1451": DBErrorForeignKeyViolation,
+	"1452": DBErrorForeignKeyViolation,
+	"1048": DBErrorNotNullViolation,
+	"3819": DBErrorCheckViolation,
+	"1213": DBErrorDeadlock,
+	"1205": DBErrorSerializationFailure,
+	"1146": DBErrorUndefinedTable,
+	"1054": DBErrorUndefinedColumn,
+	"1064": DBErrorSyntax,
+	"2002": DBErrorConnection,
+	"2003": DBErrorConnection,
+	"2006": DBErrorConnection,
+	"2013": DBErrorConnection,
+}
+
+// dbErrorTexts are the kinds of the driver errors recognised by their text,
+// checked in order, eg: the errors of lib/pq and SQLite.
+var dbErrorTexts = []struct {
+	text string
+	kind string
+}{
+	{"violates unique constraint", DBErrorUniqueViolation},
+	{"UNIQUE constraint failed", DBErrorUniqueViolation},
+	{"violates foreign key constraint", DBErrorForeignKeyViolation},
+	{"FOREIGN KEY constraint failed", DBErrorForeignKeyViolation},
+	{"violates not-null constraint", DBErrorNotNullViolation},
+	{"NOT NULL constraint failed", DBErrorNotNullViolation},
+	{"violates check constraint", DBErrorCheckViolation},
+	{"CHECK constraint failed", DBErrorCheckViolation},
+	{"deadlock detected", DBErrorDeadlock},
+	{"could not serialize access", DBErrorSerializationFailure},
+	{"database is locked", DBErrorSerializationFailure},
+	{"no such table", DBErrorUndefinedTable},
+	{"no such column", DBErrorUndefinedColumn},
+	{"syntax error at or near", DBErrorSyntax},
+	{"canceling statement due to", DBErrorQueryCanceled},
+	{"sql: no rows in result set", DBErrorNoRows},
+}
+
+// dbErrorPrefixes start the errors of the drivers, eg: "pq: " for lib/pq.
+var dbErrorPrefixes = []string{"pq: ", "sqlite3: ", "sql: ", "pgx: ", "mysql: ", "SQL logic error"}
+
+// WithDBErrorScrubbing replaces the text of database driver errors in the
+// responses, eg: `pq: duplicate key value violates unique constraint
+// "users_email_key"`, which reveals the schema, with DBErrorMessage, and
+// renders their classification as the "kind" of the error, eg:
+// "unique_violation". The errors of lib/pq, pgx, the MySQL driver, SQLite
+// and database/sql are recognised by their prefixes, SQLSTATE codes and
+// texts. It is enabled by WithErrorSanitization as well. The original error
+// is still logged and reported.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithDBErrorScrubbing(true))
+func WithDBErrorScrubbing(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.dbErrorScrubbing = enabled
+	}
+}
+
+// scrubDBErrors replaces the driver error texts of errorBody with
+// DBErrorMessage and sets its kind, from them or from err, the error passed
+// to the helper.
+func (cfg *config) scrubDBErrors(errorBody *ErrorBody, err error) {
+	if !cfg.dbErrorScrubbing && !cfg.sanitizeErrors {
+		return
+	}
+	kind := ""
+	if err != nil {
+		kind, _ = dbErrorKind(err.Error())
+	}
+	scrub := func(text string) string {
+		if textKind, ok := dbErrorKind(text); ok {
+			if kind == "" {
+				kind = textKind
+			}
+			return DBErrorMessage
+		}
+		return text
+	}
+	errorBody.Message = scrub(errorBody.Message)
+	if details, ok := errorBody.Details.(string); ok {
+		errorBody.Details = scrub(details)
+	}
+	if len(errorBody.Causes) > 0 {
+		causes := make([]string, len(errorBody.Causes))
+		for i, cause := range errorBody.Causes {
+			causes[i] = scrub(cause)
+		}
+		errorBody.Causes = causes
+	}
+	if kind != "" {
+		errorBody.Kind = kind
+	}
+}
+
+// dbErrorKind returns the kind of the database driver error of text, and
+// false when text is not one.
+func dbErrorKind(text string) (string, bool) {
+	if match := mysqlPattern.FindStringSubmatch(text); match != nil {
+		if kind, ok := mysqlKinds[match[1]]; ok {
+			return kind, true
+		}
+		return DBErrorOther, true
+	}
+	if match := sqlstatePattern.FindStringSubmatch(text); match != nil {
+		if kind, ok := sqlstateKinds[match[1]]; ok {
+			return kind, true
+		}
+		if kind, ok := sqlstateKinds[match[1][:2]]; ok {
+			return kind, true
+		}
+		return DBErrorOther, true
+	}
+	for _, known := range dbErrorTexts {
+		if strings.Contains(text, known.text) {
+			return known.kind, true
+		}
+	}
+	for _, prefix := range dbErrorPrefixes {
+		if strings.HasPrefix(text, prefix) || strings.Contains(text, ": "+prefix) {
+			return DBErrorOther, true
+		}
+	}
+	return "", false
+}
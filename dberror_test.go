@@ -0,0 +1,118 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestDBErrorScrubbing(t *testing.T) {
+	for _, tt := range []struct {
+		driver string
+		err    string
+		kind   string
+	}{
+		{"pq", `pq: duplicate key value violates unique constraint "users_email_key"`, responsehelper.DBErrorUniqueViolation},
+		{"pq", `pq: insert or update on table "orders" violates foreign key constraint "orders_user_id_fkey"`, responsehelper.DBErrorForeignKeyViolation},
+		{"pq", `pq: null value in column "email" of relation "users" violates not-null constraint`, responsehelper.DBErrorNotNullViolation},
+		{"pq", `pq: relation "userz" does not exist`, responsehelper.DBErrorOther},
+		{"pq", `pq: canceling statement due to statement timeout`, responsehelper.DBErrorQueryCanceled},
+		{"pgx", `ERROR: duplicate key value violates unique constraint "users_email_key" (SQLSTATE 23505)`, responsehelper.DBErrorUniqueViolation},
+		{"pgx", `ERROR: deadlock detected (SQLSTATE 40P01)`, responsehelper.DBErrorDeadlock},
+		{"pgx", `failed to connect to host=db user=app database=app: dial error (SQLSTATE 08006)`, responsehelper.DBErrorConnection},
+		{"pgx", `ERROR: value too long for type character varying(20) (SQLSTATE 22001)`, responsehelper.DBErrorOther},
+		{"mysql", `Error 1062 (23000): Duplicate entry 'arun@example.com' for key 'users.email'`, responsehelper.DBErrorUniqueViolation},
+		{"mysql", `Error 1452: Cannot add or update a child row: a foreign key constraint fails`, responsehelper.DBErrorForeignKeyViolation},
+		{"mysql", `Error 1146 (42S02): Table 'app.userz' doesn't exist`, responsehelper.DBErrorUndefinedTable},
+		{"mysql", `Error 1406 (22001): Data too long for column 'name' at row 1`, responsehelper.DBErrorOther},
+		{"sqlite", `UNIQUE constraint failed: users.email`, responsehelper.DBErrorUniqueViolation},
+		{"sqlite", `FOREIGN KEY constraint failed`, responsehelper.DBErrorForeignKeyViolation},
+		{"sqlite", `no such table: userz`, responsehelper.DBErrorUndefinedTable},
+		{"sqlite", `database is locked`, responsehelper.DBErrorSerializationFailure},
+		{"database/sql", `sql: no rows in result set`, responsehelper.DBErrorNoRows},
+	} {
+		t.Run(tt.driver+"/"+tt.kind, func(t *testing.T) {
+			var reports []report
+			raw := fmt.Errorf("saving user: %w", errors.New(tt.err))
+			c, w := newContext(http.MethodPost, "/users")
+			reportingHelper(&reports, responsehelper.WithDBErrorScrubbing(true)).InternalError(c, "Could not save the user", raw)
+
+			assertError(t, w, http.StatusInternalServerError, "Could not save the user")
+			errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+			if errorBody["details"] != responsehelper.DBErrorMessage || errorBody["kind"] != tt.kind {
+				t.Errorf("details = %v, kind = %v, want %q and %s", errorBody["details"], errorBody["kind"], responsehelper.DBErrorMessage, tt.kind)
+			}
+			if len(reports) != 1 || reports[0].err != raw {
+				t.Errorf("reports = %v, want the original error", reports)
+			}
+		})
+	}
+}
+
+func TestDBErrorScrubbingLeavesOtherErrors(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"other error": {responsehelper.WithDBErrorScrubbing(true)},
+		"disabled":    nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := errors.New("email is taken")
+			if name == "disabled" {
+				err = errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`)
+			}
+			c, w := newContext(http.MethodPost, "/users")
+			responsehelper.NewResponseHelper(opts...).Conflict(c, "Email already registered", err)
+
+			errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+			if errorBody["details"] != err.Error() || errorBody["kind"] != nil {
+				t.Errorf("error = %v, want the details of %q kept and no kind", errorBody, err)
+			}
+		})
+	}
+}
+
+func TestDBErrorScrubbingMessageAndCauses(t *testing.T) {
+	raw := fmt.Errorf("saving user: %w", errors.New("UNIQUE constraint failed: users.email"))
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithDBErrorScrubbing(true), responsehelper.WithDebug(true)).InternalError(c, raw.Error(), raw)
+
+	if strings.Contains(w.Body.String(), "users.email") {
+		t.Errorf("the driver error reached the body: %s", w.Body)
+	}
+	assertError(t, w, http.StatusInternalServerError, responsehelper.DBErrorMessage)
+	assertField(t, w, "error.causes", []string{responsehelper.DBErrorMessage})
+	assertField(t, w, "error.kind", responsehelper.DBErrorUniqueViolation)
+}
+
+func TestDBErrorScrubbingWithSanitization(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)).
+		Conflict(c, "Email already registered", errors.New(`pq: duplicate key value violates unique constraint "users_email_key"`))
+
+	if strings.Contains(w.Body.String(), "users_email_key") {
+		t.Errorf("the driver error reached the body: %s", w.Body)
+	}
+	assertField(t, w, "error.kind", responsehelper.DBErrorUniqueViolation)
+}
+
+func TestDBErrorKindInOtherFormats(t *testing.T) {
+	err := errors.New("Error 1062 (23000): Duplicate entry 'arun@example.com' for key 'users.email'")
+	for name, tt := range map[string]struct {
+		opt  responsehelper.Option
+		want string
+	}{
+		"problem details": {responsehelper.WithProblemDetails(true), `"kind":"unique_violation"`},
+		"JSON:API":        {responsehelper.WithDefaultFormat(responsehelper.FormatJSONAPI), `"kind":"unique_violation"`},
+		"XML":             {responsehelper.WithDefaultFormat(responsehelper.FormatXML), `<kind>unique_violation</kind>`},
+	} {
+		c, w := newContext(http.MethodPost, "/users")
+		responsehelper.NewResponseHelper(responsehelper.WithDBErrorScrubbing(true), tt.opt).Conflict(c, "Email already registered", err)
+
+		if !strings.Contains(w.Body.String(), tt.want) || strings.Contains(w.Body.String(), "Duplicate entry") {
+			t.Errorf("%s: body = %s, want %s without the driver error", name, w.Body, tt.want)
+		}
+	}
+}
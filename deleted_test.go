@@ -0,0 +1,86 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// deleteEngine returns an engine answering DELETE /users/42 with Deleted and
+// DELETE /sessions/1 with DeletedNoContent.
+func deleteEngine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(append([]responsehelper.Option{responsehelper.WithAPIVersion("1.4.2", "")}, opts...)...)
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware())
+	engine.DELETE("/users/42", func(c *gin.Context) { h.Deleted(c, "User") })
+	engine.DELETE("/sessions/1", func(c *gin.Context) { h.DeletedNoContent(c) })
+	return engine
+}
+
+// assertNoContent checks that w is a 204 without a body, its meta in the headers.
+func assertNoContent(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("a 204 has the body %q", w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != "" {
+		t.Errorf("Content-Type = %q on a 204", got)
+	}
+	if w.Header().Get(responsehelper.RequestIDHeader) == "" {
+		t.Errorf("no %s on a 204", responsehelper.RequestIDHeader)
+	}
+	if got := w.Header().Get("X-API-Version"); got != "1.4.2" {
+		t.Errorf("X-API-Version = %q on a 204, want 1.4.2", got)
+	}
+}
+
+func TestDeleted(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"default":        nil,
+		"200":            {responsehelper.WithDeleteStatus(http.StatusOK)},
+		"ignored status": {responsehelper.WithDeleteStatus(http.StatusAccepted)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(deleteEngine(opts...), httptest.NewRequest(http.MethodDelete, "/users/42", nil))
+
+			assertSuccess(t, w)
+			assertField(t, w, "message", "User deleted successfully")
+			if w.Header().Get("X-API-Version") != "1.4.2" {
+				t.Errorf("X-API-Version = %q", w.Header().Get("X-API-Version"))
+			}
+		})
+	}
+}
+
+func TestDeletedWithNoContentStatus(t *testing.T) {
+	w := serve(deleteEngine(responsehelper.WithDeleteStatus(http.StatusNoContent)), httptest.NewRequest(http.MethodDelete, "/users/42", nil))
+	assertNoContent(t, w)
+}
+
+func TestDeletedNoContent(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"default": nil,
+		"200":     {responsehelper.WithDeleteStatus(http.StatusOK)},
+		"204":     {responsehelper.WithDeleteStatus(http.StatusNoContent)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assertNoContent(t, serve(deleteEngine(opts...), httptest.NewRequest(http.MethodDelete, "/sessions/1", nil)))
+		})
+	}
+}
+
+func TestNoContentHasNoBody(t *testing.T) {
+	c, w := newContext(http.MethodPut, "/settings")
+	c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1"})
+	responsehelper.NewResponseHelper().NoContent(c)
+
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+		t.Errorf("status = %d, Content-Type = %q, body %q", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+}
@@ -0,0 +1,77 @@
+package responsehelper
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DeprecationHeader carries when the endpoint was deprecated (RFC 9745).
+	DeprecationHeader = "Deprecation"
+	// SunsetHeader carries when the endpoint stops responding (RFC 8594).
+	SunsetHeader = "Sunset"
+	// LinkHeader carries the successor of a deprecated endpoint.
+	LinkHeader = "Link"
+)
+
+// DeprecationInfo describes the deprecation of the routes wrapped by
+// DeprecationMiddleware. Zero fields are left out.
+type DeprecationInfo struct {
+	// DeprecatedAt is when the routes were deprecated.
+	DeprecatedAt time.Time
+	// SunsetAt is when the routes stop responding.
+	SunsetAt time.Time
+	// SuccessorURL links to the routes replacing them.
+	SuccessorURL string
+	// Message tells clients what to do, eg: "Use /v2/users instead".
+	Message string
+}
+
+// DeprecationMiddleware marks every response of the wrapped routes as
+// deprecated with the Deprecation, Sunset and Link; rel="successor-version"
+// headers, and adds "meta.deprecation" to the envelopes sent by h.
+//
+// Example:
+//
+//	v1 := router.Group("/v1", responsehelper.DeprecationMiddleware(responseHelper, responsehelper.DeprecationInfo{
+//		DeprecatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+//		SunsetAt:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+//		SuccessorURL: "https://api.example.com/v2",
+//	}))
+func DeprecationMiddleware(h ResponseHelper, info DeprecationInfo) gin.HandlerFunc {
+	meta := deprecationMeta(info)
+	return func(c *gin.Context) {
+		if !info.DeprecatedAt.IsZero() {
+			c.Header(DeprecationHeader, "@"+strconv.FormatInt(info.DeprecatedAt.Unix(), 10))
+		}
+		if !info.SunsetAt.IsZero() {
+			c.Header(SunsetHeader, info.SunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if info.SuccessorURL != "" {
+			c.Writer.Header().Add(LinkHeader, "<"+info.SuccessorURL+`>; rel="successor-version"`)
+		}
+		SetMetaField(c, "deprecation", meta)
+		c.Next()
+	}
+}
+
+// deprecationMeta returns the "meta.deprecation" object of info.
+func deprecationMeta(info DeprecationInfo) gin.H {
+	meta := gin.H{"deprecated": true}
+	if !info.DeprecatedAt.IsZero() {
+		meta["deprecatedAt"] = info.DeprecatedAt.UTC()
+	}
+	if !info.SunsetAt.IsZero() {
+		meta["sunsetAt"] = info.SunsetAt.UTC()
+	}
+	if info.SuccessorURL != "" {
+		meta["successorUrl"] = info.SuccessorURL
+	}
+	if info.Message != "" {
+		meta["message"] = info.Message
+	}
+	return meta
+}
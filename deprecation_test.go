@@ -0,0 +1,61 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func deprecatedEngine(info responsehelper.DeprecationInfo) *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware())
+	v1 := engine.Group("/v1", responsehelper.DeprecationMiddleware(h, info))
+	v1.GET("/ok", func(c *gin.Context) { h.Success(c, nil) })
+	v1.GET("/fail", func(c *gin.Context) { h.NotFound(c, "missing") })
+	return engine
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	engine := deprecatedEngine(responsehelper.DeprecationInfo{
+		DeprecatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		SunsetAt:     time.Date(2025, 7, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*3600)),
+		SuccessorURL: "https://api.example.com/v2",
+		Message:      "Use /v2 instead",
+	})
+	for _, path := range []string{"/v1/ok", "/v1/fail"} {
+		w := serve(engine, httptest.NewRequest(http.MethodGet, path, nil))
+
+		for header, want := range map[string]string{
+			responsehelper.DeprecationHeader: "@1735689600",
+			responsehelper.SunsetHeader:      "Tue, 01 Jul 2025 10:30:00 GMT",
+			responsehelper.LinkHeader:        `<https://api.example.com/v2>; rel="successor-version"`,
+		} {
+			if got := w.Header().Get(header); got != want {
+				t.Errorf("%s: %s = %q, want %q", path, header, got, want)
+			}
+		}
+		assertField(t, w, "meta.deprecation", map[string]interface{}{
+			"deprecated":   true,
+			"deprecatedAt": "2025-01-01T00:00:00Z",
+			"sunsetAt":     "2025-07-01T10:30:00Z",
+			"successorUrl": "https://api.example.com/v2",
+			"message":      "Use /v2 instead",
+		})
+	}
+}
+
+func TestDeprecationMiddlewareOmitsZeroFields(t *testing.T) {
+	w := serve(deprecatedEngine(responsehelper.DeprecationInfo{}), httptest.NewRequest(http.MethodGet, "/v1/ok", nil))
+
+	for _, header := range []string{responsehelper.DeprecationHeader, responsehelper.SunsetHeader, responsehelper.LinkHeader} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("%s = %q, want no header", header, got)
+		}
+	}
+	assertField(t, w, "meta.deprecation", map[string]interface{}{"deprecated": true})
+}
@@ -0,0 +1,65 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RespondedKey is the gin context key set once a helper wrote the response.
+const RespondedKey = "responsehelper.responded"
+
+// ErrResponseWritten is recorded with c.Error when a helper method is called
+// after the response was written, eg: Success after NotFound.
+var ErrResponseWritten = errors.New("responsehelper: response already written")
+
+// WithStrictDoubleWrite panics when a helper method is called after the
+// response was written, so tests fail on the handler falling through to a
+// second response. It has no effect outside gin's debug mode, where the call
+// is only skipped, recorded with c.Error and logged as a warning.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithStrictDoubleWrite(true))
+func WithStrictDoubleWrite(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.strictDoubleWrite = enabled
+	}
+}
+
+// Responded reports whether the response of c was written, by a helper or
+// by the handler with a body. The header alone written by c.AbortWithStatus
+// does not count, so the error can still be sent with the envelope.
+func Responded(c *gin.Context) bool {
+	return responded(exchangeOf(c))
+}
+
+// responded reports whether the response of c was written, see Responded.
+func responded(c Exchange) bool {
+	return c.Size() > 0 || getBool(c, RespondedKey)
+}
+
+// secondResponse reports whether the response was already written, in which
+// case method must not write another one. The call is recorded with c.Error
+// and logged, unless Timeout answered the request before the handler did.
+func (cfg *config) secondResponse(c Exchange, method string) bool {
+	if !responded(c) {
+		return false
+	}
+	if timedOut(c) {
+		return true
+	}
+	if method == "" {
+		method = "a helper method"
+	}
+	err := fmt.Errorf("%w: %s called after status %d was sent", ErrResponseWritten, method, c.Status())
+	if gc := ginContextOf(c); gc != nil {
+		_ = gc.Error(err)
+	}
+	cfg.warnf("%s called after the response was written, skipped: status=%d path=%s", method, c.Status(), requestPath(c))
+	if cfg.strictDoubleWrite && gin.Mode() == gin.DebugMode {
+		panic(err)
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecondResponseIsSkipped(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		status  int
+		body    string
+		method  string
+	}{
+		{"error then success", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.NotFound(c, "User not found")
+			h.Success(c, gin.H{"id": 1})
+		}, http.StatusNotFound, "User not found", "Success"},
+		{"success then error", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, gin.H{"id": 1})
+			h.InternalError(c, "Oops", errors.New("db down"))
+		}, http.StatusOK, `"id":1`, "InternalError"},
+		{"double success", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Created(c, gin.H{"id": 1})
+			h.Success(c, gin.H{"id": 2})
+		}, http.StatusCreated, `"id":1`, "Success"},
+		{"no content then success", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.NoContent(c)
+			h.Success(c, gin.H{"id": 2})
+		}, http.StatusNoContent, "", "Success"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := &captureHandler{level: slog.LevelWarn}
+			h := responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(logs)))
+			c, w := newContext(http.MethodGet, "/users/1")
+			tt.respond(h, c)
+
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("status = %d, body: %s, want the first response %d", w.Code, w.Body, tt.status)
+			}
+			if tt.body != "" && strings.Count(w.Body.String(), `"success"`) != 1 {
+				t.Errorf("two envelopes were written: %s", w.Body)
+			}
+			if len(c.Errors) != 1 || !errors.Is(c.Errors[0].Err, responsehelper.ErrResponseWritten) {
+				t.Fatalf("c.Errors = %v, want ErrResponseWritten", c.Errors)
+			}
+			if !strings.Contains(c.Errors[0].Error(), tt.method) {
+				t.Errorf("error = %q, want the method %s", c.Errors[0].Error(), tt.method)
+			}
+			var warnings []string
+			for _, record := range logs.records {
+				if strings.Contains(record.Message, "called after the response was written") {
+					warnings = append(warnings, record.Message)
+				}
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.method+" called after") {
+				t.Errorf("warnings = %q", warnings)
+			}
+			if !responsehelper.Responded(c) {
+				t.Error("Responded = false")
+			}
+		})
+	}
+}
+
+func TestRespondedIgnoresTheHeaderAlone(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/admin")
+	c.AbortWithStatus(http.StatusForbidden)
+	if responsehelper.Responded(c) {
+		t.Fatal("Responded = true after c.AbortWithStatus")
+	}
+
+	responsehelper.NewResponseHelper().Forbidden(c, "Admins only")
+	if !strings.Contains(w.Body.String(), "Admins only") || len(c.Errors) != 0 {
+		t.Errorf("body: %s, c.Errors = %v", w.Body, c.Errors)
+	}
+}
+
+func TestStrictDoubleWrite(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithStrictDoubleWrite(true), responsehelper.WithLogger(slog.New(&captureHandler{})))
+	secondCall := func(c *gin.Context) (recovered interface{}) {
+		defer func() { recovered = recover() }()
+		h.NotFound(c, "User not found")
+		h.Success(c, gin.H{"id": 1})
+		return nil
+	}
+
+	// created before switching to debug mode, which gin.New warns about
+	testContext, _ := newContext(http.MethodGet, "/users/1")
+	debugContext, _ := newContext(http.MethodGet, "/users/1")
+
+	if recovered := secondCall(testContext); recovered != nil {
+		t.Errorf("panicked outside debug mode: %v", recovered)
+	}
+
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(gin.TestMode)
+	recovered := secondCall(debugContext)
+	if err, ok := recovered.(error); !ok || !errors.Is(err, responsehelper.ErrResponseWritten) {
+		t.Errorf("recovered %v, want ErrResponseWritten", recovered)
+	}
+}
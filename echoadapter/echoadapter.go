@@ -0,0 +1,404 @@
+// Package echoadapter sends the responsehelper envelopes from Echo handlers.
+package echoadapter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/labstack/echo/v4"
+)
+
+// Helper has the method set of responsehelper.ResponseHelper for Echo. It
+// renders with a responsehelper.Core, so the configuration and the bodies are
+// the same as for Gin, and the values set with c.Set, eg: the meta under
+// responsehelper.MetaKey, are found like the ones of a gin context. The
+// methods return nil so handlers can end with them:
+//
+//	return h.NotFound(c, "User not found")
+type Helper struct {
+	core *responsehelper.Core
+}
+
+// New creates a Helper configured with opts, the options of
+// responsehelper.NewResponseHelper.
+func New(opts ...responsehelper.Option) *Helper {
+	return &Helper{core: responsehelper.NewCore(opts...)}
+}
+
+// Wrap creates a Helper rendering with the Core of helper, so Gin and Echo
+// handlers share one configuration, its registered codes and hooks. It panics
+// when helper was not created by responsehelper.NewResponseHelper or
+// responsehelper.New.
+func Wrap(helper responsehelper.ResponseHelper) *Helper {
+	core := responsehelper.CoreOf(helper)
+	if core == nil {
+		panic("echoadapter: Wrap needs a helper created by responsehelper.NewResponseHelper")
+	}
+	return &Helper{core: core}
+}
+
+// HTTPErrorHandler renders the errors returned by handlers, so returning an
+// error ends in the same envelope as calling the helper. An *echo.HTTPError
+// keeps its status and message, other errors are sent like Respond does.
+//
+// Example:
+//
+//	e.HTTPErrorHandler = h.HTTPErrorHandler
+func (h *Helper) HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message, ok := httpErr.Message.(string)
+		if !ok || message == "" {
+			message = http.StatusText(httpErr.Code)
+		}
+		_ = h.RespondAPIError(c, responsehelper.NewAPIError(httpErr.Code, message, httpErr.Internal))
+		return
+	}
+	_ = h.Respond(c, err, nil)
+}
+
+// exchange is the responsehelper.Exchange of an Echo context.
+type exchange struct {
+	c echo.Context
+}
+
+func (x exchange) Request() *http.Request {
+	return x.c.Request()
+}
+
+func (x exchange) Get(key string) (interface{}, bool) {
+	value := x.c.Get(key)
+	return value, value != nil
+}
+
+func (x exchange) Set(key string, value interface{}) {
+	x.c.Set(key, value)
+}
+
+func (x exchange) Header() http.Header {
+	return x.c.Response().Header()
+}
+
+func (x exchange) WriteHeader(status int) {
+	// Echo logs a warning for a second status
+	if x.c.Response().Committed {
+		return
+	}
+	x.c.Response().WriteHeader(status)
+}
+
+func (x exchange) Write(data []byte) (int, error) {
+	return x.c.Response().Write(data)
+}
+
+func (x exchange) Flush() {
+	if flusher, ok := x.c.Response().Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (x exchange) Status() int {
+	if x.c.Response().Status == 0 {
+		return http.StatusOK
+	}
+	return x.c.Response().Status
+}
+
+func (x exchange) Size() int {
+	if !x.c.Response().Committed {
+		return -1
+	}
+	return int(x.c.Response().Size)
+}
+
+func (x exchange) Written() bool {
+	return x.c.Response().Committed
+}
+
+// BadRequest sends a 400 Bad Request response with string details.
+//
+// Deprecated: use BadRequestDetails.
+func (h *Helper) BadRequest(c echo.Context, message string, details string, opts ...responsehelper.ResponseOption) error {
+	h.core.BadRequest(exchange{c}, message, details, opts...)
+	return nil
+}
+
+// BadRequestDetails sends a 400 Bad Request response with structured details.
+func (h *Helper) BadRequestDetails(c echo.Context, message string, details interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.BadRequestDetails(exchange{c}, message, details, opts...)
+	return nil
+}
+
+// AlreadyExists sends a 409 Conflict response for a resource that already exists.
+func (h *Helper) AlreadyExists(c echo.Context, resource string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.AlreadyExists(exchange{c}, resource, err, opts...)
+	return nil
+}
+
+// Conflict sends a 409 Conflict response.
+func (h *Helper) Conflict(c echo.Context, message string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.Conflict(exchange{c}, message, err, opts...)
+	return nil
+}
+
+// NotFound sends a 404 Not Found response.
+func (h *Helper) NotFound(c echo.Context, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.NotFound(exchange{c}, message, opts...)
+	return nil
+}
+
+// ResourceNotFound sends a 404 Not Found response with the resource and its identifier.
+func (h *Helper) ResourceNotFound(c echo.Context, resource string, id interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.ResourceNotFound(exchange{c}, resource, id, opts...)
+	return nil
+}
+
+// Unauthorized sends a 401 Unauthorized response.
+func (h *Helper) Unauthorized(c echo.Context, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.Unauthorized(exchange{c}, message, opts...)
+	return nil
+}
+
+// UnauthorizedWithChallenge sends a 401 Unauthorized response with a WWW-Authenticate challenge.
+func (h *Helper) UnauthorizedWithChallenge(c echo.Context, message, scheme, realm string, params map[string]string, opts ...responsehelper.ResponseOption) error {
+	h.core.UnauthorizedWithChallenge(exchange{c}, message, scheme, realm, params, opts...)
+	return nil
+}
+
+// UnauthorizedReason sends a 401 Unauthorized response telling why the credentials were rejected.
+func (h *Helper) UnauthorizedReason(c echo.Context, reason responsehelper.AuthFailureReason, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.UnauthorizedReason(exchange{c}, reason, message, opts...)
+	return nil
+}
+
+// Forbidden sends a 403 Forbidden response.
+func (h *Helper) Forbidden(c echo.Context, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.Forbidden(exchange{c}, message, opts...)
+	return nil
+}
+
+// ForbiddenScope sends a 403 Forbidden response with the required and granted permissions.
+func (h *Helper) ForbiddenScope(c echo.Context, message string, required []string, granted []string, opts ...responsehelper.ResponseOption) error {
+	h.core.ForbiddenScope(exchange{c}, message, required, granted, opts...)
+	return nil
+}
+
+// TooManyRequests sends a 429 Too Many Requests response.
+func (h *Helper) TooManyRequests(c echo.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) error {
+	h.core.TooManyRequests(exchange{c}, message, retryAfter, opts...)
+	return nil
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable response.
+func (h *Helper) ServiceUnavailable(c echo.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) error {
+	h.core.ServiceUnavailable(exchange{c}, message, retryAfter, opts...)
+	return nil
+}
+
+// InternalError sends a 500 Internal Server Error response.
+func (h *Helper) InternalError(c echo.Context, message string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.InternalError(exchange{c}, message, err, opts...)
+	return nil
+}
+
+// Success sends a 200 OK response with data.
+func (h *Helper) Success(c echo.Context, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Success(exchange{c}, data, opts...)
+	return nil
+}
+
+// PartialSuccess sends a 200 OK response with the data and the parts that could not be loaded.
+func (h *Helper) PartialSuccess(c echo.Context, data interface{}, failures []responsehelper.PartialFailure, opts ...responsehelper.ResponseOption) error {
+	h.core.PartialSuccess(exchange{c}, data, failures, opts...)
+	return nil
+}
+
+// SuccessCSV sends a 200 OK response with rows as a CSV attachment.
+func (h *Helper) SuccessCSV(c echo.Context, filename string, rows interface{}) error {
+	h.core.SuccessCSV(exchange{c}, filename, rows)
+	return nil
+}
+
+// SuccessIfModified sends a 200 OK response with the data of dataFn, or a
+// 304 Not Modified response when If-Modified-Since is not before lastModified.
+func (h *Helper) SuccessIfModified(c echo.Context, lastModified time.Time, dataFn func() (interface{}, error), opts ...responsehelper.ResponseOption) error {
+	h.core.SuccessIfModified(exchange{c}, lastModified, dataFn, opts...)
+	return nil
+}
+
+// SuccessWithPagination sends a 200 OK response with data and pagination metadata.
+func (h *Helper) SuccessWithPagination(c echo.Context, data interface{}, meta interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.SuccessWithPagination(exchange{c}, data, meta, opts...)
+	return nil
+}
+
+// SuccessWithCursor sends a 200 OK response with data and cursor pagination metadata.
+func (h *Helper) SuccessWithCursor(c echo.Context, data interface{}, cur responsehelper.CursorPagination) error {
+	h.core.SuccessWithCursor(exchange{c}, data, cur)
+	return nil
+}
+
+// SuccessWithCount sends a 200 OK response with data and its number of items.
+func (h *Helper) SuccessWithCount(c echo.Context, data interface{}) error {
+	h.core.SuccessWithCount(exchange{c}, data)
+	return nil
+}
+
+// SuccessLarge sends a 200 OK response with data streamed to the client.
+func (h *Helper) SuccessLarge(c echo.Context, data interface{}) error {
+	h.core.SuccessLarge(exchange{c}, data)
+	return nil
+}
+
+// SuccessWithLinks sends a 200 OK response with data and hypermedia links.
+func (h *Helper) SuccessWithLinks(c echo.Context, data interface{}, links responsehelper.Links) error {
+	h.core.SuccessWithLinks(exchange{c}, data, links)
+	return nil
+}
+
+// Created sends a 201 Created response with data.
+func (h *Helper) Created(c echo.Context, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Created(exchange{c}, data, opts...)
+	return nil
+}
+
+// Patched sends a 200 OK response with the updated resource and the changed fields.
+func (h *Helper) Patched(c echo.Context, resource string, data interface{}, changedFields []string, opts ...responsehelper.ResponseOption) error {
+	h.core.Patched(exchange{c}, resource, data, changedFields, opts...)
+	return nil
+}
+
+// OperationAccepted sends a 202 Accepted response with a long-running operation.
+func (h *Helper) OperationAccepted(c echo.Context, op responsehelper.Operation) error {
+	h.core.OperationAccepted(exchange{c}, op)
+	return nil
+}
+
+// OperationStatus sends the state of a long-running operation.
+func (h *Helper) OperationStatus(c echo.Context, op responsehelper.Operation) error {
+	h.core.OperationStatus(exchange{c}, op)
+	return nil
+}
+
+// Deleted sends the response for a deleted resource, see WithDeleteStatus.
+func (h *Helper) Deleted(c echo.Context, message string) error {
+	h.core.Deleted(exchange{c}, message)
+	return nil
+}
+
+// DeletedNoContent sends a 204 No Content response for a deleted resource.
+func (h *Helper) DeletedNoContent(c echo.Context) error {
+	h.core.DeletedNoContent(exchange{c})
+	return nil
+}
+
+// NoContent sends a 204 No Content response.
+func (h *Helper) NoContent(c echo.Context) error {
+	h.core.NoContent(exchange{c})
+	return nil
+}
+
+// RespondAPIError sends the error response described by err.
+func (h *Helper) RespondAPIError(c echo.Context, err *responsehelper.APIError, opts ...responsehelper.ResponseOption) error {
+	h.core.RespondAPIError(exchange{c}, err, opts...)
+	return nil
+}
+
+// Respond sends data when err is nil and the error response for err otherwise.
+func (h *Helper) Respond(c echo.Context, err error, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Respond(exchange{c}, err, data, opts...)
+	return nil
+}
+
+// Errors sends an error response listing several errors.
+func (h *Helper) Errors(c echo.Context, statusCode int, errs []responsehelper.ErrorItem, opts ...responsehelper.ResponseOption) error {
+	h.core.Errors(exchange{c}, statusCode, errs, opts...)
+	return nil
+}
+
+// Problem sends an RFC 7807 problem details response.
+func (h *Helper) Problem(c echo.Context, status int, typ, title, detail string, extensions map[string]interface{}) error {
+	h.core.Problem(exchange{c}, status, typ, title, detail, extensions)
+	return nil
+}
+
+// WriteJSON sends body as is, without the envelope.
+func (h *Helper) WriteJSON(c echo.Context, status int, body interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.WriteJSON(exchange{c}, status, body, opts...)
+	return nil
+}
+
+// WriteError sends errBody in the standard error envelope.
+func (h *Helper) WriteError(c echo.Context, status int, errBody responsehelper.ErrorBody, opts ...responsehelper.ResponseOption) error {
+	h.core.WriteError(exchange{c}, status, errBody, opts...)
+	return nil
+}
+
+// OAuthError sends an RFC 6749 error response, eg: of a token endpoint.
+func (h *Helper) OAuthError(c echo.Context, errCode string, description string, status int) error {
+	h.core.OAuthError(exchange{c}, errCode, description, status)
+	return nil
+}
+
+// ValidationFailed sends the validation errors of err.
+func (h *Helper) ValidationFailed(c echo.Context, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.ValidationFailed(exchange{c}, err, opts...)
+	return nil
+}
+
+// NotFoundKey sends a 404 Not Found response with a translated message.
+func (h *Helper) NotFoundKey(c echo.Context, key string, args ...interface{}) error {
+	h.core.NotFoundKey(exchange{c}, key, args...)
+	return nil
+}
+
+// BadRequestKey sends a 400 Bad Request response with a translated message.
+func (h *Helper) BadRequestKey(c echo.Context, key string, details string, args ...interface{}) error {
+	h.core.BadRequestKey(exchange{c}, key, details, args...)
+	return nil
+}
+
+// UnauthorizedKey sends a 401 Unauthorized response with a translated message.
+func (h *Helper) UnauthorizedKey(c echo.Context, key string, args ...interface{}) error {
+	h.core.UnauthorizedKey(exchange{c}, key, args...)
+	return nil
+}
+
+// ForbiddenKey sends a 403 Forbidden response with a translated message.
+func (h *Helper) ForbiddenKey(c echo.Context, key string, args ...interface{}) error {
+	h.core.ForbiddenKey(exchange{c}, key, args...)
+	return nil
+}
+
+// ConflictKey sends a 409 Conflict response with a translated message.
+func (h *Helper) ConflictKey(c echo.Context, key string, err error, args ...interface{}) error {
+	h.core.ConflictKey(exchange{c}, key, err, args...)
+	return nil
+}
+
+// InternalErrorKey sends a 500 Internal Server Error response with a translated message.
+func (h *Helper) InternalErrorKey(c echo.Context, key string, err error, args ...interface{}) error {
+	h.core.InternalErrorKey(exchange{c}, key, err, args...)
+	return nil
+}
+
+// RegisterCode registers a business error code for RespondCode.
+func (h *Helper) RegisterCode(code string, status int, defaultMessage string) {
+	h.core.RegisterCode(code, status, defaultMessage)
+}
+
+// PrecomputeError registers an error response whose body is marshalled once.
+func (h *Helper) PrecomputeError(status int, message string) {
+	h.core.PrecomputeError(status, message)
+}
+
+// RespondCode sends the error response registered for code.
+func (h *Helper) RespondCode(c echo.Context, code string, args ...interface{}) error {
+	h.core.RespondCode(exchange{c}, code, args...)
+	return nil
+}
@@ -0,0 +1,209 @@
+package echoadapter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/echoadapter"
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var meta = responsehelper.Meta{RequestID: "req-1", Path: "/users/42"}
+
+// foreignHelper is a ResponseHelper not created by the package.
+type foreignHelper struct {
+	responsehelper.ResponseHelper
+}
+
+// serveEcho sends the response of handle from an Echo server and records it.
+func serveEcho(t *testing.T, h *echoadapter.Helper, handle func(*echoadapter.Helper, echo.Context) error) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.HTTPErrorHandler = h.HTTPErrorHandler
+	e.GET("/users/:id", func(c echo.Context) error {
+		c.Set(responsehelper.MetaKey, meta)
+		return handle(h, c)
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/users/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	w := httptest.NewRecorder()
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// envelope is the part of the envelope the tests check.
+type envelope struct {
+	Success bool `json:"success"`
+	Data    map[string]interface{}
+	Error   struct {
+		Code    int         `json:"code"`
+		Message string      `json:"message"`
+		Details interface{} `json:"details"`
+	} `json:"error"`
+	Meta struct {
+		RequestID string `json:"requestId"`
+		Path      string `json:"path"`
+	} `json:"meta"`
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) envelope {
+	t.Helper()
+	var body envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return body
+}
+
+// TestSameBodyAsGin sends a response from Gin and from an Echo server with
+// the same Core and meta: the bodies must match byte for byte.
+func TestSameBodyAsGin(t *testing.T) {
+	helper := responsehelper.NewResponseHelper()
+	ginRouter := gin.New()
+	ginRouter.GET("/users/:id", func(c *gin.Context) {
+		c.Set(responsehelper.MetaKey, meta)
+		helper.Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+	})
+	want := httptest.NewRecorder()
+	ginRouter.ServeHTTP(want, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	w := serveEcho(t, echoadapter.Wrap(helper), func(h *echoadapter.Helper, c echo.Context) error {
+		return h.Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+	})
+
+	if w.Code != want.Code || w.Header().Get("Location") != want.Header().Get("Location") {
+		t.Errorf("status = %d, Location = %q, Gin sent %d, %q", w.Code, w.Header().Get("Location"), want.Code, want.Header().Get("Location"))
+	}
+	if w.Body.String() != want.Body.String() {
+		t.Errorf("body =\n%s\nGin sent\n%s", w.Body, want.Body)
+	}
+}
+
+func TestFindsTheMetaSetOnTheEchoContext(t *testing.T) {
+	w := serveEcho(t, echoadapter.New(), func(h *echoadapter.Helper, c echo.Context) error {
+		return h.Success(c, map[string]int{"id": 42})
+	})
+
+	if got := decode(t, w).Meta; got.RequestID != "req-1" || got.Path != "/users/42" {
+		t.Errorf("meta = %+v, want the one set with c.Set", got)
+	}
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"HTTPError", echo.NewHTTPError(http.StatusNotFound, "User not found"), http.StatusNotFound, "User not found"},
+		{"HTTPError without a string message", echo.NewHTTPError(http.StatusBadRequest, map[string]string{"field": "name"}), http.StatusBadRequest, "Bad Request"},
+		{"wrapped HTTPError", fmt.Errorf("loading user: %w", echo.ErrForbidden), http.StatusForbidden, "Forbidden"},
+		{"APIError", responsehelper.ErrNotFound.WithMessage("User not found"), http.StatusNotFound, "User not found"},
+		{"other error", errors.New("db down"), http.StatusInternalServerError, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serveEcho(t, echoadapter.New(), func(h *echoadapter.Helper, c echo.Context) error {
+				return tc.err
+			})
+
+			body := decode(t, w)
+			if w.Code != tc.status || body.Error.Code != tc.status || body.Success {
+				t.Errorf("status = %d, error.code = %d, want an error with %d\nbody: %s", w.Code, body.Error.Code, tc.status, w.Body)
+			}
+			if tc.message != "" && body.Error.Message != tc.message {
+				t.Errorf("error.message = %q, want %q", body.Error.Message, tc.message)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorHandlerKeepsTheInternalErrorOutOfTheMessage(t *testing.T) {
+	w := serveEcho(t, echoadapter.New(responsehelper.WithErrorSanitization(true)), func(h *echoadapter.Helper, c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadGateway, "Upstream failed").SetInternal(errors.New("dial tcp 10.0.0.7:5432"))
+	})
+
+	body := decode(t, w)
+	if body.Error.Message != "Upstream failed" || body.Error.Details != nil {
+		t.Errorf("error = %+v, want the message without the internal error", body.Error)
+	}
+}
+
+func TestHTTPErrorHandlerAfterTheResponse(t *testing.T) {
+	w := serveEcho(t, echoadapter.New(), func(h *echoadapter.Helper, c echo.Context) error {
+		if err := h.Success(c, map[string]int{"id": 42}); err != nil {
+			return err
+		}
+		return errors.New("too late")
+	})
+
+	if body := decode(t, w); w.Code != http.StatusOK || !body.Success {
+		t.Errorf("got %d %s, want the response sent before the error", w.Code, w.Body)
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	h := echoadapter.New()
+	e := echo.New()
+	e.HTTPErrorHandler = h.HTTPErrorHandler
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if body := decode(t, w); w.Code != http.StatusNotFound || body.Error.Code != http.StatusNotFound {
+		t.Errorf("got %d %s, want a 404 envelope", w.Code, w.Body)
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	w := serveEcho(t, echoadapter.New(), func(h *echoadapter.Helper, c echo.Context) error {
+		return h.NoContent(c)
+	})
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("got %d %q, want an empty 204", w.Code, w.Body)
+	}
+}
+
+func TestNewUsesTheOptions(t *testing.T) {
+	w := serveEcho(t, echoadapter.New(responsehelper.WithErrorSanitization(true)), func(h *echoadapter.Helper, c echo.Context) error {
+		return h.InternalError(c, "Oops", errors.New("secret"))
+	})
+	body := decode(t, w)
+	if w.Code != http.StatusInternalServerError || body.Error.Message != "Oops" {
+		t.Errorf("got %d %q, want the internal error", w.Code, body.Error.Message)
+	}
+	if body.Error.Details != nil {
+		t.Errorf("error.details = %v with sanitization", body.Error.Details)
+	}
+}
+
+func TestWrapNeedsACore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Wrap accepted a helper without a Core")
+		}
+	}()
+	echoadapter.Wrap(foreignHelper{})
+}
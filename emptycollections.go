@@ -0,0 +1,179 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// maxCollectionDepth bounds the walk of WithDeepEmptyCollections, so cyclic
+// data cannot recurse forever.
+const maxCollectionDepth = 32
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// WithEmptyCollections sends a nil slice passed as data as [] and a nil map as
+// {}, instead of null, so clients can iterate the data of every list
+// response. Only the data itself is changed, see WithDeepEmptyCollections for
+// the collections inside it. Enabled by default by New.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithEmptyCollections(true))
+func WithEmptyCollections(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.emptyCollections = enabled
+		if !enabled {
+			cfg.deepEmptyCollections = false
+		}
+	}
+}
+
+// WithDeepEmptyCollections extends WithEmptyCollections to the nil slices and
+// maps anywhere in the data: in exported struct fields, map values, slice
+// elements and behind pointers. Values with their own MarshalJSON or
+// MarshalText are left as they are, nil pointers stay null.
+//
+// The data is walked with reflection on every response and the parts holding
+// nil collections are copied, the data passed in is never modified. That
+// costs time and allocations in proportion to the size of the data, so
+// prefer returning empty collections from the handlers on hot paths.
+func WithDeepEmptyCollections(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.deepEmptyCollections = enabled
+		if enabled {
+			cfg.emptyCollections = true
+		}
+	}
+}
+
+// fillEmptyCollections returns data with its nil collections replaced
+// according to WithEmptyCollections and WithDeepEmptyCollections.
+func (cfg *config) fillEmptyCollections(data interface{}) interface{} {
+	if !cfg.emptyCollections || data == nil {
+		return data
+	}
+	value := reflect.ValueOf(data)
+	if cfg.deepEmptyCollections {
+		if filled, changed := fillCollections(value, 0); changed {
+			return filled.Interface()
+		}
+		return data
+	}
+	if empty, ok := emptyCollection(value); ok {
+		return empty.Interface()
+	}
+	return data
+}
+
+// emptyCollection returns an empty collection of the type of value when
+// value is a nil slice or map. Byte slices are rendered as strings, so they
+// are left out.
+func emptyCollection(value reflect.Value) (reflect.Value, bool) {
+	switch value.Kind() {
+	case reflect.Slice:
+		if value.IsNil() && value.Type().Elem().Kind() != reflect.Uint8 {
+			return reflect.MakeSlice(value.Type(), 0, 0), true
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return reflect.MakeMap(value.Type()), true
+		}
+	}
+	return value, false
+}
+
+// customEncoding reports whether values of typ choose their own encoding.
+func customEncoding(typ reflect.Type) bool {
+	for _, t := range []reflect.Type{typ, reflect.PointerTo(typ)} {
+		if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+			return true
+		}
+	}
+	return false
+}
+
+// fillCollections returns a copy of value with its nil slices and maps
+// replaced by empty ones, and whether there were any. value is returned
+// itself when there were none.
+func fillCollections(value reflect.Value, depth int) (reflect.Value, bool) {
+	if !value.IsValid() || depth > maxCollectionDepth || customEncoding(value.Type()) {
+		return value, false
+	}
+	if empty, ok := emptyCollection(value); ok {
+		return empty, true
+	}
+	typ := value.Type()
+	switch value.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if value.IsNil() {
+			return value, false
+		}
+		filled, changed := fillCollections(value.Elem(), depth+1)
+		if !changed {
+			return value, false
+		}
+		if value.Kind() == reflect.Pointer {
+			out := reflect.New(typ.Elem())
+			out.Elem().Set(filled)
+			return out, true
+		}
+		out := reflect.New(typ).Elem()
+		out.Set(filled)
+		return out, true
+	case reflect.Slice, reflect.Array:
+		var out reflect.Value
+		for i := 0; i < value.Len(); i++ {
+			filled, changed := fillCollections(value.Index(i), depth+1)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				if value.Kind() == reflect.Slice {
+					out = reflect.MakeSlice(typ, value.Len(), value.Len())
+					reflect.Copy(out, value)
+				} else {
+					out = reflect.New(typ).Elem()
+					out.Set(value)
+				}
+			}
+			out.Index(i).Set(filled)
+		}
+		return out, out.IsValid()
+	case reflect.Map:
+		var out reflect.Value
+		iter := value.MapRange()
+		for iter.Next() {
+			filled, changed := fillCollections(iter.Value(), depth+1)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.MakeMapWithSize(typ, value.Len())
+				copyIter := value.MapRange()
+				for copyIter.Next() {
+					out.SetMapIndex(copyIter.Key(), copyIter.Value())
+				}
+			}
+			out.SetMapIndex(iter.Key(), filled)
+		}
+		return out, out.IsValid()
+	case reflect.Struct:
+		var out reflect.Value
+		for i := 0; i < value.NumField(); i++ {
+			if !typ.Field(i).IsExported() {
+				continue
+			}
+			filled, changed := fillCollections(value.Field(i), depth+1)
+			if !changed {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.New(typ).Elem()
+				out.Set(value)
+			}
+			out.Field(i).Set(filled)
+		}
+		return out, out.IsValid()
+	}
+	return value, false
+}
@@ -0,0 +1,127 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+type emptyItem struct {
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Parent  *emptyItem        `json:"parent"`
+	Created *time.Time        `json:"created"`
+	hidden  []string
+}
+
+// rawData returns the "data" member of the response as written.
+func rawData(t *testing.T, w interface{ Bytes() []byte }) string {
+	t.Helper()
+	var body struct{ Data json.RawMessage }
+	if err := json.Unmarshal(w.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	return string(body.Data)
+}
+
+func TestEmptyCollections(t *testing.T) {
+	var nilSlice []emptyItem
+	var nilMap map[string]int
+	var nilPointer *emptyItem
+	var nilBytes []byte
+	nested := []emptyItem{{Name: "a"}}
+	for _, tc := range []struct {
+		name string
+		data interface{}
+		top  string
+		deep string
+	}{
+		{"nil slice", nilSlice, `[]`, `[]`},
+		{"empty slice", []emptyItem{}, `[]`, `[]`},
+		{"nil map", nilMap, `{}`, `{}`},
+		{"nil pointer", nilPointer, `null`, `null`},
+		{"nil bytes", nilBytes, `null`, `null`},
+		{"nested nils", nested,
+			`[{"name":"a","tags":null,"labels":null,"parent":null,"created":null}]`,
+			`[{"name":"a","tags":[],"labels":{},"parent":null,"created":null}]`},
+		{"behind pointers and maps", map[string]*emptyItem{"a": {Parent: &emptyItem{}}},
+			`{"a":{"name":"","tags":null,"labels":null,"parent":{"name":"","tags":null,"labels":null,"parent":null,"created":null},"created":null}}`,
+			`{"a":{"name":"","tags":[],"labels":{},"parent":{"name":"","tags":[],"labels":{},"parent":null,"created":null},"created":null}}`},
+		{"in interfaces", []interface{}{nilSlice, 1}, `[null,1]`, `[[],1]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, mode := range []struct {
+				name string
+				opts []responsehelper.Option
+				want string
+			}{
+				{"top level", []responsehelper.Option{responsehelper.WithEmptyCollections(true)}, tc.top},
+				{"deep", []responsehelper.Option{responsehelper.WithDeepEmptyCollections(true)}, tc.deep},
+			} {
+				c, w := newContext(http.MethodGet, "/items")
+				responsehelper.NewResponseHelper(mode.opts...).Success(c, tc.data)
+
+				if got := rawData(t, w.Body); got != mode.want {
+					t.Errorf("%s: data = %s, want %s", mode.name, got, mode.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyCollectionsDefaults(t *testing.T) {
+	var items []emptyItem
+	for _, tc := range []struct {
+		name   string
+		helper responsehelper.ResponseHelper
+		want   string
+	}{
+		{"NewResponseHelper", responsehelper.NewResponseHelper(), `null`},
+		{"New", responsehelper.New(), `[]`},
+		{"New disabled", responsehelper.New(responsehelper.WithEmptyCollections(false)), `null`},
+		{"deep disabled", responsehelper.NewResponseHelper(responsehelper.WithDeepEmptyCollections(true), responsehelper.WithEmptyCollections(false)), `null`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/items")
+			tc.helper.Success(c, items)
+
+			if got := rawData(t, w.Body); got != tc.want {
+				t.Errorf("data = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEmptyCollectionsInPagination(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/items")
+	responsehelper.New(responsehelper.WithDeepEmptyCollections(true)).
+		SuccessWithPagination(c, []emptyItem{{Name: "a"}}, responsehelper.NewPagination(1, 20, 1))
+
+	if got, want := rawData(t, w.Body), `[{"name":"a","tags":[],"labels":{},"parent":null,"created":null}]`; got != want {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+}
+
+func TestDeepEmptyCollectionsLeaveTheDataUnchanged(t *testing.T) {
+	data := []emptyItem{{Name: "a", hidden: nil}}
+	c, _ := newContext(http.MethodGet, "/items")
+	responsehelper.NewResponseHelper(responsehelper.WithDeepEmptyCollections(true)).Success(c, data)
+
+	if data[0].Tags != nil || data[0].Labels != nil {
+		t.Errorf("the data passed in was modified: %+v", data[0])
+	}
+}
+
+func TestDeepEmptyCollectionsKeepCustomEncodings(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/items")
+	responsehelper.NewResponseHelper(responsehelper.WithDeepEmptyCollections(true)).
+		Success(c, map[string]interface{}{"raw": json.RawMessage(nil), "ip": []byte(nil)})
+
+	if got, want := rawData(t, w.Body), `{"ip":null,"raw":null}`; got != want {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,207 @@
+package responsehelper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Format is the content type an envelope is rendered as.
+type Format string
+
+const (
+	// FormatJSON renders the envelope as JSON, the default.
+	FormatJSON Format = MIMEJSON
+	// FormatXML renders the envelope as XML.
+	FormatXML Format = MIMEXML
+	// FormatMsgpack renders the envelope as MessagePack. It needs the encoder
+	// registered by the responsehelper/msgpack package.
+	FormatMsgpack Format = "application/msgpack"
+	// FormatYAML renders the envelope as YAML. It needs the encoder
+	// registered by the responsehelper/yaml package.
+	FormatYAML Format = "application/yaml"
+	// FormatProtobuf renders the envelope as Protocol Buffers. It needs the
+	// encoder registered by the responsehelper/protobuf package.
+	FormatProtobuf Format = "application/x-protobuf"
+	// FormatJSONAPI renders the envelope as a JSON:API document, see
+	// JSONAPIDocument and Resource.
+	FormatJSONAPI Format = MIMEJSONAPI
+	// FormatGoogle renders the envelope as JSON like FormatJSON, except
+	// error envelopes, which take the shape of the errors of Google's APIs,
+	// see GoogleErrorDocument. It is not a content type: clients negotiating
+	// JSON get this shape when it is the default format.
+	FormatGoogle Format = "google"
+)
+
+// WarningHeader carries why the envelope was not rendered in the negotiated format.
+const WarningHeader = "Warning"
+
+// ErrUnsupportedValue is returned by an EncoderFunc that cannot represent the
+// envelope. The envelope is then rendered as JSON with a Warning header.
+var ErrUnsupportedValue = errors.New("responsehelper: value cannot be represented in the format")
+
+// EncoderFunc writes v, a SuccessEnvelope, an ErrorEnvelope or the body of
+// WriteJSON, to w. The output is buffered, so nothing is sent when it
+// returns an error. Return an error wrapping ErrUnsupportedValue to fall
+// back to JSON.
+type EncoderFunc func(w io.Writer, v interface{}) error
+
+// encoders are the encoders registered with RegisterEncoder.
+var encoders = struct {
+	sync.RWMutex
+	contentTypes []string
+	funcs        map[string]EncoderFunc
+}{funcs: map[string]EncoderFunc{}}
+
+// RegisterEncoder makes contentType available to content negotiation and
+// WithDefaultFormat, rendered by encoder. Registering a content type again
+// replaces its encoder. JSON and XML are built in and cannot be replaced.
+//
+// Encoders are usually registered by importing a package, eg:
+//
+//	import _ "github.com/aruncs31s/responsehelper/msgpack"
+func RegisterEncoder(contentType string, encoder EncoderFunc) {
+	contentType = strings.ToLower(contentType)
+	encoders.Lock()
+	defer encoders.Unlock()
+	if _, ok := encoders.funcs[contentType]; !ok {
+		encoders.contentTypes = append(encoders.contentTypes, contentType)
+	}
+	encoders.funcs[contentType] = encoder
+}
+
+// registeredEncoder returns the encoder registered for contentType.
+func registeredEncoder(contentType string) (EncoderFunc, bool) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	encoder, ok := encoders.funcs[contentType]
+	return encoder, ok
+}
+
+// registeredContentTypes returns the registered content types in the order
+// they were registered.
+func registeredContentTypes() []string {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	return append([]string(nil), encoders.contentTypes...)
+}
+
+// WithDefaultFormat renders the envelope as format when content negotiation
+// is disabled, or when the client has no preference. Formats other than JSON,
+// XML and JSON:API must be registered with RegisterEncoder, otherwise JSON is
+// used.
+//
+// Example:
+//
+//	import _ "github.com/aruncs31s/responsehelper/msgpack"
+//
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatMsgpack))
+func WithDefaultFormat(format Format) Option {
+	return func(cfg *config) {
+		if format != FormatGoogle && !formatAvailable(string(format)) {
+			cfg.warnf("no encoder registered for format %q, JSON is used instead", format)
+			return
+		}
+		cfg.defaultFormat = format
+	}
+}
+
+// formatAvailable reports whether envelopes can be rendered as contentType.
+func formatAvailable(contentType string) bool {
+	switch contentType {
+	case MIMEJSON, MIMEXML, MIMETextXML, MIMEJSONAPI:
+		return true
+	}
+	_, ok := registeredEncoder(contentType)
+	return ok
+}
+
+// encoderRender renders an envelope with a registered encoder.
+type encoderRender struct {
+	contentType string
+	encoder     EncoderFunc
+	marshalJSON func(interface{}) ([]byte, error)
+	envelope    interface{}
+}
+
+func (e encoderRender) Render(w http.ResponseWriter) error {
+	var body bytes.Buffer
+	if err := e.encoder(&body, envelopeValue(e.envelope)); err != nil {
+		if !errors.Is(err, ErrUnsupportedValue) {
+			return err
+		}
+		w.Header().Set(WarningHeader, `299 - "Rendered as JSON, the response cannot be represented as `+e.contentType+`"`)
+		fallback, err := e.marshalJSON(e.envelope)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", jsonContentType)
+		_, err = w.Write(fallback)
+		return err
+	}
+	e.WriteContentType(w)
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+func (e encoderRender) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", e.contentType)
+	}
+}
+
+// envelopeValue returns the SuccessEnvelope or ErrorEnvelope envelope points
+// to, the values an EncoderFunc receives.
+func envelopeValue(envelope interface{}) interface{} {
+	switch envelope := envelope.(type) {
+	case *SuccessEnvelope:
+		return *envelope
+	case *ErrorEnvelope:
+		return *envelope
+	}
+	return envelope
+}
+
+// JSONValue returns v as the maps, slices, strings, numbers and bools of its
+// JSON, so an EncoderFunc for a format without support for json tags and
+// json.Marshaler renders the structure the JSON envelope has. Integers are
+// returned as int64, other numbers as float64.
+func JSONValue(v interface{}) (interface{}, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value), nil
+}
+
+// convertNumbers replaces the json.Numbers in value with int64 and float64.
+func convertNumbers(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = convertNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = convertNumbers(item)
+		}
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// jsonNull renders a nil value as null in the fields left out when empty,
+// eg: the data of Success(c, nil), where only Deleted has no "data".
+var jsonNull = json.RawMessage("null")
+
+// SuccessEnvelope is the body of a success response, built by the helpers
+// without intermediate maps. The fields are in the order they are rendered,
+// which is the alphabetical order of their JSON names and never changes.
+type SuccessEnvelope struct {
+	XMLName xml.Name `json:"-" xml:"response"`
+	// Count is set by SuccessWithCount with WithCountPlacement(CountTopLevel).
+	Count *int `json:"count,omitempty" xml:"count,omitempty"`
+	// Data is the payload of the response. It is left out by Deleted only,
+	// the other helpers render a nil payload as null.
+	Data interface{} `json:"data,omitempty" xml:"data,omitempty"`
+	// Links are set by SuccessWithLinks and by Created with WithLocation.
+	Links interface{} `json:"links,omitempty" xml:"links,omitempty"`
+	// Message is set by Deleted.
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
+	// Meta is the value set by MetaMiddleware or SetMetaField, left out when
+	// there is none unless WithNullFields is enabled.
+	Meta interface{} `json:"meta,omitempty" xml:"meta,omitempty"`
+	// Pagination is set by SuccessWithPagination and SuccessWithCursor, nil
+	// pagination is left out unless WithNullFields is enabled.
+	Pagination interface{} `json:"pagination,omitempty" xml:"pagination,omitempty"`
+	// PartialFailures are the parts PartialSuccess could not load.
+	PartialFailures []PartialFailure `json:"partialFailures,omitempty" xml:"partialFailures>partialFailure,omitempty"`
+	// Success is always true.
+	Success bool `json:"success" xml:"success" example:"true"`
+	// Warnings are the caveats of WithWarnings and AddWarning.
+	Warnings []Warning `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+}
+
+// ErrorEnvelope is the body of an error response. The fields are in the
+// order they are rendered, alphabetical like the ones of SuccessEnvelope.
+type ErrorEnvelope struct {
+	XMLName xml.Name `json:"-" xml:"response"`
+	// Data is rendered as null by InternalError, for older clients reading it.
+	Data interface{} `json:"data,omitempty" xml:"-"`
+	// Error describes what went wrong.
+	Error ErrorBody `json:"error" xml:"error"`
+	// Meta is the value set by MetaMiddleware or SetMetaField, left out when
+	// there is none unless WithNullFields is enabled.
+	Meta interface{} `json:"meta,omitempty" xml:"meta,omitempty"`
+	// Success is always false.
+	Success bool `json:"success" xml:"success" example:"false"`
+}
+
+// ErrorBody is the "error" object of an ErrorEnvelope. The fields are in the
+// order they are rendered.
+type ErrorBody struct {
+	// Causes is the chain of wrapped errors, only rendered in debug mode.
+	Causes []string `json:"causes,omitempty" xml:"causes>cause,omitempty"`
+	// Code is the HTTP status code.
+	Code int `json:"code" xml:"code" example:"404"`
+	// Context holds the key-value pairs of Detail.
+	Context map[string]interface{} `json:"context,omitempty" xml:"context,omitempty"`
+	// Details holds additional information about the error.
+	Details interface{} `json:"details,omitempty" xml:"details,omitempty"`
+	// ErrorCode is the business error code, eg: "USER_NOT_FOUND".
+	ErrorCode string `json:"errorCode,omitempty" xml:"errorCode,omitempty" example:"USER_NOT_FOUND"`
+	// ErrorID identifies a server error in the logs.
+	ErrorID string `json:"errorId,omitempty" xml:"errorId,omitempty" example:"3f2a9c1e7b4d8a60"`
+	// Errors lists several errors, eg: the FieldErrors of ValidationFailed or
+	// the ErrorItems of Errors.
+	Errors interface{} `json:"errors,omitempty" xml:"errors>error,omitempty"`
+	// GrantedPermissions are the permissions the client has, set by ForbiddenScope.
+	GrantedPermissions []string `json:"grantedPermissions,omitempty" xml:"grantedPermissions>permission,omitempty"`
+	// HelpURL links to the documentation of the error.
+	HelpURL string `json:"helpUrl,omitempty" xml:"helpUrl,omitempty" example:"https://docs.example.com/errors/USER_NOT_FOUND"`
+	// Kind classifies a database error scrubbed by WithDBErrorScrubbing, eg:
+	// "unique_violation".
+	Kind string `json:"kind,omitempty" xml:"kind,omitempty" example:"unique_violation"`
+	// Message is the user facing message.
+	Message string `json:"message" xml:"message" example:"User not found"`
+	// Reason tells why the credentials were rejected, set by UnauthorizedReason.
+	Reason string `json:"reason,omitempty" xml:"reason,omitempty" example:"TOKEN_EXPIRED"`
+	// RequiredPermissions are the permissions the request needs, set by ForbiddenScope.
+	RequiredPermissions []string `json:"requiredPermissions,omitempty" xml:"requiredPermissions>permission,omitempty"`
+	// Resource is the kind of resource that was not found, set by ResourceNotFound.
+	Resource string `json:"resource,omitempty" xml:"resource,omitempty" example:"user"`
+	// ResourceID is the identifier of the resource that was not found, set by ResourceNotFound.
+	ResourceID string `json:"resourceId,omitempty" xml:"resourceId,omitempty" example:"42"`
+	// Retryable tells clients whether repeating the request may succeed.
+	Retryable bool `json:"retryable" xml:"retryable" example:"false"`
+	// Status is the status name, eg: "NOT_FOUND".
+	Status string `json:"status" xml:"status" example:"NOT_FOUND"`
+	// Type is the URI identifying the kind of error.
+	Type string `json:"type,omitempty" xml:"type,omitempty" example:"https://docs.example.com/errors/not-found"`
+}
+
+// nullable returns v, or jsonNull when v is nil, for the envelope fields
+// rendered as null when empty.
+func nullable(v interface{}) interface{} {
+	if v == nil {
+		return jsonNull
+	}
+	return v
+}
@@ -0,0 +1,149 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// discardWriter is an http.ResponseWriter throwing the response away, so the
+// benchmarks measure the helper rather than a recorder.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+// benchServe returns a function serving a GET of /bench with respond on an
+// engine, reusing the request and the writer.
+func benchServe(respond gin.HandlerFunc) func() {
+	engine := gin.New()
+	engine.GET("/bench", respond)
+	r := httptest.NewRequest(http.MethodGet, "/bench", nil)
+	w := &discardWriter{header: http.Header{}}
+	return func() {
+		clear(w.header)
+		engine.ServeHTTP(w, r)
+	}
+}
+
+// TestEnvelopesMatchTheMaps checks that the envelope structs render the same
+// bytes as the gin.H maps they replaced: encoding/json sorts the map keys, so
+// the members come in alphabetical order.
+func TestEnvelopesMatchTheMaps(t *testing.T) {
+	meta := responsehelper.Meta{RequestID: "req-1"}
+	for _, tc := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		want    gin.H
+	}{
+		{"Success", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 1}) },
+			gin.H{"success": true, "data": gin.H{"id": 1}, "meta": meta}},
+		{"Success nil", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, nil) },
+			gin.H{"success": true, "data": nil, "meta": meta}},
+		{"SuccessWithPagination", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 20, 1))
+		}, gin.H{"success": true, "data": []int{1}, "pagination": responsehelper.NewPagination(1, 20, 1), "meta": meta}},
+		{"Deleted", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Deleted(c, "User") },
+			gin.H{"success": true, "message": "User deleted successfully", "meta": meta}},
+		{"BadRequest", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.BadRequest(c, "Invalid input", "name is required")
+		}, gin.H{"success": false, "meta": meta, "error": gin.H{
+			"code": 400, "status": "BAD_REQUEST", "message": "Invalid input", "details": "name is required", "retryable": false,
+		}}},
+		{"InternalError", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			c.Set(responsehelper.ErrorIDKey, "e1")
+			h.InternalError(c, "Oops", errors.New("db down"))
+		}, gin.H{"success": false, "data": nil, "meta": meta, "error": gin.H{
+			"code": 500, "status": "INTERNAL_SERVER_ERROR", "message": "Oops", "details": "db down", "errorId": "e1", "retryable": false,
+		}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			c.Set(responsehelper.MetaKey, meta)
+			tc.respond(responsehelper.NewResponseHelper(), c)
+
+			want, err := json.Marshal(tc.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w.Body.String() != string(want) {
+				t.Errorf("body =\n%s\nwant\n%s", w.Body, want)
+			}
+		})
+	}
+}
+
+// raceEnabled is set when the race detector, which allocates on its own and
+// drops pooled values, is on.
+var raceEnabled bool
+
+// The allocations of a response, see TestAllocations.
+const (
+	successAllocs    = 17
+	badRequestAllocs = 15
+)
+
+// TestAllocations catches allocation regressions of the render path. The
+// limits are the current counts of encoding/json and gin, with the envelope
+// itself pooled.
+func TestAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector changes the allocation counts")
+	}
+	h := responsehelper.NewResponseHelper()
+	data := gin.H{"id": 1}
+	for _, tc := range []struct {
+		name    string
+		respond gin.HandlerFunc
+		max     float64
+	}{
+		{"Success", func(c *gin.Context) { h.Success(c, data) }, successAllocs},
+		{"BadRequest", func(c *gin.Context) { h.BadRequest(c, "Invalid input", "name is required") }, badRequestAllocs},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serve := benchServe(tc.respond)
+			if allocs := testing.AllocsPerRun(100, serve); allocs > tc.max {
+				t.Errorf("%s allocates %v times per response, want at most %v", tc.name, allocs, tc.max)
+			}
+		})
+	}
+}
+
+func BenchmarkSuccess(b *testing.B) {
+	h := responsehelper.NewResponseHelper()
+	data := gin.H{"id": 1, "name": "arun"}
+	serve := benchServe(func(c *gin.Context) { h.Success(c, data) })
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		serve()
+	}
+}
+
+func BenchmarkBadRequest(b *testing.B) {
+	h := responsehelper.NewResponseHelper()
+	serve := benchServe(func(c *gin.Context) { h.BadRequest(c, "Invalid input", "name is required") })
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		serve()
+	}
+}
+
+// BenchmarkHandWrittenSuccess is the gin.H response the envelopes replaced.
+func BenchmarkHandWrittenSuccess(b *testing.B) {
+	data := gin.H{"id": 1, "name": "arun"}
+	serve := benchServe(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		serve()
+	}
+}
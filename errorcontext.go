@@ -0,0 +1,45 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Detail adds a key-value pair to the "context" of an error, next to its
+// details, so tools read the facts of the error instead of parsing prose.
+// It can be passed to every error helper, a key given twice keeps the last
+// value. Values are rendered as JSON, those that cannot be marshalled are
+// rendered with fmt.Sprint and nil is rendered as null.
+//
+// Example:
+//
+//	h.responseHelper.Conflict(c, "duplicate email", err,
+//		responsehelper.Detail("field", "email"),
+//		responsehelper.Detail("attemptedValue", req.Email))
+func Detail(key string, value interface{}) ResponseOption {
+	if _, err := json.Marshal(value); err != nil {
+		value = fmt.Sprint(value)
+	}
+	return func(options *responseOptions) {
+		if options.context == nil {
+			options.context = map[string]interface{}{}
+		}
+		options.context[key] = value
+	}
+}
+
+// errorContext returns context with the pairs of Detail added, which win
+// over the pairs it already has.
+func (options *responseOptions) errorContext(context map[string]interface{}) map[string]interface{} {
+	if len(options.context) == 0 {
+		return context
+	}
+	merged := make(map[string]interface{}, len(context)+len(options.context))
+	for key, value := range context {
+		merged[key] = value
+	}
+	for key, value := range options.context {
+		merged[key] = value
+	}
+	return merged
+}
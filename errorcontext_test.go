@@ -0,0 +1,166 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// errorContext sends respond and returns the error.context of the body.
+func errorContext(t *testing.T, respond func(h responsehelper.ResponseHelper, c *gin.Context)) (map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+	c, w := newContext(http.MethodPost, "/users")
+	respond(responsehelper.NewResponseHelper(), c)
+	errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+	context, _ := errorBody["context"].(map[string]interface{})
+	return context, errorBody
+}
+
+func TestDetail(t *testing.T) {
+	context, errorBody := errorContext(t, func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Conflict(c, "duplicate email", errors.New("users_email_key"),
+			responsehelper.Detail("field", "email"),
+			responsehelper.Detail("attemptedValue", "arun@example.com"),
+			responsehelper.Detail("attempts", 3))
+	})
+
+	want := map[string]interface{}{"field": "email", "attemptedValue": "arun@example.com", "attempts": float64(3)}
+	if !reflect.DeepEqual(context, want) {
+		t.Errorf("context = %v, want %v", context, want)
+	}
+	if errorBody["details"] != "users_email_key" {
+		t.Errorf("details = %v, want them kept next to the context", errorBody["details"])
+	}
+}
+
+func TestDetailDuplicateKeys(t *testing.T) {
+	context, _ := errorContext(t, func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.BadRequest(c, "Invalid input", "", responsehelper.Detail("field", "name"), responsehelper.Detail("field", "email"))
+	})
+	if len(context) != 1 || context["field"] != "email" {
+		t.Errorf("context = %v, want the last value", context)
+	}
+}
+
+func TestDetailValues(t *testing.T) {
+	type point struct{ X, Y int }
+	context, _ := errorContext(t, func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.BadRequest(c, "Invalid input", "",
+			responsehelper.Detail("nil", nil),
+			responsehelper.Detail("struct", point{1, 2}),
+			responsehelper.Detail("slice", []string{"a", "b"}),
+			responsehelper.Detail("infinity", math.Inf(1)),
+			responsehelper.Detail("channel", make(chan int)),
+			responsehelper.Detail("duration", 1500*time.Millisecond))
+	})
+
+	for key, want := range map[string]interface{}{
+		"struct":   map[string]interface{}{"X": float64(1), "Y": float64(2)},
+		"slice":    []interface{}{"a", "b"},
+		"infinity": "+Inf",
+		"duration": float64(1500 * time.Millisecond),
+	} {
+		if !reflect.DeepEqual(context[key], want) {
+			t.Errorf("context[%q] = %#v, want %#v", key, context[key], want)
+		}
+	}
+	if value, ok := context["nil"]; !ok || value != nil {
+		t.Errorf("context[nil] = %v, %t, want null", value, ok)
+	}
+	if channel, _ := context["channel"].(string); !strings.HasPrefix(channel, "0x") {
+		t.Errorf("context[channel] = %v, want it stringified with fmt", context["channel"])
+	}
+}
+
+// TestDetailOnEveryErrorHelper calls the helpers the way they were called
+// before they took options, which must still compile, and with a Detail.
+func TestDetailOnEveryErrorHelper(t *testing.T) {
+	err := errors.New("boom")
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption){
+		"BadRequest": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.BadRequest(c, "Invalid input", "name is required", opts...)
+		},
+		"BadRequestDetails": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.BadRequestDetails(c, "Invalid input", gin.H{"name": "required"}, opts...)
+		},
+		"AlreadyExists": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.AlreadyExists(c, "User", err, opts...)
+		},
+		"Conflict": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.Conflict(c, "Conflict", err, opts...)
+		},
+		"NotFound": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.NotFound(c, "User not found", opts...)
+		},
+		"ResourceNotFound": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.ResourceNotFound(c, "user", 42, opts...)
+		},
+		"Unauthorized": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.Unauthorized(c, "Login required", opts...)
+		},
+		"Forbidden": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.Forbidden(c, "Not allowed", opts...)
+		},
+		"TooManyRequests": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.TooManyRequests(c, "Slow down", time.Second, opts...)
+		},
+		"ServiceUnavailable": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.ServiceUnavailable(c, "Down", time.Second, opts...)
+		},
+		"InternalError": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.InternalError(c, "Oops", err, opts...)
+		},
+		"Respond": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.Respond(c, err, nil, opts...)
+		},
+		"RespondAPIError": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusConflict, Message: "Conflict"}, opts...)
+		},
+		"Errors": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{{Message: "name is required"}}, opts...)
+		},
+		"WriteError": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+			h.WriteError(c, http.StatusLocked, responsehelper.ErrorBody{Message: "Locked"}, opts...)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			context, errorBody := errorContext(t, func(h responsehelper.ResponseHelper, c *gin.Context) { respond(h, c) })
+			if errorBody == nil || context != nil {
+				t.Errorf("without options: error = %v", errorBody)
+			}
+			context, _ = errorContext(t, func(h responsehelper.ResponseHelper, c *gin.Context) {
+				respond(h, c, responsehelper.Detail("requestSource", "test"))
+			})
+			if context["requestSource"] != "test" {
+				t.Errorf("context = %v, want the Detail", context)
+			}
+		})
+	}
+}
+
+func TestDetailInOtherFormats(t *testing.T) {
+	respond := func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Conflict(c, "duplicate email", nil, responsehelper.Detail("field", "email"))
+	}
+	for name, tt := range map[string]struct {
+		opts []responsehelper.Option
+		want string
+	}{
+		"problem details": {[]responsehelper.Option{responsehelper.WithProblemDetails(true)}, `"context":{"field":"email"}`},
+		"JSON:API":        {[]responsehelper.Option{responsehelper.WithDefaultFormat(responsehelper.FormatJSONAPI)}, `"context":{"field":"email"}`},
+		"XML":             {[]responsehelper.Option{responsehelper.WithDefaultFormat(responsehelper.FormatXML)}, `email`},
+	} {
+		c, w := newContext(http.MethodPost, "/users")
+		respond(responsehelper.NewResponseHelper(tt.opts...), c)
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%s: body = %s, want %s", name, w.Body, tt.want)
+		}
+	}
+}
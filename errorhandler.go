@@ -0,0 +1,59 @@
+package responsehelper
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler sends the errors recorded with c.Error once the later
+// handlers return without writing a response. The last error decides the
+// response:
+//
+//   - an *APIError or a StatusCoder, or an error wrapping one, is sent like
+//     Respond does
+//   - a gin.ErrorTypeBind error is sent like ValidationFailed does
+//   - a gin.ErrorTypePublic error is sent with its text as the message
+//   - any other error is sent with a generic message, its text is never exposed
+//
+// The status set with c.AbortWithError or c.Status is kept when it is an
+// error status, otherwise 500 Internal Server Error is sent. Responses
+// already written are left alone, see Responded. Prefer c.Status over
+// c.AbortWithStatus, as the latter sends the header before the JSON content
+// type is set.
+//
+// Example:
+//
+//	router.Use(responsehelper.ErrorHandler(responseHelper))
+func ErrorHandler(h ResponseHelper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		last := c.Errors.Last()
+		// c.AbortWithError writes the header without a body, which does
+		// not count as a response
+		if last == nil || Responded(c) {
+			return
+		}
+		var apiErr *APIError
+		var coder StatusCoder
+		switch {
+		case errors.As(last.Err, &apiErr), errors.As(last.Err, &coder):
+			h.Respond(c, last.Err, nil)
+		case last.IsType(gin.ErrorTypeBind):
+			h.ValidationFailed(c, last.Err)
+		default:
+			status := c.Writer.Status()
+			if status < http.StatusBadRequest {
+				status = http.StatusInternalServerError
+			}
+			message := "An unexpected error occurred"
+			if last.IsType(gin.ErrorTypePublic) {
+				message = last.Error()
+			} else if status < http.StatusInternalServerError {
+				message = http.StatusText(status)
+			}
+			h.RespondAPIError(c, NewAPIError(status, message, nil))
+		}
+	}
+}
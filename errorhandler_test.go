@@ -0,0 +1,87 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func errorHandlerEngine(handler gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.Use(responsehelper.ErrorHandler(responsehelper.NewResponseHelper()))
+	engine.GET("/", handler)
+	return engine
+}
+
+func TestErrorHandler(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		handler gin.HandlerFunc
+		status  int
+		message string
+	}{
+		{"api error", func(c *gin.Context) {
+			_ = c.Error(errors.New("first"))
+			_ = c.Error(responsehelper.ErrNotFound.WithMessage("User not found"))
+		}, http.StatusNotFound, "User not found"},
+		{"public error", func(c *gin.Context) {
+			_ = c.Error(errors.New("quota exceeded")).SetType(gin.ErrorTypePublic)
+			c.Status(http.StatusTooManyRequests)
+		}, http.StatusTooManyRequests, "quota exceeded"},
+		{"private error", func(c *gin.Context) {
+			_ = c.Error(errors.New("pq: connection refused"))
+		}, http.StatusInternalServerError, "An unexpected error occurred"},
+		{"private error with a 4xx status", func(c *gin.Context) {
+			_ = c.Error(errors.New("token store said no"))
+			c.Status(http.StatusForbidden)
+		}, http.StatusForbidden, "Forbidden"},
+		{"abort with error", func(c *gin.Context) {
+			_ = c.AbortWithError(http.StatusConflict, errors.New("version mismatch"))
+		}, http.StatusConflict, "Conflict"},
+		{"status coder", func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("loading: %w", statusError{http.StatusGone, "user deleted"}))
+		}, http.StatusGone, "loading: user deleted"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(errorHandlerEngine(tc.handler), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assertError(t, w, tc.status, tc.message)
+		})
+	}
+}
+
+func TestErrorHandlerBindError(t *testing.T) {
+	w := serve(errorHandlerEngine(func(c *gin.Context) {
+		var req struct {
+			Name string `form:"name" binding:"required"`
+		}
+		_ = c.Error(c.ShouldBindQuery(&req)).SetType(gin.ErrorTypeBind)
+	}), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertError(t, w, http.StatusBadRequest, "Validation failed")
+	assertField(t, w, "error.errors.0.field", "name")
+}
+
+func TestErrorHandlerLeavesWrittenResponsesAlone(t *testing.T) {
+	w := serve(errorHandlerEngine(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"done": true})
+		_ = c.Error(errors.New("cleanup failed"))
+	}), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"done":true}` {
+		t.Errorf("response = %d %s, want the handler's", w.Code, w.Body)
+	}
+}
+
+func TestErrorHandlerWithoutErrors(t *testing.T) {
+	w := serve(errorHandlerEngine(func(c *gin.Context) {}), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %s, want none", w.Body)
+	}
+}
@@ -0,0 +1,39 @@
+package responsehelper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ErrorIDKey is the gin context key holding the error ID of a 5xx response.
+	ErrorIDKey = "errorId"
+	// ErrorIDHeader is the response header carrying the error ID of a 5xx response.
+	ErrorIDHeader = "X-Error-ID"
+)
+
+// ErrorID returns the error ID of the request, generating and storing one
+// under ErrorIDKey when none exists yet. Layers that run before the helper,
+// eg: panic recovery, can call it so the same ID ends up in the response.
+func ErrorID(c *gin.Context) string {
+	return errorID(exchangeOf(c))
+}
+
+// errorID is ErrorID for an Exchange.
+func errorID(c Exchange) string {
+	if id := getString(c, ErrorIDKey); id != "" {
+		return id
+	}
+	id := newErrorID()
+	c.Set(ErrorIDKey, id)
+	return id
+}
+
+// newErrorID returns a random 16 character hex ID.
+func newErrorID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,61 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+var errorIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+func TestInternalErrorID(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().InternalError(c, "oops", errors.New("boom"))
+
+	header := w.Header().Get(responsehelper.ErrorIDHeader)
+	if !errorIDPattern.MatchString(header) {
+		t.Fatalf("%s = %q, want 16 hex characters", responsehelper.ErrorIDHeader, header)
+	}
+	assertField(t, w, "error.errorId", header)
+	if got := c.GetString(responsehelper.ErrorIDKey); got != header {
+		t.Errorf("c.Get(%q) = %q, want %q", responsehelper.ErrorIDKey, got, header)
+	}
+}
+
+func TestErrorIDIsReused(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.ErrorIDKey, "from-recovery")
+	responsehelper.NewResponseHelper().InternalError(c, "oops", errors.New("boom"))
+
+	if got := w.Header().Get(responsehelper.ErrorIDHeader); got != "from-recovery" {
+		t.Errorf("%s = %q, want %q", responsehelper.ErrorIDHeader, got, "from-recovery")
+	}
+	assertField(t, w, "error.errorId", "from-recovery")
+}
+
+func TestErrorIDIsStable(t *testing.T) {
+	c, _ := newContext(http.MethodGet, "/users")
+	first := responsehelper.ErrorID(c)
+	if second := responsehelper.ErrorID(c); second != first {
+		t.Errorf("ErrorID = %q then %q, want the same ID", first, second)
+	}
+	other, _ := newContext(http.MethodGet, "/users")
+	if responsehelper.ErrorID(other) == first {
+		t.Errorf("two requests share the error ID %q", first)
+	}
+}
+
+func TestErrorIDOnlyForServerErrors(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper().NotFound(c, "missing")
+
+	if got := w.Header().Get(responsehelper.ErrorIDHeader); got != "" {
+		t.Errorf("%s = %q on a 404", responsehelper.ErrorIDHeader, got)
+	}
+	if _, ok := decodeBody(t, w)["error"].(map[string]interface{})["errorId"]; ok {
+		t.Errorf("a 404 has an errorId\nbody: %s", w.Body)
+	}
+}
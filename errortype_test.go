@@ -0,0 +1,46 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestErrorTypeDerivedFromTheStatus(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithErrorTypeBase("https://errors.example.com/"))
+	for status, want := range map[int]string{
+		http.StatusNotFound:            "https://errors.example.com/not-found",
+		http.StatusUnprocessableEntity: "https://errors.example.com/unprocessable-entity",
+		http.StatusInternalServerError: "https://errors.example.com/internal-server-error",
+	} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		h.RespondAPIError(c, responsehelper.NewAPIError(status, "boom", nil))
+
+		assertField(t, w, "error.type", want)
+	}
+}
+
+func TestErrorTypeExplicitSlug(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithErrorTypeBase("https://errors.example.com")).
+		NotFound(c, "User not found", responsehelper.WithErrorType("user-not-found"))
+
+	assertField(t, w, "error.type", "https://errors.example.com/user-not-found")
+}
+
+func TestErrorTypeOmitted(t *testing.T) {
+	for name, h := range map[string]responsehelper.ResponseHelper{
+		"no base":      responsehelper.NewResponseHelper(),
+		"relative URI": responsehelper.NewResponseHelper(responsehelper.WithErrorTypeBase("errors/")),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/42")
+			h.NotFound(c, "User not found", responsehelper.WithErrorType("user-not-found"))
+
+			if typ, ok := decodeBody(t, w)["error"].(map[string]interface{})["type"]; ok {
+				t.Errorf("error.type = %v, want no type", typ)
+			}
+		})
+	}
+}
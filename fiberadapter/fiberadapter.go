@@ -0,0 +1,452 @@
+// Package fiberadapter sends the responsehelper envelopes from Fiber
+// handlers.
+package fiberadapter
+
+import (
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Helper has the method set of responsehelper.ResponseHelper for Fiber. It
+// renders with a responsehelper.Core straight into the Fiber response, the
+// JSON envelopes with the JSON encoder of the app like c.JSON, and the values
+// stored with c.Locals, eg: the meta under responsehelper.MetaKey, are found
+// like the ones set with c.Set in Gin. The methods return nil so handlers can
+// end with them:
+//
+//	return h.NotFound(c, "User not found")
+type Helper struct {
+	core *responsehelper.Core
+}
+
+// New creates a Helper configured with opts, the options of
+// responsehelper.NewResponseHelper.
+func New(opts ...responsehelper.Option) *Helper {
+	return &Helper{core: responsehelper.NewCore(opts...)}
+}
+
+// Wrap creates a Helper rendering with the Core of helper, so Gin and Fiber
+// handlers share one configuration, its registered codes and hooks, and send
+// identical bodies. It panics when helper was not created by
+// responsehelper.NewResponseHelper or responsehelper.New.
+func Wrap(helper responsehelper.ResponseHelper) *Helper {
+	core := responsehelper.CoreOf(helper)
+	if core == nil {
+		panic("fiberadapter: Wrap needs a helper created by responsehelper.NewResponseHelper")
+	}
+	return &Helper{core: core}
+}
+
+// exchange is the responsehelper.Exchange of a Fiber context. fasthttp sends
+// the response once the handler returns, so the headers are kept in an
+// http.Header, seeded with the ones already set on the response, and copied
+// to it with the status.
+type exchange struct {
+	c      *fiber.Ctx
+	r      *http.Request
+	header http.Header
+	// seeded are the keys of the headers found on the response.
+	seeded []string
+	status int
+	size   int
+}
+
+// newExchange returns the Exchange the Core renders to for c.
+func newExchange(c *fiber.Ctx) *exchange {
+	return &exchange{c: c, size: -1}
+}
+
+// Request returns the request of c without its body, built on the first call
+// as few responses read it.
+func (x *exchange) Request() *http.Request {
+	if x.r == nil {
+		x.r = x.request()
+	}
+	return x.r
+}
+
+// request returns the method, URL, headers, host and remote address of the
+// request of c, with its user context.
+func (x *exchange) request() *http.Request {
+	r, err := http.NewRequestWithContext(x.c.UserContext(), x.c.Method(), x.c.OriginalURL(), nil)
+	if err != nil {
+		r, _ = http.NewRequestWithContext(x.c.UserContext(), x.c.Method(), "/", nil)
+	}
+	x.c.Request().Header.VisitAll(func(key, value []byte) {
+		r.Header.Add(string(key), string(value))
+	})
+	r.Host = x.c.Hostname()
+	r.RequestURI = x.c.OriginalURL()
+	r.RemoteAddr = x.c.Context().RemoteAddr().String()
+	return r
+}
+
+func (x *exchange) Get(key string) (interface{}, bool) {
+	value := x.c.Locals(key)
+	return value, value != nil
+}
+
+func (x *exchange) Set(key string, value interface{}) {
+	x.c.Locals(key, value)
+}
+
+func (x *exchange) Header() http.Header {
+	if x.header == nil {
+		x.header = http.Header{}
+		x.c.Response().Header.VisitAll(func(key, value []byte) {
+			name := textproto.CanonicalMIMEHeaderKey(string(key))
+			switch name {
+			// managed by fasthttp, which reports a default content type
+			case fiber.HeaderContentType, fiber.HeaderContentLength, fiber.HeaderServer, fiber.HeaderConnection:
+				return
+			}
+			if _, ok := x.header[name]; !ok {
+				x.seeded = append(x.seeded, name)
+			}
+			x.header[name] = append(x.header[name], string(value))
+		})
+	}
+	return x.header
+}
+
+func (x *exchange) WriteHeader(status int) {
+	if x.Written() {
+		return
+	}
+	x.status, x.size = status, 0
+	header := &x.c.Response().Header
+	for _, key := range x.seeded {
+		header.Del(key)
+	}
+	for key, values := range x.header {
+		header.Del(key)
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	x.c.Status(status)
+}
+
+func (x *exchange) Write(data []byte) (int, error) {
+	x.WriteHeader(http.StatusOK)
+	x.c.Response().AppendBody(data)
+	x.size += len(data)
+	return len(data), nil
+}
+
+// WriteJSON sends v with c.JSON, marshalled by the JSON encoder of the app.
+func (x *exchange) WriteJSON(status int, contentType string, v interface{}) error {
+	if preset := x.Header().Get(fiber.HeaderContentType); preset != "" {
+		contentType = preset
+	}
+	if err := x.c.JSON(v, contentType); err != nil {
+		return err
+	}
+	x.header.Set(fiber.HeaderContentType, contentType)
+	x.WriteHeader(status)
+	x.size = len(x.c.Response().Body())
+	return nil
+}
+
+func (x *exchange) Status() int {
+	if x.Written() {
+		return x.status
+	}
+	return x.c.Response().StatusCode()
+}
+
+func (x *exchange) Size() int {
+	return x.size
+}
+
+func (x *exchange) Written() bool {
+	return x.size >= 0
+}
+
+// BadRequest sends a 400 Bad Request response with string details.
+//
+// Deprecated: use BadRequestDetails.
+func (h *Helper) BadRequest(c *fiber.Ctx, message string, details string, opts ...responsehelper.ResponseOption) error {
+	h.core.BadRequest(newExchange(c), message, details, opts...)
+	return nil
+}
+
+// BadRequestDetails sends a 400 Bad Request response with structured details.
+func (h *Helper) BadRequestDetails(c *fiber.Ctx, message string, details interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.BadRequestDetails(newExchange(c), message, details, opts...)
+	return nil
+}
+
+// AlreadyExists sends a 409 Conflict response for a resource that already exists.
+func (h *Helper) AlreadyExists(c *fiber.Ctx, resource string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.AlreadyExists(newExchange(c), resource, err, opts...)
+	return nil
+}
+
+// Conflict sends a 409 Conflict response.
+func (h *Helper) Conflict(c *fiber.Ctx, message string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.Conflict(newExchange(c), message, err, opts...)
+	return nil
+}
+
+// NotFound sends a 404 Not Found response.
+func (h *Helper) NotFound(c *fiber.Ctx, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.NotFound(newExchange(c), message, opts...)
+	return nil
+}
+
+// ResourceNotFound sends a 404 Not Found response with the resource and its identifier.
+func (h *Helper) ResourceNotFound(c *fiber.Ctx, resource string, id interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.ResourceNotFound(newExchange(c), resource, id, opts...)
+	return nil
+}
+
+// Unauthorized sends a 401 Unauthorized response.
+func (h *Helper) Unauthorized(c *fiber.Ctx, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.Unauthorized(newExchange(c), message, opts...)
+	return nil
+}
+
+// UnauthorizedWithChallenge sends a 401 Unauthorized response with a WWW-Authenticate challenge.
+func (h *Helper) UnauthorizedWithChallenge(c *fiber.Ctx, message, scheme, realm string, params map[string]string, opts ...responsehelper.ResponseOption) error {
+	h.core.UnauthorizedWithChallenge(newExchange(c), message, scheme, realm, params, opts...)
+	return nil
+}
+
+// UnauthorizedReason sends a 401 Unauthorized response telling why the credentials were rejected.
+func (h *Helper) UnauthorizedReason(c *fiber.Ctx, reason responsehelper.AuthFailureReason, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.UnauthorizedReason(newExchange(c), reason, message, opts...)
+	return nil
+}
+
+// Forbidden sends a 403 Forbidden response.
+func (h *Helper) Forbidden(c *fiber.Ctx, message string, opts ...responsehelper.ResponseOption) error {
+	h.core.Forbidden(newExchange(c), message, opts...)
+	return nil
+}
+
+// ForbiddenScope sends a 403 Forbidden response with the required and granted permissions.
+func (h *Helper) ForbiddenScope(c *fiber.Ctx, message string, required []string, granted []string, opts ...responsehelper.ResponseOption) error {
+	h.core.ForbiddenScope(newExchange(c), message, required, granted, opts...)
+	return nil
+}
+
+// TooManyRequests sends a 429 Too Many Requests response.
+func (h *Helper) TooManyRequests(c *fiber.Ctx, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) error {
+	h.core.TooManyRequests(newExchange(c), message, retryAfter, opts...)
+	return nil
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable response.
+func (h *Helper) ServiceUnavailable(c *fiber.Ctx, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) error {
+	h.core.ServiceUnavailable(newExchange(c), message, retryAfter, opts...)
+	return nil
+}
+
+// InternalError sends a 500 Internal Server Error response.
+func (h *Helper) InternalError(c *fiber.Ctx, message string, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.InternalError(newExchange(c), message, err, opts...)
+	return nil
+}
+
+// Success sends a 200 OK response with data.
+func (h *Helper) Success(c *fiber.Ctx, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Success(newExchange(c), data, opts...)
+	return nil
+}
+
+// PartialSuccess sends a 200 OK response with the data and the parts that could not be loaded.
+func (h *Helper) PartialSuccess(c *fiber.Ctx, data interface{}, failures []responsehelper.PartialFailure, opts ...responsehelper.ResponseOption) error {
+	h.core.PartialSuccess(newExchange(c), data, failures, opts...)
+	return nil
+}
+
+// SuccessCSV sends a 200 OK response with rows as a CSV attachment.
+func (h *Helper) SuccessCSV(c *fiber.Ctx, filename string, rows interface{}) error {
+	h.core.SuccessCSV(newExchange(c), filename, rows)
+	return nil
+}
+
+// SuccessIfModified sends a 200 OK response with the data of dataFn, or a
+// 304 Not Modified response when If-Modified-Since is not before lastModified.
+func (h *Helper) SuccessIfModified(c *fiber.Ctx, lastModified time.Time, dataFn func() (interface{}, error), opts ...responsehelper.ResponseOption) error {
+	h.core.SuccessIfModified(newExchange(c), lastModified, dataFn, opts...)
+	return nil
+}
+
+// SuccessWithPagination sends a 200 OK response with data and pagination metadata.
+func (h *Helper) SuccessWithPagination(c *fiber.Ctx, data interface{}, meta interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.SuccessWithPagination(newExchange(c), data, meta, opts...)
+	return nil
+}
+
+// SuccessWithCursor sends a 200 OK response with data and cursor pagination metadata.
+func (h *Helper) SuccessWithCursor(c *fiber.Ctx, data interface{}, cur responsehelper.CursorPagination) error {
+	h.core.SuccessWithCursor(newExchange(c), data, cur)
+	return nil
+}
+
+// SuccessWithCount sends a 200 OK response with data and its number of items.
+func (h *Helper) SuccessWithCount(c *fiber.Ctx, data interface{}) error {
+	h.core.SuccessWithCount(newExchange(c), data)
+	return nil
+}
+
+// SuccessLarge sends a 200 OK response with data. Fiber buffers the
+// response, so the data is not streamed.
+func (h *Helper) SuccessLarge(c *fiber.Ctx, data interface{}) error {
+	h.core.SuccessLarge(newExchange(c), data)
+	return nil
+}
+
+// SuccessWithLinks sends a 200 OK response with data and hypermedia links.
+func (h *Helper) SuccessWithLinks(c *fiber.Ctx, data interface{}, links responsehelper.Links) error {
+	h.core.SuccessWithLinks(newExchange(c), data, links)
+	return nil
+}
+
+// Created sends a 201 Created response with data.
+func (h *Helper) Created(c *fiber.Ctx, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Created(newExchange(c), data, opts...)
+	return nil
+}
+
+// Patched sends a 200 OK response with the updated resource and the changed fields.
+func (h *Helper) Patched(c *fiber.Ctx, resource string, data interface{}, changedFields []string, opts ...responsehelper.ResponseOption) error {
+	h.core.Patched(newExchange(c), resource, data, changedFields, opts...)
+	return nil
+}
+
+// OperationAccepted sends a 202 Accepted response with a long-running operation.
+func (h *Helper) OperationAccepted(c *fiber.Ctx, op responsehelper.Operation) error {
+	h.core.OperationAccepted(newExchange(c), op)
+	return nil
+}
+
+// OperationStatus sends the state of a long-running operation.
+func (h *Helper) OperationStatus(c *fiber.Ctx, op responsehelper.Operation) error {
+	h.core.OperationStatus(newExchange(c), op)
+	return nil
+}
+
+// Deleted sends the response for a deleted resource, see WithDeleteStatus.
+func (h *Helper) Deleted(c *fiber.Ctx, message string) error {
+	h.core.Deleted(newExchange(c), message)
+	return nil
+}
+
+// DeletedNoContent sends a 204 No Content response for a deleted resource.
+func (h *Helper) DeletedNoContent(c *fiber.Ctx) error {
+	h.core.DeletedNoContent(newExchange(c))
+	return nil
+}
+
+// NoContent sends a 204 No Content response.
+func (h *Helper) NoContent(c *fiber.Ctx) error {
+	h.core.NoContent(newExchange(c))
+	return nil
+}
+
+// RespondAPIError sends the error response described by err.
+func (h *Helper) RespondAPIError(c *fiber.Ctx, err *responsehelper.APIError, opts ...responsehelper.ResponseOption) error {
+	h.core.RespondAPIError(newExchange(c), err, opts...)
+	return nil
+}
+
+// Respond sends data when err is nil and the error response for err otherwise.
+func (h *Helper) Respond(c *fiber.Ctx, err error, data interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.Respond(newExchange(c), err, data, opts...)
+	return nil
+}
+
+// Errors sends an error response listing several errors.
+func (h *Helper) Errors(c *fiber.Ctx, statusCode int, errs []responsehelper.ErrorItem, opts ...responsehelper.ResponseOption) error {
+	h.core.Errors(newExchange(c), statusCode, errs, opts...)
+	return nil
+}
+
+// Problem sends an RFC 7807 problem details response.
+func (h *Helper) Problem(c *fiber.Ctx, status int, typ, title, detail string, extensions map[string]interface{}) error {
+	h.core.Problem(newExchange(c), status, typ, title, detail, extensions)
+	return nil
+}
+
+// WriteJSON sends body as is, without the envelope.
+func (h *Helper) WriteJSON(c *fiber.Ctx, status int, body interface{}, opts ...responsehelper.ResponseOption) error {
+	h.core.WriteJSON(newExchange(c), status, body, opts...)
+	return nil
+}
+
+// WriteError sends errBody in the standard error envelope.
+func (h *Helper) WriteError(c *fiber.Ctx, status int, errBody responsehelper.ErrorBody, opts ...responsehelper.ResponseOption) error {
+	h.core.WriteError(newExchange(c), status, errBody, opts...)
+	return nil
+}
+
+// OAuthError sends an RFC 6749 error response, eg: of a token endpoint.
+func (h *Helper) OAuthError(c *fiber.Ctx, errCode string, description string, status int) error {
+	h.core.OAuthError(newExchange(c), errCode, description, status)
+	return nil
+}
+
+// ValidationFailed sends the validation errors of err.
+func (h *Helper) ValidationFailed(c *fiber.Ctx, err error, opts ...responsehelper.ResponseOption) error {
+	h.core.ValidationFailed(newExchange(c), err, opts...)
+	return nil
+}
+
+// NotFoundKey sends a 404 Not Found response with a translated message.
+func (h *Helper) NotFoundKey(c *fiber.Ctx, key string, args ...interface{}) error {
+	h.core.NotFoundKey(newExchange(c), key, args...)
+	return nil
+}
+
+// BadRequestKey sends a 400 Bad Request response with a translated message.
+func (h *Helper) BadRequestKey(c *fiber.Ctx, key string, details string, args ...interface{}) error {
+	h.core.BadRequestKey(newExchange(c), key, details, args...)
+	return nil
+}
+
+// UnauthorizedKey sends a 401 Unauthorized response with a translated message.
+func (h *Helper) UnauthorizedKey(c *fiber.Ctx, key string, args ...interface{}) error {
+	h.core.UnauthorizedKey(newExchange(c), key, args...)
+	return nil
+}
+
+// ForbiddenKey sends a 403 Forbidden response with a translated message.
+func (h *Helper) ForbiddenKey(c *fiber.Ctx, key string, args ...interface{}) error {
+	h.core.ForbiddenKey(newExchange(c), key, args...)
+	return nil
+}
+
+// ConflictKey sends a 409 Conflict response with a translated message.
+func (h *Helper) ConflictKey(c *fiber.Ctx, key string, err error, args ...interface{}) error {
+	h.core.ConflictKey(newExchange(c), key, err, args...)
+	return nil
+}
+
+// InternalErrorKey sends a 500 Internal Server Error response with a translated message.
+func (h *Helper) InternalErrorKey(c *fiber.Ctx, key string, err error, args ...interface{}) error {
+	h.core.InternalErrorKey(newExchange(c), key, err, args...)
+	return nil
+}
+
+// RegisterCode registers a business error code for RespondCode.
+func (h *Helper) RegisterCode(code string, status int, defaultMessage string) {
+	h.core.RegisterCode(code, status, defaultMessage)
+}
+
+// PrecomputeError registers an error response whose body is marshalled once.
+func (h *Helper) PrecomputeError(status int, message string) {
+	h.core.PrecomputeError(status, message)
+}
+
+// RespondCode sends the error response registered for code.
+func (h *Helper) RespondCode(c *fiber.Ctx, code string, args ...interface{}) error {
+	h.core.RespondCode(newExchange(c), code, args...)
+	return nil
+}
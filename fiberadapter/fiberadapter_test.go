@@ -0,0 +1,315 @@
+package fiberadapter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/fiberadapter"
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var meta = responsehelper.Meta{RequestID: "req-1", Path: "/users/42"}
+
+// foreignHelper is a ResponseHelper not created by the package.
+type foreignHelper struct {
+	responsehelper.ResponseHelper
+}
+
+// parityCall sends the same response from a Gin and a Fiber handler.
+type parityCall struct {
+	name  string
+	gin   func(h responsehelper.ResponseHelper, c *gin.Context)
+	fiber func(h *fiberadapter.Helper, c *fiber.Ctx) error
+}
+
+var parityCalls = []parityCall{
+	{"BadRequest",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.BadRequest(c, "Invalid input", "name is required")
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.BadRequest(c, "Invalid input", "name is required")
+		}},
+	{"ResourceNotFound",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "user", 42) },
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error { return h.ResourceNotFound(c, "user", 42) }},
+	{"UnauthorizedReason",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.UnauthorizedReason(c, responsehelper.TokenExpired, "Your session expired")
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.UnauthorizedReason(c, responsehelper.TokenExpired, "Your session expired")
+		}},
+	{"TooManyRequests",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.TooManyRequests(c, "Slow down", 30*time.Second)
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.TooManyRequests(c, "Slow down", 30*time.Second)
+		}},
+	{"InternalError",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.InternalError(c, "Oops", errors.New("db down"))
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.InternalError(c, "Oops", errors.New("db down"))
+		}},
+	{"Success",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, map[string]int{"id": 42}) },
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error { return h.Success(c, map[string]int{"id": 42}) }},
+	{"Created",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+		}},
+	{"SuccessWithPagination",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+		}},
+	{"RawJSON",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, responsehelper.RawJSON([]byte(`{"id": 42}`)))
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.Success(c, responsehelper.RawJSON([]byte(`{"id": 42}`)))
+		}},
+	{"SuccessCSV",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+		}},
+	{"Problem",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Problem(c, http.StatusConflict, "", "Conflict", "Already taken", map[string]interface{}{"field": "email"})
+		},
+		func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+			return h.Problem(c, http.StatusConflict, "", "Conflict", "Already taken", map[string]interface{}{"field": "email"})
+		}},
+}
+
+// serveGin sends the response of call from a Gin context.
+func serveGin(helper responsehelper.ResponseHelper, call parityCall) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	c.Set(responsehelper.MetaKey, meta)
+	c.Set(responsehelper.ErrorIDKey, "e1")
+	call.gin(helper, c)
+	return w
+}
+
+// serveFiber sends the response of handle from a Fiber app.
+func serveFiber(t *testing.T, h *fiberadapter.Helper, handle func(*fiberadapter.Helper, *fiber.Ctx) error) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		c.Locals(responsehelper.MetaKey, meta)
+		c.Locals(responsehelper.ErrorIDKey, "e1")
+		return handle(h, c)
+	})
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// TestParityWithGin sends every response from Gin and Fiber with the same
+// configuration and meta: the statuses, the headers sent by Gin and the
+// bodies must match byte for byte.
+func TestParityWithGin(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"default":   nil,
+		"sanitized": {responsehelper.WithErrorSanitization(true), responsehelper.WithAPIVersion("1.4.2", "")},
+		"xml": {
+			responsehelper.WithContentNegotiation(true),
+			responsehelper.WithDefaultFormat(responsehelper.FormatXML),
+		},
+	} {
+		helper := responsehelper.NewResponseHelper(opts...)
+		h := fiberadapter.Wrap(helper)
+		for _, call := range parityCalls {
+			t.Run(name+"/"+call.name, func(t *testing.T) {
+				want := serveGin(helper, call)
+				resp := serveFiber(t, h, call.fiber)
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if resp.StatusCode != want.Code {
+					t.Errorf("status = %d, Gin sent %d", resp.StatusCode, want.Code)
+				}
+				for key, values := range want.Header() {
+					if got := resp.Header.Values(key); !reflect.DeepEqual(got, values) {
+						t.Errorf("%s = %q, Gin sent %q", key, got, values)
+					}
+				}
+				if string(body) != want.Body.String() {
+					t.Errorf("body =\n%s\nGin sent\n%s", body, want.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestKeepsTheHeadersSetBefore(t *testing.T) {
+	resp := serveFiber(t, fiberadapter.New(), func(h *fiberadapter.Helper, c *fiber.Ctx) error {
+		c.Set(fiber.HeaderVary, "Origin")
+		c.Set("X-Trace", "t1")
+		return h.NotFound(c, "User not found")
+	})
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderVary); got != "Origin" {
+		t.Errorf("Vary = %q, want the one of the handler", got)
+	}
+	if got := resp.Header.Get("X-Trace"); got != "t1" {
+		t.Errorf("X-Trace = %q, want the one of the handler", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestUsesTheJSONEncoderOfTheApp(t *testing.T) {
+	app := fiber.New(fiber.Config{JSONEncoder: func(v interface{}) ([]byte, error) {
+		return []byte(`{"encodedBy":"app"}`), nil
+	}})
+	h := fiberadapter.New()
+	app.Get("/", func(c *fiber.Ctx) error { return h.Success(c, "ok") })
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != `{"encodedBy":"app"}` {
+		t.Errorf("body = %s, want the one of the app encoder", body)
+	}
+}
+
+func TestFailingJSONEncoderSendsAnInternalError(t *testing.T) {
+	app := fiber.New(fiber.Config{JSONEncoder: func(v interface{}) ([]byte, error) {
+		return nil, errors.New("cannot encode")
+	}})
+	h := fiberadapter.New()
+	app.Get("/", func(c *fiber.Ctx) error { return h.Created(c, "ok") })
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+}
+
+func TestReadsTheRequest(t *testing.T) {
+	app := fiber.New()
+	h := fiberadapter.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return h.Problem(c, http.StatusConflict, "", "Conflict", "", nil)
+	})
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/users/42?verbose=1", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Instance string `json:"instance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Instance != "/users/42" {
+		t.Errorf("instance = %q, want the path of the request", body.Instance)
+	}
+}
+
+func TestWrapNeedsACore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Wrap accepted a helper without a Core")
+		}
+	}()
+	fiberadapter.Wrap(foreignHelper{})
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// benchmarkHandler serves GET /users/42 with handler without a network.
+func benchmarkHandler(b *testing.B, handler fiber.Handler) {
+	app := fiber.New()
+	app.Get("/users/:id", handler)
+	serve := app.Handler()
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	ctx.Request.SetRequestURI("/users/42")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.Response.Reset()
+		ctx.ResetUserValues()
+		serve(ctx)
+	}
+}
+
+func BenchmarkSuccess(b *testing.B) {
+	h := fiberadapter.New()
+	benchmarkHandler(b, func(c *fiber.Ctx) error {
+		return h.Success(c, user{ID: 42, Name: "arun"})
+	})
+}
+
+func BenchmarkHandWrittenSuccess(b *testing.B) {
+	benchmarkHandler(b, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"success": true, "data": user{ID: 42, Name: "arun"}})
+	})
+}
+
+func BenchmarkNotFound(b *testing.B) {
+	h := fiberadapter.New()
+	benchmarkHandler(b, func(c *fiber.Ctx) error {
+		return h.NotFound(c, "User not found")
+	})
+}
+
+func BenchmarkHandWrittenNotFound(b *testing.B) {
+	benchmarkHandler(b, func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"success": false,
+			"error":   fiber.Map{"code": fiber.StatusNotFound, "message": "User not found", "status": "NOT_FOUND"},
+		})
+	})
+}
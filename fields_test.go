@@ -0,0 +1,138 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type fieldsAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type fieldsUser struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Email     string          `json:"email"`
+	CreatedAt string          `json:"created_at"`
+	Address   fieldsAddress   `json:"address"`
+	Previous  []fieldsAddress `json:"previous"`
+}
+
+var fieldsUsers = []fieldsUser{
+	{ID: 1, Name: "a", Email: "a@example.com", CreatedAt: "2024", Address: fieldsAddress{"Kochi", "IN"}, Previous: []fieldsAddress{{"Pune", "IN"}}},
+	{ID: 2, Name: "b", Email: "b@example.com", CreatedAt: "2025", Address: fieldsAddress{"Berlin", "DE"}},
+}
+
+// sparse sends respond for a request with the fields query parameter and
+// returns the decoded body.
+func sparse(t *testing.T, fields string, respond func(h responsehelper.ResponseHelper, c *gin.Context)) (int, map[string]interface{}) {
+	t.Helper()
+	c, w := newContext(http.MethodGet, "/users?fields="+url.QueryEscape(fields))
+	respond(responsehelper.NewResponseHelper(responsehelper.WithFieldsQueryParam("fields")), c)
+	return w.Code, decodeBody(t, w)
+}
+
+func TestSparseFieldsets(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		fields string
+		want   interface{}
+	}{
+		{"members", "id,name", map[string]interface{}{"id": float64(1), "name": "a"}},
+		{"spaces", " id , name ", map[string]interface{}{"id": float64(1), "name": "a"}},
+		{"nested", "id,address.city", map[string]interface{}{"id": float64(1), "address": map[string]interface{}{"city": "Kochi"}}},
+		{"whole and nested", "address.city,address", map[string]interface{}{"address": map[string]interface{}{"city": "Kochi", "country": "IN"}}},
+		{"array of objects", "previous.country", map[string]interface{}{"previous": []interface{}{map[string]interface{}{"country": "IN"}}}},
+		{"unknown", "id,nickname,address.zip", map[string]interface{}{"id": float64(1), "address": map[string]interface{}{}}},
+		{"path into a scalar", "name.first", map[string]interface{}{}},
+		{"json names", "created_at,CreatedAt", map[string]interface{}{"created_at": "2024"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := sparse(t, tt.fields, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, fieldsUsers[0]) })
+
+			if status != http.StatusOK || !reflect.DeepEqual(body["data"], tt.want) {
+				t.Errorf("%d data = %v, want %v", status, body["data"], tt.want)
+			}
+		})
+	}
+}
+
+func TestSparseFieldsetsCollections(t *testing.T) {
+	pagination := responsehelper.NewPagination(1, 2, 2)
+	status, body := sparse(t, "id,address.country", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, fieldsUsers, pagination)
+	})
+
+	want := []interface{}{
+		map[string]interface{}{"id": float64(1), "address": map[string]interface{}{"country": "IN"}},
+		map[string]interface{}{"id": float64(2), "address": map[string]interface{}{"country": "DE"}},
+	}
+	if status != http.StatusOK || !reflect.DeepEqual(body["data"], want) {
+		t.Errorf("%d data = %v, want %v", status, body["data"], want)
+	}
+	if page, _ := body["pagination"].(map[string]interface{}); page["totalRecords"] != float64(2) {
+		t.Errorf("pagination = %v, want it unfiltered", body["pagination"])
+	}
+}
+
+func TestSparseFieldsetsInvalid(t *testing.T) {
+	status, body := sparse(t, "id,,address..city,na me", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, fieldsUsers[0]) })
+
+	if status != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", status)
+	}
+	errorBody, _ := body["error"].(map[string]interface{})
+	errs, _ := errorBody["errors"].([]interface{})
+	if errorBody["message"] != "Invalid fields parameter" || len(errs) != 3 {
+		t.Fatalf("error = %v, want a field error per bad path", errorBody)
+	}
+	var params []interface{}
+	for _, e := range errs {
+		item, _ := e.(map[string]interface{})
+		if item["field"] != "fields" {
+			t.Errorf("field error = %v, want the fields parameter", item)
+		}
+		params = append(params, item["param"])
+	}
+	if want := []interface{}{nil, "address..city", "na me"}; !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v, want %v", params, want)
+	}
+}
+
+func TestSparseFieldsetsNotApplied(t *testing.T) {
+	for name, tt := range map[string]struct {
+		target  string
+		opts    []responsehelper.Option
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		"disabled": {"/users?fields=id", nil, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, fieldsUsers[0]) }},
+		"no parameter": {"/users", []responsehelper.Option{responsehelper.WithFieldsQueryParam("fields")},
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, fieldsUsers[0]) }},
+		"other helpers": {"/users?fields=id", []responsehelper.Option{responsehelper.WithFieldsQueryParam("fields")},
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Created(c, fieldsUsers[0]) }},
+		"JSON:API": {"/users?fields=id", []responsehelper.Option{
+			responsehelper.WithFieldsQueryParam("fields"),
+			responsehelper.WithDefaultFormat(responsehelper.FormatJSONAPI),
+		}, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, fieldsUsers[0]) }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, tt.target)
+			tt.respond(responsehelper.NewResponseHelper(tt.opts...), c)
+
+			body := decodeBody(t, w)
+			data, _ := body["data"].(map[string]interface{})
+			if attributes, ok := data["attributes"].(map[string]interface{}); ok {
+				data = attributes
+			}
+			if data["email"] != "a@example.com" {
+				t.Errorf("data = %v, want it unfiltered", body["data"])
+			}
+		})
+	}
+}
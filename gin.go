@@ -0,0 +1,190 @@
+package responsehelper
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The gin methods of the ResponseHelper render through the Core.
+
+// Deprecated: use BadRequestDetails.
+func (r *responseHelper) BadRequest(c *gin.Context, message string, details string, opts ...ResponseOption) {
+	r.Core.BadRequest(exchangeOf(c), message, details, opts...)
+}
+
+func (r *responseHelper) BadRequestDetails(c *gin.Context, message string, details interface{}, opts ...ResponseOption) {
+	r.Core.BadRequestDetails(exchangeOf(c), message, details, opts...)
+}
+
+func (r *responseHelper) AlreadyExists(c *gin.Context, resource string, err error, opts ...ResponseOption) {
+	r.Core.AlreadyExists(exchangeOf(c), resource, err, opts...)
+}
+
+func (r *responseHelper) Conflict(c *gin.Context, message string, err error, opts ...ResponseOption) {
+	r.Core.Conflict(exchangeOf(c), message, err, opts...)
+}
+
+func (r *responseHelper) NotFound(c *gin.Context, message string, opts ...ResponseOption) {
+	r.Core.NotFound(exchangeOf(c), message, opts...)
+}
+
+func (r *responseHelper) ResourceNotFound(c *gin.Context, resource string, id interface{}, opts ...ResponseOption) {
+	r.Core.ResourceNotFound(exchangeOf(c), resource, id, opts...)
+}
+
+func (r *responseHelper) Unauthorized(c *gin.Context, message string, opts ...ResponseOption) {
+	r.Core.Unauthorized(exchangeOf(c), message, opts...)
+}
+
+func (r *responseHelper) UnauthorizedWithChallenge(c *gin.Context, message, scheme, realm string, params map[string]string, opts ...ResponseOption) {
+	r.Core.UnauthorizedWithChallenge(exchangeOf(c), message, scheme, realm, params, opts...)
+}
+
+func (r *responseHelper) UnauthorizedReason(c *gin.Context, reason AuthFailureReason, message string, opts ...ResponseOption) {
+	r.Core.UnauthorizedReason(exchangeOf(c), reason, message, opts...)
+}
+
+func (r *responseHelper) OAuthError(c *gin.Context, errCode string, description string, status int) {
+	r.Core.OAuthError(exchangeOf(c), errCode, description, status)
+}
+
+func (r *responseHelper) Forbidden(c *gin.Context, message string, opts ...ResponseOption) {
+	r.Core.Forbidden(exchangeOf(c), message, opts...)
+}
+
+func (r *responseHelper) ForbiddenScope(c *gin.Context, message string, required []string, granted []string, opts ...ResponseOption) {
+	r.Core.ForbiddenScope(exchangeOf(c), message, required, granted, opts...)
+}
+
+func (r *responseHelper) TooManyRequests(c *gin.Context, message string, retryAfter time.Duration, opts ...ResponseOption) {
+	r.Core.TooManyRequests(exchangeOf(c), message, retryAfter, opts...)
+}
+
+func (r *responseHelper) ServiceUnavailable(c *gin.Context, message string, retryAfter time.Duration, opts ...ResponseOption) {
+	r.Core.ServiceUnavailable(exchangeOf(c), message, retryAfter, opts...)
+}
+
+func (r *responseHelper) InternalError(c *gin.Context, message string, err error, opts ...ResponseOption) {
+	r.Core.InternalError(exchangeOf(c), message, err, opts...)
+}
+
+func (r *responseHelper) Success(c *gin.Context, data interface{}, opts ...ResponseOption) {
+	r.Core.Success(exchangeOf(c), data, opts...)
+}
+
+func (r *responseHelper) PartialSuccess(c *gin.Context, data interface{}, failures []PartialFailure, opts ...ResponseOption) {
+	r.Core.PartialSuccess(exchangeOf(c), data, failures, opts...)
+}
+
+func (r *responseHelper) SuccessCSV(c *gin.Context, filename string, rows interface{}) {
+	r.Core.SuccessCSV(exchangeOf(c), filename, rows)
+}
+
+func (r *responseHelper) SuccessIfModified(c *gin.Context, lastModified time.Time, dataFn func() (interface{}, error), opts ...ResponseOption) {
+	r.Core.SuccessIfModified(exchangeOf(c), lastModified, dataFn, opts...)
+}
+
+func (r *responseHelper) SuccessWithPagination(c *gin.Context, data interface{}, meta interface{}, opts ...ResponseOption) {
+	r.Core.SuccessWithPagination(exchangeOf(c), data, meta, opts...)
+}
+
+func (r *responseHelper) SuccessWithCursor(c *gin.Context, data interface{}, cur CursorPagination) {
+	r.Core.SuccessWithCursor(exchangeOf(c), data, cur)
+}
+
+func (r *responseHelper) SuccessWithCount(c *gin.Context, data interface{}) {
+	r.Core.SuccessWithCount(exchangeOf(c), data)
+}
+
+func (r *responseHelper) SuccessWithLinks(c *gin.Context, data interface{}, links Links) {
+	r.Core.SuccessWithLinks(exchangeOf(c), data, links)
+}
+
+func (r *responseHelper) SuccessLarge(c *gin.Context, data interface{}) {
+	r.Core.SuccessLarge(exchangeOf(c), data)
+}
+
+func (r *responseHelper) Created(c *gin.Context, data interface{}, opts ...ResponseOption) {
+	r.Core.Created(exchangeOf(c), data, opts...)
+}
+
+func (r *responseHelper) Patched(c *gin.Context, resource string, data interface{}, changedFields []string, opts ...ResponseOption) {
+	r.Core.Patched(exchangeOf(c), resource, data, changedFields, opts...)
+}
+
+func (r *responseHelper) OperationAccepted(c *gin.Context, op Operation) {
+	r.Core.OperationAccepted(exchangeOf(c), op)
+}
+
+func (r *responseHelper) OperationStatus(c *gin.Context, op Operation) {
+	r.Core.OperationStatus(exchangeOf(c), op)
+}
+
+func (r *responseHelper) Deleted(c *gin.Context, message string) {
+	r.Core.Deleted(exchangeOf(c), message)
+}
+
+func (r *responseHelper) DeletedNoContent(c *gin.Context) {
+	r.Core.DeletedNoContent(exchangeOf(c))
+}
+
+func (r *responseHelper) NoContent(c *gin.Context) {
+	r.Core.NoContent(exchangeOf(c))
+}
+
+func (r *responseHelper) RespondAPIError(c *gin.Context, err *APIError, opts ...ResponseOption) {
+	r.Core.RespondAPIError(exchangeOf(c), err, opts...)
+}
+
+func (r *responseHelper) Respond(c *gin.Context, err error, data interface{}, opts ...ResponseOption) {
+	r.Core.Respond(exchangeOf(c), err, data, opts...)
+}
+
+func (r *responseHelper) Errors(c *gin.Context, statusCode int, errs []ErrorItem, opts ...ResponseOption) {
+	r.Core.Errors(exchangeOf(c), statusCode, errs, opts...)
+}
+
+func (r *responseHelper) Problem(c *gin.Context, status int, typ, title, detail string, extensions map[string]interface{}) {
+	r.Core.Problem(exchangeOf(c), status, typ, title, detail, extensions)
+}
+
+func (r *responseHelper) WriteJSON(c *gin.Context, status int, body interface{}, opts ...ResponseOption) {
+	r.Core.WriteJSON(exchangeOf(c), status, body, opts...)
+}
+
+func (r *responseHelper) WriteError(c *gin.Context, status int, errBody ErrorBody, opts ...ResponseOption) {
+	r.Core.WriteError(exchangeOf(c), status, errBody, opts...)
+}
+
+func (r *responseHelper) ValidationFailed(c *gin.Context, err error, opts ...ResponseOption) {
+	r.Core.ValidationFailed(exchangeOf(c), err, opts...)
+}
+
+func (r *responseHelper) NotFoundKey(c *gin.Context, key string, args ...interface{}) {
+	r.Core.NotFoundKey(exchangeOf(c), key, args...)
+}
+
+func (r *responseHelper) BadRequestKey(c *gin.Context, key string, details string, args ...interface{}) {
+	r.Core.BadRequestKey(exchangeOf(c), key, details, args...)
+}
+
+func (r *responseHelper) UnauthorizedKey(c *gin.Context, key string, args ...interface{}) {
+	r.Core.UnauthorizedKey(exchangeOf(c), key, args...)
+}
+
+func (r *responseHelper) ForbiddenKey(c *gin.Context, key string, args ...interface{}) {
+	r.Core.ForbiddenKey(exchangeOf(c), key, args...)
+}
+
+func (r *responseHelper) ConflictKey(c *gin.Context, key string, err error, args ...interface{}) {
+	r.Core.ConflictKey(exchangeOf(c), key, err, args...)
+}
+
+func (r *responseHelper) InternalErrorKey(c *gin.Context, key string, err error, args ...interface{}) {
+	r.Core.InternalErrorKey(exchangeOf(c), key, err, args...)
+}
+
+func (r *responseHelper) RespondCode(c *gin.Context, code string, args ...interface{}) {
+	r.Core.RespondCode(exchangeOf(c), code, args...)
+}
@@ -0,0 +1,124 @@
+package responsehelper
+
+import (
+	"net/http"
+	"strings"
+)
+
+// GoogleErrorDocument is the body of an error response under FormatGoogle,
+// the shape of the errors of Google's JSON APIs.
+type GoogleErrorDocument struct {
+	Error GoogleErrorBody `json:"error"`
+}
+
+// GoogleErrorBody is the "error" member of a GoogleErrorDocument.
+type GoogleErrorBody struct {
+	// Code is the HTTP status code.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Status is the canonical status name, eg: "NOT_FOUND", see googleStatus.
+	Status string            `json:"status"`
+	Errors []GoogleErrorItem `json:"errors"`
+}
+
+// GoogleErrorItem is an entry of GoogleErrorBody.Errors.
+type GoogleErrorItem struct {
+	// Domain is always "global".
+	Domain string `json:"domain"`
+	// Reason is "invalid" for field errors, the errorCode or the status in
+	// lowerCamelCase otherwise, eg: "notFound".
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Location is the request field the error refers to, if any.
+	Location string `json:"location,omitempty"`
+	// LocationType is "parameter" when Location is set.
+	LocationType string `json:"locationType,omitempty"`
+}
+
+// googleStatuses are the canonical status names of the HTTP statuses they
+// map to one to one.
+var googleStatuses = map[int]string{
+	http.StatusBadRequest:                   "INVALID_ARGUMENT",
+	http.StatusUnauthorized:                 "UNAUTHENTICATED",
+	http.StatusForbidden:                    "PERMISSION_DENIED",
+	http.StatusNotFound:                     "NOT_FOUND",
+	http.StatusConflict:                     "ALREADY_EXISTS",
+	http.StatusPreconditionFailed:           "FAILED_PRECONDITION",
+	http.StatusRequestedRangeNotSatisfiable: "OUT_OF_RANGE",
+	http.StatusUnprocessableEntity:          "INVALID_ARGUMENT",
+	http.StatusTooManyRequests:              "RESOURCE_EXHAUSTED",
+	499:                                     "CANCELLED",
+	http.StatusInternalServerError:          "INTERNAL",
+	http.StatusNotImplemented:               "UNIMPLEMENTED",
+	http.StatusServiceUnavailable:           "UNAVAILABLE",
+	http.StatusGatewayTimeout:               "DEADLINE_EXCEEDED",
+}
+
+// googleStatus returns the canonical status name of code, eg: 404 ->
+// "NOT_FOUND". Other 4xx statuses are "FAILED_PRECONDITION", other 5xx
+// statuses "INTERNAL".
+func googleStatus(code int) string {
+	if status, ok := googleStatuses[code]; ok {
+		return status
+	}
+	if code < http.StatusInternalServerError {
+		return "FAILED_PRECONDITION"
+	}
+	return "INTERNAL"
+}
+
+// googleError returns errorBody in the shape of a Google API error. Field
+// errors and ErrorItems become one entry of "errors" each, any other error a
+// single entry repeating the message.
+func googleError(errorBody *ErrorBody) *GoogleErrorDocument {
+	status := googleStatus(errorBody.Code)
+	reason := errorBody.ErrorCode
+	if reason == "" {
+		reason = lowerCamelCase(status)
+	}
+	body := GoogleErrorBody{
+		Code:    errorBody.Code,
+		Message: errorBody.Message,
+		Status:  status,
+	}
+	switch items := errorBody.Errors.(type) {
+	case []FieldError:
+		for _, item := range items {
+			body.Errors = append(body.Errors, googleErrorItem("invalid", item.Message, item.Field))
+		}
+	case []ErrorItem:
+		for _, item := range items {
+			itemReason := item.Code
+			if itemReason == "" && item.Field != "" {
+				itemReason = "invalid"
+			} else if itemReason == "" {
+				itemReason = reason
+			}
+			body.Errors = append(body.Errors, googleErrorItem(itemReason, item.Message, item.Field))
+		}
+	}
+	if len(body.Errors) == 0 {
+		body.Errors = []GoogleErrorItem{googleErrorItem(reason, errorBody.Message, "")}
+	}
+	return &GoogleErrorDocument{Error: body}
+}
+
+func googleErrorItem(reason, message, location string) GoogleErrorItem {
+	item := GoogleErrorItem{Domain: "global", Reason: reason, Message: message}
+	if location != "" {
+		item.Location, item.LocationType = location, "parameter"
+	}
+	return item
+}
+
+// lowerCamelCase returns a SCREAMING_SNAKE_CASE name in lowerCamelCase, eg:
+// "PERMISSION_DENIED" -> "permissionDenied".
+func lowerCamelCase(name string) string {
+	words := strings.Split(strings.ToLower(name), "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,85 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// TestGoogleErrorGolden pins the errors of FormatGoogle: field errors, a
+// resource that was not found and a server error.
+func TestGoogleErrorGolden(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatGoogle))
+	for _, tt := range []struct {
+		name    string
+		status  int
+		respond func(t *testing.T) *httptest.ResponseRecorder
+	}{
+		{"400-fields", http.StatusBadRequest, func(t *testing.T) *httptest.ResponseRecorder {
+			c, w, req, err := bindOrder(t)
+			h.ValidationFailed(c, err, responsehelper.BoundTo(req))
+			return w
+		}},
+		{"404", http.StatusNotFound, func(t *testing.T) *httptest.ResponseRecorder {
+			c, w := newContext(http.MethodGet, "/users/42")
+			h.ResourceNotFound(c, "user", 42)
+			return w
+		}},
+		{"500", http.StatusInternalServerError, func(t *testing.T) *httptest.ResponseRecorder {
+			c, w := newContext(http.MethodGet, "/users/42")
+			h.InternalError(c, "An unexpected error occurred", errors.New("db down"))
+			return w
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.respond(t)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want JSON", got)
+			}
+			goldenBytes(t, w.Body.Bytes(), filepath.Join("testdata", "google", tt.name+".json"))
+		})
+	}
+}
+
+func TestGoogleErrorStatuses(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatGoogle))
+	for status, want := range map[int]string{
+		http.StatusUnauthorized:            "UNAUTHENTICATED",
+		http.StatusForbidden:               "PERMISSION_DENIED",
+		http.StatusConflict:                "ALREADY_EXISTS",
+		http.StatusTooManyRequests:         "RESOURCE_EXHAUSTED",
+		http.StatusServiceUnavailable:      "UNAVAILABLE",
+		http.StatusGone:                    "FAILED_PRECONDITION",
+		http.StatusHTTPVersionNotSupported: "INTERNAL",
+	} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		h.RespondAPIError(c, responsehelper.NewAPIError(status, "Failed", nil))
+
+		errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+		if errorBody["status"] != want || errorBody["code"] != float64(status) {
+			t.Errorf("%d: error = %v, want status %s", status, errorBody, want)
+		}
+	}
+}
+
+func TestGoogleFormatKeepsTheSuccessEnvelope(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatGoogle)).
+		Success(c, map[string]int{"id": 42})
+
+	body := decodeBody(t, w)
+	if body["success"] != true || body["data"].(map[string]interface{})["id"] != float64(42) {
+		t.Errorf("body = %s, want the native envelope", w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want JSON", got)
+	}
+}
@@ -0,0 +1,100 @@
+// Package grpcerror renders gRPC status errors with the responsehelper error
+// envelope.
+package grpcerror
+
+import (
+	"net/http"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPStatus maps a canonical gRPC code to the HTTP status sent to the client.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// RespondGRPCError renders err, as returned by a gRPC client, with the
+// standard error envelope.
+//
+// The gRPC message is only used as the user facing message when error
+// sanitization is disabled, and errdetails.BadRequest field violations are
+// rendered as the "errors" array. A nil err, which is not a gRPC failure, is
+// rendered as an internal error like a nil *APIError given to
+// RespondAPIError.
+//
+// Example:
+//
+//	user, err := h.userClient.GetUser(ctx, req)
+//	if err != nil {
+//		grpcerror.RespondGRPCError(h.responseHelper, c, err)
+//		return
+//	}
+func RespondGRPCError(h responsehelper.ResponseHelper, c *gin.Context, err error) {
+	if err == nil {
+		h.RespondAPIError(c, nil)
+		return
+	}
+	st, _ := status.FromError(err)
+	h.RespondAPIError(c, &responsehelper.APIError{
+		Status:      HTTPStatus(st.Code()),
+		FieldErrors: fieldErrors(st),
+		Err:         &statusError{st: st},
+	})
+}
+
+// fieldErrors converts the BadRequest field violations attached to st.
+func fieldErrors(st *status.Status) []responsehelper.FieldError {
+	var fieldErrors []responsehelper.FieldError
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, violation := range badRequest.GetFieldViolations() {
+			fieldErrors = append(fieldErrors, responsehelper.FieldError{
+				Field:   violation.GetField(),
+				Tag:     "invalid",
+				Message: violation.GetDescription(),
+			})
+		}
+	}
+	return fieldErrors
+}
+
+// statusError exposes only the message of a gRPC status as its error text,
+// while status.FromError and status.Code keep working on it.
+type statusError struct {
+	st *status.Status
+}
+
+func (e *statusError) Error() string {
+	return e.st.Message()
+}
+
+// GRPCStatus returns the wrapped gRPC status.
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.st
+}
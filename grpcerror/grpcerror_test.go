@@ -0,0 +1,143 @@
+package grpcerror_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/grpcerror"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func respond(h responsehelper.ResponseHelper, err error) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	grpcerror.RespondGRPCError(h, c, err)
+	return w
+}
+
+// envelope is the part of the error envelope the tests check.
+type envelope struct {
+	Success bool `json:"success"`
+	Error   struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) envelope {
+	t.Helper()
+	var body envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return body
+}
+
+func TestHTTPStatus(t *testing.T) {
+	for code, want := range map[codes.Code]int{
+		codes.InvalidArgument:   http.StatusBadRequest,
+		codes.Unauthenticated:   http.StatusUnauthorized,
+		codes.PermissionDenied:  http.StatusForbidden,
+		codes.NotFound:          http.StatusNotFound,
+		codes.AlreadyExists:     http.StatusConflict,
+		codes.ResourceExhausted: http.StatusTooManyRequests,
+		codes.Unavailable:       http.StatusServiceUnavailable,
+		codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+		codes.Internal:          http.StatusInternalServerError,
+		codes.Unknown:           http.StatusInternalServerError,
+		codes.DataLoss:          http.StatusInternalServerError,
+	} {
+		if got := grpcerror.HTTPStatus(code); got != want {
+			t.Errorf("HTTPStatus(%s) = %d, want %d", code, got, want)
+		}
+	}
+}
+
+func TestRespondGRPCError(t *testing.T) {
+	w := respond(responsehelper.NewResponseHelper(), status.Error(codes.NotFound, "user 42 not found"))
+
+	body := decode(t, w)
+	if w.Code != http.StatusNotFound || body.Error.Code != http.StatusNotFound {
+		t.Errorf("status = %d, error.code = %d, want %d", w.Code, body.Error.Code, http.StatusNotFound)
+	}
+	if body.Error.Message != "user 42 not found" {
+		t.Errorf("error.message = %q, want the status message", body.Error.Message)
+	}
+}
+
+func TestRespondGRPCErrorWrapped(t *testing.T) {
+	err := fmt.Errorf("loading user: %w", status.Error(codes.PermissionDenied, "denied"))
+	w := respond(responsehelper.NewResponseHelper(), err)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRespondGRPCErrorSanitized(t *testing.T) {
+	w := respond(responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)), status.Error(codes.AlreadyExists, "duplicate key users_email_key"))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if got := decode(t, w).Error.Message; got != http.StatusText(http.StatusConflict) {
+		t.Errorf("error.message = %q, want %q", got, http.StatusText(http.StatusConflict))
+	}
+}
+
+func TestRespondGRPCErrorFieldViolations(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid user").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "email", Description: "email is not valid"},
+			{Field: "name", Description: "name is required"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := respond(responsehelper.NewResponseHelper(), st.Err())
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	body := decode(t, w)
+	if body.Error.Message != "invalid user" {
+		t.Errorf("error.message = %q, want %q", body.Error.Message, "invalid user")
+	}
+	if len(body.Error.Errors) != 2 {
+		t.Fatalf("%d errors, want one per violation\nbody: %s", len(body.Error.Errors), w.Body)
+	}
+	if got := body.Error.Errors[0]; got.Field != "email" || got.Message != "email is not valid" {
+		t.Errorf("errors[0] = %+v, want the email violation", got)
+	}
+	if got := body.Error.Errors[1].Field; got != "name" {
+		t.Errorf("errors[1].field = %q, want %q", got, "name")
+	}
+}
+
+func TestRespondGRPCErrorNil(t *testing.T) {
+	w := respond(responsehelper.NewResponseHelper(), nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d\nbody: %s", w.Code, http.StatusInternalServerError, w.Body)
+	}
+	if decode(t, w).Success {
+		t.Errorf("success = true for a nil error\nbody: %s", w.Body)
+	}
+}
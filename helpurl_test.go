@@ -0,0 +1,73 @@
+package responsehelper_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestHelpURLTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		template string
+		want     string
+	}{
+		{"https://docs.example.com/errors/{status}", "https://docs.example.com/errors/404"},
+		{"https://docs.example.com/errors/{code}", "https://docs.example.com/errors/NOT_FOUND"},
+	} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		responsehelper.NewResponseHelper(responsehelper.WithHelpURLTemplate(tc.template)).NotFound(c, "missing")
+
+		assertField(t, w, "error.helpUrl", tc.want)
+	}
+}
+
+func TestHelpURLTemplateErrorCode(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	h := responsehelper.NewResponseHelper(responsehelper.WithHelpURLTemplate("https://docs.example.com/errors/{code}?status={status}"))
+	h.RespondAPIError(c, responsehelper.ErrForbidden.WithCode("USER SUSPENDED"))
+
+	assertField(t, w, "error.helpUrl", "https://docs.example.com/errors/USER%20SUSPENDED?status=403")
+}
+
+func TestHelpURLOverridesTheTemplate(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithHelpURLTemplate("https://docs.example.com/errors/{status}")).
+		NotFound(c, "missing", responsehelper.WithHelpURL("https://runbooks.example.com/users"))
+
+	assertField(t, w, "error.helpUrl", "https://runbooks.example.com/users")
+}
+
+func TestHelpURLAbsent(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper().NotFound(c, "missing")
+
+	if helpURL, ok := decodeBody(t, w)["error"].(map[string]interface{})["helpUrl"]; ok {
+		t.Errorf("error.helpUrl = %v, want no helpUrl", helpURL)
+	}
+}
+
+func TestHelpURLInvalidTemplate(t *testing.T) {
+	for _, template := range []string{
+		"https://docs.example.com/errors/{id}",
+		"/errors/{status}",
+	} {
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+		h := responsehelper.NewResponseHelper(responsehelper.WithLogger(logger), responsehelper.WithHelpURLTemplate(template))
+		for i := 0; i < 3; i++ {
+			c, w := newContext(http.MethodGet, "/users/42")
+			h.NotFound(c, "missing")
+
+			if helpURL, ok := decodeBody(t, w)["error"].(map[string]interface{})["helpUrl"]; ok {
+				t.Errorf("template %q: error.helpUrl = %v, want no helpUrl", template, helpURL)
+			}
+		}
+		if got := strings.Count(logs.String(), "ignoring help URL template"); got != 1 {
+			t.Errorf("template %q logged %d times, want once:\n%s", template, got, logs.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package responsehelper
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseInfo describes a response sent by the helper.
+type ResponseInfo struct {
+	// Status is the HTTP status of the response.
+	Status int
+	// Method is the name of the helper method that sent it, eg: "NotFound".
+	Method string
+	// BytesWritten is the size of the written body.
+	BytesWritten int
+	// ErrorCode is the errorCode of the error, if any.
+	ErrorCode string
+	// Err is the error passed to the helper, before sanitization.
+	Err error
+	// Duration is the time spent on the request, see WithRequestDuration.
+	Duration time.Duration
+	// Skipped tells that the response was not written as the client
+	// disconnected, see WithSkipOnClientGone.
+	Skipped bool
+}
+
+// ResponseHook is called after the helper wrote a response.
+type ResponseHook func(c *gin.Context, info ResponseInfo)
+
+// WithOnResponse adds a hook called after every response the helper writes,
+// eg: for analytics. Hooks run synchronously in the order they were added, a
+// panicking hook is recovered and logged.
+//
+// Example:
+//
+//	responsehelper.WithOnResponse(func(c *gin.Context, info responsehelper.ResponseInfo) {
+//		analytics.Track(info.Method, info.Status, info.BytesWritten)
+//	})
+func WithOnResponse(hook ResponseHook) Option {
+	return func(cfg *config) {
+		if hook != nil {
+			cfg.responseHooks = append(cfg.responseHooks, hook)
+		}
+	}
+}
+
+// runResponseHooks calls the response hooks with info. written is the body
+// size before the response was written.
+func (cfg *config) runResponseHooks(c Exchange, info ResponseInfo, written int) {
+	if len(cfg.responseHooks) == 0 || detached(c) {
+		return
+	}
+	info.BytesWritten = max(c.Size(), 0) - max(written, 0)
+	info.Duration, _ = cfg.measureDuration(c)
+	for _, hook := range cfg.responseHooks {
+		cfg.runResponseHook(hook, c, info)
+	}
+}
+
+func (cfg *config) runResponseHook(hook ResponseHook, c Exchange, info ResponseInfo) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			cfg.warnf("response hook panicked: %v", recovered)
+		}
+	}()
+	hook(ginContextOf(c), info)
+}
@@ -0,0 +1,78 @@
+package responsehelper_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// hookRecorder records the ResponseInfo of every call of its hook.
+type hookRecorder struct {
+	calls []responsehelper.ResponseInfo
+}
+
+func (r *hookRecorder) hook(c *gin.Context, info responsehelper.ResponseInfo) {
+	r.calls = append(r.calls, info)
+}
+
+func TestOnResponse(t *testing.T) {
+	var recorder hookRecorder
+	h := responsehelper.NewResponseHelper(responsehelper.WithOnResponse(recorder.hook))
+	boom := errors.New("boom")
+
+	c, w := newContext(http.MethodGet, "/users/42")
+	h.NotFound(c, "missing")
+	c, _ = newContext(http.MethodPost, "/users")
+	h.Created(c, gin.H{"id": 1})
+	c, _ = newContext(http.MethodGet, "/users")
+	h.InternalError(c, "oops", boom)
+	c, _ = newContext(http.MethodGet, "/users")
+	h.RespondAPIError(c, responsehelper.ErrConflict.WithCode("TAKEN"))
+
+	if len(recorder.calls) != 4 {
+		t.Fatalf("%d hook calls, want 4: %+v", len(recorder.calls), recorder.calls)
+	}
+	for i, want := range []responsehelper.ResponseInfo{
+		{Status: http.StatusNotFound, Method: "NotFound", BytesWritten: w.Body.Len()},
+		{Status: http.StatusCreated, Method: "Created"},
+		{Status: http.StatusInternalServerError, Method: "InternalError", Err: boom},
+		{Status: http.StatusConflict, Method: "RespondAPIError", ErrorCode: "TAKEN"},
+	} {
+		got := recorder.calls[i]
+		if got.Status != want.Status || got.Method != want.Method || got.ErrorCode != want.ErrorCode || want.Err != nil && !errors.Is(got.Err, want.Err) {
+			t.Errorf("calls[%d] = %+v, want %+v", i, got, want)
+		}
+		if got.BytesWritten <= 0 || want.BytesWritten != 0 && got.BytesWritten != want.BytesWritten {
+			t.Errorf("calls[%d].BytesWritten = %d, want %d", i, got.BytesWritten, want.BytesWritten)
+		}
+	}
+}
+
+func TestOnResponseOrderAndPanics(t *testing.T) {
+	var logs bytes.Buffer
+	var order []string
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		responsehelper.WithOnResponse(func(*gin.Context, responsehelper.ResponseInfo) { order = append(order, "first") }),
+		responsehelper.WithOnResponse(func(*gin.Context, responsehelper.ResponseInfo) { panic("broken analytics") }),
+		responsehelper.WithOnResponse(func(*gin.Context, responsehelper.ResponseInfo) { order = append(order, "third") }),
+	)
+	c, w := newContext(http.MethodGet, "/users")
+	h.Success(c, nil)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if strings.Join(order, ",") != "first,third" {
+		t.Errorf("hooks ran as %v, want first,third", order)
+	}
+	if !strings.Contains(logs.String(), "response hook panicked: broken analytics") {
+		t.Errorf("the panic was not logged:\n%s", logs.String())
+	}
+}
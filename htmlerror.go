@@ -0,0 +1,100 @@
+package responsehelper
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+// MIMEHTML is the content type of the HTML error pages.
+const MIMEHTML = "text/html"
+
+// ErrorPage is the data the template of WithHTMLErrorFallback is executed with.
+type ErrorPage struct {
+	// Status is the HTTP status code, eg: 404.
+	Status int
+	// StatusText is the text of the status, eg: "Not Found".
+	StatusText string
+	// Message is the message of the error envelope.
+	Message string
+	// RequestID is the ID of the request, empty without MetaMiddleware.
+	RequestID string
+	// ErrorID identifies a server error in the logs, empty for 4xx errors.
+	ErrorID string
+	// HelpURL links to the documentation of the error, if any.
+	HelpURL string
+}
+
+// DefaultErrorPageTemplate is the error page used by WithHTMLErrorFallback(nil).
+var DefaultErrorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.StatusText}}</title>
+</head>
+<body>
+<h1>{{.Status}} {{.StatusText}}</h1>
+<p>{{.Message}}</p>
+{{- if .HelpURL}}
+<p><a href="{{.HelpURL}}">More about this error</a></p>
+{{- end}}
+{{- if or .RequestID .ErrorID}}
+<p><small>
+{{- if .RequestID}}Request ID: <code>{{.RequestID}}</code>{{end}}
+{{- if and .RequestID .ErrorID}}<br>{{end}}
+{{- if .ErrorID}}Error ID: <code>{{.ErrorID}}</code>{{end -}}
+</small></p>
+{{- end}}
+</body>
+</html>
+`))
+
+// WithHTMLErrorFallback renders error responses as an HTML page from tmpl,
+// executed with an ErrorPage, when a browser prefers text/html over
+// application/json, eg: a person opening an API URL. DefaultErrorPageTemplate
+// is used when tmpl is nil.
+//
+// JSON stays the response when the Accept header likes both equally, eg:
+// "*/*", and for clients whose User-Agent does not start with "Mozilla/",
+// which all browsers send, so scripts and API clients always get JSON.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithHTMLErrorFallback(nil))
+func WithHTMLErrorFallback(tmpl *template.Template) Option {
+	return func(cfg *config) {
+		if tmpl == nil {
+			tmpl = DefaultErrorPageTemplate
+		}
+		cfg.errorPage = tmpl
+	}
+}
+
+// prefersHTML reports whether the error response should be an HTML page.
+func (cfg *config) prefersHTML(c Exchange) bool {
+	if cfg.errorPage == nil || c.Request() == nil {
+		return false
+	}
+	addVary(c, "Accept", "User-Agent")
+	if !strings.HasPrefix(c.Request().UserAgent(), "Mozilla/") {
+		return false
+	}
+	return negotiate(requestHeader(c, "Accept"), MIMEJSON, MIMEHTML) == MIMEHTML
+}
+
+// writeErrorPage renders the HTML page of an error response.
+func (cfg *config) writeErrorPage(c Exchange, status int, errorBody *ErrorBody) {
+	page := ErrorPage{
+		Status:     status,
+		StatusText: http.StatusText(status),
+		Message:    errorBody.Message,
+		RequestID:  requestID(c),
+		ErrorID:    errorBody.ErrorID,
+		HelpURL:    errorBody.HelpURL,
+	}
+	if err := renderTo(c, status, render.HTML{Template: cfg.errorPage, Data: page}); err != nil {
+		cfg.warnf("rendering the error page: %v", err)
+	}
+}
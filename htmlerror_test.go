@@ -0,0 +1,109 @@
+package responsehelper_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+const browserUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+
+func htmlErrorEngine(tmpl *template.Template) *gin.Engine {
+	h := responsehelper.NewResponseHelper(responsehelper.WithHTMLErrorFallback(tmpl))
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set(responsehelper.RequestIDKey, "req-1")
+	})
+	engine.GET("/users/42", func(c *gin.Context) { h.Success(c, gin.H{"id": 42}) })
+	engine.GET("/users/0", func(c *gin.Context) {
+		h.NotFound(c, "User <0> not found", responsehelper.WithHelpURL("https://docs.example.com/errors/not-found"))
+	})
+	return engine
+}
+
+func TestHTMLErrorFallbackNegotiation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		accept    string
+		userAgent string
+		html      bool
+	}{
+		{"browser", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", browserUserAgent, true},
+		{"html only", "text/html", browserUserAgent, true},
+		{"any", "*/*", browserUserAgent, false},
+		{"no accept", "", browserUserAgent, false},
+		{"json preferred", "text/html;q=0.1, application/json", browserUserAgent, false},
+		{"html preferred by q", "application/json;q=0.5, text/html", browserUserAgent, true},
+		{"text wildcard", "text/*", browserUserAgent, true},
+		{"html refused", "text/html;q=0, */*", browserUserAgent, false},
+		{"equal q", "application/json, text/html", browserUserAgent, false},
+		{"uppercase", "TEXT/HTML", browserUserAgent, true},
+		{"curl", "text/html", "curl/8.4.0", false},
+		{"no user agent", "text/html", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/0", nil)
+			r.Header.Set("Accept", tc.accept)
+			r.Header.Set("User-Agent", tc.userAgent)
+			w := serve(htmlErrorEngine(nil), r)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want 404", w.Code)
+			}
+			html := strings.HasPrefix(w.Header().Get("Content-Type"), "text/html")
+			if html != tc.html {
+				t.Errorf("Content-Type = %q, want HTML %t", w.Header().Get("Content-Type"), tc.html)
+			}
+			if got := w.Header().Values("Vary"); strings.Join(got, ", ") != "Accept, User-Agent" {
+				t.Errorf("Vary = %q, want Accept and User-Agent", got)
+			}
+		})
+	}
+}
+
+func TestHTMLErrorFallbackPage(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/0", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("User-Agent", browserUserAgent)
+	w := serve(htmlErrorEngine(nil), r)
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"<title>404 Not Found</title>",
+		"<p>User &lt;0&gt; not found</p>",
+		`<a href="https://docs.example.com/errors/not-found">`,
+		"Request ID: <code>req-1</code>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("the page has no %s\n%s", want, body)
+		}
+	}
+}
+
+func TestHTMLErrorFallbackCustomTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("error").Parse(`{{.Status}}|{{.Message}}|{{.RequestID}}`))
+	r := httptest.NewRequest(http.MethodGet, "/users/0", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("User-Agent", browserUserAgent)
+	w := serve(htmlErrorEngine(tmpl), r)
+
+	if want := "404|User &lt;0&gt; not found|req-1"; w.Body.String() != want {
+		t.Errorf("body = %q, want %q", w.Body, want)
+	}
+}
+
+func TestHTMLErrorFallbackLeavesSuccessAlone(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("User-Agent", browserUserAgent)
+	w := serve(htmlErrorEngine(nil), r)
+
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want JSON for a success", got)
+	}
+}
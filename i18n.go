@@ -0,0 +1,160 @@
+package responsehelper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleKey is the gin context key a middleware can set to force the locale
+// of a request. It takes precedence over the Accept-Language header.
+const LocaleKey = "locale"
+
+// TranslatorFunc resolves a message key to the message sent to the client.
+type TranslatorFunc func(c *gin.Context, key string, args ...interface{}) string
+
+// Catalog is a simple TranslatorFunc implementation backed by a map of
+// locale to message key to message.
+//
+// Example:
+//
+//	catalog := responsehelper.NewCatalog("en", map[string]map[string]string{
+//		"en": {"errors.user.not_found": "User %s not found"},
+//		"de": {"errors.user.not_found": "Benutzer %s nicht gefunden"},
+//	})
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithTranslator(catalog.Translate))
+type Catalog struct {
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// NewCatalog creates a Catalog that falls back to defaultLocale when the
+// requested locale has no message for a key.
+func NewCatalog(defaultLocale string, messages map[string]map[string]string) *Catalog {
+	normalized := make(map[string]map[string]string, len(messages))
+	for locale, localeMessages := range messages {
+		normalized[normalizeLocale(locale)] = localeMessages
+	}
+	return &Catalog{defaultLocale: normalizeLocale(defaultLocale), messages: normalized}
+}
+
+// Translate returns the message for key in the locale of the request,
+// formatted with args. The key itself is returned when no message exists. A
+// nil c, eg: for a response sent through another Exchange, gets the default
+// locale.
+func (cat *Catalog) Translate(c *gin.Context, key string, args ...interface{}) string {
+	var locales []string
+	if c != nil {
+		locales = requestLocales(exchangeOf(c))
+	}
+	for _, locale := range append(locales, cat.defaultLocale) {
+		if message, ok := cat.lookup(locale, key); ok {
+			return formatMessage(message, args...)
+		}
+	}
+	return key
+}
+
+// lookup finds key in locale, falling back from a region ("de-at") to its language ("de").
+func (cat *Catalog) lookup(locale, key string) (string, bool) {
+	if message, ok := cat.messages[locale][key]; ok {
+		return message, true
+	}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		message, ok := cat.messages[language][key]
+		return message, ok
+	}
+	return "", false
+}
+
+// translate resolves key with the configured translator. Without one, or
+// when the translator has no message, the key is used as the message.
+func (cfg *config) translate(c Exchange, key string, args ...interface{}) string {
+	if cfg.translator == nil {
+		return key
+	}
+	if message := cfg.translator(ginContextOf(c), key, args...); message != "" {
+		return message
+	}
+	return key
+}
+
+// formatMessage formats message with args, if any.
+func formatMessage(message string, args ...interface{}) string {
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// requestLocales returns the locales preferred by the request, the context
+// locale first and then the Accept-Language header ordered by quality.
+func requestLocales(c Exchange) []string {
+	var locales []string
+	if locale := getString(c, LocaleKey); locale != "" {
+		locales = append(locales, normalizeLocale(locale))
+	}
+	if c.Request() == nil {
+		return locales
+	}
+	addVary(c, "Accept-Language")
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+	var accepted []weighted
+	for _, part := range strings.Split(requestHeader(c, "Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, weighted{normalizeLocale(tag), quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+	for _, a := range accepted {
+		locales = append(locales, a.locale)
+	}
+	return locales
+}
+
+// normalizeLocale lower cases a locale and uses "-" as separator, eg: "de_AT" -> "de-at".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func (r *Core) BadRequestKey(c Exchange, key string, details string, args ...interface{}) {
+	r.BadRequest(c, r.translate(c, key, args...), details, helperCall(nil, "BadRequestKey", nil)...)
+}
+
+func (r *Core) NotFoundKey(c Exchange, key string, args ...interface{}) {
+	r.NotFound(c, r.translate(c, key, args...), helperCall(nil, "NotFoundKey", nil)...)
+}
+
+func (r *Core) UnauthorizedKey(c Exchange, key string, args ...interface{}) {
+	r.Unauthorized(c, r.translate(c, key, args...), helperCall(nil, "UnauthorizedKey", nil)...)
+}
+
+func (r *Core) ForbiddenKey(c Exchange, key string, args ...interface{}) {
+	r.Forbidden(c, r.translate(c, key, args...), helperCall(nil, "ForbiddenKey", nil)...)
+}
+
+func (r *Core) ConflictKey(c Exchange, key string, err error, args ...interface{}) {
+	r.Conflict(c, r.translate(c, key, args...), err, helperCall(nil, "ConflictKey", err)...)
+}
+
+func (r *Core) InternalErrorKey(c Exchange, key string, err error, args ...interface{}) {
+	r.InternalError(c, r.translate(c, key, args...), err, helperCall(nil, "InternalErrorKey", err)...)
+}
@@ -0,0 +1,82 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var catalog = responsehelper.NewCatalog("en", map[string]map[string]string{
+	"en": {
+		"errors.user.not_found": "User %s not found",
+		"errors.conflict":       "Already taken",
+	},
+	"de": {"errors.user.not_found": "Benutzer %s nicht gefunden"},
+	"ml": {"errors.user.not_found": "ഉപയോക്താവ് %s കണ്ടെത്തിയില്ല"},
+})
+
+func TestNotFoundKeyAcceptLanguage(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithTranslator(catalog.Translate))
+	for header, want := range map[string]string{
+		"":                       "User arun not found",
+		"de":                     "Benutzer arun nicht gefunden",
+		"de-AT":                  "Benutzer arun nicht gefunden",
+		"fr, ml;q=0.9, de;q=0.5": "ഉപയോക്താവ് arun കണ്ടെത്തിയില്ല",
+		"fr":                     "User arun not found",
+	} {
+		c, w := newContext(http.MethodGet, "/users/arun")
+		c.Request.Header.Set("Accept-Language", header)
+		h.NotFoundKey(c, "errors.user.not_found", "arun")
+
+		assertError(t, w, http.StatusNotFound, want)
+	}
+}
+
+func TestContextLocaleWins(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/arun")
+	c.Request.Header.Set("Accept-Language", "de")
+	c.Set(responsehelper.LocaleKey, "ml")
+	responsehelper.NewResponseHelper(responsehelper.WithTranslator(catalog.Translate)).NotFoundKey(c, "errors.user.not_found", "arun")
+
+	assertError(t, w, http.StatusNotFound, "ഉപയോക്താവ് arun കണ്ടെത്തിയില്ല")
+}
+
+func TestMissingKeyRendersTheKey(t *testing.T) {
+	for name, h := range map[string]responsehelper.ResponseHelper{
+		"catalog":       responsehelper.NewResponseHelper(responsehelper.WithTranslator(catalog.Translate)),
+		"no translator": responsehelper.NewResponseHelper(),
+		"empty message": responsehelper.NewResponseHelper(responsehelper.WithTranslator(func(*gin.Context, string, ...interface{}) string { return "" })),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/arun")
+			h.ForbiddenKey(c, "errors.missing")
+
+			assertError(t, w, http.StatusForbidden, "errors.missing")
+		})
+	}
+}
+
+func TestKeyVariants(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithTranslator(catalog.Translate))
+	for _, tc := range []struct {
+		name   string
+		status int
+		call   func(c *gin.Context)
+	}{
+		{"BadRequestKey", http.StatusBadRequest, func(c *gin.Context) { h.BadRequestKey(c, "errors.conflict", "") }},
+		{"UnauthorizedKey", http.StatusUnauthorized, func(c *gin.Context) { h.UnauthorizedKey(c, "errors.conflict") }},
+		{"ForbiddenKey", http.StatusForbidden, func(c *gin.Context) { h.ForbiddenKey(c, "errors.conflict") }},
+		{"ConflictKey", http.StatusConflict, func(c *gin.Context) { h.ConflictKey(c, "errors.conflict", errors.New("duplicate")) }},
+		{"InternalErrorKey", http.StatusInternalServerError, func(c *gin.Context) { h.InternalErrorKey(c, "errors.conflict", errors.New("boom")) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/arun")
+			tc.call(c)
+
+			assertError(t, w, tc.status, "Already taken")
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package responsehelper
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key clients retry a mutation with.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on the responses Idempotency
+	// replays.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// DefaultIdempotencyTTL is how long Idempotency keeps a response when
+	// WithIdempotencyTTL is not used.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// defaultIdempotencyCapacity is the number of responses a
+	// MemoryIdempotencyStore keeps when its capacity is not positive.
+	defaultIdempotencyCapacity = 10000
+	// maxIdempotencyBodyBytes caps the body Idempotency stores, larger
+	// responses are not replayed.
+	maxIdempotencyBodyBytes = 1 << 20
+	// DefaultIdempotencyMaxRequestBytes caps the request body Idempotency
+	// reads when WithIdempotencyMaxRequestBytes is not used.
+	DefaultIdempotencyMaxRequestBytes = 1 << 20
+)
+
+// errIdempotencyBodyTooLarge is returned by bodyFingerprint for a request
+// body over the cap.
+var errIdempotencyBodyTooLarge = errors.New("responsehelper: the request body is over the idempotency cap")
+
+// StoredResponse is a response kept by an IdempotencyStore.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Fingerprint is the SHA-256 of the body of the request answered, in
+	// hex, so a key reused with another body is not replayed.
+	Fingerprint string
+}
+
+// IdempotencyStore keeps the responses of Idempotency by key. It must be
+// safe for concurrent use, the stored responses must not be modified.
+type IdempotencyStore interface {
+	// Get returns the response stored for key, and false when there is none
+	// or it expired.
+	Get(key string) (*StoredResponse, bool)
+	// Set stores response for key during ttl.
+	Set(key string, response *StoredResponse, ttl time.Duration)
+}
+
+// IdempotencyOption configures Idempotency.
+type IdempotencyOption func(*idempotencyConfig)
+
+type idempotencyConfig struct {
+	// ttl is how long the responses are stored.
+	ttl time.Duration
+	// scope returns the scope of the keys of a request, the method and the
+	// path by default.
+	scope func(c *gin.Context) string
+	// maxRequestBytes caps the request body read for its fingerprint.
+	maxRequestBytes int64
+}
+
+// WithIdempotencyTTL replaces DefaultIdempotencyTTL as how long the
+// responses are replayed.
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		cfg.ttl = ttl
+	}
+}
+
+// WithIdempotencyScope scopes the Idempotency-Key of a request with the
+// value scope returns for it, next to its method and path, eg: the
+// authenticated user, so clients cannot replay the responses of others.
+//
+// Example:
+//
+//	responsehelper.WithIdempotencyScope(func(c *gin.Context) string { return c.GetString("userId") })
+func WithIdempotencyScope(scope func(c *gin.Context) string) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		cfg.scope = scope
+	}
+}
+
+// WithIdempotencyMaxRequestBytes replaces DefaultIdempotencyMaxRequestBytes
+// as the largest request body Idempotency reads, to fingerprint it, before
+// the handlers run. A request with a larger body and an Idempotency-Key gets
+// a 413 Request Entity Too Large. A cap that is not positive restores the
+// default.
+func WithIdempotencyMaxRequestBytes(n int64) IdempotencyOption {
+	return func(cfg *idempotencyConfig) {
+		cfg.maxRequestBytes = n
+	}
+}
+
+// Idempotency replays the stored response of a request carrying an
+// Idempotency-Key already used on the same method and path, byte for byte,
+// with IdempotentReplayedHeader set, instead of running the handlers again.
+// The headers already set on the new response, eg: the X-Request-ID of
+// MetaMiddleware, are kept. The first response of a key is stored in store,
+// except 5xx, 408 and 429 ones so failed requests can be retried. A request
+// arriving while the first one with its key still runs gets a retryable 409
+// Conflict, and a key reused with another request body a 422 Unprocessable
+// Entity. A request body over WithIdempotencyMaxRequestBytes gets a 413
+// Request Entity Too Large. Requests without the header are passed through.
+//
+// Example:
+//
+//	payments := router.Group("/payments", responsehelper.Idempotency(responseHelper, responsehelper.NewMemoryIdempotencyStore(0)))
+func Idempotency(h ResponseHelper, store IdempotencyStore, opts ...IdempotencyOption) gin.HandlerFunc {
+	cfg := idempotencyConfig{ttl: DefaultIdempotencyTTL}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.maxRequestBytes <= 0 {
+		cfg.maxRequestBytes = DefaultIdempotencyMaxRequestBytes
+	}
+	var mu sync.Mutex
+	inFlight := map[string]bool{}
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		key := c.Request.Method + " " + requestPath(exchangeOf(c)) + " " + idempotencyKey
+		if cfg.scope != nil {
+			key = cfg.scope(c) + " " + key
+		}
+		fingerprint, err := bodyFingerprint(c.Request, cfg.maxRequestBytes)
+		if errors.Is(err, errIdempotencyBodyTooLarge) {
+			h.RespondAPIError(c, &APIError{
+				Status:  http.StatusRequestEntityTooLarge,
+				Code:    "IDEMPOTENCY_BODY_TOO_LARGE",
+				Message: "The request body is too large to be sent with an Idempotency-Key",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			h.BadRequest(c, "The request body could not be read", err.Error())
+			c.Abort()
+			return
+		}
+		mu.Lock()
+		if stored, ok := store.Get(key); ok {
+			mu.Unlock()
+			if stored.Fingerprint != fingerprint {
+				h.RespondAPIError(c, &APIError{
+					Status:  http.StatusUnprocessableEntity,
+					Code:    "IDEMPOTENCY_KEY_REUSED",
+					Message: "The Idempotency-Key was already used with another request body",
+				})
+				c.Abort()
+				return
+			}
+			replayResponse(c, stored)
+			return
+		}
+		if inFlight[key] {
+			mu.Unlock()
+			h.Conflict(c, "A request with the same Idempotency-Key is still in flight", nil, Retryable(true))
+			c.Abort()
+			return
+		}
+		inFlight[key] = true
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(inFlight, key)
+			mu.Unlock()
+		}()
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		if writer.Written() && !writer.truncated && replayable(writer.Status()) {
+			store.Set(key, &StoredResponse{
+				Status:      writer.Status(),
+				Header:      writer.Header().Clone(),
+				Body:        writer.body.Bytes(),
+				Fingerprint: fingerprint,
+			}, cfg.ttl)
+		}
+	}
+}
+
+// replayable reports whether the responses with status are stored: not the
+// ones of requests worth retrying, 5xx, 408 Request Timeout and 429 Too Many
+// Requests.
+func replayable(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+// bodyFingerprint returns the SHA-256 of the body of request in hex. The
+// body is read and replaced, so the handlers can still read it. A body over
+// limit bytes is not read past the limit, errIdempotencyBodyTooLarge is
+// returned instead.
+func bodyFingerprint(request *http.Request, limit int64) (string, error) {
+	hash := sha256.New()
+	if request.Body != nil && request.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(request.Body, limit+1))
+		_ = request.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if int64(len(body)) > limit {
+			return "", errIdempotencyBodyTooLarge
+		}
+		hash.Write(body)
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// replayResponse writes stored as the response of c.
+func replayResponse(c *gin.Context, stored *StoredResponse) {
+	header := c.Writer.Header()
+	for name, values := range stored.Header {
+		if _, ok := header[name]; !ok {
+			header[name] = append([]string(nil), values...)
+		}
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+	c.Set(RespondedKey, true)
+	c.Status(stored.Status)
+	c.Writer.WriteHeaderNow()
+	_, _ = c.Writer.Write(stored.Body)
+	c.Abort()
+}
+
+// captureWriter copies the body written to the response, up to
+// maxIdempotencyBodyBytes.
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.capture(data[:n])
+	return n, err
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture([]byte(s[:n]))
+	return n, err
+}
+
+func (w *captureWriter) capture(data []byte) {
+	if w.truncated || w.body.Len()+len(data) > maxIdempotencyBodyBytes {
+		w.truncated = true
+		return
+	}
+	w.body.Write(data)
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore evicting the least
+// recently used responses beyond its capacity. It suits a single instance,
+// use a shared store when requests are spread across instances.
+type MemoryIdempotencyStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	// order holds the entries, the most recently used first.
+	order *list.List
+}
+
+// memoryIdempotencyEntry is an element of MemoryIdempotencyStore.order.
+type memoryIdempotencyEntry struct {
+	key      string
+	response *StoredResponse
+	// expires is the zero time for responses kept until evicted.
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore returns a MemoryIdempotencyStore keeping up to
+// capacity responses, 10000 when capacity is not positive.
+func NewMemoryIdempotencyStore(capacity int) *MemoryIdempotencyStore {
+	if capacity <= 0 {
+		capacity = defaultIdempotencyCapacity
+	}
+	return &MemoryIdempotencyStore{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Get returns the response stored for key, and false when there is none or
+// it expired.
+func (s *MemoryIdempotencyStore) Get(key string) (*StoredResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*memoryIdempotencyEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		s.remove(element)
+		return nil, false
+	}
+	s.order.MoveToFront(element)
+	return entry.response, true
+}
+
+// Set stores response for key during ttl, until it is evicted when ttl is
+// not positive.
+func (s *MemoryIdempotencyStore) Set(key string, response *StoredResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &memoryIdempotencyEntry{key: key, response: response}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+}
+
+// Len returns the number of stored responses, expired ones included until
+// they are looked up or evicted.
+func (s *MemoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryIdempotencyStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*memoryIdempotencyEntry).key)
+}
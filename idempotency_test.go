@@ -0,0 +1,236 @@
+package responsehelper_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyEngine returns an engine whose POST /payments answers with
+// status, echoing the request body, and counts its calls.
+func idempotencyEngine(store responsehelper.IdempotencyStore, status *int, calls *int32, opts ...responsehelper.IdempotencyOption) *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("userId", c.GetHeader("X-User"))
+	})
+	engine.Use(responsehelper.Idempotency(h, store, opts...))
+	engine.POST("/payments", func(c *gin.Context) {
+		n := atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Header("X-Call", strings.Repeat("I", int(n)))
+		if *status >= http.StatusBadRequest {
+			h.RespondAPIError(c, responsehelper.NewAPIError(*status, "failed", nil))
+			return
+		}
+		h.Created(c, gin.H{"call": n, "body": string(body)})
+	})
+	return engine
+}
+
+// pay posts body to /payments with key as Idempotency-Key, when not empty.
+func pay(engine http.Handler, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(responsehelper.IdempotencyKeyHeader, key)
+	}
+	return serve(engine, r)
+}
+
+func TestIdempotencyReplays(t *testing.T) {
+	status, calls := http.StatusCreated, int32(0)
+	engine := idempotencyEngine(responsehelper.NewMemoryIdempotencyStore(0), &status, &calls)
+
+	first := pay(engine, "k1", `{"amount":10}`)
+	replayed := pay(engine, "k1", `{"amount":10}`)
+
+	if calls != 1 {
+		t.Errorf("the handler ran %d times, want once", calls)
+	}
+	if replayed.Code != first.Code || replayed.Body.String() != first.Body.String() {
+		t.Errorf("replayed %d %s, want %d %s", replayed.Code, replayed.Body, first.Code, first.Body)
+	}
+	assertField(t, first, "data.body", `{"amount":10}`)
+	if replayed.Header().Get("X-Call") != "I" || replayed.Header().Get(responsehelper.IdempotentReplayedHeader) != "true" {
+		t.Errorf("replayed headers = %v", replayed.Header())
+	}
+	if first.Header().Get(responsehelper.IdempotentReplayedHeader) != "" {
+		t.Errorf("the first response has %s", responsehelper.IdempotentReplayedHeader)
+	}
+
+	if w := pay(engine, "k2", `{"amount":10}`); calls != 2 || w.Header().Get(responsehelper.IdempotentReplayedHeader) != "" {
+		t.Errorf("another key was replayed: %d calls", calls)
+	}
+	pay(engine, "", `{"amount":10}`)
+	pay(engine, "", `{"amount":10}`)
+	if calls != 4 {
+		t.Errorf("requests without a key ran the handler %d times, want 4", calls)
+	}
+}
+
+func TestIdempotencyRejectsAnotherBody(t *testing.T) {
+	status, calls := http.StatusCreated, int32(0)
+	engine := idempotencyEngine(responsehelper.NewMemoryIdempotencyStore(0), &status, &calls)
+
+	pay(engine, "k1", `{"amount":10}`)
+	w := pay(engine, "k1", `{"amount":99}`)
+
+	assertError(t, w, http.StatusUnprocessableEntity, "The Idempotency-Key was already used with another request body")
+	assertField(t, w, "error.errorCode", "IDEMPOTENCY_KEY_REUSED")
+	if calls != 1 {
+		t.Errorf("the handler ran %d times, want once", calls)
+	}
+	if w := pay(engine, "k1", `{"amount":10}`); w.Header().Get(responsehelper.IdempotentReplayedHeader) != "true" {
+		t.Error("the first response is not replayed after a mismatch")
+	}
+}
+
+func TestIdempotencyMaxRequestBytes(t *testing.T) {
+	status, calls := http.StatusCreated, int32(0)
+	engine := idempotencyEngine(responsehelper.NewMemoryIdempotencyStore(0), &status, &calls,
+		responsehelper.WithIdempotencyMaxRequestBytes(16))
+
+	w := pay(engine, "k1", `{"amount":100000}`)
+	assertError(t, w, http.StatusRequestEntityTooLarge, "The request body is too large to be sent with an Idempotency-Key")
+	assertField(t, w, "error.errorCode", "IDEMPOTENCY_BODY_TOO_LARGE")
+	if calls != 0 {
+		t.Errorf("the handler ran %d times for a body over the cap", calls)
+	}
+
+	// a body of exactly the cap is read, and so are large bodies without a key
+	if w := pay(engine, "k2", `{"amount":10000}`); w.Code != http.StatusCreated {
+		t.Errorf("status = %d for a body of the cap", w.Code)
+	}
+	if w := pay(engine, "", strings.Repeat("x", 1<<10)); w.Code != http.StatusCreated {
+		t.Errorf("status = %d for a large body without a key", w.Code)
+	}
+	if calls != 2 {
+		t.Errorf("the handler ran %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	status, calls := http.StatusCreated, int32(0)
+	engine := idempotencyEngine(responsehelper.NewMemoryIdempotencyStore(0), &status, &calls,
+		responsehelper.WithIdempotencyScope(func(c *gin.Context) string { return c.GetString("userId") }))
+	payAs := func(user string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount":10}`))
+		r.Header.Set(responsehelper.IdempotencyKeyHeader, "k1")
+		r.Header.Set("X-User", user)
+		return serve(engine, r)
+	}
+
+	payAs("arun")
+	if w := payAs("dev"); w.Header().Get(responsehelper.IdempotentReplayedHeader) != "" || calls != 2 {
+		t.Errorf("the response of another user was replayed, %d calls", calls)
+	}
+	if w := payAs("arun"); w.Header().Get(responsehelper.IdempotentReplayedHeader) != "true" || calls != 2 {
+		t.Errorf("the response of the same user was not replayed, %d calls", calls)
+	}
+}
+
+func TestIdempotencyDoesNotStoreRetryableFailures(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		stored bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusConflict, true},
+		{http.StatusRequestTimeout, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusServiceUnavailable, false},
+	} {
+		status, calls := tt.status, int32(0)
+		store := responsehelper.NewMemoryIdempotencyStore(0)
+		engine := idempotencyEngine(store, &status, &calls)
+
+		pay(engine, "k1", `{}`)
+		pay(engine, "k1", `{}`)
+		wantCalls, wantLen := int32(2), 0
+		if tt.stored {
+			wantCalls, wantLen = 1, 1
+		}
+		if calls != wantCalls || store.Len() != wantLen {
+			t.Errorf("%d: the handler ran %d times, %d stored, want stored %t", tt.status, calls, store.Len(), tt.stored)
+		}
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(responsehelper.Idempotency(h, responsehelper.NewMemoryIdempotencyStore(0)))
+	engine.POST("/payments", func(c *gin.Context) {
+		close(started)
+		<-release
+		h.Created(c, gin.H{"id": 1})
+	})
+
+	var first *httptest.ResponseRecorder
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = pay(engine, "k1", `{}`)
+	}()
+	<-started
+	w := pay(engine, "k1", `{}`)
+	close(release)
+	wg.Wait()
+
+	assertError(t, w, http.StatusConflict, "A request with the same Idempotency-Key is still in flight")
+	assertField(t, w, "error.retryable", true)
+	if first.Code != http.StatusCreated {
+		t.Errorf("the first request got %d", first.Code)
+	}
+	if w := pay(engine, "k1", `{}`); w.Code != http.StatusCreated || w.Header().Get(responsehelper.IdempotentReplayedHeader) != "true" {
+		t.Errorf("after the first request: %d %v", w.Code, w.Header())
+	}
+}
+
+func TestIdempotencyTTL(t *testing.T) {
+	status, calls := http.StatusCreated, int32(0)
+	engine := idempotencyEngine(responsehelper.NewMemoryIdempotencyStore(0), &status, &calls, responsehelper.WithIdempotencyTTL(20*time.Millisecond))
+
+	pay(engine, "k1", `{}`)
+	if pay(engine, "k1", `{}`); calls != 1 {
+		t.Fatalf("replayed before the TTL: %d calls", calls)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if w := pay(engine, "k1", `{}`); calls != 2 || w.Header().Get(responsehelper.IdempotentReplayedHeader) != "" {
+		t.Errorf("replayed after the TTL: %d calls", calls)
+	}
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	store := responsehelper.NewMemoryIdempotencyStore(2)
+	store.Set("a", &responsehelper.StoredResponse{Status: 201}, time.Hour)
+	store.Set("b", &responsehelper.StoredResponse{Status: 202}, 0)
+	store.Get("a")
+	store.Set("c", &responsehelper.StoredResponse{Status: 203}, time.Hour)
+
+	if _, ok := store.Get("b"); ok {
+		t.Error("the least recently used response was not evicted")
+	}
+	for key, status := range map[string]int{"a": 201, "c": 203} {
+		if stored, ok := store.Get(key); !ok || stored.Status != status {
+			t.Errorf("Get(%s) = %+v, %t", key, stored, ok)
+		}
+	}
+	store.Set("a", &responsehelper.StoredResponse{Status: 204}, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := store.Get("a"); ok || store.Len() != 1 {
+		t.Errorf("an expired response was returned, %d stored", store.Len())
+	}
+}
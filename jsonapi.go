@@ -0,0 +1,324 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Resource is implemented by the data rendered as JSON:API resource objects,
+// see FormatJSONAPI. Without it the type is the name of the struct with a
+// lowercase first letter, eg: "user", and the id is its "id" JSON member.
+//
+// Example:
+//
+//	func (u User) ResourceType() string { return "users" }
+//	func (u User) ResourceID() string   { return strconv.Itoa(u.ID) }
+type Resource interface {
+	ResourceType() string
+	ResourceID() string
+}
+
+// JSONAPIDocument is the top-level object of a JSON:API response.
+type JSONAPIDocument struct {
+	// Data is a resource object, a collection of them or null.
+	Data interface{} `json:"data,omitempty"`
+	// Errors are the error objects of an error response.
+	Errors []JSONAPIError `json:"errors,omitempty"`
+	// Links are the links of the envelope and the page links of a Pagination.
+	Links map[string]string `json:"links,omitempty"`
+	// Meta holds the meta of the envelope and the members JSON:API has no
+	// place for, eg: "pagination" or "warnings".
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIResource is a JSON:API resource object.
+type JSONAPIResource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// Links are the links of a LinkedResource.
+	Links Links `json:"links,omitempty"`
+}
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	// ID is the errorId of a server error.
+	ID string `json:"id,omitempty"`
+	// Links holds "about", the helpUrl, and "type", the type of the error.
+	Links  map[string]string `json:"links,omitempty"`
+	Status string            `json:"status"`
+	// Code is the errorCode, or the code of an ErrorItem.
+	Code string `json:"code,omitempty"`
+	// Title is the message of the error.
+	Title string `json:"title"`
+	// Detail is the string details of the error, or the message of a field
+	// error or an ErrorItem.
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource    `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIErrorSource points at the member of the request an error is about.
+type JSONAPIErrorSource struct {
+	// Pointer is the JSON pointer of the member, eg: "/data/attributes/name".
+	Pointer string `json:"pointer,omitempty"`
+}
+
+// jsonapiFormat reports whether the response of c is rendered as JSON:API.
+func (cfg *config) jsonapiFormat(c Exchange) bool {
+	if _, ok := cfg.jsonpCallback(c); ok {
+		return false
+	}
+	return cfg.responseFormat(c) == MIMEJSONAPI
+}
+
+// jsonapiDocument returns the JSON:API document of envelope, a
+// *SuccessEnvelope or an *ErrorEnvelope. Other bodies, eg: the ones of
+// WriteJSON, are returned as is.
+func (cfg *config) jsonapiDocument(c Exchange, envelope interface{}) interface{} {
+	switch envelope := envelope.(type) {
+	case *SuccessEnvelope:
+		return cfg.jsonapiSuccess(c, envelope)
+	case *ErrorEnvelope:
+		return &JSONAPIDocument{
+			Errors: jsonapiErrors(&envelope.Error),
+			Meta:   jsonapiMeta(envelope.Meta),
+		}
+	}
+	return envelope
+}
+
+// jsonapiSuccess returns the JSON:API document of a success envelope. Data
+// that is not a resource or a collection of them is sent as "meta.data".
+func (cfg *config) jsonapiSuccess(c Exchange, envelope *SuccessEnvelope) *JSONAPIDocument {
+	document := &JSONAPIDocument{Meta: jsonapiMeta(envelope.Meta)}
+	addMeta := func(key string, value interface{}) {
+		if document.Meta == nil {
+			document.Meta = map[string]interface{}{}
+		}
+		document.Meta[key] = value
+	}
+	if envelope.Data != nil {
+		if data, ok := cfg.jsonapiData(envelope.Data); ok {
+			document.Data = data
+		} else {
+			addMeta("data", envelope.Data)
+		}
+	}
+	if links, ok := envelope.Links.(Links); ok && len(links) > 0 {
+		document.Links = map[string]string{}
+		for rel, link := range links {
+			document.Links[rel] = link
+		}
+	}
+	switch pagination := envelope.Pagination.(type) {
+	case Pagination:
+		addMeta("pagination", pagination)
+		for _, page := range cfg.pageLinks(c, pagination) {
+			if document.Links == nil {
+				document.Links = map[string]string{}
+			}
+			document.Links[page.rel] = page.target
+		}
+	case nil:
+	default:
+		addMeta("pagination", pagination)
+	}
+	if envelope.Count != nil {
+		addMeta("count", *envelope.Count)
+	}
+	if envelope.Message != "" {
+		addMeta("message", envelope.Message)
+	}
+	if len(envelope.PartialFailures) > 0 {
+		addMeta("partialFailures", envelope.PartialFailures)
+	}
+	if len(envelope.Warnings) > 0 {
+		addMeta("warnings", envelope.Warnings)
+	}
+	if document.Data == nil && document.Meta == nil {
+		// a document needs data, errors or meta
+		document.Data = jsonNull
+	}
+	return document
+}
+
+// jsonapiData returns data as a resource object or a collection of them,
+// and false when it is neither.
+func (cfg *config) jsonapiData(data interface{}) (interface{}, bool) {
+	if data == nil || isJSONNull(data) {
+		return jsonNull, true
+	}
+	value := reflect.ValueOf(data)
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8 {
+		resources := make([]JSONAPIResource, value.Len())
+		for i := range resources {
+			resource, ok := cfg.jsonapiResource(value.Index(i).Interface())
+			if !ok {
+				return nil, false
+			}
+			resources[i] = resource
+		}
+		return resources, true
+	}
+	resource, ok := cfg.jsonapiResource(data)
+	if !ok {
+		return nil, false
+	}
+	return resource, true
+}
+
+// jsonapiResource returns item as a resource object, and false when it is
+// neither a Resource nor a struct.
+func (cfg *config) jsonapiResource(item interface{}) (JSONAPIResource, bool) {
+	value := reflect.ValueOf(item)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return JSONAPIResource{}, false
+		}
+		value = value.Elem()
+	}
+	resource, isResource := item.(Resource)
+	if !isResource && value.Kind() != reflect.Struct {
+		return JSONAPIResource{}, false
+	}
+	object, err := JSONValue(item)
+	attributes, ok := object.(map[string]interface{})
+	if err != nil || !ok {
+		return JSONAPIResource{}, false
+	}
+	result := JSONAPIResource{Attributes: attributes}
+	if isResource {
+		result.Type, result.ID = resource.ResourceType(), resource.ResourceID()
+	} else {
+		result.Type = lowerFirst(value.Type().Name())
+		if id, ok := attributes["id"]; ok && id != nil {
+			result.ID = fmt.Sprint(id)
+		}
+	}
+	delete(attributes, "id")
+	if len(attributes) == 0 {
+		result.Attributes = nil
+	}
+	if linked, ok := item.(LinkedResource); ok {
+		result.Links = cfg.resolveLinks(linked.Links())
+	}
+	return result, true
+}
+
+// jsonapiErrors returns the error objects of errorBody, one per field error
+// or ErrorItem, a single one otherwise.
+func jsonapiErrors(errorBody *ErrorBody) []JSONAPIError {
+	base := JSONAPIError{
+		ID:     errorBody.ErrorID,
+		Status: strconv.Itoa(errorBody.Code),
+		Code:   errorBody.ErrorCode,
+		Title:  errorBody.Message,
+	}
+	if errorBody.HelpURL != "" || errorBody.Type != "" {
+		base.Links = map[string]string{}
+		if errorBody.HelpURL != "" {
+			base.Links["about"] = errorBody.HelpURL
+		}
+		if errorBody.Type != "" {
+			base.Links["type"] = errorBody.Type
+		}
+	}
+	switch items := errorBody.Errors.(type) {
+	case []FieldError:
+		errors := make([]JSONAPIError, len(items))
+		for i, item := range items {
+			errors[i] = base
+			errors[i].Detail = item.Message
+			errors[i].Source = &JSONAPIErrorSource{Pointer: attributePointer(item.Field)}
+		}
+		return errors
+	case []ErrorItem:
+		errors := make([]JSONAPIError, len(items))
+		for i, item := range items {
+			errors[i] = base
+			errors[i].Detail = item.Message
+			if item.Code != "" {
+				errors[i].Code = item.Code
+			}
+			if item.Field != "" {
+				errors[i].Source = &JSONAPIErrorSource{Pointer: attributePointer(item.Field)}
+			}
+			if item.Details != nil {
+				errors[i].Meta = map[string]interface{}{"details": item.Details}
+			}
+		}
+		return errors
+	}
+	meta := map[string]interface{}{"retryable": errorBody.Retryable}
+	if detail, ok := errorBody.Details.(string); ok {
+		base.Detail = detail
+	} else if errorBody.Details != nil {
+		meta["details"] = errorBody.Details
+	}
+	if len(errorBody.Context) > 0 {
+		meta["context"] = errorBody.Context
+	}
+	if errorBody.Kind != "" {
+		meta["kind"] = errorBody.Kind
+	}
+	if errorBody.Reason != "" {
+		meta["reason"] = errorBody.Reason
+	}
+	if len(errorBody.RequiredPermissions) > 0 {
+		meta["requiredPermissions"] = errorBody.RequiredPermissions
+	}
+	if len(errorBody.GrantedPermissions) > 0 {
+		meta["grantedPermissions"] = errorBody.GrantedPermissions
+	}
+	if errorBody.Resource != "" {
+		meta["resource"] = errorBody.Resource
+	}
+	if errorBody.ResourceID != "" {
+		meta["resourceId"] = errorBody.ResourceID
+	}
+	if len(errorBody.Causes) > 0 {
+		meta["causes"] = errorBody.Causes
+	}
+	base.Meta = meta
+	return []JSONAPIError{base}
+}
+
+// jsonapiMeta returns the members of the meta of an envelope, nil when there
+// are none.
+func jsonapiMeta(meta interface{}) map[string]interface{} {
+	if meta == nil || isJSONNull(meta) {
+		return nil
+	}
+	value, err := JSONValue(meta)
+	members, ok := value.(map[string]interface{})
+	if err != nil || !ok || len(members) == 0 {
+		return nil
+	}
+	return members
+}
+
+// attributePointer returns the JSON pointer of the attribute field, eg:
+// "items[2].name" -> "/data/attributes/items/2/name".
+func attributePointer(field string) string {
+	path := strings.NewReplacer("~", "~0", "/", "~1").Replace(field)
+	path = strings.NewReplacer("[", "/", "]", "", ".", "/").Replace(path)
+	return "/data/attributes/" + strings.TrimPrefix(path, "/")
+}
+
+// isJSONNull reports whether v is the jsonNull placeholder of a nil value.
+func isJSONNull(v interface{}) bool {
+	raw, ok := v.(json.RawMessage)
+	return ok && string(raw) == "null"
+}
+
+// lowerFirst returns s with its first letter in lowercase, eg: "User" -> "user".
+func lowerFirst(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(first)) + s[size:]
+}
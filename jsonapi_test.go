@@ -0,0 +1,90 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// article implements Resource.
+type article struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func (a article) ResourceType() string { return "articles" }
+func (a article) ResourceID() string   { return strconv.Itoa(a.ID) }
+
+// person is rendered with the reflection fallbacks.
+type person struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// jsonapiCalls are the responses pinned by TestJSONAPIGolden.
+var jsonapiCalls = []struct {
+	name    string
+	respond func(h responsehelper.ResponseHelper, c *gin.Context)
+}{
+	{"resource", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, article{ID: 1, Title: "JSON:API paints my bikeshed"})
+	}},
+	{"reflected", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, &person{ID: 42, Name: "arun"}) }},
+	{"collection", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, []article{{ID: 1, Title: "First"}, {ID: 2, Title: "Second"}})
+	}},
+	{"empty", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, nil) }},
+	{"non-resource", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, []int{1, 2}) }},
+	{"pagination", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, []person{{ID: 3, Name: "c"}, {ID: 4, Name: "d"}}, responsehelper.NewPagination(2, 2, 6))
+	}},
+	{"error", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "article", 7) }},
+	{"errors", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{
+			{Code: "REQUIRED", Field: "title", Message: "title is required"},
+			{Code: "TOO_LONG", Field: "tags[2]", Message: "tags must be shorter than 16 characters"},
+		})
+	}},
+}
+
+// TestJSONAPIGolden pins the JSON:API documents of resources, collections,
+// pagination and errors.
+func TestJSONAPIGolden(t *testing.T) {
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithDefaultFormat(responsehelper.FormatJSONAPI),
+		responsehelper.WithPaginationLinks("page"),
+	)
+	for _, call := range jsonapiCalls {
+		t.Run(call.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "https://api.example.com/articles?page=2&sort=title")
+			c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: "/articles"})
+			call.respond(h, c)
+
+			if got := w.Header().Get("Content-Type"); got != responsehelper.MIMEJSONAPI {
+				t.Errorf("Content-Type = %q, want %q", got, responsehelper.MIMEJSONAPI)
+			}
+			goldenBytes(t, w.Body.Bytes(), filepath.Join("testdata", "jsonapi", call.name+".json"))
+		})
+	}
+}
+
+func TestJSONAPINegotiated(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithContentNegotiation(true))
+	for accept, want := range map[string]string{
+		responsehelper.MIMEJSONAPI: responsehelper.MIMEJSONAPI,
+		"application/json":         "application/json; charset=utf-8",
+		"":                         "application/json; charset=utf-8",
+	} {
+		c, w := newContext(http.MethodGet, "/articles/1")
+		c.Request.Header.Set("Accept", accept)
+		h.Success(c, article{ID: 1, Title: "First"})
+
+		if got := w.Header().Get("Content-Type"); got != want {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", accept, got, want)
+		}
+	}
+}
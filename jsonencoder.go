@@ -0,0 +1,107 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+const (
+	// jsonContentType is the content type gin writes JSON responses with.
+	jsonContentType = "application/json; charset=utf-8"
+	// jsonpContentType is the content type gin writes JSONP responses with.
+	jsonpContentType = "application/javascript; charset=utf-8"
+)
+
+// fallbackErrorBody is sent when an envelope cannot be marshalled, and
+// nullFallbackErrorBody with WithNullFields.
+var (
+	fallbackErrorBody     = []byte(`{"error":{"code":500,"message":"An unexpected error occurred","retryable":false,"status":"INTERNAL_SERVER_ERROR"},"success":false}`)
+	nullFallbackErrorBody = []byte(`{"error":{"code":500,"message":"An unexpected error occurred","retryable":false,"status":"INTERNAL_SERVER_ERROR"},"meta":null,"success":false}`)
+)
+
+// JSONEncoder marshals the JSON envelopes, see WithJSONEncoder. The jsoniter
+// package provides one.
+type JSONEncoder interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// WithJSONEncoder marshals the envelopes, the JSONP responses and the problem
+// details with encoder instead of encoding/json, eg: a faster library on hot
+// services. An envelope the encoder fails on is replaced by a 500 Internal
+// Server Error. nil restores encoding/json.
+//
+// Example:
+//
+//	import "github.com/aruncs31s/responsehelper/jsoniter"
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithJSONEncoder(jsoniter.Encoder))
+func WithJSONEncoder(encoder JSONEncoder) Option {
+	return func(cfg *config) {
+		cfg.jsonEncoder = encoder
+	}
+}
+
+// marshalJSON returns the JSON of v made by the encoder of WithJSONEncoder.
+func (cfg *config) marshalJSON(v interface{}) ([]byte, error) {
+	if cfg.jsonEncoder == nil {
+		return json.Marshal(v)
+	}
+	return cfg.jsonEncoder.Marshal(v)
+}
+
+// fallbackErrorBody returns the body sent when an envelope cannot be marshalled.
+func (cfg *config) fallbackErrorBody() []byte {
+	if cfg.nullFields {
+		return nullFallbackErrorBody
+	}
+	return fallbackErrorBody
+}
+
+// marshalFailed passes err, the error of a response that could not be
+// rendered, to a DetachedExchange, and logs it for the other ones.
+func (cfg *config) marshalFailed(c Exchange, err error) {
+	if x, ok := c.(DetachedExchange); ok {
+		x.MarshalFailed(err)
+		return
+	}
+	cfg.warnf("%v", err)
+}
+
+// writeJSON writes v as JSON with contentType, or a fixed 500 envelope when
+// it cannot be marshalled.
+func (cfg *config) writeJSON(c Exchange, status int, contentType string, v interface{}) {
+	if w, ok := c.(JSONWriter); ok && cfg.jsonEncoder == nil && !rawEnvelope(v) && bodyAllowedForStatus(status) {
+		if err := w.WriteJSON(status, contentType, cfg.withNullMeta(v)); err != nil {
+			cfg.marshalFailed(c, fmt.Errorf("cannot marshal the response: %w", err))
+			writeData(c, http.StatusInternalServerError, jsonContentType, cfg.fallbackErrorBody())
+		}
+		return
+	}
+	body, err := cfg.marshalEnvelope(v)
+	if err != nil {
+		cfg.marshalFailed(c, fmt.Errorf("cannot marshal the response: %w", err))
+		writeData(c, http.StatusInternalServerError, jsonContentType, cfg.fallbackErrorBody())
+		return
+	}
+	writeData(c, status, contentType, body)
+}
+
+// writeJSONPBody writes v as JSON wrapped in a call of callback, the way gin
+// renders JSONP.
+func (cfg *config) writeJSONPBody(c Exchange, status int, callback string, v interface{}) {
+	body, err := cfg.marshalEnvelope(v)
+	if err != nil {
+		cfg.marshalFailed(c, fmt.Errorf("cannot marshal the response: %w", err))
+		writeData(c, http.StatusInternalServerError, jsonContentType, cfg.fallbackErrorBody())
+		return
+	}
+	callback = template.JSEscapeString(callback)
+	out := make([]byte, 0, len(callback)+len(body)+3)
+	out = append(out, callback...)
+	out = append(out, '(')
+	out = append(out, body...)
+	out = append(out, ");"...)
+	writeData(c, status, jsonpContentType, out)
+}
@@ -0,0 +1,99 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// stdEncoder is encoding/json behind the JSONEncoder interface.
+type stdEncoder struct{}
+
+func (stdEncoder) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// failingEncoder fails on every value.
+type failingEncoder struct{}
+
+func (failingEncoder) Marshal(interface{}) ([]byte, error) { return nil, errors.New("cannot encode") }
+
+// encoderCalls are responses of every kind of envelope.
+var encoderCalls = map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+	"Success": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, gin.H{"name": "<arun>", "tags": []string{"a", "b"}})
+	},
+	"SuccessWithPagination": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+	},
+	"BadRequest": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.BadRequest(c, "Invalid input", "name & email are required")
+	},
+	"Problem": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Problem(c, http.StatusConflict, "", "Conflict", "Already taken", map[string]interface{}{"field": "email"})
+	},
+	"JSONP": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		c.Request.URL.RawQuery = "callback=cb"
+		h.Success(c, gin.H{"id": 1})
+	},
+}
+
+func TestJSONEncoderSendsTheSameBytes(t *testing.T) {
+	for name, respond := range encoderCalls {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			c.Set(responsehelper.ErrorIDKey, "e1")
+			respond(responsehelper.NewResponseHelper(responsehelper.WithJSONPCallbackParam("callback"), responsehelper.WithJSONEncoder(stdEncoder{})), c)
+			plain, want := newContext(http.MethodGet, "/users")
+			plain.Set(responsehelper.ErrorIDKey, "e1")
+			respond(responsehelper.NewResponseHelper(responsehelper.WithJSONPCallbackParam("callback")), plain)
+
+			if w.Code != want.Code || w.Header().Get("Content-Type") != want.Header().Get("Content-Type") {
+				t.Errorf("sent %d %q, want %d %q", w.Code, w.Header().Get("Content-Type"), want.Code, want.Header().Get("Content-Type"))
+			}
+			if w.Body.String() != want.Body.String() {
+				t.Errorf("body =\n%s\nwant\n%s", w.Body, want.Body)
+			}
+		})
+	}
+}
+
+func TestFailingJSONEncoderSendsAFallbackError(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []responsehelper.Option
+		want string
+	}{
+		{"default", nil,
+			`{"error":{"code":500,"message":"An unexpected error occurred","retryable":false,"status":"INTERNAL_SERVER_ERROR"},"success":false}`},
+		{"null fields", []responsehelper.Option{responsehelper.WithNullFields(true)},
+			`{"error":{"code":500,"message":"An unexpected error occurred","retryable":false,"status":"INTERNAL_SERVER_ERROR"},"meta":null,"success":false}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := responsehelper.NewResponseHelper(append(tc.opts, responsehelper.WithJSONEncoder(failingEncoder{}), responsehelper.WithJSONPCallbackParam("callback"))...)
+			for name, respond := range encoderCalls {
+				c, w := newContext(http.MethodGet, "/users")
+				respond(h, c)
+
+				if w.Code != http.StatusInternalServerError {
+					t.Errorf("%s: status = %d, want %d", name, w.Code, http.StatusInternalServerError)
+				}
+				if w.Body.String() != tc.want {
+					t.Errorf("%s: body =\n%s\nwant\n%s", name, w.Body, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNilJSONEncoderRestoresEncodingJSON(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithJSONEncoder(failingEncoder{}), responsehelper.WithJSONEncoder(nil)).
+		Success(c, gin.H{"id": 1})
+
+	if got, want := w.Body.String(), `{"data":{"id":1},"success":true}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
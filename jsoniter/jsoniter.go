@@ -0,0 +1,16 @@
+// Package jsoniter marshals the responsehelper JSON responses with
+// github.com/json-iterator/go.
+//
+//	import "github.com/aruncs31s/responsehelper/jsoniter"
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithJSONEncoder(jsoniter.Encoder))
+package jsoniter
+
+import (
+	"github.com/aruncs31s/responsehelper"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Encoder marshals like encoding/json: sorted map keys and HTML escaping
+// included, so the responses are the same as without it.
+var Encoder responsehelper.JSONEncoder = jsoniter.ConfigCompatibleWithStandardLibrary
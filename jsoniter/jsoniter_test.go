@@ -0,0 +1,85 @@
+package jsoniter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/jsoniter"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var calls = map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+	"Success": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, gin.H{"name": "<arun> & co", "zone": 1, "age": 3.5, "tags": []string{"b", "a"}})
+	},
+	"SuccessWithPagination": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+	},
+	"InternalError": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.InternalError(c, "Oops", errors.New("db down"))
+	},
+	"RespondAPIError": func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.RespondAPIError(c, &responsehelper.APIError{
+			Status:      http.StatusUnprocessableEntity,
+			Message:     "Validation failed",
+			FieldErrors: []responsehelper.FieldError{{Field: "email", Tag: "email", Message: "invalid email"}},
+		})
+	},
+}
+
+// respond sends a response through h and returns its recorder.
+func respond(h responsehelper.ResponseHelper, call func(h responsehelper.ResponseHelper, c *gin.Context)) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+	c.Set(responsehelper.MetaKey, gin.H{"requestId": "req-1"})
+	c.Set(responsehelper.ErrorIDKey, "e1")
+	call(h, c)
+	return w
+}
+
+func TestSameBytesAsEncodingJSON(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithJSONEncoder(jsoniter.Encoder))
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			got := respond(h, call)
+			want := respond(responsehelper.NewResponseHelper(), call)
+
+			if got.Code != want.Code {
+				t.Errorf("status = %d, want %d", got.Code, want.Code)
+			}
+			if got.Body.String() != want.Body.String() {
+				t.Errorf("body =\n%s\nwant\n%s", got.Body, want.Body)
+			}
+		})
+	}
+}
+
+func BenchmarkEncoders(b *testing.B) {
+	data := make([]gin.H, 50)
+	for i := range data {
+		data[i] = gin.H{"id": i, "name": "arun", "email": "arun@example.com", "active": true}
+	}
+	for _, bench := range []struct {
+		name    string
+		encoder responsehelper.JSONEncoder
+	}{
+		{"encoding/json", nil},
+		{"jsoniter", jsoniter.Encoder},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			h := responsehelper.NewResponseHelper(responsehelper.WithJSONEncoder(bench.encoder))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				respond(h, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, data) })
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package responsehelper
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// jsonpCallbackPattern matches the callback names accepted for JSONP, plain
+// JavaScript identifiers optionally joined with dots, eg: "widget.load".
+var jsonpCallbackPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+// WithJSONPCallbackParam wraps the envelope in a call of the function named
+// by the query parameter name, eg: "?callback=loadWidget" renders
+// loadWidget({...}); as application/javascript. Callback names that are not
+// plain JavaScript identifiers are ignored and the envelope is rendered as
+// usual. Requests without the parameter are not affected.
+//
+// As a JSONP script tag cannot read the status, error responses are sent
+// with 200 OK, the envelope keeps the real code. See WithJSONPErrorStatus.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithJSONPCallbackParam("callback"))
+func WithJSONPCallbackParam(name string) Option {
+	return func(cfg *config) {
+		cfg.jsonpCallbackParam = name
+	}
+}
+
+// WithJSONPErrorStatus sends the real status of error responses to JSONP
+// requests instead of 200 OK.
+func WithJSONPErrorStatus(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.jsonpErrorStatus = enabled
+	}
+}
+
+// jsonpCallback returns the JSONP callback of the request, if any.
+func (cfg *config) jsonpCallback(c Exchange) (string, bool) {
+	if cfg.jsonpCallbackParam == "" || c.Request() == nil {
+		return "", false
+	}
+	callback := query(c, cfg.jsonpCallbackParam)
+	if callback == "" || !jsonpCallbackPattern.MatchString(callback) {
+		return "", false
+	}
+	return callback, true
+}
+
+// writeJSONP writes envelope wrapped in a call of callback.
+func (cfg *config) writeJSONP(c Exchange, status int, callback string, envelope interface{}) {
+	if status >= http.StatusBadRequest && !cfg.jsonpErrorStatus {
+		status = http.StatusOK
+	}
+	cfg.writeJSONPBody(c, status, callback, envelope)
+}
@@ -0,0 +1,86 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func jsonpEngine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(append([]responsehelper.Option{responsehelper.WithJSONPCallbackParam("callback")}, opts...)...)
+	engine := gin.New()
+	engine.GET("/widget", func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) })
+	engine.GET("/missing", func(c *gin.Context) { h.NotFound(c, "Widget not found") })
+	return engine
+}
+
+func TestJSONP(t *testing.T) {
+	w := serve(jsonpEngine(), httptest.NewRequest(http.MethodGet, "/widget?callback=widget.load", nil))
+
+	if got := w.Header().Get("Content-Type"); got != "application/javascript; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want application/javascript", got)
+	}
+	if want := `widget.load({"data":{"id":1},"success":true});`; w.Body.String() != want {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+}
+
+func TestJSONPErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   []responsehelper.Option
+		status int
+	}{
+		{"classic", nil, http.StatusOK},
+		{"real status", []responsehelper.Option{responsehelper.WithJSONPErrorStatus(true)}, http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(jsonpEngine(tc.opts...), httptest.NewRequest(http.MethodGet, "/missing?callback=cb", nil))
+
+			if w.Code != tc.status {
+				t.Errorf("status = %d, want %d", w.Code, tc.status)
+			}
+			if !strings.HasPrefix(w.Body.String(), "cb(") || !strings.Contains(w.Body.String(), `"code":404`) {
+				t.Errorf("body = %s, want the 404 envelope wrapped in cb", w.Body)
+			}
+		})
+	}
+}
+
+func TestJSONPRejectsUnsafeCallbacks(t *testing.T) {
+	for _, callback := range []string{
+		"alert(1)//",
+		"a;b",
+		"1abc",
+		"cb<script>",
+		"a..b",
+		"a.",
+		"%E2%80%A8",
+	} {
+		t.Run(callback, func(t *testing.T) {
+			w := serve(jsonpEngine(), httptest.NewRequest(http.MethodGet, "/widget?callback="+strings.ReplaceAll(callback, ";", "%3B"), nil))
+
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want the JSON envelope", got)
+			}
+			if want := `{"data":{"id":1},"success":true}`; w.Body.String() != want {
+				t.Errorf("body = %s, want %s", w.Body, want)
+			}
+		})
+	}
+}
+
+func TestJSONPWithoutTheParameter(t *testing.T) {
+	w := serve(jsonpEngine(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want the JSON envelope", got)
+	}
+}
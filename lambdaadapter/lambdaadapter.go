@@ -0,0 +1,550 @@
+// Package lambdaadapter sends the responsehelper envelopes from AWS Lambda
+// functions behind API Gateway. The methods return the proxy response to
+// hand back to API Gateway, V2 converts it to the payload of HTTP APIs.
+package lambdaadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/stdlib"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Helper has the method set of responsehelper.ResponseHelper for Lambda
+// handlers, with the context of the invocation in place of the Gin context.
+//
+// Example:
+//
+//	func handle(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+//		ctx = lambdaadapter.WithRequest(ctx, req)
+//		user, err := users.Get(ctx, req.PathParameters["id"])
+//		if err != nil {
+//			return h.Respond(ctx, err, nil), nil
+//		}
+//		return h.Success(ctx, user), nil
+//	}
+type Helper struct {
+	responder *stdlib.Responder
+}
+
+// New creates a Helper configured with opts, the options of
+// responsehelper.NewResponseHelper.
+func New(opts ...responsehelper.Option) *Helper {
+	return Wrap(responsehelper.NewResponseHelper(opts...))
+}
+
+// Wrap creates a Helper rendering through helper, so Gin handlers and Lambda
+// functions share one helper, its configuration and registered codes.
+func Wrap(helper responsehelper.ResponseHelper) *Helper {
+	return &Helper{responder: stdlib.Wrap(helper)}
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of ctx carrying the method, path, query and
+// headers of req, eg: the Accept header the format is negotiated from.
+func WithRequest(ctx context.Context, req events.APIGatewayProxyRequest) context.Context {
+	query := url.Values{}
+	for key, values := range req.MultiValueQueryStringParameters {
+		query[key] = values
+	}
+	for key, value := range req.QueryStringParameters {
+		if _, ok := query[key]; !ok {
+			query.Set(key, value)
+		}
+	}
+	header := http.Header{}
+	for key, values := range req.MultiValueHeaders {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	for key, value := range req.Headers {
+		if header.Get(key) == "" {
+			header.Set(key, value)
+		}
+	}
+	return withRequest(ctx, req.HTTPMethod, req.Path, query.Encode(), header)
+}
+
+// WithRequestV2 is WithRequest for the payload of HTTP APIs.
+func WithRequestV2(ctx context.Context, req events.APIGatewayV2HTTPRequest) context.Context {
+	header := http.Header{}
+	for key, value := range req.Headers {
+		// HTTP APIs join the values of repeated headers with commas
+		header.Set(key, value)
+	}
+	return withRequest(ctx, req.RequestContext.HTTP.Method, req.RawPath, req.RawQueryString, header)
+}
+
+func withRequest(ctx context.Context, method, path, rawQuery string, header http.Header) context.Context {
+	if method == "" {
+		method = http.MethodGet
+	}
+	r := &http.Request{
+		Method: method,
+		URL:    &url.URL{Path: path, RawQuery: rawQuery},
+		Header: header,
+	}
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// WithValue returns a copy of ctx in which the Helper finds value under
+// key, like a Gin handler finds values set with c.Set, eg: the locale
+// (responsehelper.LocaleKey).
+func WithValue(ctx context.Context, key string, value interface{}) context.Context {
+	return stdlib.WithValue(ctx, key, value)
+}
+
+// WithMeta returns a copy of ctx in which the Helper finds the meta sent
+// with every response.
+func WithMeta(ctx context.Context, meta interface{}) context.Context {
+	return stdlib.WithMeta(ctx, meta)
+}
+
+// V2 returns resp as the response payload of HTTP APIs, with the
+// Set-Cookie headers as its cookies.
+func V2(resp events.APIGatewayProxyResponse) events.APIGatewayV2HTTPResponse {
+	v2 := events.APIGatewayV2HTTPResponse{
+		StatusCode:        resp.StatusCode,
+		Headers:           map[string]string{},
+		MultiValueHeaders: map[string][]string{},
+		Body:              resp.Body,
+		IsBase64Encoded:   resp.IsBase64Encoded,
+	}
+	for key, values := range resp.MultiValueHeaders {
+		if http.CanonicalHeaderKey(key) == "Set-Cookie" {
+			v2.Cookies = append(v2.Cookies, values...)
+			continue
+		}
+		v2.Headers[key] = resp.Headers[key]
+		v2.MultiValueHeaders[key] = values
+	}
+	return v2
+}
+
+// respond renders the response written by write for the invocation of ctx.
+// The request ID of the invocation is used when ctx carries none, and sent
+// in the X-Request-ID header.
+func (h *Helper) respond(ctx context.Context, write func(w http.ResponseWriter, r *http.Request)) events.APIGatewayProxyResponse {
+	requestID := ""
+	if id, ok := stdlib.Value(ctx, responsehelper.RequestIDKey); ok {
+		requestID, _ = id.(string)
+	} else if lc, ok := lambdacontext.FromContext(ctx); ok && lc.AwsRequestID != "" {
+		requestID = lc.AwsRequestID
+		ctx = stdlib.WithValue(ctx, responsehelper.RequestIDKey, requestID)
+	}
+	r, ok := ctx.Value(requestKey{}).(*http.Request)
+	if !ok {
+		r = &http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: http.Header{}}
+	}
+	w := &responseWriter{header: http.Header{}}
+	if requestID != "" {
+		w.header.Set(responsehelper.RequestIDHeader, requestID)
+	}
+	write(w, r.WithContext(ctx))
+	return w.response()
+}
+
+// responseWriter is the http.ResponseWriter the response is collected with.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header { return w.header }
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// response returns the proxy response of what was written, the body base64
+// encoded when it is binary, eg: MessagePack.
+func (w *responseWriter) response() events.APIGatewayProxyResponse {
+	resp := events.APIGatewayProxyResponse{
+		StatusCode:        w.status,
+		Headers:           make(map[string]string, len(w.header)),
+		MultiValueHeaders: make(map[string][]string, len(w.header)),
+		Body:              w.body.String(),
+	}
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+	for key, values := range w.header {
+		if len(values) > 0 {
+			resp.Headers[key] = values[0]
+			resp.MultiValueHeaders[key] = values
+		}
+	}
+	if w.body.Len() > 0 && binaryContentType(w.header.Get("Content-Type")) {
+		resp.Body = base64.StdEncoding.EncodeToString(w.body.Bytes())
+		resp.IsBase64Encoded = true
+	}
+	return resp
+}
+
+// binaryContentType reports whether a body of contentType is not text and
+// must be base64 encoded for API Gateway.
+func binaryContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType != ""
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return false
+	}
+	for _, suffix := range []string{"json", "xml", "yaml", "javascript"} {
+		// eg: "application/json" or "application/problem+json"
+		if strings.HasSuffix(mediaType, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisterCode registers a business error code for RespondCode.
+func (h *Helper) RegisterCode(code string, status int, defaultMessage string) {
+	h.responder.RegisterCode(code, status, defaultMessage)
+}
+
+// PrecomputeError registers an error response whose body is marshalled once.
+func (h *Helper) PrecomputeError(status int, message string) {
+	h.responder.PrecomputeError(status, message)
+}
+
+// BadRequest sends a 400 Bad Request response with string details.
+//
+// Deprecated: use BadRequestDetails.
+func (h *Helper) BadRequest(ctx context.Context, message string, details string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.BadRequest(w, r, message, details, opts...)
+	})
+}
+
+// BadRequestDetails sends a 400 Bad Request response with structured details.
+func (h *Helper) BadRequestDetails(ctx context.Context, message string, details interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.BadRequestDetails(w, r, message, details, opts...)
+	})
+}
+
+// AlreadyExists sends a 409 Conflict response for a resource that already exists.
+func (h *Helper) AlreadyExists(ctx context.Context, resource string, err error, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.AlreadyExists(w, r, resource, err, opts...)
+	})
+}
+
+// Conflict sends a 409 Conflict response.
+func (h *Helper) Conflict(ctx context.Context, message string, err error, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Conflict(w, r, message, err, opts...)
+	})
+}
+
+// NotFound sends a 404 Not Found response.
+func (h *Helper) NotFound(ctx context.Context, message string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.NotFound(w, r, message, opts...)
+	})
+}
+
+// ResourceNotFound sends a 404 Not Found response with the resource and its identifier.
+func (h *Helper) ResourceNotFound(ctx context.Context, resource string, id interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ResourceNotFound(w, r, resource, id, opts...)
+	})
+}
+
+// Unauthorized sends a 401 Unauthorized response.
+func (h *Helper) Unauthorized(ctx context.Context, message string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Unauthorized(w, r, message, opts...)
+	})
+}
+
+// UnauthorizedWithChallenge sends a 401 Unauthorized response with a WWW-Authenticate challenge.
+func (h *Helper) UnauthorizedWithChallenge(ctx context.Context, message, scheme, realm string, params map[string]string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.UnauthorizedWithChallenge(w, r, message, scheme, realm, params, opts...)
+	})
+}
+
+// UnauthorizedReason sends a 401 Unauthorized response telling why the credentials were rejected.
+func (h *Helper) UnauthorizedReason(ctx context.Context, reason responsehelper.AuthFailureReason, message string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.UnauthorizedReason(w, r, reason, message, opts...)
+	})
+}
+
+// Forbidden sends a 403 Forbidden response.
+func (h *Helper) Forbidden(ctx context.Context, message string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Forbidden(w, r, message, opts...)
+	})
+}
+
+// ForbiddenScope sends a 403 Forbidden response with the required and granted permissions.
+func (h *Helper) ForbiddenScope(ctx context.Context, message string, required []string, granted []string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ForbiddenScope(w, r, message, required, granted, opts...)
+	})
+}
+
+// TooManyRequests sends a 429 Too Many Requests response.
+func (h *Helper) TooManyRequests(ctx context.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.TooManyRequests(w, r, message, retryAfter, opts...)
+	})
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable response.
+func (h *Helper) ServiceUnavailable(ctx context.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ServiceUnavailable(w, r, message, retryAfter, opts...)
+	})
+}
+
+// InternalError sends a 500 Internal Server Error response.
+func (h *Helper) InternalError(ctx context.Context, message string, err error, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.InternalError(w, r, message, err, opts...)
+	})
+}
+
+// Success sends a 200 OK response with data.
+func (h *Helper) Success(ctx context.Context, data interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Success(w, r, data, opts...)
+	})
+}
+
+// PartialSuccess sends a 200 OK response with the data and the parts that could not be loaded.
+func (h *Helper) PartialSuccess(ctx context.Context, data interface{}, failures []responsehelper.PartialFailure, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.PartialSuccess(w, r, data, failures, opts...)
+	})
+}
+
+// SuccessCSV sends a 200 OK response with rows as a CSV attachment.
+func (h *Helper) SuccessCSV(ctx context.Context, filename string, rows interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessCSV(w, r, filename, rows)
+	})
+}
+
+// SuccessIfModified sends a 200 OK response with the data of dataFn, or a
+// 304 Not Modified response when If-Modified-Since is not before lastModified.
+func (h *Helper) SuccessIfModified(ctx context.Context, lastModified time.Time, dataFn func() (interface{}, error), opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessIfModified(w, r, lastModified, dataFn, opts...)
+	})
+}
+
+// SuccessWithPagination sends a 200 OK response with data and pagination metadata.
+func (h *Helper) SuccessWithPagination(ctx context.Context, data interface{}, meta interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessWithPagination(w, r, data, meta, opts...)
+	})
+}
+
+// SuccessWithCursor sends a 200 OK response with data and cursor pagination metadata.
+func (h *Helper) SuccessWithCursor(ctx context.Context, data interface{}, cur responsehelper.CursorPagination) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessWithCursor(w, r, data, cur)
+	})
+}
+
+// SuccessWithCount sends a 200 OK response with data and its number of items.
+func (h *Helper) SuccessWithCount(ctx context.Context, data interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessWithCount(w, r, data)
+	})
+}
+
+// SuccessLarge sends a 200 OK response with data streamed to the client.
+func (h *Helper) SuccessLarge(ctx context.Context, data interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessLarge(w, r, data)
+	})
+}
+
+// SuccessWithLinks sends a 200 OK response with data and hypermedia links.
+func (h *Helper) SuccessWithLinks(ctx context.Context, data interface{}, links responsehelper.Links) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.SuccessWithLinks(w, r, data, links)
+	})
+}
+
+// Created sends a 201 Created response with data.
+func (h *Helper) Created(ctx context.Context, data interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Created(w, r, data, opts...)
+	})
+}
+
+// Patched sends a 200 OK response with the updated resource and the changed fields.
+func (h *Helper) Patched(ctx context.Context, resource string, data interface{}, changedFields []string, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Patched(w, r, resource, data, changedFields, opts...)
+	})
+}
+
+// OperationAccepted sends a 202 Accepted response with a long-running operation.
+func (h *Helper) OperationAccepted(ctx context.Context, op responsehelper.Operation) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.OperationAccepted(w, r, op)
+	})
+}
+
+// OperationStatus sends the state of a long-running operation.
+func (h *Helper) OperationStatus(ctx context.Context, op responsehelper.Operation) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.OperationStatus(w, r, op)
+	})
+}
+
+// Deleted sends the response for a deleted resource, see WithDeleteStatus.
+func (h *Helper) Deleted(ctx context.Context, message string) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Deleted(w, r, message)
+	})
+}
+
+// DeletedNoContent sends a 204 No Content response for a deleted resource.
+func (h *Helper) DeletedNoContent(ctx context.Context) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.DeletedNoContent(w, r)
+	})
+}
+
+// NoContent sends a 204 No Content response.
+func (h *Helper) NoContent(ctx context.Context) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.NoContent(w, r)
+	})
+}
+
+// RespondAPIError sends the error response described by err.
+func (h *Helper) RespondAPIError(ctx context.Context, err *responsehelper.APIError, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.RespondAPIError(w, r, err, opts...)
+	})
+}
+
+// Respond sends data when err is nil and the error response for err otherwise.
+func (h *Helper) Respond(ctx context.Context, err error, data interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Respond(w, r, err, data, opts...)
+	})
+}
+
+// Errors sends an error response listing several errors.
+func (h *Helper) Errors(ctx context.Context, statusCode int, errs []responsehelper.ErrorItem, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Errors(w, r, statusCode, errs, opts...)
+	})
+}
+
+// Problem sends an RFC 7807 problem details response.
+func (h *Helper) Problem(ctx context.Context, status int, typ, title, detail string, extensions map[string]interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.Problem(w, r, status, typ, title, detail, extensions)
+	})
+}
+
+// WriteJSON sends body as is, without the envelope.
+func (h *Helper) WriteJSON(ctx context.Context, status int, body interface{}, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.WriteJSON(w, r, status, body, opts...)
+	})
+}
+
+// WriteError sends errBody in the standard error envelope.
+func (h *Helper) WriteError(ctx context.Context, status int, errBody responsehelper.ErrorBody, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.WriteError(w, r, status, errBody, opts...)
+	})
+}
+
+// OAuthError sends an RFC 6749 error response, eg: of a token endpoint.
+func (h *Helper) OAuthError(ctx context.Context, errCode string, description string, status int) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.OAuthError(w, r, errCode, description, status)
+	})
+}
+
+// ValidationFailed sends the validation errors of err.
+func (h *Helper) ValidationFailed(ctx context.Context, err error, opts ...responsehelper.ResponseOption) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ValidationFailed(w, r, err, opts...)
+	})
+}
+
+// NotFoundKey sends a 404 Not Found response with a translated message.
+func (h *Helper) NotFoundKey(ctx context.Context, key string, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.NotFoundKey(w, r, key, args...)
+	})
+}
+
+// BadRequestKey sends a 400 Bad Request response with a translated message.
+func (h *Helper) BadRequestKey(ctx context.Context, key string, details string, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.BadRequestKey(w, r, key, details, args...)
+	})
+}
+
+// UnauthorizedKey sends a 401 Unauthorized response with a translated message.
+func (h *Helper) UnauthorizedKey(ctx context.Context, key string, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.UnauthorizedKey(w, r, key, args...)
+	})
+}
+
+// ForbiddenKey sends a 403 Forbidden response with a translated message.
+func (h *Helper) ForbiddenKey(ctx context.Context, key string, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ForbiddenKey(w, r, key, args...)
+	})
+}
+
+// ConflictKey sends a 409 Conflict response with a translated message.
+func (h *Helper) ConflictKey(ctx context.Context, key string, err error, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.ConflictKey(w, r, key, err, args...)
+	})
+}
+
+// InternalErrorKey sends a 500 Internal Server Error response with a translated message.
+func (h *Helper) InternalErrorKey(ctx context.Context, key string, err error, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.InternalErrorKey(w, r, key, err, args...)
+	})
+}
+
+// RespondCode sends the error response registered for code.
+func (h *Helper) RespondCode(ctx context.Context, code string, args ...interface{}) events.APIGatewayProxyResponse {
+	return h.respond(ctx, func(w http.ResponseWriter, r *http.Request) {
+		h.responder.RespondCode(w, r, code, args...)
+	})
+}
@@ -0,0 +1,170 @@
+package lambdaadapter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/lambdaadapter"
+	"github.com/aruncs31s/responsehelper/msgpack"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetaOfTheContext(t *testing.T) {
+	h := lambdaadapter.New()
+	ctx := lambdaadapter.WithMeta(context.Background(), responsehelper.Meta{RequestID: "req-1", Version: "v1", Path: "/users"})
+
+	for name, resp := range map[string]events.APIGatewayProxyResponse{
+		"success": h.Success(ctx, gin.H{"id": 1}),
+		"error":   h.NotFound(ctx, "missing"),
+	} {
+		var body struct {
+			Meta map[string]interface{} `json:"meta"`
+		}
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			t.Fatalf("%s: %v\nbody: %s", name, err, resp.Body)
+		}
+		if body.Meta["requestId"] != "req-1" || body.Meta["version"] != "v1" || body.Meta["path"] != "/users" {
+			t.Errorf("%s: meta = %v, want the one of the context", name, body.Meta)
+		}
+		if got := resp.Headers["Content-Type"]; got != "application/json; charset=utf-8" || resp.IsBase64Encoded {
+			t.Errorf("%s: Content-Type = %q, base64 = %t, want JSON as text", name, got, resp.IsBase64Encoded)
+		}
+	}
+}
+
+func TestStatusOfTheResponse(t *testing.T) {
+	h := lambdaadapter.New(responsehelper.WithDefaultFormat(responsehelper.FormatGoogle))
+	resp := h.InternalError(context.Background(), "An unexpected error occurred", errors.New("db down"))
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+	if !strings.Contains(resp.Body, `"status":"INTERNAL"`) {
+		t.Errorf("body = %s, want the Google error format of the options", resp.Body)
+	}
+}
+
+func TestAcceptOfTheRequest(t *testing.T) {
+	h := lambdaadapter.New(responsehelper.WithContentNegotiation(true))
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"REST API", withRequest(context.Background(), "/users/42", "application/xml")},
+		{"HTTP API", withRequestV2(context.Background(), "/users/42", "text/xml")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := h.Success(tc.ctx, gin.H{"id": 42})
+
+			if got := resp.Headers["Content-Type"]; got != "application/xml; charset=utf-8" || resp.IsBase64Encoded {
+				t.Errorf("Content-Type = %q, base64 = %t, want XML as text", got, resp.IsBase64Encoded)
+			}
+			if !strings.HasPrefix(resp.Body, "<response>") || !strings.Contains(resp.Body, "<data><id>42</id></data>") {
+				t.Errorf("body = %s, want the data as XML", resp.Body)
+			}
+		})
+	}
+	if got := h.Success(context.Background(), gin.H{"id": 42}).Headers["Content-Type"]; got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q without a request, want JSON", got)
+	}
+}
+
+func withRequest(ctx context.Context, path, accept string) context.Context {
+	return lambdaadapter.WithRequest(ctx, events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       path,
+		Headers:    map[string]string{"accept": accept},
+	})
+}
+
+func withRequestV2(ctx context.Context, path, accept string) context.Context {
+	req := events.APIGatewayV2HTTPRequest{RawPath: path, Headers: map[string]string{"accept": accept}}
+	req.RequestContext.HTTP.Method = http.MethodGet
+	return lambdaadapter.WithRequestV2(ctx, req)
+}
+
+func TestRequestID(t *testing.T) {
+	h := lambdaadapter.New()
+	header := http.CanonicalHeaderKey(responsehelper.RequestIDHeader)
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "aws-req-1"})
+
+	if got := h.NotFound(ctx, "missing").Headers[header]; got != "aws-req-1" {
+		t.Errorf("%s = %q, want the request ID of the invocation", responsehelper.RequestIDHeader, got)
+	}
+	ctx = lambdaadapter.WithValue(ctx, responsehelper.RequestIDKey, "req-1")
+	if got := h.NotFound(ctx, "missing").Headers[header]; got != "req-1" {
+		t.Errorf("%s = %q, want the request ID of the context", responsehelper.RequestIDHeader, got)
+	}
+	if got, ok := h.NotFound(context.Background(), "missing").Headers[header]; ok {
+		t.Errorf("%s = %q without a request ID", responsehelper.RequestIDHeader, got)
+	}
+}
+
+func TestBinaryBody(t *testing.T) {
+	ctx := lambdaadapter.WithRequest(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		Path:       "/users/42",
+		Headers:    map[string]string{"Accept": msgpack.ContentType},
+	})
+	resp := lambdaadapter.New(responsehelper.WithContentNegotiation(true)).Success(ctx, gin.H{"id": 42})
+
+	if !resp.IsBase64Encoded || resp.Headers["Content-Type"] != msgpack.ContentType {
+		t.Fatalf("base64 = %t, Content-Type = %q, want a base64 MessagePack body", resp.IsBase64Encoded, resp.Headers["Content-Type"])
+	}
+	body, err := base64.StdEncoding.DecodeString(resp.Body)
+	if err != nil || len(body) == 0 || body[0]&0xf0 != 0x80 {
+		t.Errorf("body = %q, %v, want a base64 MessagePack map", resp.Body, err)
+	}
+}
+
+func TestNoContent(t *testing.T) {
+	resp := lambdaadapter.New().NoContent(context.Background())
+	if resp.StatusCode != http.StatusNoContent || resp.Body != "" || resp.IsBase64Encoded {
+		t.Errorf("response = %+v, want an empty 204", resp)
+	}
+}
+
+func TestV2(t *testing.T) {
+	resp := events.APIGatewayProxyResponse{
+		StatusCode: http.StatusOK,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8", "Set-Cookie": "a=1"},
+		MultiValueHeaders: map[string][]string{
+			"Content-Type": {"application/json; charset=utf-8"},
+			"Set-Cookie":   {"a=1", "b=2"},
+		},
+		Body: `{"success":true}`,
+	}
+	v2 := lambdaadapter.V2(resp)
+
+	if v2.StatusCode != http.StatusOK || v2.Body != resp.Body || v2.Headers["Content-Type"] != "application/json; charset=utf-8" {
+		t.Errorf("V2 = %+v", v2)
+	}
+	if len(v2.Cookies) != 2 || v2.Cookies[0] != "a=1" || v2.Cookies[1] != "b=2" {
+		t.Errorf("cookies = %v, want both Set-Cookie values", v2.Cookies)
+	}
+	if _, ok := v2.Headers["Set-Cookie"]; ok {
+		t.Errorf("headers = %v, want Set-Cookie moved to the cookies", v2.Headers)
+	}
+}
+
+func TestWrapSharesTheConfiguration(t *testing.T) {
+	helper := responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true))
+	helper.RegisterCode("QUOTA_EXCEEDED", http.StatusPaymentRequired, "Your quota is exhausted")
+	h := lambdaadapter.Wrap(helper)
+
+	if resp := h.Conflict(context.Background(), "Email already registered", errors.New("pq: duplicate key")); bytes.Contains([]byte(resp.Body), []byte("pq:")) {
+		t.Errorf("body = %s, want the error sanitized", resp.Body)
+	}
+	if resp := h.RespondCode(context.Background(), "QUOTA_EXCEEDED"); resp.StatusCode != http.StatusPaymentRequired {
+		t.Errorf("status = %d, want the one of the registered code", resp.StatusCode)
+	}
+}
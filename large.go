@@ -0,0 +1,155 @@
+package responsehelper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// largeFlushSize is the amount of data SuccessLarge writes between flushes.
+const largeFlushSize = 64 << 10
+
+func (r *Core) SuccessLarge(c Exchange, data interface{}) {
+	if _, ok := r.jsonpCallback(c); ok || r.responseFormat(c) != MIMEJSON {
+		r.renderSuccess(c, "SuccessLarge", http.StatusOK, SuccessEnvelope{
+			Data:    nullable(data),
+			Success: true,
+		})
+		return
+	}
+	if r.secondResponse(c, "SuccessLarge") {
+		return
+	}
+	response := sentResponse{status: http.StatusOK, options: responseOptions{method: "SuccessLarge"}, streamed: true}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	if r.invalidRawJSON(data) {
+		r.InternalError(c, "An unexpected error occurred", errInvalidRawJSON, helperCall(nil, "SuccessLarge", errInvalidRawJSON)...)
+		return
+	}
+	data = r.fillEmptyCollections(data)
+	// the members after the data are marshalled first, so an error can still be sent
+	suffix, err := r.marshalEnvelope(&SuccessEnvelope{
+		Meta:     r.successMeta(c, "SuccessLarge"),
+		Success:  true,
+		Warnings: responseWarnings(c, nil),
+	})
+	if err != nil {
+		r.InternalError(c, "An unexpected error occurred", err, helperCall(nil, "SuccessLarge", err)...)
+		return
+	}
+	setHeader(c, "Content-Type", jsonContentType)
+	r.writeResponse(c, response, func(c Exchange) {
+		c.WriteHeader(http.StatusOK)
+		stream := newLargeStream(c, &r.config)
+		stream.writeString(`{"data":`)
+		r.streamData(stream, data)
+		stream.writeString(",")
+		stream.write(suffix[1:])
+		if stream.err != nil {
+			// the header and part of the body are sent, an error object appended
+			// to them would not be valid JSON, so the connection is closed
+			r.warnf("streaming SuccessLarge response: %v", stream.err)
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+// streamData writes data to stream, a slice or an array one item at a time.
+func (r *Core) streamData(stream *largeStream, data interface{}) {
+	if raw, ok := data.(json.RawMessage); ok && len(raw) > 0 {
+		stream.write(raw)
+		return
+	}
+	value := reflect.ValueOf(data)
+	streamed := (value.Kind() == reflect.Slice && !value.IsNil() && value.Type().Elem().Kind() != reflect.Uint8 ||
+		value.Kind() == reflect.Array) && !customEncoding(value.Type())
+	if !streamed {
+		stream.encode(data)
+		return
+	}
+	stream.writeString("[")
+	for i := 0; i < value.Len() && stream.err == nil; i++ {
+		if i > 0 {
+			stream.writeString(",")
+		}
+		item := value.Index(i)
+		// slice items are encoded through their address, like encoding/json
+		// does, which saves copying them
+		var v interface{}
+		if item.CanAddr() {
+			v = item.Addr().Interface()
+		} else {
+			v = item.Interface()
+		}
+		if linked, ok := r.linkedItem(v); ok {
+			v = linked
+		}
+		stream.encode(v)
+	}
+	stream.writeString("]")
+}
+
+// largeStream writes a SuccessLarge body, flushing it every largeFlushSize
+// bytes. It keeps the first error and writes nothing after it.
+type largeStream struct {
+	w         Exchange
+	cfg       *config
+	buf       bytes.Buffer
+	encoder   *json.Encoder
+	unflushed int
+	err       error
+}
+
+func newLargeStream(w Exchange, cfg *config) *largeStream {
+	s := &largeStream{w: w, cfg: cfg}
+	if cfg.jsonEncoder == nil {
+		s.encoder = json.NewEncoder(&s.buf)
+	}
+	return s
+}
+
+// encode writes the JSON of v, encoded in a reused buffer with encoding/json
+// or with the encoder of WithJSONEncoder.
+func (s *largeStream) encode(v interface{}) {
+	if s.err != nil {
+		return
+	}
+	if s.encoder == nil {
+		var body []byte
+		if body, s.err = s.cfg.marshalJSON(v); s.err == nil {
+			s.write(body)
+		}
+		return
+	}
+	s.buf.Reset()
+	if s.err = s.encoder.Encode(v); s.err == nil {
+		// without the newline Encode ends with
+		s.write(s.buf.Bytes()[:s.buf.Len()-1])
+	}
+}
+
+func (s *largeStream) writeString(str string) {
+	if s.err == nil {
+		_, s.err = io.WriteString(s.w, str)
+	}
+}
+
+func (s *largeStream) write(b []byte) {
+	if s.err != nil {
+		return
+	}
+	if _, s.err = s.w.Write(b); s.err != nil {
+		return
+	}
+	if s.unflushed += len(b); s.unflushed >= largeFlushSize {
+		if flusher, ok := s.w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		s.unflushed = 0
+	}
+}
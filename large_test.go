@@ -0,0 +1,163 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type largeEvent struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	Note string `json:"note,omitempty"`
+}
+
+// brokenEvent cannot be encoded.
+type brokenEvent struct{}
+
+func (brokenEvent) MarshalJSON() ([]byte, error) { return nil, errors.New("broken event") }
+
+// chunkWriter records the size of the largest write.
+type chunkWriter struct {
+	discardWriter
+	largest int
+	total   int
+	flushes int
+}
+
+func (w *chunkWriter) Write(b []byte) (int, error) {
+	w.largest = max(w.largest, len(b))
+	w.total += len(b)
+	return len(b), nil
+}
+
+func (w *chunkWriter) Flush() { w.flushes++ }
+
+// largeEvents returns n events of about 100 bytes of JSON each.
+func largeEvents(n int) []largeEvent {
+	events := make([]largeEvent, n)
+	note := strings.Repeat("x", 64)
+	for i := range events {
+		events[i] = largeEvent{ID: i, Kind: "click", Note: note}
+	}
+	return events
+}
+
+func TestSuccessLargeSendsTheBodyOfSuccess(t *testing.T) {
+	var nilEvents []largeEvent
+	for name, data := range map[string]interface{}{
+		"slice":       largeEvents(3),
+		"array":       [2]largeEvent{{ID: 1}, {ID: 2, Note: "<b>"}},
+		"empty slice": []largeEvent{},
+		"nil slice":   nilEvents,
+		"map":         map[string]int{"b": 2, "a": 1},
+		"nil":         nil,
+		"raw JSON":    responsehelper.RawJSON([]byte(`[1, 2]`)),
+		"linked":      []linkedUser{{ID: 7, Name: "arun"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, h := range []responsehelper.ResponseHelper{responsehelper.NewResponseHelper(), responsehelper.New()} {
+				c, w := newContext(http.MethodGet, "/events")
+				c.Set(responsehelper.MetaKey, gin.H{"requestId": "req-1"})
+				h.SuccessLarge(c, data)
+				plain, want := newContext(http.MethodGet, "/events")
+				plain.Set(responsehelper.MetaKey, gin.H{"requestId": "req-1"})
+				h.Success(plain, data)
+
+				if w.Code != want.Code || w.Body.String() != want.Body.String() {
+					t.Errorf("sent %d\n%s\nwant %d\n%s", w.Code, w.Body, want.Code, want.Body)
+				}
+				if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+					t.Errorf("Content-Type = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestSuccessLargeStreamsInChunks(t *testing.T) {
+	engine := gin.New()
+	h := responsehelper.NewResponseHelper()
+	events := largeEvents(20000)
+	engine.GET("/events", func(c *gin.Context) { h.SuccessLarge(c, events) })
+	w := &chunkWriter{discardWriter: discardWriter{header: http.Header{}}}
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if w.total < len(events)*100 {
+		t.Fatalf("wrote %d bytes, want the whole listing", w.total)
+	}
+	if w.largest > 1024 {
+		t.Errorf("the largest write is %d bytes, want one item at a time", w.largest)
+	}
+	if w.flushes < w.total/(128<<10) {
+		t.Errorf("flushed %d times for %d bytes", w.flushes, w.total)
+	}
+}
+
+func TestSuccessLargeClosesTheConnectionOnAnEncodingError(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/events")
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("status = %d, the headers were sent first", w.Code)
+		}
+		if got, want := w.Body.String(), `{"data":[{"id":1,"kind":"a"},`; got != want {
+			t.Errorf("body = %s, want the truncated %s", got, want)
+		}
+	}()
+	responsehelper.NewResponseHelper().SuccessLarge(c, []interface{}{largeEvent{ID: 1, Kind: "a"}, brokenEvent{}, largeEvent{ID: 2}})
+	t.Fatal("SuccessLarge returned after an encoding error")
+}
+
+func TestSuccessLargeSendsAnErrorBeforeStreaming(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/events")
+	c.Set(responsehelper.MetaKey, gin.H{"broken": brokenEvent{}})
+	responsehelper.NewResponseHelper().SuccessLarge(c, largeEvents(2))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+// BenchmarkSuccessLarge sends about 50MB of events: the allocations of
+// SuccessLarge stay a fraction of the payload, Success holds it twice.
+func BenchmarkSuccessLarge(b *testing.B) {
+	events := largeEvents(500000)
+	h := responsehelper.NewResponseHelper()
+	for _, bench := range []struct {
+		name    string
+		respond gin.HandlerFunc
+	}{
+		{"SuccessLarge", func(c *gin.Context) { h.SuccessLarge(c, events) }},
+		{"Success", func(c *gin.Context) { h.Success(c, events) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			serve := benchServe(bench.respond)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				serve()
+			}
+		})
+	}
+}
+
+func TestSuccessLargeWithAWriterThatCannotFlush(t *testing.T) {
+	engine := gin.New()
+	h := responsehelper.NewResponseHelper()
+	events := largeEvents(2000)
+	engine.GET("/events", func(c *gin.Context) { h.SuccessLarge(c, events) })
+	w := httptest.NewRecorder()
+	// only the methods of http.ResponseWriter, no Flush
+	engine.ServeHTTP(struct{ http.ResponseWriter }{w}, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if w.Code != http.StatusOK || w.Body.Len() < len(events)*100 {
+		t.Errorf("sent %d with %d bytes", w.Code, w.Body.Len())
+	}
+}
@@ -0,0 +1,55 @@
+package responsehelper
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// errNilDataFn is the error of the InternalError sent by SuccessIfModified
+// without a dataFn.
+var errNilDataFn = errors.New("responsehelper: SuccessIfModified called with a nil dataFn")
+
+func (r *Core) SuccessIfModified(c Exchange, lastModified time.Time, dataFn func() (interface{}, error), opts ...ResponseOption) {
+	if r.secondResponse(c, "SuccessIfModified") {
+		return
+	}
+	if dataFn == nil {
+		r.InternalError(c, "An unexpected error occurred", errNilDataFn, helperCall(nil, "SuccessIfModified", errNilDataFn)...)
+		return
+	}
+	if !lastModified.IsZero() {
+		lastModified = lastModified.UTC().Truncate(time.Second)
+		setHeader(c, "Last-Modified", lastModified.Format(http.TimeFormat))
+		if notModifiedSince(c.Request(), lastModified) {
+			r.renderEmpty(c, "SuccessIfModified", http.StatusNotModified)
+			return
+		}
+	}
+	data, err := dataFn()
+	if err != nil {
+		r.InternalError(c, "An unexpected error occurred", err, helperCall(nil, "SuccessIfModified", err)...)
+		return
+	}
+	r.renderSuccess(c, "SuccessIfModified", http.StatusOK, SuccessEnvelope{
+		Data:    nullable(data),
+		Success: true,
+	}, opts...)
+}
+
+// notModifiedSince reports whether the If-Modified-Since of request is not
+// before lastModified. Only GET and HEAD requests are conditional, and the
+// header is ignored along with an If-None-Match, as RFC 9110 asks.
+func notModifiedSince(request *http.Request, lastModified time.Time) bool {
+	if request == nil || (request.Method != http.MethodGet && request.Method != http.MethodHead) {
+		return false
+	}
+	if request.Header.Get("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(request.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
@@ -0,0 +1,109 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// updatedAt is the time the resource of the tests last changed, with a
+// fraction of a second.
+var updatedAt = time.Date(2024, 5, 1, 10, 0, 0, 750_000_000, time.UTC)
+
+// ifModified sends a request with If-Modified-Since set to since, when not
+// empty, to SuccessIfModified and reports whether dataFn was called.
+func ifModified(method, since string, lastModified time.Time, opts ...responsehelper.ResponseOption) (*httptest.ResponseRecorder, bool) {
+	c, w := newContext(method, "/articles/1")
+	if since != "" {
+		c.Request.Header.Set("If-Modified-Since", since)
+	}
+	called := false
+	responsehelper.NewResponseHelper().SuccessIfModified(c, lastModified, func() (interface{}, error) {
+		called = true
+		return gin.H{"id": 1}, nil
+	}, opts...)
+	return w, called
+}
+
+func TestSuccessIfModified(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		method       string
+		since        string
+		lastModified time.Time
+		notModified  bool
+	}{
+		{"no header", http.MethodGet, "", updatedAt, false},
+		{"same second", http.MethodGet, "Wed, 01 May 2024 10:00:00 GMT", updatedAt, true},
+		{"later", http.MethodGet, "Wed, 01 May 2024 11:00:00 GMT", updatedAt, true},
+		{"earlier", http.MethodGet, "Wed, 01 May 2024 09:59:59 GMT", updatedAt, false},
+		{"sub-second change", http.MethodGet, "Wed, 01 May 2024 10:00:00 GMT", updatedAt.Add(300 * time.Millisecond), false},
+		{"other time zone", http.MethodGet, "Wed, 01 May 2024 10:00:00 GMT", updatedAt.In(time.FixedZone("IST", 5*3600+1800)), true},
+		{"local time", http.MethodGet, "Wed, 01 May 2024 10:00:00 GMT", time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600)), true},
+		{"RFC 850 date", http.MethodGet, "Wednesday, 01-May-24 10:00:00 GMT", updatedAt, true},
+		{"invalid date", http.MethodGet, "yesterday", updatedAt, false},
+		{"HEAD", http.MethodHead, "Wed, 01 May 2024 10:00:00 GMT", updatedAt, true},
+		{"POST", http.MethodPost, "Wed, 01 May 2024 10:00:00 GMT", updatedAt, false},
+		{"zero time", http.MethodGet, "Wed, 01 May 2024 10:00:00 GMT", time.Time{}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w, called := ifModified(tt.method, tt.since, tt.lastModified)
+
+			if tt.notModified {
+				if w.Code != http.StatusNotModified || w.Body.Len() != 0 || called {
+					t.Errorf("status = %d, body %q, dataFn called = %t, want a 304 without calling dataFn", w.Code, w.Body, called)
+				}
+			} else if w.Code != http.StatusOK || !called {
+				t.Errorf("status = %d, dataFn called = %t, want a 200 with its data", w.Code, called)
+			}
+			want := tt.lastModified.UTC().Truncate(time.Second).Format(http.TimeFormat)
+			if tt.lastModified.IsZero() {
+				want = ""
+			}
+			if got := w.Header().Get("Last-Modified"); got != want {
+				t.Errorf("Last-Modified = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSuccessIfModifiedIgnoresItWithIfNoneMatch(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/articles/1")
+	c.Request.Header.Set("If-Modified-Since", "Wed, 01 May 2024 11:00:00 GMT")
+	c.Request.Header.Set("If-None-Match", `"v1"`)
+	responsehelper.NewResponseHelper().SuccessIfModified(c, updatedAt, func() (interface{}, error) { return gin.H{"id": 1}, nil })
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestSuccessIfModifiedOptions(t *testing.T) {
+	w, _ := ifModified(http.MethodGet, "", updatedAt, responsehelper.WithRateLimit(responsehelper.RateLimitInfo{Limit: 100, Remaining: 7}))
+	if got := w.Header().Get(responsehelper.RateLimitRemainingHeader); got != "7" {
+		t.Errorf("%s = %q, want %q", responsehelper.RateLimitRemainingHeader, got, "7")
+	}
+	assertField(t, w, "data.id", 1.0)
+}
+
+func TestSuccessIfModifiedErrors(t *testing.T) {
+	for name, dataFn := range map[string]func() (interface{}, error){
+		"dataFn error": func() (interface{}, error) { return nil, errors.New("db down") },
+		"nil dataFn":   nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/articles/1")
+			responsehelper.NewResponseHelper().SuccessIfModified(c, updatedAt, dataFn, responsehelper.WithRateLimit(responsehelper.RateLimitInfo{Limit: 100, Remaining: 7}))
+
+			assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+			if len(c.Errors) != 0 {
+				t.Errorf("c.Errors = %v", c.Errors)
+			}
+		})
+	}
+}
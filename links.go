@@ -0,0 +1,183 @@
+package responsehelper
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultPageParam is the page query parameter of WithPaginationLinks("").
+const defaultPageParam = "page"
+
+// WithPaginationLinks adds RFC 8288 Link headers to the responses of
+// SuccessWithPagination sent with a Pagination, eg:
+//
+//	Link: <https://api.example.com/users?page=1&size=10>; rel="first", <https://api.example.com/users?page=1&size=10>; rel="prev", <https://api.example.com/users?page=3&size=10>; rel="next", <https://api.example.com/users?page=3&size=10>; rel="last"
+//
+// The links are the URL of the request with the query parameter pageParam,
+// "page" when empty, set to the page of the link. The other query parameters
+// are kept as they were sent. There is no prev link on the first page and no
+// next link on the last one. See WithBaseURL and WithForwardedHeaders for
+// services behind a proxy.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithPaginationLinks("page"))
+func WithPaginationLinks(pageParam string) Option {
+	return func(cfg *config) {
+		if pageParam == "" {
+			pageParam = defaultPageParam
+		}
+		cfg.pageLinkParam = pageParam
+	}
+}
+
+// WithBaseURL sets the scheme, the host and the path prefix of the links
+// built from the request URL, eg: "https://api.example.com/v1" when a proxy
+// strips "/v1" before the request reaches the service. Relative Links and
+// locations are resolved against it as well.
+func WithBaseURL(baseURL string) Option {
+	return func(cfg *config) {
+		parsed, err := url.Parse(baseURL)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			cfg.warnf("ignoring base URL %q: not an absolute URL", baseURL)
+			return
+		}
+		cfg.linkBaseURL = parsed
+	}
+}
+
+// WithForwardedHeaders takes the scheme and the host of the links built from
+// the request URL from the X-Forwarded-Proto and X-Forwarded-Host headers.
+// Enable it only behind a proxy setting them, clients can send them too.
+// WithBaseURL takes precedence.
+func WithForwardedHeaders(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.forwardedHeaders = enabled
+	}
+}
+
+// setPaginationLinks adds the Link header of the page p of a listing.
+func (cfg *config) setPaginationLinks(c Exchange, p Pagination) {
+	pages := cfg.pageLinks(c, p)
+	if len(pages) == 0 {
+		return
+	}
+	links := make([]string, len(pages))
+	for i, page := range pages {
+		links[i] = "<" + escapeLinkTarget(page.target) + `>; rel="` + page.rel + `"`
+	}
+	c.Header().Add(LinkHeader, strings.Join(links, ", "))
+}
+
+// pageLink is a link to a page of a listing.
+type pageLink struct {
+	rel    string
+	target string
+}
+
+// pageLinks returns the first, prev, next and last links of p, none without
+// WithPaginationLinks.
+func (cfg *config) pageLinks(c Exchange, p Pagination) []pageLink {
+	if cfg.pageLinkParam == "" || c.Request() == nil || c.Request().URL == nil {
+		return nil
+	}
+	base := cfg.requestBaseURL(c) + c.Request().URL.EscapedPath()
+	var links []pageLink
+	link := func(page int, rel string) {
+		target := base + "?" + withQueryParam(c.Request().URL.RawQuery, cfg.pageLinkParam, strconv.Itoa(page))
+		links = append(links, pageLink{rel: rel, target: target})
+	}
+	link(1, "first")
+	if p.HasPrev {
+		link(p.CurrentPage-1, "prev")
+	}
+	if p.HasNext {
+		link(p.CurrentPage+1, "next")
+	}
+	if p.TotalPages > 0 {
+		link(p.TotalPages, "last")
+	}
+	return links
+}
+
+// requestBaseURL returns the scheme, the host and the path prefix of the
+// URLs pointing back at the service, without a trailing slash.
+func (cfg *config) requestBaseURL(c Exchange) string {
+	if cfg.linkBaseURL != nil {
+		return cfg.linkBase()
+	}
+	scheme, host := "http", c.Request().Host
+	if c.Request().TLS != nil {
+		scheme = "https"
+	}
+	if cfg.forwardedHeaders {
+		if proto := strings.ToLower(firstForwardedValue(requestHeader(c, "X-Forwarded-Proto"))); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwardedHost := firstForwardedValue(requestHeader(c, "X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+	return scheme + "://" + host
+}
+
+// linkBase returns WithBaseURL without a trailing slash.
+func (cfg *config) linkBase() string {
+	return cfg.linkBaseURL.Scheme + "://" + cfg.linkBaseURL.Host + strings.TrimRight(cfg.linkBaseURL.EscapedPath(), "/")
+}
+
+// firstForwardedValue returns the value set by the proxy closest to the
+// client in a comma separated X-Forwarded-* header.
+func firstForwardedValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
+
+// withQueryParam returns the raw query with the parameter name set to value.
+// The other parameters are kept as they are, in their order and with their
+// encoding, repeated occurrences of name are dropped.
+func withQueryParam(rawQuery, name, value string) string {
+	param := url.QueryEscape(name) + "=" + url.QueryEscape(value)
+	var pairs []string
+	replaced := false
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if key != name {
+			pairs = append(pairs, pair)
+			continue
+		}
+		if !replaced {
+			pairs = append(pairs, param)
+			replaced = true
+		}
+	}
+	if !replaced {
+		pairs = append(pairs, param)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapeLinkTarget percent-encodes the bytes that are not allowed in a URI,
+// so a raw query sent by the client cannot break out of the <> of a link.
+func escapeLinkTarget(target string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(target); i++ {
+		ch := target[i]
+		if ch <= ' ' || ch >= 0x7f || strings.IndexByte(`<>"\^`+"`{|}", ch) >= 0 {
+			b.WriteByte('%')
+			b.WriteByte(hex[ch>>4])
+			b.WriteByte(hex[ch&0x0f])
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
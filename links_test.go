@@ -0,0 +1,194 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// linksEngine answers /users with page "page" of 35 users split in pages of 10.
+func linksEngine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(opts...)
+	engine := gin.New()
+	engine.GET("/users", func(c *gin.Context) {
+		page, _ := responsehelper.ParsePagination(c, h)
+		h.SuccessWithPagination(c, []int{}, responsehelper.NewPagination(page.Page, 10, 35))
+	})
+	engine.GET("/empty", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []int{}, responsehelper.NewPagination(1, 10, 0))
+	})
+	engine.GET("/map", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []int{}, gin.H{"page": 1})
+	})
+	return engine
+}
+
+// pageLinks returns the targets of the Link header by relation.
+func pageLinks(t *testing.T, w *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	links := map[string]string{}
+	header := w.Header().Get(responsehelper.LinkHeader)
+	if header == "" {
+		return links
+	}
+	for _, link := range strings.Split(header, ", ") {
+		target, rel, ok := strings.Cut(link, `>; rel="`)
+		if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(rel, `"`) {
+			t.Fatalf("malformed link %q in %q", link, header)
+		}
+		links[strings.TrimSuffix(rel, `"`)] = strings.TrimPrefix(target, "<")
+	}
+	return links
+}
+
+func TestPaginationLinks(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		target string
+		want   map[string]string
+	}{
+		{"middle page", "/users?size=10&page=2", map[string]string{
+			"first": "http://example.com/users?size=10&page=1",
+			"prev":  "http://example.com/users?size=10&page=1",
+			"next":  "http://example.com/users?size=10&page=3",
+			"last":  "http://example.com/users?size=10&page=4",
+		}},
+		{"first page", "/users?page=1", map[string]string{
+			"first": "http://example.com/users?page=1",
+			"next":  "http://example.com/users?page=2",
+			"last":  "http://example.com/users?page=4",
+		}},
+		{"last page", "/users?page=4", map[string]string{
+			"first": "http://example.com/users?page=1",
+			"prev":  "http://example.com/users?page=3",
+			"last":  "http://example.com/users?page=4",
+		}},
+		{"no page parameter", "/users?q=arun", map[string]string{
+			"first": "http://example.com/users?q=arun&page=1",
+			"next":  "http://example.com/users?q=arun&page=2",
+			"last":  "http://example.com/users?q=arun&page=4",
+		}},
+		{"encoded and repeated parameters", "/users?q=a%20b%2Cc&tag=x&tag=y&page=2&page=3&sort=-name", map[string]string{
+			"first": "http://example.com/users?q=a%20b%2Cc&tag=x&tag=y&page=1&sort=-name",
+			"prev":  "http://example.com/users?q=a%20b%2Cc&tag=x&tag=y&page=1&sort=-name",
+			"next":  "http://example.com/users?q=a%20b%2Cc&tag=x&tag=y&page=3&sort=-name",
+			"last":  "http://example.com/users?q=a%20b%2Cc&tag=x&tag=y&page=4&sort=-name",
+		}},
+		{"encoded page name", "/users?p%61ge=2&q=%E0%B4%85", map[string]string{
+			"first": "http://example.com/users?page=1&q=%E0%B4%85",
+			"prev":  "http://example.com/users?page=1&q=%E0%B4%85",
+			"next":  "http://example.com/users?page=3&q=%E0%B4%85",
+			"last":  "http://example.com/users?page=4&q=%E0%B4%85",
+		}},
+		{"empty values", "/users?&q=&page=2&", map[string]string{
+			"first": "http://example.com/users?q=&page=1",
+			"prev":  "http://example.com/users?q=&page=1",
+			"next":  "http://example.com/users?q=&page=3",
+			"last":  "http://example.com/users?q=&page=4",
+		}},
+		{"encoded value on the last page", "/users?page=4&x=%3E", map[string]string{
+			"first": "http://example.com/users?page=1&x=%3E",
+			"prev":  "http://example.com/users?page=3&x=%3E",
+			"last":  "http://example.com/users?page=4&x=%3E",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(linksEngine(responsehelper.WithPaginationLinks("")), httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if got := pageLinks(t, w); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("links = %v\nwant %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPaginationLinksFormat(t *testing.T) {
+	w := serve(linksEngine(responsehelper.WithPaginationLinks("page")), httptest.NewRequest(http.MethodGet, "/users?page=2", nil))
+
+	want := `<http://example.com/users?page=1>; rel="first", <http://example.com/users?page=1>; rel="prev", ` +
+		`<http://example.com/users?page=3>; rel="next", <http://example.com/users?page=4>; rel="last"`
+	if got := w.Header().Get(responsehelper.LinkHeader); got != want {
+		t.Errorf("Link = %s\nwant %s", got, want)
+	}
+}
+
+func TestPaginationLinksEscapeTheRawQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?page=4", nil)
+	r.URL.RawQuery = `page=4&q=<a>"b"`
+	w := serve(linksEngine(responsehelper.WithPaginationLinks("")), r)
+
+	if got := pageLinks(t, w)["first"]; got != "http://example.com/users?page=1&q=%3Ca%3E%22b%22" {
+		t.Errorf("first = %s, want the raw query escaped", got)
+	}
+}
+
+func TestPaginationLinksBehindAProxy(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   []responsehelper.Option
+		header map[string]string
+		first  string
+	}{
+		{"forwarded headers ignored", nil,
+			map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "api.example.com"},
+			"http://example.com/users?page=1"},
+		{"forwarded headers", []responsehelper.Option{responsehelper.WithForwardedHeaders(true)},
+			map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Host": "api.example.com, proxy.internal"},
+			"https://api.example.com/users?page=1"},
+		{"unknown forwarded scheme", []responsehelper.Option{responsehelper.WithForwardedHeaders(true)},
+			map[string]string{"X-Forwarded-Proto": "javascript"},
+			"http://example.com/users?page=1"},
+		{"base URL", []responsehelper.Option{responsehelper.WithBaseURL("https://api.example.com/v1/")}, nil,
+			"https://api.example.com/v1/users?page=1"},
+		{"base URL wins", []responsehelper.Option{
+			responsehelper.WithBaseURL("https://api.example.com"),
+			responsehelper.WithForwardedHeaders(true),
+		}, map[string]string{"X-Forwarded-Host": "evil.example.com"},
+			"https://api.example.com/users?page=1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+			for key, value := range tc.header {
+				r.Header.Set(key, value)
+			}
+			w := serve(linksEngine(append(tc.opts, responsehelper.WithPaginationLinks(""))...), r)
+
+			if got := pageLinks(t, w)["first"]; got != tc.first {
+				t.Errorf("first = %s, want %s", got, tc.first)
+			}
+		})
+	}
+}
+
+func TestPaginationLinksEmptyListing(t *testing.T) {
+	w := serve(linksEngine(responsehelper.WithPaginationLinks("")), httptest.NewRequest(http.MethodGet, "/empty", nil))
+
+	want := map[string]string{"first": "http://example.com/empty?page=1"}
+	if got := pageLinks(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("links = %v, want %v", got, want)
+	}
+}
+
+func TestPaginationLinksOnlyForPagination(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   []responsehelper.Option
+		target string
+	}{
+		{"disabled", nil, "/users?page=2"},
+		{"other pagination value", []responsehelper.Option{responsehelper.WithPaginationLinks("")}, "/map"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(linksEngine(tc.opts...), httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if got := w.Header().Get(responsehelper.LinkHeader); got != "" {
+				t.Errorf("Link = %s, want none", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package responsehelper
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// WithLogger logs the error responses, and the success responses when
+// WithSuccessLogging is enabled, to logger. 5xx responses are logged at
+// Error and 4xx responses at Warn unless WithLogLevels says otherwise. The
+// warnings of the helper itself go to logger as well. nil disables logging.
+//
+// Example:
+//
+//	responsehelper.WithLogger(slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(cfg *config) {
+		cfg.logger = logger
+	}
+}
+
+// WithLogLevels overrides the level responses are logged at by status, eg:
+// map[int]slog.Level{http.StatusNotFound: slog.LevelDebug}.
+func WithLogLevels(levels map[int]slog.Level) Option {
+	return func(cfg *config) {
+		if cfg.logLevels == nil {
+			cfg.logLevels = make(map[int]slog.Level, len(levels))
+		}
+		for status, level := range levels {
+			cfg.logLevels[status] = level
+		}
+	}
+}
+
+// WithSuccessLogging logs the success responses at Debug, when a logger is set.
+func WithSuccessLogging(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.logSuccess = enabled
+	}
+}
+
+// logLevel returns the level a response with status is logged at.
+func (cfg *config) logLevel(status int) slog.Level {
+	if level, ok := cfg.logLevels[status]; ok {
+		return level
+	}
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// logResponse logs a response with its status, message, errorId, requestId,
+// path and the error passed to the helper.
+func (cfg *config) logResponse(c Exchange, status int, message string, err error) {
+	if cfg.logger == nil || (status < http.StatusBadRequest && !cfg.logSuccess) || detached(c) {
+		return
+	}
+	level := cfg.logLevel(status)
+	ctx := context.Background()
+	if c.Request() != nil {
+		ctx = c.Request().Context()
+	}
+	if !cfg.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.Int("status", status),
+		slog.String("path", requestPath(c)),
+	}
+	if message != "" {
+		attrs = append(attrs, slog.String("message", message))
+	}
+	if errorID := getString(c, ErrorIDKey); errorID != "" {
+		attrs = append(attrs, slog.String("errorId", errorID))
+	}
+	if requestID := requestID(c); requestID != "" {
+		attrs = append(attrs, slog.String("requestId", requestID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	msg := "response sent"
+	if status >= http.StatusBadRequest {
+		msg = "error response sent"
+	}
+	cfg.logger.LogAttrs(ctx, level, msg, attrs...)
+}
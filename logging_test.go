@@ -0,0 +1,122 @@
+package responsehelper_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// captureHandler is a slog.Handler keeping the records it handles.
+type captureHandler struct {
+	mu      sync.Mutex
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(_ context.Context, level slog.Level) bool { return level >= h.level }
+
+func (h *captureHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *captureHandler) WithGroup(string) slog.Handler { return h }
+
+// responseRecords returns the records logged about responses, with their attributes.
+func (h *captureHandler) responseRecords() []map[string]interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var records []map[string]interface{}
+	for _, record := range h.records {
+		if record.Message != "response sent" && record.Message != "error response sent" {
+			continue
+		}
+		attrs := map[string]interface{}{"level": record.Level}
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs[attr.Key] = attr.Value.Any()
+			return true
+		})
+		records = append(records, attrs)
+	}
+	return records
+}
+
+func TestLoggerServerError(t *testing.T) {
+	handler := &captureHandler{level: slog.LevelDebug}
+	h := responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(handler)))
+	boom := errors.New("boom")
+
+	c, w := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.RequestIDKey, "req-1")
+	h.InternalError(c, "oops", boom)
+
+	records := handler.responseRecords()
+	if len(records) != 1 {
+		t.Fatalf("%d records, want 1", len(records))
+	}
+	for key, want := range map[string]interface{}{
+		"level":     slog.LevelError,
+		"status":    int64(http.StatusInternalServerError),
+		"message":   "oops",
+		"path":      "/users",
+		"requestId": "req-1",
+		"errorId":   w.Header().Get(responsehelper.ErrorIDHeader),
+		"error":     boom,
+	} {
+		if got := records[0][key]; got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	handler := &captureHandler{level: slog.LevelDebug}
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithLogger(slog.New(handler)),
+		responsehelper.WithLogLevels(map[int]slog.Level{http.StatusNotFound: slog.LevelDebug}),
+	)
+	c, _ := newContext(http.MethodGet, "/users/42")
+	h.NotFound(c, "missing")
+	c, _ = newContext(http.MethodPost, "/users")
+	h.BadRequest(c, "bad", "")
+	c, _ = newContext(http.MethodGet, "/users")
+	h.Success(c, nil)
+
+	records := handler.responseRecords()
+	if len(records) != 2 {
+		t.Fatalf("%d records, want 2 as success logging is off: %v", len(records), records)
+	}
+	if records[0]["level"] != slog.LevelDebug || records[1]["level"] != slog.LevelWarn {
+		t.Errorf("levels = %v, %v, want DEBUG, WARN", records[0]["level"], records[1]["level"])
+	}
+}
+
+func TestLoggerSuccessLogging(t *testing.T) {
+	handler := &captureHandler{level: slog.LevelDebug}
+	h := responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(handler)), responsehelper.WithSuccessLogging(true))
+	c, _ := newContext(http.MethodGet, "/users")
+	h.Success(c, nil)
+
+	records := handler.responseRecords()
+	if len(records) != 1 || records[0]["level"] != slog.LevelDebug || records[0]["status"] != int64(http.StatusOK) {
+		t.Errorf("records = %v, want one DEBUG record of the 200", records)
+	}
+}
+
+func TestLoggerNil(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithLogger(nil)).InternalError(c, "oops", errors.New("boom"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d", w.Code)
+	}
+}
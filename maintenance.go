@@ -0,0 +1,43 @@
+package responsehelper
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceInfo describes an ongoing maintenance.
+type MaintenanceInfo struct {
+	// Message is sent as the error message. Defaults to "The service is under maintenance".
+	Message string
+	// RetryAfter is sent as the Retry-After header when positive.
+	RetryAfter time.Duration
+}
+
+// MaintenanceMiddleware aborts requests with the 503 envelope while check
+// reports maintenance. check runs for every request, so it can exclude routes
+// like health checks and be switched at runtime.
+//
+// Example:
+//
+//	var maintenance atomic.Bool
+//	router.Use(responsehelper.MaintenanceMiddleware(responseHelper, func(c *gin.Context) (bool, responsehelper.MaintenanceInfo) {
+//		if !maintenance.Load() || c.FullPath() == "/healthz" {
+//			return false, responsehelper.MaintenanceInfo{}
+//		}
+//		return true, responsehelper.MaintenanceInfo{RetryAfter: 10 * time.Minute}
+//	}))
+func MaintenanceMiddleware(h ResponseHelper, check func(*gin.Context) (bool, MaintenanceInfo)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		active, info := check(c)
+		if !active {
+			return
+		}
+		message := info.Message
+		if message == "" {
+			message = "The service is under maintenance"
+		}
+		c.Abort()
+		h.ServiceUnavailable(c, message, info.RetryAfter)
+	}
+}
@@ -0,0 +1,57 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	var maintenance atomic.Bool
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(responsehelper.MaintenanceMiddleware(h, func(c *gin.Context) (bool, responsehelper.MaintenanceInfo) {
+		if !maintenance.Load() || c.FullPath() == "/healthz" {
+			return false, responsehelper.MaintenanceInfo{}
+		}
+		return true, responsehelper.MaintenanceInfo{RetryAfter: 10 * time.Minute}
+	}))
+	engine.GET("/users", func(c *gin.Context) { h.Success(c, nil) })
+	engine.GET("/healthz", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assertSuccess(t, w)
+
+	maintenance.Store(true)
+	w = serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assertError(t, w, http.StatusServiceUnavailable, "The service is under maintenance")
+	if got := w.Header().Get(responsehelper.RetryAfterHeader); got != "600" {
+		t.Errorf("%s = %q, want %q", responsehelper.RetryAfterHeader, got, "600")
+	}
+	if w := serve(engine, httptest.NewRequest(http.MethodGet, "/healthz", nil)); w.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d during maintenance", w.Code)
+	}
+
+	maintenance.Store(false)
+	w = serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assertSuccess(t, w)
+}
+
+func TestMaintenanceMessage(t *testing.T) {
+	engine := gin.New()
+	engine.Use(responsehelper.MaintenanceMiddleware(responsehelper.NewResponseHelper(), func(*gin.Context) (bool, responsehelper.MaintenanceInfo) {
+		return true, responsehelper.MaintenanceInfo{Message: "Back at 10:00"}
+	}))
+	engine.GET("/users", func(c *gin.Context) { t.Error("the handler ran during maintenance") })
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	assertError(t, w, http.StatusServiceUnavailable, "Back at 10:00")
+	if got := w.Header().Get(responsehelper.RetryAfterHeader); got != "" {
+		t.Errorf("%s = %q without a retry delay", responsehelper.RetryAfterHeader, got)
+	}
+}
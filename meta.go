@@ -0,0 +1,357 @@
+package responsehelper
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MetaKey is the gin context key holding the meta sent with every response.
+	MetaKey = "meta"
+	// RequestIDKey is the gin context key holding the request ID.
+	RequestIDKey = "requestId"
+	// RequestIDHeader is the default header carrying the request ID, both in
+	// the request and in the response.
+	RequestIDHeader = "X-Request-ID"
+)
+
+// Meta is the request metadata set by MetaMiddleware and sent as "meta".
+type Meta struct {
+	RequestID string    `json:"requestId" example:"9b2f6c1e-8d4a-4f3b-a1c7-2e5d9f0b6a84"`
+	Timestamp time.Time `json:"timestamp" example:"2024-05-01T12:00:00Z"`
+	Version   string    `json:"version,omitempty" example:"1.4.0"`
+	Path      string    `json:"path" example:"/users/42"`
+	// DurationMs is the time spent on the request when the response is
+	// written, in milliseconds. It is only sent with WithRequestDuration.
+	DurationMs float64 `json:"durationMs,omitempty" example:"12.5"`
+	// Extra are sent next to the fields above, eg: "traceId". Set them with SetMetaField.
+	Extra map[string]interface{} `json:"-"`
+
+	// clock is the clock of MetaMiddleware, measuring the duration.
+	clock func() time.Time
+	// started is when the request reached MetaMiddleware, with its monotonic reading.
+	started time.Time
+}
+
+// metaKeys are the members of the meta object Extra cannot override.
+var metaKeys = map[string]bool{"requestId": true, "timestamp": true, "version": true, "path": true, "durationMs": true}
+
+// MarshalJSON sends Extra as members of the meta object. Extra named like
+// one of the fields above are dropped.
+func (m Meta) MarshalJSON() ([]byte, error) {
+	type plain Meta
+	body, err := json.Marshal(plain(m))
+	if err != nil || len(m.Extra) == 0 {
+		return body, err
+	}
+	keys := make([]string, 0, len(m.Extra))
+	for key := range m.Extra {
+		if !metaKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.Write(body[:len(body)-1])
+	for _, key := range keys {
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(m.Extra[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MetaOption configures MetaMiddleware.
+type MetaOption func(*metaConfig)
+
+type metaConfig struct {
+	// now returns the timestamp of a request.
+	now func() time.Time
+	// version is the API version sent in every meta.
+	version string
+	// requestIDHeader is read for the request ID and echoes it in the response.
+	requestIDHeader string
+}
+
+// WithMetaVersion sets the API version sent as "meta.version".
+func WithMetaVersion(version string) MetaOption {
+	return func(cfg *metaConfig) {
+		cfg.version = version
+	}
+}
+
+// WithRequestIDHeader replaces X-Request-ID as the header carrying the request ID.
+func WithRequestIDHeader(name string) MetaOption {
+	return func(cfg *metaConfig) {
+		if name != "" {
+			cfg.requestIDHeader = name
+		}
+	}
+}
+
+// WithMetaClock replaces time.Now as the source of "meta.timestamp" and
+// "meta.durationMs", eg: to get stable values in tests.
+func WithMetaClock(now func() time.Time) MetaOption {
+	return func(cfg *metaConfig) {
+		if now != nil {
+			cfg.now = now
+		}
+	}
+}
+
+// MetaMiddleware stores a Meta under MetaKey for every request, so all
+// responses of the helper carry the request ID, timestamp, API version and
+// path, and with WithRequestDuration the time spent on the request. The
+// request ID is taken from the X-Request-ID header or generated, stored under
+// RequestIDKey and echoed in the X-Request-ID response header.
+//
+// Example:
+//
+//	router.Use(responsehelper.MetaMiddleware(responsehelper.WithMetaVersion("v1")))
+func MetaMiddleware(opts ...MetaOption) gin.HandlerFunc {
+	cfg := metaConfig{now: time.Now, requestIDHeader: RequestIDHeader}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(cfg.requestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		started := cfg.now()
+		c.Set(RequestIDKey, requestID)
+		c.Header(cfg.requestIDHeader, requestID)
+		c.Set(MetaKey, Meta{
+			RequestID: requestID,
+			Timestamp: started.UTC(),
+			Version:   cfg.version,
+			Path:      requestPath(exchangeOf(c)),
+			Extra:     metaFields(exchangeOf(c)),
+			clock:     cfg.now,
+			started:   started,
+		})
+		c.Next()
+	}
+}
+
+// RequestID returns the request ID stored by MetaMiddleware, or "" when the
+// middleware is not used.
+func RequestID(c *gin.Context) string {
+	return requestID(exchangeOf(c))
+}
+
+// requestID is RequestID for an Exchange.
+func requestID(c Exchange) string {
+	return getString(c, RequestIDKey)
+}
+
+// GetMeta returns the Meta stored by MetaMiddleware.
+func GetMeta(c *gin.Context) (Meta, bool) {
+	return getMeta(exchangeOf(c))
+}
+
+// getMeta is GetMeta for an Exchange.
+func getMeta(c Exchange) (Meta, bool) {
+	switch meta := requestMeta(c).(type) {
+	case Meta:
+		return meta, true
+	case *Meta:
+		return *meta, true
+	default:
+		return Meta{}, false
+	}
+}
+
+// SetMetaField adds key to the meta of the request, so middleware can extend
+// the meta without knowing how it was set. A Meta gets the key in Extra, a
+// map meta gets the key, and without a meta a map is created. Other meta
+// values are left unchanged.
+//
+// Example:
+//
+//	responsehelper.SetMetaField(c, "region", "eu-west-1")
+func SetMetaField(c *gin.Context, key string, value interface{}) {
+	switch meta := requestMeta(exchangeOf(c)).(type) {
+	case Meta:
+		extra := make(map[string]interface{}, len(meta.Extra)+1)
+		for k, v := range meta.Extra {
+			extra[k] = v
+		}
+		extra[key] = value
+		meta.Extra = extra
+		c.Set(MetaKey, meta)
+	case gin.H:
+		meta[key] = value
+	case map[string]interface{}:
+		meta[key] = value
+	case nil:
+		c.Set(MetaKey, gin.H{key: value})
+	}
+}
+
+// MetaKV adds key to the meta of a single success response, eg: whether it
+// was served from a cache, merged like SetMetaField does. It wins over a key
+// of the same name set by middleware, but the members of Meta, eg:
+// "requestId" or "timestamp", cannot be overridden: those are ignored with a
+// warning.
+//
+// Example:
+//
+//	h.responseHelper.Success(c, product, responsehelper.MetaKV("cache", "hit"))
+func MetaKV(key string, value interface{}) ResponseOption {
+	return func(options *responseOptions) {
+		if options.meta == nil {
+			options.meta = map[string]interface{}{}
+		}
+		options.meta[key] = value
+	}
+}
+
+// callMeta returns meta with the keys of MetaKV added.
+func (cfg *config) callMeta(meta interface{}, fields map[string]interface{}) interface{} {
+	if isJSONNull(meta) && len(fields) > 0 {
+		meta = nil
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if metaKeys[key] {
+			cfg.warnf("MetaKV: %q is a reserved meta key, ignored", key)
+			continue
+		}
+		meta = metaWithField(meta, key, fields[key])
+	}
+	return meta
+}
+
+// metaFields returns the fields added with SetMetaField before MetaMiddleware ran.
+func metaFields(c Exchange) map[string]interface{} {
+	switch meta := requestMeta(c).(type) {
+	case gin.H:
+		return meta
+	case map[string]interface{}:
+		return meta
+	}
+	return nil
+}
+
+// requestMeta returns the meta stored under MetaKey, dereferencing a *Meta so
+// it is sent the same way as a Meta.
+func requestMeta(c Exchange) interface{} {
+	meta, _ := c.Get(MetaKey)
+	if typed, ok := meta.(*Meta); ok && typed != nil {
+		return *typed
+	}
+	return meta
+}
+
+// WithLegacyMetaConversion sends meta values set by code predating
+// MetaMiddleware as a Meta, so every response has the same "meta" shape. A
+// timestamp string becomes Meta.Timestamp, any other string Extra "value",
+// and the members of a map fill the Meta fields they are named after, eg:
+// "requestId", the others go to Extra. Other values are sent as they are.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithLegacyMetaConversion(true))
+func WithLegacyMetaConversion(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.legacyMeta = enabled
+	}
+}
+
+// responseMeta returns the meta sent with a response written now.
+func (cfg *config) responseMeta(c Exchange) interface{} {
+	meta := requestMeta(c)
+	if cfg.legacyMeta {
+		meta = legacyMeta(meta)
+	}
+	meta = cfg.tenantMeta(c, cfg.versionedMeta(meta))
+	if typed, ok := meta.(Meta); ok {
+		if duration, ok := cfg.measureDuration(c); ok {
+			typed.DurationMs = durationMs(duration)
+		}
+		return typed
+	}
+	return meta
+}
+
+// legacyMeta converts a string or map meta into a Meta.
+func legacyMeta(meta interface{}) interface{} {
+	switch meta := meta.(type) {
+	case string:
+		if timestamp, err := time.Parse(time.RFC3339Nano, meta); err == nil {
+			return Meta{Timestamp: timestamp.UTC()}
+		}
+		return Meta{Extra: map[string]interface{}{"value": meta}}
+	case gin.H:
+		return metaFromMap(meta)
+	case map[string]interface{}:
+		return metaFromMap(meta)
+	}
+	return meta
+}
+
+// metaFromMap returns the Meta of a map meta. Members named like a Meta field
+// but of another type are dropped.
+func metaFromMap(fields map[string]interface{}) Meta {
+	var meta Meta
+	for key, value := range fields {
+		if !metaKeys[key] {
+			if meta.Extra == nil {
+				meta.Extra = make(map[string]interface{}, len(fields))
+			}
+			meta.Extra[key] = value
+			continue
+		}
+		switch value := value.(type) {
+		case string:
+			switch key {
+			case "requestId":
+				meta.RequestID = value
+			case "version":
+				meta.Version = value
+			case "path":
+				meta.Path = value
+			case "timestamp":
+				if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+					meta.Timestamp = timestamp.UTC()
+				}
+			}
+		case time.Time:
+			if key == "timestamp" {
+				meta.Timestamp = value.UTC()
+			}
+		case float64:
+			if key == "durationMs" {
+				meta.DurationMs = value
+			}
+		}
+	}
+	return meta
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,321 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var metaNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+
+// metaEngine returns an engine with MetaMiddleware configured by opts,
+// answering /ok with a success and /fail with an error.
+func metaEngine(opts ...responsehelper.MetaOption) *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware(opts...))
+	engine.GET("/ok", func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) })
+	engine.GET("/fail", func(c *gin.Context) { h.NotFound(c, "missing") })
+	return engine
+}
+
+func serve(engine http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, r)
+	return w
+}
+
+func TestMetaMiddleware(t *testing.T) {
+	engine := metaEngine(responsehelper.WithMetaVersion("v1"), responsehelper.WithMetaClock(func() time.Time { return metaNow }))
+	for _, path := range []string{"/ok", "/fail"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set(responsehelper.RequestIDHeader, "req-1")
+		w := serve(engine, r)
+
+		assertField(t, w, "meta.requestId", "req-1")
+		assertField(t, w, "meta.timestamp", "2024-05-01T06:30:00Z")
+		assertField(t, w, "meta.version", "v1")
+		assertField(t, w, "meta.path", path)
+	}
+}
+
+func TestMetaMiddlewareWithoutVersion(t *testing.T) {
+	w := serve(metaEngine(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	meta := decodeBody(t, w)["meta"].(map[string]interface{})
+	if version, ok := meta["version"]; ok {
+		t.Errorf("meta.version = %v, want no version", version)
+	}
+	if _, ok := meta["timestamp"]; !ok {
+		t.Errorf("meta has no timestamp: %v", meta)
+	}
+}
+
+func TestGetMeta(t *testing.T) {
+	var got responsehelper.Meta
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware(responsehelper.WithMetaVersion("v2")))
+	engine.GET("/", func(c *gin.Context) { got, _ = responsehelper.GetMeta(c) })
+	serve(engine, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got.Version != "v2" || got.Path != "/" || got.RequestID == "" {
+		t.Errorf("GetMeta = %+v", got)
+	}
+}
+
+func TestRequestIDIsEchoed(t *testing.T) {
+	for _, path := range []string{"/ok", "/fail"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set(responsehelper.RequestIDHeader, "Client-ID 42")
+		w := serve(metaEngine(), r)
+
+		if got := w.Header().Get(responsehelper.RequestIDHeader); got != "Client-ID 42" {
+			t.Errorf("%s: %s = %q, want %q", path, responsehelper.RequestIDHeader, got, "Client-ID 42")
+		}
+		assertField(t, w, "meta.requestId", "Client-ID 42")
+	}
+}
+
+func TestRequestIDIsGenerated(t *testing.T) {
+	engine := metaEngine()
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	header := w.Header().Get(responsehelper.RequestIDHeader)
+	if header == "" {
+		t.Fatalf("no %s header", responsehelper.RequestIDHeader)
+	}
+	assertField(t, w, "meta.requestId", header)
+	if other := serve(engine, httptest.NewRequest(http.MethodGet, "/fail", nil)); other.Header().Get(responsehelper.RequestIDHeader) == header {
+		t.Errorf("two requests got the request ID %q", header)
+	}
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	var stored string
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware(responsehelper.WithRequestIDHeader("X-Correlation-ID")))
+	engine.GET("/", func(c *gin.Context) { stored = responsehelper.RequestID(c) })
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Correlation-ID", "corr-7")
+	r.Header.Set(responsehelper.RequestIDHeader, "ignored")
+	w := serve(engine, r)
+
+	if got := w.Header().Get("X-Correlation-ID"); got != "corr-7" {
+		t.Errorf("X-Correlation-ID = %q, want %q", got, "corr-7")
+	}
+	if got := w.Header().Get(responsehelper.RequestIDHeader); got != "" {
+		t.Errorf("%s = %q, want no header", responsehelper.RequestIDHeader, got)
+	}
+	if stored != "corr-7" {
+		t.Errorf("RequestID = %q, want %q", stored, "corr-7")
+	}
+}
+
+// steppingClock returns start, then start plus step on every later call.
+func steppingClock(start time.Time, step time.Duration) func() time.Time {
+	next := start
+	return func() time.Time {
+		now := next
+		next = next.Add(step)
+		return now
+	}
+}
+
+// TestMetaGolden pins the serialized shape of the meta sent by every method,
+// so it does not drift again.
+func TestMetaGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    []responsehelper.Option
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		{"success", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, gin.H{"id": 1})
+		}},
+		{"pagination", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 20, 1))
+		}},
+		{"error", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.NotFound(c, "missing")
+		}},
+		{"extra", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			responsehelper.SetMetaField(c, "region", "eu-west-1")
+			responsehelper.SetMetaField(c, "path", "/overridden")
+			h.Success(c, gin.H{"id": 1}, responsehelper.MetaKV("cache", "hit"))
+		}},
+		{"duration", []responsehelper.Option{responsehelper.WithRequestDuration(true)}, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, gin.H{"id": 1})
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := responsehelper.NewResponseHelper(tc.opts...)
+			engine := gin.New()
+			engine.Use(responsehelper.MetaMiddleware(
+				responsehelper.WithMetaVersion("v1"),
+				responsehelper.WithMetaClock(steppingClock(metaNow, 12500*time.Microsecond)),
+			))
+			engine.GET("/users", func(c *gin.Context) { tc.respond(h, c) })
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+			r.Header.Set(responsehelper.RequestIDHeader, "req-1")
+
+			goldenBytes(t, serve(engine, r).Body.Bytes(), "testdata/meta/"+tc.name+".json")
+		})
+	}
+}
+
+func TestMetaMarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		meta responsehelper.Meta
+		want string
+	}{
+		{"zero", responsehelper.Meta{}, `{"requestId":"","timestamp":"0001-01-01T00:00:00Z","path":""}`},
+		{"fields", responsehelper.Meta{RequestID: "req-1", Timestamp: metaNow.UTC(), Version: "v1", Path: "/users", DurationMs: 1.5},
+			`{"requestId":"req-1","timestamp":"2024-05-01T06:30:00Z","version":"v1","path":"/users","durationMs":1.5}`},
+		{"extra flattened and sorted", responsehelper.Meta{RequestID: "req-1", Extra: map[string]interface{}{"zone": "a", "region": "eu", "requestId": "other"}},
+			`{"requestId":"req-1","timestamp":"0001-01-01T00:00:00Z","path":"","region":"eu","zone":"a"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("MarshalJSON = %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLegacyMetaConversion(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		meta interface{}
+		want string
+	}{
+		{"timestamp string", "2024-05-01T06:30:00Z",
+			`{"requestId":"","timestamp":"2024-05-01T06:30:00Z","path":""}`},
+		{"other string", "v1",
+			`{"requestId":"","timestamp":"0001-01-01T00:00:00Z","path":"","value":"v1"}`},
+		{"map", gin.H{"requestId": "req-1", "timestamp": metaNow, "durationMs": 2.5, "version": 3, "shard": 7},
+			`{"requestId":"req-1","timestamp":"2024-05-01T06:30:00Z","path":"","durationMs":2.5,"shard":7}`},
+		{"typed", responsehelper.Meta{RequestID: "req-1", Timestamp: metaNow.UTC()},
+			`{"requestId":"req-1","timestamp":"2024-05-01T06:30:00Z","path":""}`},
+		{"other value", []string{"a"}, `["a"]`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/")
+			c.Set(responsehelper.MetaKey, tc.meta)
+			responsehelper.NewResponseHelper(responsehelper.WithLegacyMetaConversion(true)).Success(c, nil)
+
+			var body struct{ Meta json.RawMessage }
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if string(body.Meta) != tc.want {
+				t.Errorf("meta = %s\nwant %s", body.Meta, tc.want)
+			}
+		})
+	}
+}
+
+func TestLegacyMetaIsKeptWithoutConversion(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/")
+	c.Set(responsehelper.MetaKey, "v1")
+	responsehelper.NewResponseHelper().Success(c, nil)
+
+	assertField(t, w, "meta", "v1")
+}
+
+func TestMetaKVPrecedence(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		meta interface{}
+		want map[string]interface{}
+	}{
+		{"no meta", nil, map[string]interface{}{"cache": "hit", "bucket": "b"}},
+		{"typed", responsehelper.Meta{RequestID: "req-1", Extra: map[string]interface{}{"cache": "miss", "region": "eu"}},
+			map[string]interface{}{"requestId": "req-1", "cache": "hit", "bucket": "b", "region": "eu"}},
+		{"legacy map", gin.H{"cache": "miss", "region": "eu"}, map[string]interface{}{"cache": "hit", "bucket": "b", "region": "eu"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption){
+				"Success": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+					h.Success(c, gin.H{"id": 1}, opts...)
+				},
+				"Created": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+					h.Created(c, gin.H{"id": 1}, opts...)
+				},
+				"SuccessWithPagination": func(h responsehelper.ResponseHelper, c *gin.Context, opts ...responsehelper.ResponseOption) {
+					h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 10, 1), opts...)
+				},
+			} {
+				c, w := newContext(http.MethodGet, "/users")
+				if tc.meta != nil {
+					c.Set(responsehelper.MetaKey, tc.meta)
+				}
+				respond(responsehelper.NewResponseHelper(), c, responsehelper.MetaKV("bucket", "a"), responsehelper.MetaKV("cache", "hit"), responsehelper.MetaKV("bucket", "b"))
+
+				meta, _ := decodeBody(t, w)["meta"].(map[string]interface{})
+				for key, want := range tc.want {
+					if meta[key] != want {
+						t.Errorf("%s: meta[%q] = %v, want %v", name, key, meta[key], want)
+					}
+				}
+			}
+			if extra := metaExtra(tc.meta); extra != nil && extra["cache"] != "miss" {
+				t.Errorf("the meta set by the middleware was changed: %v", extra)
+			}
+		})
+	}
+}
+
+// metaExtra returns the members set on meta besides the fields of Meta.
+func metaExtra(meta interface{}) map[string]interface{} {
+	switch meta := meta.(type) {
+	case responsehelper.Meta:
+		return meta.Extra
+	case gin.H:
+		return meta
+	}
+	return nil
+}
+
+func TestMetaKVReservedKeys(t *testing.T) {
+	logs := &captureHandler{level: slog.LevelWarn}
+	c, w := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Timestamp: metaNow.UTC(), Path: "/users"})
+	responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(logs))).Success(c, gin.H{"id": 1},
+		responsehelper.MetaKV("requestId", "forged"),
+		responsehelper.MetaKV("timestamp", "yesterday"),
+		responsehelper.MetaKV("cache", "hit"))
+
+	assertField(t, w, "meta.requestId", "req-1")
+	assertField(t, w, "meta.timestamp", "2024-05-01T06:30:00Z")
+	assertField(t, w, "meta.cache", "hit")
+	if len(logs.records) != 2 {
+		t.Fatalf("%d warnings, want one per reserved key", len(logs.records))
+	}
+	for i, key := range []string{"requestId", "timestamp"} {
+		if !strings.Contains(logs.records[i].Message, `"`+key+`" is a reserved meta key`) {
+			t.Errorf("warning = %q, want one for %s", logs.records[i].Message, key)
+		}
+	}
+}
+
+func TestMetaKVWithoutMeta(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().Success(c, gin.H{"id": 1}, responsehelper.MetaKV("requestId", "forged"))
+
+	if _, ok := decodeBody(t, w)["meta"]; ok {
+		t.Errorf("body = %s, want no meta for a reserved key alone", w.Body)
+	}
+}
@@ -0,0 +1,77 @@
+// Package metrics exports Prometheus metrics about the responses sent by
+// responsehelper.
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RouteKey is the gin context key holding the route template used as the
+// "route" label, eg: "/users/:id". Without it the label is empty, so the
+// number of series stays bounded by the helper methods and status codes.
+const RouteKey = "responsehelper.metrics.route"
+
+// SetRoute sets the "route" label of the response metrics of the request.
+// Pass the route template, never the raw path.
+//
+// Example:
+//
+//	router.Use(func(c *gin.Context) {
+//		metrics.SetRoute(c, c.FullPath())
+//	})
+func SetRoute(c *gin.Context, route string) {
+	c.Set(RouteKey, route)
+}
+
+// WithMetrics registers the response metrics on reg and records every
+// response of the helper:
+//
+//   - responsehelper_responses_total{method="NotFound",status="404",route=""}
+//   - responsehelper_response_size_bytes{method="NotFound",status="404",route=""}
+//   - responsehelper_responses_skipped_total{method="NotFound",status="404",route=""}
+//
+// The responses skipped by responsehelper.WithSkipOnClientGone are only
+// counted by responsehelper_responses_skipped_total.
+//
+// It panics when the metrics are already registered on reg, like
+// prometheus.MustRegister.
+//
+// Example:
+//
+//	responseHelper := responsehelper.NewResponseHelper(metrics.WithMetrics(prometheus.DefaultRegisterer))
+func WithMetrics(reg prometheus.Registerer) responsehelper.Option {
+	labels := []string{"method", "status", "route"}
+	responses := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "responsehelper_responses_total",
+		Help: "Responses sent by responsehelper, by helper method and status code.",
+	}, labels)
+	sizes := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "responsehelper_response_size_bytes",
+		Help:    "Size of the bodies sent by responsehelper, by helper method and status code.",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	}, labels)
+	skipped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "responsehelper_responses_skipped_total",
+		Help: "Responses responsehelper skipped as the client disconnected, by helper method and status code.",
+	}, labels)
+	reg.MustRegister(responses, sizes, skipped)
+
+	return responsehelper.WithOnResponse(func(c *gin.Context, info responsehelper.ResponseInfo) {
+		var route string
+		if c != nil {
+			// nil for the responses of the other frameworks, see responsehelper.Core
+			route = c.GetString(RouteKey)
+		}
+		values := []string{info.Method, strconv.Itoa(info.Status), route}
+		if info.Skipped {
+			skipped.WithLabelValues(values...).Inc()
+			return
+		}
+		responses.WithLabelValues(values...).Inc()
+		sizes.WithLabelValues(values...).Observe(float64(info.BytesWritten))
+	})
+}
@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newContext(path string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+	return c
+}
+
+func TestWithMetrics(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	h := responsehelper.NewResponseHelper(metrics.WithMetrics(reg))
+
+	h.NotFound(newContext("/users/1"), "missing")
+	h.NotFound(newContext("/users/2"), "missing")
+	h.Success(newContext("/users"), []int{1, 2, 3})
+
+	want := `
+# HELP responsehelper_responses_total Responses sent by responsehelper, by helper method and status code.
+# TYPE responsehelper_responses_total counter
+responsehelper_responses_total{method="NotFound",route="",status="404"} 2
+responsehelper_responses_total{method="Success",route="",status="200"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "responsehelper_responses_total"); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(reg, "responsehelper_response_size_bytes"); got != 2 {
+		t.Errorf("%d size histograms, want 2", got)
+	}
+}
+
+func TestWithMetricsRoute(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	h := responsehelper.NewResponseHelper(metrics.WithMetrics(reg))
+	for _, path := range []string{"/users/1", "/users/2", "/users/3"} {
+		c := newContext(path)
+		metrics.SetRoute(c, "/users/:id")
+		h.NotFound(c, "missing")
+	}
+
+	want := `
+# HELP responsehelper_responses_total Responses sent by responsehelper, by helper method and status code.
+# TYPE responsehelper_responses_total counter
+responsehelper_responses_total{method="NotFound",route="/users/:id",status="404"} 3
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "responsehelper_responses_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithMetricsRegistersOnce(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics.WithMetrics(reg)
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the metrics twice did not panic")
+		}
+	}()
+	metrics.WithMetrics(reg)
+}
+
+func TestWithMetricsSkipped(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	h := responsehelper.NewResponseHelper(metrics.WithMetrics(reg), responsehelper.WithSkipOnClientGone(true))
+	gone := newContext("/users/1")
+	ctx, cancel := context.WithCancel(gone.Request.Context())
+	cancel()
+	gone.Request = gone.Request.WithContext(ctx)
+
+	h.NotFound(gone, "missing")
+	h.NotFound(newContext("/users/2"), "missing")
+
+	want := `
+# HELP responsehelper_responses_skipped_total Responses responsehelper skipped as the client disconnected, by helper method and status code.
+# TYPE responsehelper_responses_skipped_total counter
+responsehelper_responses_skipped_total{method="NotFound",route="",status="404"} 1
+# HELP responsehelper_responses_total Responses sent by responsehelper, by helper method and status code.
+# TYPE responsehelper_responses_total counter
+responsehelper_responses_total{method="NotFound",route="",status="404"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "responsehelper_responses_total", "responsehelper_responses_skipped_total"); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(reg, "responsehelper_response_size_bytes"); got != 1 {
+		t.Errorf("%d size histograms, want 1", got)
+	}
+}
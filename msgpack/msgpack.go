@@ -0,0 +1,45 @@
+// Package msgpack registers a MessagePack encoder for the responsehelper
+// envelopes.
+//
+// Importing the package registers the encoder for application/msgpack and
+// application/x-msgpack:
+//
+//	import _ "github.com/aruncs31s/responsehelper/msgpack"
+package msgpack
+
+import (
+	"io"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// ContentType is the content type of the MessagePack envelope.
+	ContentType = string(responsehelper.FormatMsgpack)
+	// LegacyContentType is accepted as an alias of ContentType.
+	LegacyContentType = "application/x-msgpack"
+)
+
+// handle writes maps with sorted keys, so equal envelopes encode to equal bytes.
+var handle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.Canonical = true
+	return h
+}()
+
+func init() {
+	responsehelper.RegisterEncoder(ContentType, Encode)
+	responsehelper.RegisterEncoder(LegacyContentType, Encode)
+}
+
+// Encode writes v to w as MessagePack, with the structure of its JSON: the
+// keys are the JSON names and values implementing json.Marshaler are
+// encoded like their JSON.
+func Encode(w io.Writer, v interface{}) error {
+	value, err := responsehelper.JSONValue(v)
+	if err != nil {
+		return err
+	}
+	return codec.NewEncoder(w, handle).Encode(value)
+}
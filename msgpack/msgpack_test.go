@@ -0,0 +1,120 @@
+package msgpack_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/msgpack"
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// engine returns an engine answering /users with a page of users and
+// /users/0 with a not found error.
+func engine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(opts...)
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: c.Request.URL.Path})
+	})
+	e.GET("/users", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []gin.H{{"id": 1, "name": "arun", "admin": true}}, responsehelper.NewPagination(1, 1, 3))
+	})
+	e.GET("/users/0", func(c *gin.Context) {
+		h.NotFound(c, "user not found")
+	})
+	return e
+}
+
+func get(e http.Handler, path, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	return w
+}
+
+// decode returns the MessagePack body as the values of its JSON, so it
+// compares with a decoded JSON body.
+func decode(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	handle := &codec.MsgpackHandle{}
+	handle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	handle.RawToString = true
+	var value interface{}
+	if err := codec.NewDecoderBytes(body, handle).Decode(&value); err != nil {
+		t.Fatalf("decoding the MessagePack body: %v", err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	return envelope
+}
+
+func decodeJSON(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("decoding the JSON body: %v\nbody: %s", err, body)
+	}
+	return envelope
+}
+
+func TestParityWithJSON(t *testing.T) {
+	e := engine(responsehelper.WithContentNegotiation(true))
+	for _, path := range []string{"/users", "/users/0"} {
+		for _, accept := range []string{msgpack.ContentType, msgpack.LegacyContentType} {
+			t.Run(path+" "+accept, func(t *testing.T) {
+				w := get(e, path, accept)
+				want := get(e, path, "application/json")
+
+				if w.Code != want.Code {
+					t.Errorf("status = %d, JSON sent %d", w.Code, want.Code)
+				}
+				if got := w.Header().Get("Content-Type"); got != accept {
+					t.Errorf("Content-Type = %q, want %q", got, accept)
+				}
+				if got, want := decode(t, w.Body.Bytes()), decodeJSON(t, want.Body.Bytes()); !reflect.DeepEqual(got, want) {
+					t.Errorf("envelope = %v\nJSON sent %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultFormat(t *testing.T) {
+	e := engine(responsehelper.WithDefaultFormat(responsehelper.FormatMsgpack))
+	w := get(e, "/users", "application/json")
+
+	if got := w.Header().Get("Content-Type"); got != msgpack.ContentType {
+		t.Fatalf("Content-Type = %q, want %q without negotiation", got, msgpack.ContentType)
+	}
+	if got := decode(t, w.Body.Bytes())["success"]; got != true {
+		t.Errorf("success = %v, want true", got)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	e := engine(responsehelper.WithDefaultFormat(responsehelper.FormatMsgpack))
+	first := get(e, "/users/0", "").Body.String()
+	for i := 0; i < 10; i++ {
+		if got := get(e, "/users/0", "").Body.String(); got != first {
+			t.Fatal("equal envelopes encoded to different bytes")
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package responsehelper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+const (
+	// MIMEJSON is the content type of the JSON envelope.
+	MIMEJSON = "application/json"
+	// MIMEXML is the content type of the XML envelope.
+	MIMEXML = "application/xml"
+	// MIMETextXML is accepted as an alias of MIMEXML.
+	MIMETextXML = "text/xml"
+	// MIMEJSONAPI is the content type of JSON:API documents, see FormatJSONAPI.
+	MIMEJSONAPI = "application/vnd.api+json"
+)
+
+// WithContentNegotiation makes the helpers pick the format of the envelope
+// from the Accept header of the request. Clients preferring application/xml
+// or text/xml get the envelope as XML, see SuccessEnvelope and ErrorEnvelope,
+// clients preferring a content type registered with RegisterEncoder get it
+// in that format, every other client gets the default format, JSON unless
+// changed with WithDefaultFormat. When disabled, the default, the envelope
+// is always rendered in the default format.
+//
+// Problem details (WithProblemDetails) are always rendered as JSON.
+func WithContentNegotiation(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.contentNegotiation = enabled
+	}
+}
+
+// WithFormatQueryParam lets clients pick the format with the query parameter
+// name, eg: "?format=yaml", overriding the Accept header. The value is the
+// subtype of the content type without an "x-" prefix: json, xml, msgpack,
+// yaml. Unknown values are ignored. Needs WithContentNegotiation.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(
+//		responsehelper.WithContentNegotiation(true),
+//		responsehelper.WithFormatQueryParam("format"),
+//	)
+func WithFormatQueryParam(name string) Option {
+	return func(cfg *config) {
+		cfg.formatQueryParam = name
+	}
+}
+
+// writeBody writes envelope, a *SuccessEnvelope or an *ErrorEnvelope, in the
+// format negotiated with the client.
+func (cfg *config) writeBody(c Exchange, status int, envelope interface{}) {
+	if callback, ok := cfg.jsonpCallback(c); ok {
+		cfg.writeJSONP(c, status, callback, envelope)
+		return
+	}
+	switch contentType := cfg.responseFormat(c); contentType {
+	case MIMEJSON:
+		if errorEnvelope, ok := envelope.(*ErrorEnvelope); ok && cfg.defaultFormat == FormatGoogle {
+			cfg.writeJSON(c, status, jsonContentType, googleError(&errorEnvelope.Error))
+			return
+		}
+		cfg.writeJSON(c, status, jsonContentType, envelope)
+	case MIMEXML, MIMETextXML:
+		if err := renderTo(c, status, render.XML{Data: envelope}); err != nil {
+			cfg.marshalFailed(c, fmt.Errorf("cannot render the XML response: %w", err))
+		}
+	case MIMEJSONAPI:
+		cfg.writeJSON(c, status, MIMEJSONAPI, cfg.jsonapiDocument(c, envelope))
+	default:
+		encoder, _ := registeredEncoder(contentType)
+		err := renderTo(c, status, encoderRender{
+			contentType: contentType,
+			encoder:     encoder,
+			marshalJSON: cfg.marshalJSON,
+			envelope:    envelope,
+		})
+		if err != nil {
+			cfg.marshalFailed(c, fmt.Errorf("cannot render the %s response: %w", contentType, err))
+		}
+	}
+}
+
+// responseFormat returns the content type the envelope is rendered as.
+func (cfg *config) responseFormat(c Exchange) string {
+	defaultFormat := string(cfg.defaultFormat)
+	if defaultFormat == "" || !formatAvailable(defaultFormat) {
+		defaultFormat = MIMEJSON
+	}
+	if !cfg.contentNegotiation || c.Request() == nil {
+		return defaultFormat
+	}
+	offers := []string{defaultFormat}
+	for _, offer := range append([]string{MIMEJSON, MIMEXML, MIMETextXML, MIMEJSONAPI}, registeredContentTypes()...) {
+		if offer != defaultFormat {
+			offers = append(offers, offer)
+		}
+	}
+	if cfg.formatQueryParam != "" {
+		if name := strings.ToLower(query(c, cfg.formatQueryParam)); name != "" {
+			for _, offer := range offers {
+				if formatName(offer) == name {
+					return offer
+				}
+			}
+		}
+	}
+	addVary(c, "Accept")
+	return negotiate(requestHeader(c, "Accept"), offers...)
+}
+
+// formatName returns the name a content type is picked by with the format
+// query parameter, eg: "application/x-msgpack" -> "msgpack".
+func formatName(contentType string) string {
+	if contentType == MIMEJSONAPI {
+		return "jsonapi"
+	}
+	_, subtype, _ := strings.Cut(contentType, "/")
+	return strings.TrimPrefix(subtype, "x-")
+}
+
+// negotiate returns the offer the Accept header prefers, the first offer
+// when the header is empty or accepts none of them. Ties are won by the
+// earlier offer.
+func negotiate(accept string, offers ...string) string {
+	best, bestQ, bestSpecificity := offers[0], 0.0, -1
+	if strings.TrimSpace(accept) == "" {
+		return best
+	}
+	for _, offer := range offers {
+		q, specificity := acceptQuality(accept, offer)
+		if q > bestQ || (q == bestQ && q > 0 && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the quality the Accept header gives offer, taken from
+// the most specific matching media range, and how specific that range is:
+// 2 for an exact match, 1 for type/* and 0 for */*.
+func acceptQuality(accept, offer string) (float64, int) {
+	offerType, _, _ := strings.Cut(offer, "/")
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+		rangeType, rangeSubtype, _ := strings.Cut(mediaRange, "/")
+		match := -1
+		switch {
+		case mediaRange == offer:
+			match = 2
+		case rangeSubtype == "*" && rangeType == offerType:
+			match = 1
+		case mediaRange == "*/*":
+			match = 0
+		}
+		if match <= specificity {
+			continue
+		}
+		quality, specificity = 1, match
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(strings.ToLower(key)) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+	}
+	return quality, specificity
+}
@@ -0,0 +1,122 @@
+package responsehelper_test
+
+import (
+	"bytes"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// goldenBytes compares got with the golden file at path, or rewrites the
+// file when the tests run with -update.
+func goldenBytes(t *testing.T, got []byte, path string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v, run the test with -update to create it\ngot: %s", path, err, got)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("the body differs from %s\ngot:  %s\nwant: %s", path, got, want)
+	}
+}
+
+// negotiationEngine returns an engine answering /users with a page of users,
+// /users/42 with a user and /users/0 with validation errors.
+func negotiationEngine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(opts...)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: c.Request.URL.Path})
+	})
+	engine.GET("/users", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []gin.H{{"id": 1, "name": "arun"}, {"id": 2, "name": "anu"}}, responsehelper.NewPagination(2, 2, 5))
+	})
+	engine.GET("/users/42", func(c *gin.Context) {
+		h.Success(c, gin.H{"id": 42, "name": "arun", "roles": []string{"admin", "dev"}})
+	})
+	engine.GET("/users/0", func(c *gin.Context) {
+		h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{
+			{Code: "REQUIRED", Field: "name", Message: "name is required"},
+			{Code: "INVALID_EMAIL", Field: "email", Message: "email is not valid"},
+		})
+	})
+	return engine
+}
+
+func TestXMLGolden(t *testing.T) {
+	engine := negotiationEngine(responsehelper.WithContentNegotiation(true))
+	for _, tc := range []struct {
+		path   string
+		accept string
+		golden string
+	}{
+		{"/users/42", "application/xml", "testdata/xml/success.xml"},
+		{"/users", "application/xml", "testdata/xml/pagination.xml"},
+		{"/users/0", "text/xml", "testdata/xml/errors.xml"},
+	} {
+		t.Run(tc.golden, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			r.Header.Set("Accept", tc.accept)
+			w := serve(engine, r)
+
+			if got := w.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+				t.Errorf("Content-Type = %q, want application/xml", got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+			goldenBytes(t, w.Body.Bytes(), tc.golden)
+		})
+	}
+}
+
+func TestNegotiationPicksTheFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		opts        []responsehelper.Option
+		accept      string
+		contentType string
+	}{
+		{"no accept", []responsehelper.Option{responsehelper.WithContentNegotiation(true)}, "", "application/json; charset=utf-8"},
+		{"any", []responsehelper.Option{responsehelper.WithContentNegotiation(true)}, "*/*", "application/json; charset=utf-8"},
+		{"json preferred", []responsehelper.Option{responsehelper.WithContentNegotiation(true)},
+			"application/xml;q=0.5, application/json", "application/json; charset=utf-8"},
+		{"xml preferred", []responsehelper.Option{responsehelper.WithContentNegotiation(true)},
+			"application/json;q=0.5, application/xml", "application/xml; charset=utf-8"},
+		{"unknown", []responsehelper.Option{responsehelper.WithContentNegotiation(true)}, "image/png", "application/json; charset=utf-8"},
+		{"negotiation off", nil, "application/xml", "application/json; charset=utf-8"},
+		{"xml default", []responsehelper.Option{
+			responsehelper.WithContentNegotiation(true),
+			responsehelper.WithDefaultFormat(responsehelper.FormatXML),
+		}, "", "application/xml; charset=utf-8"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := serve(negotiationEngine(tc.opts...), r)
+
+			if got := w.Header().Get("Content-Type"); got != tc.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tc.contentType)
+			}
+		})
+	}
+}
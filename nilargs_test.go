@@ -0,0 +1,126 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// TestNilArguments calls every method of the ResponseHelper with nil and
+// zero arguments: none of them may panic, and the ones answering the
+// request must still write a response.
+func TestNilArguments(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		call func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		{"BadRequest", func(h responsehelper.ResponseHelper, c *gin.Context) { h.BadRequest(c, "", "") }},
+		{"BadRequestDetails", func(h responsehelper.ResponseHelper, c *gin.Context) { h.BadRequestDetails(c, "", nil) }},
+		{"AlreadyExists", func(h responsehelper.ResponseHelper, c *gin.Context) { h.AlreadyExists(c, "", nil) }},
+		{"Conflict", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Conflict(c, "", nil) }},
+		{"NotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "") }},
+		{"ResourceNotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "", nil) }},
+		{"Unauthorized", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Unauthorized(c, "") }},
+		{"UnauthorizedWithChallenge", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.UnauthorizedWithChallenge(c, "", "", "", nil)
+		}},
+		{"UnauthorizedReason", func(h responsehelper.ResponseHelper, c *gin.Context) { h.UnauthorizedReason(c, "", "") }},
+		{"OAuthError", func(h responsehelper.ResponseHelper, c *gin.Context) { h.OAuthError(c, "", "", 0) }},
+		{"Forbidden", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Forbidden(c, "") }},
+		{"ForbiddenScope", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ForbiddenScope(c, "", nil, nil) }},
+		{"TooManyRequests", func(h responsehelper.ResponseHelper, c *gin.Context) { h.TooManyRequests(c, "", 0) }},
+		{"ServiceUnavailable", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ServiceUnavailable(c, "", 0) }},
+		{"InternalError", func(h responsehelper.ResponseHelper, c *gin.Context) { h.InternalError(c, "", nil) }},
+		{"Success", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, nil) }},
+		{"PartialSuccess", func(h responsehelper.ResponseHelper, c *gin.Context) { h.PartialSuccess(c, nil, nil) }},
+		{"SuccessCSV", func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessCSV(c, "", nil) }},
+		{"SuccessIfModified", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessIfModified(c, time.Time{}, nil)
+		}},
+		{"SuccessWithPagination", func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessWithPagination(c, nil, nil) }},
+		{"SuccessWithCursor", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithCursor(c, nil, responsehelper.CursorPagination{})
+		}},
+		{"SuccessWithCount", func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessWithCount(c, nil) }},
+		{"SuccessWithLinks", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithLinks(c, nil, responsehelper.Links{})
+		}},
+		{"SuccessLarge", func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessLarge(c, nil) }},
+		{"Created", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Created(c, nil) }},
+		{"Patched", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Patched(c, "", nil, nil) }},
+		{"OperationAccepted", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.OperationAccepted(c, responsehelper.Operation{})
+		}},
+		{"OperationStatus", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.OperationStatus(c, responsehelper.Operation{})
+		}},
+		{"Deleted", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Deleted(c, "") }},
+		{"DeletedNoContent", func(h responsehelper.ResponseHelper, c *gin.Context) { h.DeletedNoContent(c) }},
+		{"NoContent", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NoContent(c) }},
+		{"RespondAPIError", func(h responsehelper.ResponseHelper, c *gin.Context) { h.RespondAPIError(c, nil) }},
+		{"Respond", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Respond(c, nil, nil) }},
+		{"Errors", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Errors(c, 0, nil) }},
+		{"Problem", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Problem(c, 0, "", "", "", nil) }},
+		{"WriteJSON", func(h responsehelper.ResponseHelper, c *gin.Context) { h.WriteJSON(c, 0, nil) }},
+		{"WriteError", func(h responsehelper.ResponseHelper, c *gin.Context) { h.WriteError(c, 0, responsehelper.ErrorBody{}) }},
+		{"ValidationFailed", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ValidationFailed(c, nil) }},
+		{"NotFoundKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFoundKey(c, "") }},
+		{"BadRequestKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.BadRequestKey(c, "", "") }},
+		{"UnauthorizedKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.UnauthorizedKey(c, "") }},
+		{"ForbiddenKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ForbiddenKey(c, "") }},
+		{"ConflictKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ConflictKey(c, "", nil) }},
+		{"InternalErrorKey", func(h responsehelper.ResponseHelper, c *gin.Context) { h.InternalErrorKey(c, "", nil) }},
+		{"RespondCode", func(h responsehelper.ResponseHelper, c *gin.Context) { h.RespondCode(c, "") }},
+	} {
+		for name, h := range map[string]responsehelper.ResponseHelper{
+			"default":   responsehelper.NewResponseHelper(),
+			"sanitized": responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)),
+		} {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				c, w := newContext(http.MethodGet, "/")
+				tc.call(h, c)
+
+				if !c.Writer.Written() {
+					t.Fatalf("%s wrote no response", tc.name)
+				}
+				if w.Code < 200 || w.Code > 599 {
+					t.Errorf("status = %d", w.Code)
+				}
+			})
+		}
+	}
+}
+
+func TestNilErrorsOmitTheDetails(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	for name, call := range map[string]func(c *gin.Context){
+		"Conflict":      func(c *gin.Context) { h.Conflict(c, "Already taken", nil) },
+		"AlreadyExists": func(c *gin.Context) { h.AlreadyExists(c, "user", nil) },
+		"InternalError": func(c *gin.Context) { h.InternalError(c, "oops", nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodPost, "/users")
+			call(c)
+
+			assertField(t, w, "success", false)
+			if details, ok := decodeBody(t, w)["error"].(map[string]interface{})["details"]; ok {
+				t.Errorf("error.details = %v, want no details", details)
+			}
+		})
+	}
+}
+
+func TestRegistrationMethodsWithZeroArguments(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	h.RegisterCode("", 0, "")
+	h.PrecomputeError(0, "")
+
+	c, w := newContext(http.MethodGet, "/")
+	h.RespondCode(c, "")
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,53 @@
+package responsehelper
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func (r *Core) ResourceNotFound(c Exchange, resource string, id interface{}, opts ...ResponseOption) {
+	opts = helperCall(opts, "ResourceNotFound", nil)
+	errorBody := ErrorBody{
+		Code:     404,
+		Status:   "NOT_FOUND",
+		Message:  resource + " not found",
+		Resource: resource,
+	}
+	if resourceID, ok := formatResourceID(id); ok {
+		errorBody.Message = fmt.Sprintf("%s with id '%s' not found", resource, resourceID)
+		errorBody.ResourceID = resourceID
+	}
+	r.respondError(c, http.StatusNotFound, errorBody, opts...)
+}
+
+// formatResourceID returns id as a string, and false when it is nil. Maps,
+// the parts of a composite id, are rendered as their sorted "key=value"
+// pairs, eg: "orgId=7,userId=42".
+func formatResourceID(id interface{}) (string, bool) {
+	value := reflect.ValueOf(id)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return "", false
+	}
+	if value.Kind() != reflect.Map {
+		return fmt.Sprint(value.Interface()), true
+	}
+	if value.Len() == 0 {
+		return "", false
+	}
+	pairs := make([]string, 0, value.Len())
+	for iter := value.MapRange(); iter.Next(); {
+		part, _ := formatResourceID(iter.Value().Interface())
+		pairs = append(pairs, fmt.Sprintf("%v=%s", iter.Key().Interface(), part))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ","), true
+}
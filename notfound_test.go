@@ -0,0 +1,66 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+type orderID string
+
+func TestResourceNotFound(t *testing.T) {
+	var missing *int
+	id := 42
+	for _, tt := range []struct {
+		name       string
+		id         interface{}
+		message    string
+		resourceID interface{}
+	}{
+		{"string", "arun", "user with id 'arun' not found", "arun"},
+		{"int", 42, "user with id '42' not found", "42"},
+		{"pointer", &id, "user with id '42' not found", "42"},
+		{"named string", orderID("o-7"), "user with id 'o-7' not found", "o-7"},
+		{"map", map[string]interface{}{"userId": 42, "orgId": "acme"}, "user with id 'orgId=acme,userId=42' not found", "orgId=acme,userId=42"},
+		{"map of ints", map[int]int{2: 20, 1: 10}, "user with id '1=10,2=20' not found", "1=10,2=20"},
+		{"nil", nil, "user not found", nil},
+		{"nil pointer", missing, "user not found", nil},
+		{"empty map", map[string]int{}, "user not found", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/42")
+			responsehelper.NewResponseHelper().ResourceNotFound(c, "user", tt.id)
+
+			assertError(t, w, http.StatusNotFound, tt.message)
+			errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+			if errorBody["resource"] != "user" || errorBody["resourceId"] != tt.resourceID {
+				t.Errorf("resource = %v, resourceId = %v, want user and %v", errorBody["resource"], errorBody["resourceId"], tt.resourceID)
+			}
+			if errorBody["status"] != "NOT_FOUND" {
+				t.Errorf("status = %v", errorBody["status"])
+			}
+		})
+	}
+}
+
+func TestResourceNotFoundOptions(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper().ResourceNotFound(c, "user", 42, responsehelper.WithHelpURL("https://docs.example.com/errors/user-not-found"))
+
+	if errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{}); errorBody["helpUrl"] != "https://docs.example.com/errors/user-not-found" {
+		t.Errorf("error = %v, want the helpUrl of the option", errorBody)
+	}
+}
+
+func TestResourceNotFoundXML(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatXML)).ResourceNotFound(c, "user", 42)
+
+	for _, want := range []string{"<resource>user</resource>", "<resourceId>42</resourceId>"} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("body = %s, want %s", w.Body, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package responsehelper
+
+// WithNullFields restores the members rendered for nothing by older
+// versions: "meta": null when no meta was set, "pagination": null for nil
+// pagination and "details": "" for empty details. They are left out by
+// default, enable it for clients that expect them. Only the JSON envelopes
+// are affected.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithNullFields(true))
+func WithNullFields(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.nullFields = enabled
+	}
+}
+
+// nullField returns v, or null with WithNullFields when v is nil.
+func (cfg *config) nullField(v interface{}) interface{} {
+	if cfg.nullFields {
+		return nullable(v)
+	}
+	return v
+}
+
+// withNullMeta returns envelope with a null meta when WithNullFields is
+// enabled and it has none. The envelope is copied, not changed.
+func (cfg *config) withNullMeta(envelope interface{}) interface{} {
+	if !cfg.nullFields {
+		return envelope
+	}
+	switch typed := envelope.(type) {
+	case *SuccessEnvelope:
+		if typed.Meta == nil {
+			copied := *typed
+			copied.Meta = jsonNull
+			return &copied
+		}
+	case *ErrorEnvelope:
+		if typed.Meta == nil {
+			copied := *typed
+			copied.Meta = jsonNull
+			return &copied
+		}
+	}
+	return envelope
+}
@@ -0,0 +1,69 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// nullFieldsCalls are the responses with nothing set for meta, pagination or details.
+var nullFieldsCalls = []struct {
+	name    string
+	respond func(h responsehelper.ResponseHelper, c *gin.Context)
+}{
+	{"success", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 42}) }},
+	{"pagination", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, []int{1, 2}, nil)
+	}},
+	{"error", func(h responsehelper.ResponseHelper, c *gin.Context) { h.BadRequest(c, "Invalid input", "") }},
+	{"errors", func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{{Code: "REQUIRED", Field: "name", Message: "name is required"}})
+	}},
+}
+
+// TestNullFieldsGolden pins the envelopes with the empty members left out,
+// the default, and rendered as null with WithNullFields. Both ways of
+// marshalling the envelopes must agree.
+func TestNullFieldsGolden(t *testing.T) {
+	for dir, opts := range map[string][]responsehelper.Option{
+		"omitted": nil,
+		"null":    {responsehelper.WithNullFields(true)},
+	} {
+		for _, call := range nullFieldsCalls {
+			for encoder, extra := range map[string][]responsehelper.Option{
+				"gin":     nil,
+				"encoder": {responsehelper.WithJSONEncoder(stdEncoder{})},
+			} {
+				t.Run(dir+"/"+call.name+"/"+encoder, func(t *testing.T) {
+					c, w := newContext(http.MethodGet, "/users")
+					call.respond(responsehelper.NewResponseHelper(append(extra, opts...)...), c)
+					goldenBytes(t, w.Body.Bytes(), filepath.Join("testdata", "nullfields", dir, call.name+".json"))
+				})
+			}
+		}
+	}
+}
+
+func TestNullFieldsKeepTheSetMembers(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"omitted": nil,
+		"null":    {responsehelper.WithNullFields(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			c.Set(responsehelper.MetaKey, gin.H{"region": "eu"})
+			responsehelper.NewResponseHelper(opts...).SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 1, 1))
+
+			body := decodeBody(t, w)
+			if meta, _ := body["meta"].(map[string]interface{}); meta["region"] != "eu" {
+				t.Errorf("meta = %v", body["meta"])
+			}
+			if body["pagination"] == nil {
+				t.Errorf("pagination = %v", body["pagination"])
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package responsehelper
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Error codes of RFC 6749, the token endpoint ones (section 5.2) and the
+// authorization endpoint ones (section 4.1.2.1).
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorUnauthorizedClient      = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorAccessDenied            = "access_denied"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorServerError             = "server_error"
+	OAuthErrorTemporarilyUnavailable  = "temporarily_unavailable"
+)
+
+// oauthErrorCodes are the error codes OAuthError accepts without
+// WithCustomOAuthCodes.
+var oauthErrorCodes = map[string]bool{
+	OAuthErrorInvalidRequest:          true,
+	OAuthErrorInvalidClient:           true,
+	OAuthErrorInvalidGrant:            true,
+	OAuthErrorUnauthorizedClient:      true,
+	OAuthErrorUnsupportedGrantType:    true,
+	OAuthErrorInvalidScope:            true,
+	OAuthErrorAccessDenied:            true,
+	OAuthErrorUnsupportedResponseType: true,
+	OAuthErrorServerError:             true,
+	OAuthErrorTemporarilyUnavailable:  true,
+}
+
+// OAuthErrorBody is the body of OAuthError, the flat error response of RFC
+// 6749 without the envelope.
+type OAuthErrorBody struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// WithCustomOAuthCodes lets OAuthError send codes besides the ones of RFC
+// 6749, eg: the "authorization_pending" of device flows (RFC 8628).
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithCustomOAuthCodes("authorization_pending", "slow_down"))
+func WithCustomOAuthCodes(codes ...string) Option {
+	return func(cfg *config) {
+		if cfg.oauthCodes == nil {
+			cfg.oauthCodes = map[string]bool{}
+		}
+		for _, code := range codes {
+			cfg.oauthCodes[code] = true
+		}
+	}
+}
+
+func (r *Core) OAuthError(c Exchange, errCode string, description string, status int) {
+	if r.secondResponse(c, "OAuthError") {
+		return
+	}
+	if !oauthErrorCodes[errCode] && !r.oauthCodes[errCode] {
+		r.warnf("OAuthError: unknown error code %q, sending %s instead, see WithCustomOAuthCodes", errCode, OAuthErrorServerError)
+		errCode, status = OAuthErrorServerError, http.StatusInternalServerError
+	}
+	status = r.validErrorStatus(status, "OAuthError")
+	description = r.truncateMessage(description)
+	response := sentResponse{status: status, options: responseOptions{method: "OAuthError"}, errorCode: errCode, message: description}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	if status == http.StatusUnauthorized {
+		// RFC 6749 challenges a client that authenticated with the scheme it used
+		if scheme, _, _ := strings.Cut(requestHeader(c, "Authorization"), " "); scheme != "" {
+			setHeader(c, WWWAuthenticateHeader, FormatChallenge(scheme, "", nil))
+		}
+	}
+	setHeader(c, "Pragma", "no-cache")
+	r.writeResponse(c, response, func(c Exchange) {
+		r.writeJSON(c, status, jsonContentType, &OAuthErrorBody{Error: errCode, ErrorDescription: description})
+	})
+}
@@ -0,0 +1,238 @@
+// Package openapi describes the responsehelper envelopes as OpenAPI 3
+// components, generated from the Go types so the API documentation cannot
+// drift from the responses. The documents are plain maps, marshal them into
+// the spec or merge them into the one of a generator.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// SchemaRef returns the reference to the component schema named name, eg:
+// {"$ref": "#/components/schemas/ErrorEnvelope"}.
+func SchemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// components are the types described by Components, by schema name.
+var components = map[string]reflect.Type{
+	"SuccessEnvelope":  reflect.TypeOf(responsehelper.SuccessEnvelope{}),
+	"ErrorEnvelope":    reflect.TypeOf(responsehelper.ErrorEnvelope{}),
+	"ErrorBody":        reflect.TypeOf(responsehelper.ErrorBody{}),
+	"FieldError":       reflect.TypeOf(responsehelper.FieldError{}),
+	"ErrorItem":        reflect.TypeOf(responsehelper.ErrorItem{}),
+	"Pagination":       reflect.TypeOf(responsehelper.Pagination{}),
+	"CursorPagination": reflect.TypeOf(responsehelper.CursorPagination{}),
+	"Meta":             reflect.TypeOf(responsehelper.Meta{}),
+	"Warning":          reflect.TypeOf(responsehelper.Warning{}),
+	"Operation":        reflect.TypeOf(responsehelper.Operation{}),
+	"PartialFailure":   reflect.TypeOf(responsehelper.PartialFailure{}),
+}
+
+// memberSchemas returns the schemas of the members holding values of several
+// shapes, by schema name and member. They are built for every Components, so
+// callers may modify the documents.
+func memberSchemas() map[string]map[string]interface{} {
+	return map[string]map[string]interface{}{
+		"SuccessEnvelope": {
+			"data":       map[string]interface{}{"description": "The payload of the response."},
+			"links":      map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"meta":       SchemaRef("Meta"),
+			"pagination": map[string]interface{}{"oneOf": []interface{}{SchemaRef("Pagination"), SchemaRef("CursorPagination")}},
+		},
+		"ErrorEnvelope": {
+			"data": map[string]interface{}{"description": "Null for server errors, for older clients reading it.", "nullable": true},
+			"meta": SchemaRef("Meta"),
+		},
+		"ErrorBody": {
+			"details": map[string]interface{}{"description": "Additional information about the error."},
+			"errors": map[string]interface{}{"oneOf": []interface{}{
+				map[string]interface{}{"type": "array", "items": SchemaRef("FieldError")},
+				map[string]interface{}{"type": "array", "items": SchemaRef("ErrorItem")},
+			}},
+		},
+		"ErrorItem": {
+			"details": map[string]interface{}{"description": "Additional information about the error."},
+		},
+	}
+}
+
+// Components returns the components object describing the envelopes:
+// SuccessEnvelope, ErrorEnvelope, ErrorBody, FieldError, ErrorItem,
+// Pagination, CursorPagination, Meta, Warning, Operation and PartialFailure
+// under "schemas", and the responses of ErrorResponses for the common errors
+// under "responses".
+//
+// Example:
+//
+//	spec["components"] = openapi.Components()
+func Components() map[string]interface{} {
+	schemas := make(map[string]interface{}, len(components))
+	members := memberSchemas()
+	for name, typ := range components {
+		schemas[name] = structSchema(typ, members[name])
+	}
+	return map[string]interface{}{
+		"schemas":   schemas,
+		"responses": ErrorResponses(),
+	}
+}
+
+// defaultErrorStatuses are the statuses of ErrorResponses without arguments.
+var defaultErrorStatuses = []int{
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusConflict,
+	http.StatusUnprocessableEntity,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusServiceUnavailable,
+}
+
+// ErrorResponses returns reusable response objects sending an ErrorEnvelope
+// by status code, eg: "404", for statuses, or for 400, 401, 403, 404, 409,
+// 422, 429, 500 and 503 when none is given. Each has an example body.
+//
+// Example:
+//
+//	errors := openapi.ErrorResponses(http.StatusNotFound, http.StatusInternalServerError)
+//	operation["responses"].(map[string]interface{})["404"] = errors["404"]
+func ErrorResponses(statuses ...int) map[string]interface{} {
+	if len(statuses) == 0 {
+		statuses = defaultErrorStatuses
+	}
+	responses := make(map[string]interface{}, len(statuses))
+	for _, status := range statuses {
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":  SchemaRef("ErrorEnvelope"),
+					"example": errorExample(status),
+				},
+			},
+		}
+	}
+	return responses
+}
+
+// errorExample returns the example ErrorEnvelope of status.
+func errorExample(status int) map[string]interface{} {
+	message := http.StatusText(status)
+	if message == "" {
+		message = "Error"
+	}
+	return map[string]interface{}{
+		"success": false,
+		"error": map[string]interface{}{
+			"code":      status,
+			"status":    strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(strings.ToUpper(message)),
+			"message":   message,
+			"retryable": status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable,
+		},
+	}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// structSchema returns the object schema of the struct typ, with the schemas
+// of members instead of the ones derived from the field types. Members left
+// out when empty are not required.
+func structSchema(typ reflect.Type, members map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		member, options, _ := strings.Cut(tag, ",")
+		if !field.IsExported() || member == "-" || tag == "" {
+			continue
+		}
+		schema, ok := members[member].(map[string]interface{})
+		if !ok {
+			schema = typeSchema(field.Type)
+			if example, ok := field.Tag.Lookup("example"); ok {
+				schema["example"] = exampleValue(field.Type, example)
+			}
+		}
+		properties[member] = schema
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, member)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	if typ == components["Meta"] {
+		// the members set with SetMetaField
+		schema["additionalProperties"] = true
+	}
+	return schema
+}
+
+// typeSchema returns the schema of typ, a reference for the types of the
+// components.
+func typeSchema(typ reflect.Type) map[string]interface{} {
+	for name, component := range components {
+		if typ == component {
+			return SchemaRef(name)
+		}
+	}
+	switch {
+	case typ == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case typ == interfaceType:
+		return map[string]interface{}{}
+	}
+	switch typ.Kind() {
+	case reflect.Pointer:
+		schema := typeSchema(typ.Elem())
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(typ.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(typ.Elem())}
+	}
+	return map[string]interface{}{"type": "object"}
+}
+
+// exampleValue returns the example tag of a member of type typ as a value of
+// that type, eg: "404" -> 404 for an int.
+func exampleValue(typ reflect.Type, example string) interface{} {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ != timeType && typ.Kind() != reflect.String {
+		var value interface{}
+		if json.Unmarshal([]byte(example), &value) == nil {
+			return value
+		}
+	}
+	return example
+}
@@ -0,0 +1,86 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/components.json")
+
+// TestComponentsFixture compares the generated components with
+// testdata/components.json, so a change of the envelopes fails until the
+// fixture, and the documentation built from it, is updated with -update.
+func TestComponentsFixture(t *testing.T) {
+	got, err := json.MarshalIndent(openapi.Components(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshalling the components: %v", err)
+	}
+	got = append(got, '\n')
+	path := filepath.Join("testdata", "components.json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v, run the test with -update to create it", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("the components differ from %s, run the test with -update and review the diff\ngot:\n%s", path, got)
+	}
+}
+
+func TestComponentsAreIndependent(t *testing.T) {
+	first := openapi.Components()
+	schemas := first["schemas"].(map[string]interface{})
+	schemas["SuccessEnvelope"].(map[string]interface{})["properties"].(map[string]interface{})["data"] = "changed"
+
+	second := openapi.Components()
+	data := second["schemas"].(map[string]interface{})["SuccessEnvelope"].(map[string]interface{})["properties"].(map[string]interface{})["data"]
+	if data == "changed" {
+		t.Error("changing the components of one call changed the ones of the next")
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	defaults := openapi.ErrorResponses()
+	var statuses []string
+	for status := range defaults {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	if want := []string{"400", "401", "403", "404", "409", "422", "429", "500", "503"}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	responses := openapi.ErrorResponses(http.StatusNotFound, http.StatusTeapot)
+	if len(responses) != 2 {
+		t.Fatalf("responses = %v, want 404 and 418", responses)
+	}
+	body, _ := json.Marshal(responses["404"])
+	want := `{"content":{"application/json":{"example":{"error":{"code":404,"message":"Not Found","retryable":false,"status":"NOT_FOUND"},"success":false},"schema":{"$ref":"#/components/schemas/ErrorEnvelope"}}},"description":"Not Found"}`
+	if string(body) != want {
+		t.Errorf("404 =\n%s\nwant\n%s", body, want)
+	}
+	body, _ = json.Marshal(responses["418"])
+	if !bytes.Contains(body, []byte(`"status":"IM_A_TEAPOT"`)) {
+		t.Errorf("418 = %s", body)
+	}
+}
+
+func TestSchemaRef(t *testing.T) {
+	if got := openapi.SchemaRef("Meta"); !reflect.DeepEqual(got, map[string]interface{}{"$ref": "#/components/schemas/Meta"}) {
+		t.Errorf("SchemaRef(Meta) = %v", got)
+	}
+}
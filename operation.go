@@ -0,0 +1,92 @@
+package responsehelper
+
+import (
+	"net/http"
+	"time"
+)
+
+// The states of an Operation, its Status.
+const (
+	// OperationPending is an operation accepted but not started yet.
+	OperationPending = "pending"
+	// OperationRunning is an operation in progress.
+	OperationRunning = "running"
+	// OperationSucceeded is an operation done, with its Result.
+	OperationSucceeded = "succeeded"
+	// OperationFailed is an operation done, with its Error.
+	OperationFailed = "failed"
+)
+
+// Operation is a long-running operation, sent as the data of
+// OperationAccepted and OperationStatus. Its members are always sent, except
+// result and error, so clients can poll any operation the same way.
+type Operation struct {
+	// ID identifies the operation.
+	ID string `json:"id" example:"op_7f3a2c"`
+	// Status is OperationPending, OperationRunning, OperationSucceeded or
+	// OperationFailed.
+	Status string `json:"status" example:"running"`
+	// StatusURL is where clients poll the operation, resolved against
+	// WithBaseURL when relative.
+	StatusURL string `json:"statusUrl" example:"/operations/op_7f3a2c"`
+	// Progress is the completion in percent, from 0 to 100.
+	Progress int `json:"progress" example:"40"`
+	// Result is the outcome of a succeeded operation.
+	Result interface{} `json:"result,omitempty"`
+	// Error is why the operation failed.
+	Error *ErrorBody `json:"error,omitempty"`
+	// CreatedAt is when the operation was accepted.
+	CreatedAt time.Time `json:"createdAt" example:"2024-05-01T12:00:00Z"`
+	// UpdatedAt is when the status or progress last changed.
+	UpdatedAt time.Time `json:"updatedAt" example:"2024-05-01T12:00:05Z"`
+}
+
+func (r *Core) OperationAccepted(c Exchange, op Operation) {
+	op = r.normalizeOperation(op)
+	if op.StatusURL != "" {
+		setHeader(c, "Location", op.StatusURL)
+	}
+	r.renderSuccess(c, "OperationAccepted", http.StatusAccepted, SuccessEnvelope{
+		Data:    op,
+		Success: true,
+	})
+}
+
+func (r *Core) OperationStatus(c Exchange, op Operation) {
+	op = r.normalizeOperation(op)
+	switch op.Status {
+	case OperationFailed:
+		errorBody := ErrorBody{
+			Code:    http.StatusInternalServerError,
+			Status:  statusText(http.StatusInternalServerError),
+			Message: "The operation failed",
+		}
+		if op.Error != nil {
+			errorBody = *op.Error
+		}
+		status := r.validErrorStatus(errorBody.Code, "OperationStatus")
+		errorBody.Code, errorBody.Status = status, statusText(status)
+		r.respondError(c, status, errorBody, helperCall(nil, "OperationStatus", nil)...)
+		return
+	case OperationPending, OperationRunning, OperationSucceeded:
+	default:
+		r.warnf("OperationStatus: unknown operation status %q", op.Status)
+	}
+	r.renderSuccess(c, "OperationStatus", http.StatusOK, SuccessEnvelope{
+		Data:    op,
+		Success: true,
+	})
+}
+
+// normalizeOperation returns op with its progress within 0 to 100, 100 once
+// succeeded, and its status URL resolved.
+func (r *Core) normalizeOperation(op Operation) Operation {
+	op.Progress = min(max(op.Progress, 0), 100)
+	if op.Status == OperationSucceeded {
+		op.Progress = 100
+	}
+	if op.StatusURL != "" {
+		op.StatusURL = r.resolveLink(op.StatusURL)
+	}
+	return op
+}
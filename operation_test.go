@@ -0,0 +1,136 @@
+package responsehelper_test
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// operationStep sends op with OperationStatus and returns the status and body.
+func operationStep(t *testing.T, h responsehelper.ResponseHelper, op responsehelper.Operation) (int, map[string]interface{}) {
+	t.Helper()
+	c, w := newContext(http.MethodGet, "/operations/"+op.ID)
+	h.OperationStatus(c, op)
+	return w.Code, decodeBody(t, w)
+}
+
+func TestOperationSucceeds(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	op := responsehelper.Operation{
+		ID:        "op_1",
+		Status:    responsehelper.OperationPending,
+		StatusURL: "/operations/op_1",
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	c, w := newContext(http.MethodPost, "/exports")
+	h.OperationAccepted(c, op)
+	if w.Code != http.StatusAccepted || w.Header().Get("Location") != "/operations/op_1" {
+		t.Fatalf("OperationAccepted: %d, Location %q", w.Code, w.Header().Get("Location"))
+	}
+	want := `"data":{"id":"op_1","status":"pending","statusUrl":"/operations/op_1","progress":0,"createdAt":"2024-05-01T12:00:00Z","updatedAt":"2024-05-01T12:00:00Z"}`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+
+	op.Status, op.Progress, op.UpdatedAt = responsehelper.OperationRunning, 40, created.Add(5*time.Second)
+	status, body := operationStep(t, h, op)
+	data, _ := body["data"].(map[string]interface{})
+	if status != http.StatusOK || data["status"] != "running" || data["progress"] != float64(40) || data["updatedAt"] != "2024-05-01T12:00:05Z" {
+		t.Errorf("running: %d %v", status, data)
+	}
+	if _, ok := data["result"]; ok {
+		t.Errorf("running: result = %v", data["result"])
+	}
+
+	op.Status, op.Progress, op.Result = responsehelper.OperationSucceeded, 90, map[string]string{"url": "/exports/1.csv"}
+	status, body = operationStep(t, h, op)
+	data, _ = body["data"].(map[string]interface{})
+	if status != http.StatusOK || body["success"] != true || data["status"] != "succeeded" || data["progress"] != float64(100) {
+		t.Errorf("succeeded: %d %v, want progress 100", status, data)
+	}
+	if result, _ := data["result"].(map[string]interface{}); result["url"] != "/exports/1.csv" {
+		t.Errorf("succeeded: result = %v", data["result"])
+	}
+}
+
+func TestOperationFails(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	op := responsehelper.Operation{ID: "op_2", Status: responsehelper.OperationRunning, Progress: 60}
+	if status, _ := operationStep(t, h, op); status != http.StatusOK {
+		t.Fatalf("running: status = %d", status)
+	}
+
+	t.Run("with an error", func(t *testing.T) {
+		op := op
+		op.Status = responsehelper.OperationFailed
+		op.Error = &responsehelper.ErrorBody{Code: http.StatusConflict, Message: "The export conflicts with another", ErrorCode: "EXPORT_CONFLICT"}
+		status, body := operationStep(t, h, op)
+
+		errorBody, _ := body["error"].(map[string]interface{})
+		if status != http.StatusConflict || body["success"] != false {
+			t.Errorf("status = %d, body = %v", status, body)
+		}
+		if errorBody["status"] != "CONFLICT" || errorBody["errorCode"] != "EXPORT_CONFLICT" || errorBody["message"] != "The export conflicts with another" {
+			t.Errorf("error = %v", errorBody)
+		}
+	})
+	t.Run("without an error", func(t *testing.T) {
+		op := op
+		op.Status = responsehelper.OperationFailed
+		status, body := operationStep(t, h, op)
+
+		if errorBody, _ := body["error"].(map[string]interface{}); status != http.StatusInternalServerError || errorBody["message"] != "The operation failed" {
+			t.Errorf("%d %v", status, body)
+		}
+	})
+	t.Run("with an invalid status", func(t *testing.T) {
+		op := op
+		op.Status = responsehelper.OperationFailed
+		op.Error = &responsehelper.ErrorBody{Code: http.StatusOK, Message: "Not an error"}
+		if status, _ := operationStep(t, h, op); status != http.StatusInternalServerError {
+			t.Errorf("status = %d, want 500", status)
+		}
+	})
+}
+
+func TestOperationNormalized(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithBaseURL("https://api.example.com/v1"))
+	for progress, want := range map[int]float64{-5: 0, 50: 50, 250: 100} {
+		c, w := newContext(http.MethodPost, "/exports")
+		h.OperationAccepted(c, responsehelper.Operation{ID: "op_3", Status: responsehelper.OperationPending, StatusURL: "/operations/op_3", Progress: progress})
+
+		data, _ := decodeBody(t, w)["data"].(map[string]interface{})
+		if data["progress"] != want {
+			t.Errorf("progress %d sent as %v, want %v", progress, data["progress"], want)
+		}
+		if location := w.Header().Get("Location"); location != "https://api.example.com/v1/operations/op_3" || data["statusUrl"] != location {
+			t.Errorf("Location = %q, statusUrl = %v, want them resolved", location, data["statusUrl"])
+		}
+	}
+
+	c, w := newContext(http.MethodPost, "/exports")
+	h.OperationAccepted(c, responsehelper.Operation{ID: "op_4", Status: responsehelper.OperationPending})
+	if location, ok := w.Header()["Location"]; ok {
+		t.Errorf("Location = %q without a status URL", location)
+	}
+}
+
+func TestOperationUnknownStatus(t *testing.T) {
+	logs := &captureHandler{level: slog.LevelWarn}
+	h := responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(logs)))
+	status, body := operationStep(t, h, responsehelper.Operation{ID: "op_5", Status: "paused"})
+
+	if data, _ := body["data"].(map[string]interface{}); status != http.StatusOK || data["status"] != "paused" {
+		t.Errorf("%d %v", status, body)
+	}
+	if len(logs.records) == 0 || !strings.Contains(logs.records[0].Message, `unknown operation status "paused"`) {
+		t.Errorf("logs = %v, want a warning about the status", logs.records)
+	}
+}
@@ -0,0 +1,356 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultErrorCauseDepth is the number of causes rendered when WithErrorCauseDepth is not used.
+const defaultErrorCauseDepth = 5
+
+// config holds the settings shared by every response written by a helper.
+type config struct {
+	// sanitizeErrors drops the text of underlying errors from the response
+	// details so internal information is not leaked to clients.
+	sanitizeErrors bool
+	// validationStatus is the status used by ValidationFailed, 400 when unset.
+	validationStatus int
+	// problemDetails renders errors as RFC 7807 problem details.
+	problemDetails bool
+	// debug enables output that helps during development, never in gin's release mode.
+	debug bool
+	// errorCauseDepth caps the number of causes rendered in debug mode.
+	errorCauseDepth int
+	// errorTypeBase is the base URL of the error "type" URI, no type is rendered when empty.
+	errorTypeBase string
+	// translator resolves the message keys passed to the *Key methods.
+	translator TranslatorFunc
+	// bearerChallenge sets Bearer WWW-Authenticate challenges where the RFC 6750 flow expects them.
+	bearerChallenge bool
+	// bearerRealm is the realm of the Bearer challenges.
+	bearerRealm string
+	// helpURLTemplate is expanded into the "helpUrl" of every error.
+	helpURLTemplate string
+	// rateLimitReset is the format of the X-RateLimit-Reset header.
+	rateLimitReset RateLimitResetFormat
+	// responseHooks are called after every response.
+	responseHooks []ResponseHook
+	// audit delivers the audit entries of error responses.
+	audit *auditQueue
+	// errorReporter receives the errors of 5xx responses.
+	errorReporter ErrorReporterFunc
+	// reportedContextKeys are the gin context keys passed to the error reporter.
+	reportedContextKeys []string
+	// logger receives the responses and warnings, nil disables logging.
+	logger *slog.Logger
+	// logLevels overrides the level of responses by status.
+	logLevels map[int]slog.Level
+	// logSuccess logs success responses as well.
+	logSuccess bool
+	// contentNegotiation picks the format of the envelope from the Accept header.
+	contentNegotiation bool
+	// defaultFormat is the format of the envelope when the client has no preference.
+	defaultFormat Format
+	// formatQueryParam is the query parameter overriding the Accept header.
+	formatQueryParam string
+	// jsonpCallbackParam is the query parameter naming the JSONP callback.
+	jsonpCallbackParam string
+	// jsonpErrorStatus sends the real status of errors to JSONP requests.
+	jsonpErrorStatus bool
+	// errorPage renders error responses for browsers preferring HTML.
+	errorPage *template.Template
+	// pageLinkParam is the page query parameter of the pagination Link headers, no links are set when empty.
+	pageLinkParam string
+	// linkBaseURL replaces the scheme, the host and the path prefix of the request in links.
+	linkBaseURL *url.URL
+	// forwardedHeaders takes the scheme and the host of links from the X-Forwarded-* headers.
+	forwardedHeaders bool
+	// queryMeta adds the AppliedQuery of the request to the meta of paginated responses.
+	queryMeta bool
+	// queryMetaOnSuccess adds the AppliedQuery to the meta of Success as well.
+	queryMetaOnSuccess bool
+	// queryMetaDenylist are the lowercased filters left out of the AppliedQuery, the defaults when nil.
+	queryMetaDenylist map[string]bool
+	// legacyMeta converts string and map meta values into a Meta.
+	legacyMeta bool
+	// requestDuration reports the time spent on the request in the meta and the Server-Timing header.
+	requestDuration bool
+	// responseTime is the header reporting the time spent on the request, none when nil.
+	responseTime *responseTime
+	// apiVersion is sent in the meta and the X-API-Version header of every response.
+	apiVersion *apiVersion
+	// countHeaders sets X-Total-Count and X-Total-Pages on paginated responses.
+	countHeaders bool
+	// exposeCountHeaders adds the count headers to Access-Control-Expose-Headers.
+	exposeCountHeaders bool
+	// emptyCollections sends nil slices and maps passed as data as [] and {}.
+	emptyCollections bool
+	// deepEmptyCollections does the same for the collections inside the data.
+	deepEmptyCollections bool
+	// countPlacement is where SuccessWithCount sends the count.
+	countPlacement CountPlacement
+	// noPooling allocates a new envelope per response instead of reusing pooled ones.
+	noPooling bool
+	// jsonEncoder marshals the JSON responses, encoding/json when nil.
+	jsonEncoder JSONEncoder
+	// validateRawJSON checks json.RawMessage data before sending it.
+	validateRawJSON bool
+	// requestEcho adds "meta.request" to 4xx envelopes outside release mode.
+	requestEcho bool
+	// requestEchoDenylist are the lowercased query parameters masked in "meta.request", the defaults when nil.
+	requestEchoDenylist map[string]bool
+	// requestEchoHeaders are the headers echoed in "meta.request", the defaults when nil.
+	requestEchoHeaders []string
+	// strictDoubleWrite panics in debug mode when a second response is written.
+	strictDoubleWrite bool
+	// deleteStatus is the status sent by Deleted, 200 OK unless it is 204 No Content.
+	deleteStatus int
+	// nullFields renders a missing meta and pagination as null and empty details as "".
+	nullFields bool
+	// maxMessageLength is the number of characters error messages are truncated to, none below 1.
+	maxMessageLength int
+	// skipOnClientGone skips the responses of requests whose client disconnected.
+	skipOnClientGone bool
+	// defaultCache is the Cache-Control of the success responses sent without WithCache.
+	defaultCache *CachePolicy
+	// oauthCodes are the error codes OAuthError accepts besides the ones of RFC 6749.
+	oauthCodes map[string]bool
+	// signingSecret is the HMAC secret of WithResponseSigning, signing is off when empty.
+	signingSecret []byte
+	// signingHeader carries the signature of WithResponseSigning.
+	signingHeader string
+	// maxResponseBytes caps the JSON size of the success envelopes, no cap when 0.
+	maxResponseBytes int64
+	// responseTooLargeStatus is the status of the error replacing an envelope beyond maxResponseBytes.
+	responseTooLargeStatus int
+	// truncateResponses cuts the slice data beyond maxResponseBytes instead.
+	truncateResponses bool
+	// fieldsQueryParam is the query parameter of the sparse fieldsets, disabled when empty.
+	fieldsQueryParam string
+	// beforeSendHooks modify the envelopes before they are marshalled.
+	beforeSendHooks []BeforeSendHook
+	// tenantKey is the gin context key of the tenant added to the meta.
+	tenantKey string
+	// tenantHeader sends the tenant in the X-Tenant-ID header.
+	tenantHeader bool
+	// dbErrorScrubbing replaces the database driver errors of the responses.
+	dbErrorScrubbing bool
+}
+
+// Option configures a ResponseHelper created by NewResponseHelper.
+type Option func(*config)
+
+// WithErrorSanitization controls whether the text of underlying errors
+// (the err passed to InternalError, Conflict, AlreadyExists or wrapped in an
+// APIError) is written to the "details" field. Enable it in production. It
+// also enables WithDBErrorScrubbing.
+func WithErrorSanitization(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.sanitizeErrors = enabled
+	}
+}
+
+// WithValidationStatus sets the status used by ValidationFailed, eg:
+// http.StatusUnprocessableEntity. Defaults to 400 Bad Request.
+func WithValidationStatus(status int) Option {
+	return func(cfg *config) {
+		cfg.validationStatus = status
+	}
+}
+
+// WithDeleteStatus sets the status sent by Deleted: http.StatusOK with the
+// message in the body, the default, or http.StatusNoContent without a body.
+// Other statuses are ignored.
+func WithDeleteStatus(status int) Option {
+	return func(cfg *config) {
+		if status == http.StatusOK || status == http.StatusNoContent {
+			cfg.deleteStatus = status
+		}
+	}
+}
+
+// WithProblemDetails makes every error helper respond with RFC 7807
+// "application/problem+json" instead of the standard error envelope. The
+// message becomes the title, the details become the detail and the request
+// path becomes the instance. Success responses are not affected.
+func WithProblemDetails(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.problemDetails = enabled
+	}
+}
+
+// WithDebug enables debug output, eg: the "causes" of an error. It has no
+// effect when gin runs in release mode, so it is safe to leave enabled.
+func WithDebug(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.debug = enabled
+	}
+}
+
+// WithErrorCauseDepth sets how many unwrapped errors are rendered as "causes"
+// in debug mode. Defaults to 5.
+func WithErrorCauseDepth(depth int) Option {
+	return func(cfg *config) {
+		cfg.errorCauseDepth = depth
+	}
+}
+
+// WithErrorTypeBase adds a machine readable "type" URI to every error,
+// composed of baseURL and a slug. The slug is set per response with
+// WithErrorType, or derived from the status, eg: ".../not-found".
+func WithErrorTypeBase(baseURL string) Option {
+	return func(cfg *config) {
+		cfg.errorTypeBase = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTranslator sets the function used by the *Key methods, eg:
+// NotFoundKey, to resolve a message key into the message for the request.
+// A Catalog can be used as a simple implementation.
+func WithTranslator(translator TranslatorFunc) Option {
+	return func(cfg *config) {
+		cfg.translator = translator
+	}
+}
+
+// WithBearerChallenge tells the helper the API uses Bearer tokens, so
+// ForbiddenScope also sets `WWW-Authenticate: Bearer error="insufficient_scope"`
+// with the required scope. The realm, also used by UnauthorizedReason, is
+// left out when empty.
+func WithBearerChallenge(realm string) Option {
+	return func(cfg *config) {
+		cfg.bearerChallenge = true
+		cfg.bearerRealm = realm
+	}
+}
+
+// WithHelpURLTemplate links every error to its documentation through
+// "helpUrl". The placeholders {status} (eg: "404") and {code} (the errorCode,
+// or the status name eg: "NOT_FOUND" when there is none) are expanded. An
+// invalid template is logged once and ignored.
+//
+// Example:
+//
+//	responsehelper.WithHelpURLTemplate("https://docs.example.com/errors/{code}")
+func WithHelpURLTemplate(template string) Option {
+	return func(cfg *config) {
+		if err := validateHelpURLTemplate(template); err != nil {
+			cfg.warnf("ignoring help URL template %q: %v", template, err)
+			cfg.helpURLTemplate = ""
+			return
+		}
+		cfg.helpURLTemplate = template
+	}
+}
+
+// WithRateLimitResetFormat sets how X-RateLimit-Reset is sent, as the unix
+// time of the reset (RateLimitResetEpoch, the default) or as the seconds left
+// until it (RateLimitResetDelta).
+func WithRateLimitResetFormat(format RateLimitResetFormat) Option {
+	return func(cfg *config) {
+		cfg.rateLimitReset = format
+	}
+}
+
+// debugEnabled reports whether debug output may be written.
+func (cfg *config) debugEnabled() bool {
+	return cfg.debug && gin.Mode() != gin.ReleaseMode
+}
+
+// errorDetails returns the details to render for err, and false when the
+// details should be left out of the response.
+func (cfg *config) errorDetails(err error) (string, bool) {
+	if err == nil || cfg.sanitizeErrors {
+		return "", false
+	}
+	return err.Error(), true
+}
+
+// addErrorCauses adds the chain of errors wrapped by err to errorBody as
+// "causes", eg: for fmt.Errorf("saving user: %w", pgErr) it renders the text
+// of pgErr. Only done in debug mode.
+func (cfg *config) addErrorCauses(errorBody *ErrorBody, err error) {
+	if err == nil || cfg.sanitizeErrors || !cfg.debugEnabled() {
+		return
+	}
+	depth := cfg.errorCauseDepth
+	if depth <= 0 {
+		depth = defaultErrorCauseDepth
+	}
+	var causes []string
+	for cause := errors.Unwrap(err); cause != nil && len(causes) < depth; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	if len(causes) > 0 {
+		errorBody.Causes = causes
+	}
+}
+
+// errorTypeURI returns the error "type" URI for status, using slug when set.
+// It reports false when no base URL is configured or the URI is not valid.
+func (cfg *config) errorTypeURI(status int, slug string) (string, bool) {
+	if cfg.errorTypeBase == "" {
+		return "", false
+	}
+	if slug == "" {
+		slug = strings.ToLower(strings.ReplaceAll(statusText(status), "_", "-"))
+	}
+	uri := cfg.errorTypeBase + "/" + strings.TrimLeft(slug, "/")
+	parsed, err := url.Parse(uri)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return "", false
+	}
+	return uri, true
+}
+
+// warnf logs a warning about a misuse of the helper.
+func (cfg *config) warnf(format string, args ...interface{}) {
+	if cfg.logger != nil {
+		cfg.logger.Warn("responsehelper: " + fmt.Sprintf(format, args...))
+		return
+	}
+	log.Printf("[responsehelper] WARNING: "+format, args...)
+}
+
+// validateHelpURLTemplate checks that template only uses the known
+// placeholders and expands to an absolute URL.
+func validateHelpURLTemplate(template string) error {
+	expanded := expandHelpURL(template, "500", "INTERNAL_SERVER_ERROR")
+	if strings.ContainsAny(expanded, "{}") {
+		return errors.New("unknown placeholder, only {status} and {code} are supported")
+	}
+	parsed, err := url.Parse(expanded)
+	if err != nil {
+		return err
+	}
+	if !parsed.IsAbs() || parsed.Host == "" {
+		return errors.New("not an absolute URL")
+	}
+	return nil
+}
+
+// expandHelpURL replaces the placeholders of a help URL template.
+func expandHelpURL(template, status, code string) string {
+	return strings.NewReplacer("{status}", url.PathEscape(status), "{code}", url.PathEscape(code)).Replace(template)
+}
+
+// helpURL returns the "helpUrl" of an error, preferring the per response URL.
+func (cfg *config) helpURL(status int, errorBody *ErrorBody, override string) string {
+	if override != "" || cfg.helpURLTemplate == "" {
+		return override
+	}
+	code := errorBody.ErrorCode
+	if code == "" {
+		code = statusText(status)
+	}
+	return expandHelpURL(cfg.helpURLTemplate, strconv.Itoa(status), code)
+}
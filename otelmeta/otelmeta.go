@@ -0,0 +1,84 @@
+// Package otelmeta adds the OpenTelemetry trace context to the responsehelper
+// envelopes.
+package otelmeta
+
+import (
+	"net/http"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDHeader is the response header carrying the trace ID of a 5xx response.
+	TraceIDHeader = "X-Trace-ID"
+
+	// ErrorIDAttribute is the span attribute holding the errorId of a 5xx response.
+	ErrorIDAttribute = attribute.Key("responsehelper.error_id")
+	// StatusCodeAttribute is the span attribute holding the response status.
+	StatusCodeAttribute = attribute.Key("http.response.status_code")
+)
+
+// Middleware adds "meta.traceId" and "meta.spanId" of the span in the request
+// context to every envelope and the X-Trace-ID header to 5xx responses. The
+// response status and errorId are recorded on the span. Nothing is added when
+// the span is not recording.
+//
+// Register it after the tracing middleware that starts the span and after
+// responsehelper.MetaMiddleware.
+//
+// Example:
+//
+//	router.Use(otelgin.Middleware("api"))
+//	router.Use(responsehelper.MetaMiddleware())
+//	router.Use(otelmeta.Middleware())
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		span := trace.SpanFromContext(c.Request.Context())
+		if !span.IsRecording() {
+			c.Next()
+			return
+		}
+		spanContext := span.SpanContext()
+		traceID := spanContext.TraceID().String()
+		responsehelper.SetMetaField(c, "traceId", traceID)
+		responsehelper.SetMetaField(c, "spanId", spanContext.SpanID().String())
+		c.Writer = &traceWriter{ResponseWriter: c.Writer, traceID: traceID}
+
+		c.Next()
+
+		span.SetAttributes(StatusCodeAttribute.Int(c.Writer.Status()))
+		if errorID := c.GetString(responsehelper.ErrorIDKey); errorID != "" {
+			span.SetAttributes(ErrorIDAttribute.String(errorID))
+		}
+	}
+}
+
+// traceWriter sets the X-Trace-ID header right before a 5xx status is written.
+type traceWriter struct {
+	gin.ResponseWriter
+	traceID string
+}
+
+func (w *traceWriter) WriteHeaderNow() {
+	w.setTraceHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *traceWriter) Write(data []byte) (int, error) {
+	w.setTraceHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *traceWriter) WriteString(s string) (int, error) {
+	w.setTraceHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *traceWriter) setTraceHeader() {
+	if !w.Written() && w.Status() >= http.StatusInternalServerError {
+		w.Header().Set(TraceIDHeader, w.traceID)
+	}
+}
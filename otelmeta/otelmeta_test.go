@@ -0,0 +1,145 @@
+package otelmeta_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/otelmeta"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// tracedEngine returns an engine starting a span of provider for every
+// request, answering /ok with a success and /fail with an internal error.
+func tracedEngine(provider trace.TracerProvider) *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		ctx, span := provider.Tracer("test").Start(c.Request.Context(), c.Request.URL.Path)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	engine.Use(responsehelper.MetaMiddleware())
+	engine.Use(otelmeta.Middleware())
+	engine.GET("/ok", func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) })
+	engine.GET("/fail", func(c *gin.Context) { h.InternalError(c, "oops", errors.New("boom")) })
+	return engine
+}
+
+func serve(engine *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+// traceMeta decodes the trace members of the meta of the body of w.
+func traceMeta(t *testing.T, w *httptest.ResponseRecorder) (traceID, spanID *string) {
+	t.Helper()
+	var body struct {
+		Meta struct {
+			TraceID *string `json:"traceId"`
+			SpanID  *string `json:"spanId"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return body.Meta.TraceID, body.Meta.SpanID
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestMiddlewareSuccess(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	w := serve(tracedEngine(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), "/ok")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(spans))
+	}
+	spanContext := spans[0].SpanContext()
+	traceID, spanID := traceMeta(t, w)
+	if traceID == nil || *traceID != spanContext.TraceID().String() {
+		t.Errorf("meta.traceId is not the trace of the span\nbody: %s", w.Body)
+	}
+	if spanID == nil || *spanID != spanContext.SpanID().String() {
+		t.Errorf("meta.spanId is not the span\nbody: %s", w.Body)
+	}
+	if got := w.Header().Get(otelmeta.TraceIDHeader); got != "" {
+		t.Errorf("%s = %q on a success", otelmeta.TraceIDHeader, got)
+	}
+	if got := spanAttributes(spans[0])[otelmeta.StatusCodeAttribute].AsInt64(); got != http.StatusOK {
+		t.Errorf("status attribute = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestMiddlewareServerError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	w := serve(tracedEngine(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), "/fail")
+
+	span := recorder.Ended()[0]
+	traceID := span.SpanContext().TraceID().String()
+	if got := w.Header().Get(otelmeta.TraceIDHeader); got != traceID {
+		t.Errorf("%s = %q, want %q", otelmeta.TraceIDHeader, got, traceID)
+	}
+	if got, _ := traceMeta(t, w); got == nil || *got != traceID {
+		t.Errorf("meta.traceId is not the trace of the span\nbody: %s", w.Body)
+	}
+	attributes := spanAttributes(span)
+	if got := attributes[otelmeta.StatusCodeAttribute].AsInt64(); got != http.StatusInternalServerError {
+		t.Errorf("status attribute = %d, want %d", got, http.StatusInternalServerError)
+	}
+	if got := attributes[otelmeta.ErrorIDAttribute].AsString(); got == "" || got != w.Header().Get(responsehelper.ErrorIDHeader) {
+		t.Errorf("errorId attribute = %q, want %q", got, w.Header().Get(responsehelper.ErrorIDHeader))
+	}
+}
+
+func TestMiddlewareWithoutRecordingSpan(t *testing.T) {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	for _, path := range []string{"/ok", "/fail"} {
+		w := serve(tracedEngine(provider), path)
+
+		if got := w.Header().Get(otelmeta.TraceIDHeader); got != "" {
+			t.Errorf("%s: %s = %q without a recording span", path, otelmeta.TraceIDHeader, got)
+		}
+		if traceID, spanID := traceMeta(t, w); traceID != nil || spanID != nil {
+			t.Errorf("%s: trace members set without a recording span\nbody: %s", path, w.Body)
+		}
+	}
+}
+
+func TestMiddlewareWithoutSpan(t *testing.T) {
+	engine := gin.New()
+	engine.Use(otelmeta.Middleware())
+	engine.GET("/", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.Background())
+		responsehelper.NewResponseHelper().Success(c, nil)
+	})
+	w := serve(engine, "/")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d\nbody: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if traceID, _ := traceMeta(t, w); traceID != nil {
+		t.Errorf("meta.traceId set without a span\nbody: %s", w.Body)
+	}
+}
@@ -0,0 +1,305 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultPageSize is the page size of ParsePagination when WithDefaultPageSize is not used.
+	defaultPageSize = 20
+	// defaultMaxPageSize is the largest page size of ParsePagination when WithMaxPageSize is not used.
+	defaultMaxPageSize = 100
+)
+
+// sortFieldPattern matches the sort fields accepted without WithSortFields.
+var sortFieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Pagination is the "pagination" object of SuccessWithPagination. Create it
+// with NewPagination so the totals are computed consistently.
+type Pagination struct {
+	CurrentPage  int   `json:"currentPage" example:"2"`
+	PageSize     int   `json:"pageSize" example:"20"`
+	TotalPages   int   `json:"totalPages" example:"5"`
+	TotalRecords int64 `json:"totalRecords" example:"93"`
+	HasNext      bool  `json:"hasNext" example:"true"`
+	HasPrev      bool  `json:"hasPrev" example:"true"`
+}
+
+// NewPagination returns the Pagination of page, 1-based, of a listing of
+// total records split in pages of size records. A page below 1 is treated as
+// 1, a size below 1 as 1 and a negative total as 0.
+//
+// Example:
+//
+//	h.responseHelper.SuccessWithPagination(c, users, responsehelper.NewPagination(page, size, total))
+func NewPagination(page, size int, total int64) Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 1
+	}
+	if total < 0 {
+		total = 0
+	}
+	pages := total / int64(size)
+	if total%int64(size) != 0 {
+		pages++
+	}
+	if pages > math.MaxInt {
+		pages = math.MaxInt
+	}
+	return Pagination{
+		CurrentPage:  page,
+		PageSize:     size,
+		TotalPages:   int(pages),
+		TotalRecords: total,
+		HasNext:      int64(page) < pages,
+		HasPrev:      page > 1,
+	}
+}
+
+// paginationValue returns the "pagination" of SuccessWithPagination. A
+// Pagination has its totals recomputed, other values are kept as they are.
+func paginationValue(pagination interface{}) interface{} {
+	switch p := pagination.(type) {
+	case Pagination:
+		return NewPagination(p.CurrentPage, p.PageSize, p.TotalRecords)
+	case *Pagination:
+		if p != nil {
+			return NewPagination(p.CurrentPage, p.PageSize, p.TotalRecords)
+		}
+	}
+	return pagination
+}
+
+// SortOrder is the direction of a sort field.
+type SortOrder string
+
+const (
+	// SortAsc sorts from the smallest value to the largest.
+	SortAsc SortOrder = "asc"
+	// SortDesc sorts from the largest value to the smallest.
+	SortDesc SortOrder = "desc"
+)
+
+// SortField is a field of the "sort" query parameter, eg: "-createdAt" is
+// SortField{Field: "createdAt", Order: SortDesc}.
+type SortField struct {
+	Field string    `json:"field"`
+	Order SortOrder `json:"order"`
+}
+
+// PageRequest is the page of a listing requested in the query string, as
+// parsed by ParsePagination.
+type PageRequest struct {
+	// Page is the requested page, 1-based.
+	Page int
+	// PageSize is the number of records per page.
+	PageSize int
+	// Sort are the fields to sort by, in order of precedence.
+	Sort []SortField
+	// Order is the direction of the sort fields without a "-" or "+" prefix.
+	Order SortOrder
+}
+
+// Offset returns the number of records before the page.
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// Pagination returns the Pagination of the page in a listing of total records.
+func (p PageRequest) Pagination(total int64) Pagination {
+	return NewPagination(p.Page, p.PageSize, total)
+}
+
+// PaginationOption configures ParsePagination.
+type PaginationOption func(*paginationConfig)
+
+type paginationConfig struct {
+	defaultPageSize int
+	maxPageSize     int
+	sortFields      map[string]bool
+	allowedSort     []string
+	filters         map[string]string
+}
+
+// WithDefaultPageSize sets the page size of requests without "pageSize", 20
+// by default.
+func WithDefaultPageSize(size int) PaginationOption {
+	return func(cfg *paginationConfig) {
+		cfg.defaultPageSize = size
+	}
+}
+
+// WithMaxPageSize sets the largest "pageSize" accepted, 100 by default.
+func WithMaxPageSize(size int) PaginationOption {
+	return func(cfg *paginationConfig) {
+		cfg.maxPageSize = size
+	}
+}
+
+// WithSortFields restricts "sort" to the given fields. Without it any field
+// made of letters, digits, "_" and "." is accepted.
+func WithSortFields(fields ...string) PaginationOption {
+	return func(cfg *paginationConfig) {
+		cfg.sortFields = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			cfg.sortFields[field] = true
+		}
+		cfg.allowedSort = fields
+	}
+}
+
+// WithAppliedFilters sets the filters the handler applies to the listing,
+// eg: {"status": "active"}, sent in "meta.query" with WithQueryMeta.
+func WithAppliedFilters(filters map[string]string) PaginationOption {
+	return func(cfg *paginationConfig) {
+		cfg.filters = filters
+	}
+}
+
+// ParsePagination parses the "page", "pageSize", "sort" and "order" query
+// parameters, empty ones are treated as missing. When one of them is invalid
+// it sends a 400 response with a field error for each invalid parameter and
+// returns false, like BindJSON.
+//
+// "page" defaults to 1, "pageSize" to WithDefaultPageSize and "order" to
+// "asc". A page whose offset does not fit in an int is too large. "sort" is
+// a comma separated list of fields, a "-" prefix sorts a field in descending
+// order and a "+" prefix in ascending order, eg: "?sort=-createdAt,name". A
+// "+" that was not escaped arrives as a space and is accepted as well.
+//
+// Example:
+//
+//	page, ok := responsehelper.ParsePagination(c, h.responseHelper, responsehelper.WithSortFields("createdAt", "name"))
+//	if !ok {
+//		return
+//	}
+//	users, total := h.users.List(page.Offset(), page.PageSize, page.Sort)
+//	h.responseHelper.SuccessWithPagination(c, users, page.Pagination(total))
+func ParsePagination(c *gin.Context, h ResponseHelper, opts ...PaginationOption) (PageRequest, bool) {
+	cfg := paginationConfig{defaultPageSize: defaultPageSize, maxPageSize: defaultMaxPageSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.maxPageSize < 1 {
+		cfg.maxPageSize = defaultMaxPageSize
+	}
+	if cfg.defaultPageSize < 1 || cfg.defaultPageSize > cfg.maxPageSize {
+		cfg.defaultPageSize = min(defaultPageSize, cfg.maxPageSize)
+	}
+	request := PageRequest{Page: 1, PageSize: cfg.defaultPageSize, Order: SortAsc}
+	var fieldErrors []FieldError
+	// the page size caps the page, the default stands in for an invalid one
+	size, sizeErr := request.PageSize, (*FieldError)(nil)
+	if value := c.Query("pageSize"); value != "" {
+		request.PageSize, sizeErr = parsePageParam("pageSize", value, cfg.maxPageSize)
+		if sizeErr == nil {
+			size = request.PageSize
+		}
+	}
+	if value := c.Query("page"); value != "" {
+		// Offset must fit in an int
+		page, fieldErr := parsePageParam("page", value, math.MaxInt/size)
+		if fieldErr != nil {
+			fieldErrors = append(fieldErrors, *fieldErr)
+		}
+		request.Page = page
+	}
+	if sizeErr != nil {
+		fieldErrors = append(fieldErrors, *sizeErr)
+	}
+	if value := c.Query("order"); value != "" {
+		switch order := SortOrder(strings.ToLower(value)); order {
+		case SortAsc, SortDesc:
+			request.Order = order
+		default:
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   "order",
+				Tag:     "oneof",
+				Param:   "asc desc",
+				Message: "must be asc or desc",
+			})
+		}
+	}
+	if value := c.Query("sort"); value != "" {
+		sort, sortErrors := cfg.parseSort(value, request.Order)
+		fieldErrors = append(fieldErrors, sortErrors...)
+		request.Sort = sort
+	}
+	if len(fieldErrors) > 0 {
+		h.RespondAPIError(c, &APIError{
+			Status:      http.StatusBadRequest,
+			Message:     "Invalid pagination parameters",
+			FieldErrors: fieldErrors,
+		})
+		return PageRequest{}, false
+	}
+	c.Set(AppliedQueryKey, AppliedQuery{
+		Page:     request.Page,
+		PageSize: request.PageSize,
+		Sort:     request.Sort,
+		Order:    request.Order,
+		Filters:  cfg.filters,
+	})
+	return request, true
+}
+
+// parsePageParam parses a page or page size between 1 and max.
+func parsePageParam(name, value string, max int) (int, *FieldError) {
+	// out of range values are clamped by strconv and reported as too small or too large
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil && !errors.Is(err, strconv.ErrRange):
+		return 0, &FieldError{Field: name, Tag: "number", Message: "must be a whole number"}
+	case n < 1:
+		return 0, &FieldError{Field: name, Tag: "min", Param: "1", Message: "must be at least 1"}
+	case n > max:
+		return 0, &FieldError{Field: name, Tag: "max", Param: strconv.Itoa(max), Message: fmt.Sprintf("must be at most %d", max)}
+	}
+	return n, nil
+}
+
+// parseSort parses the "sort" query parameter, fields without a prefix are
+// sorted in order.
+func (cfg *paginationConfig) parseSort(value string, order SortOrder) ([]SortField, []FieldError) {
+	var fields []SortField
+	var fieldErrors []FieldError
+	for _, part := range strings.Split(value, ",") {
+		field := SortField{Field: strings.TrimSpace(part), Order: order}
+		switch {
+		case strings.HasPrefix(field.Field, "-"):
+			field.Field, field.Order = field.Field[1:], SortDesc
+		case strings.HasPrefix(field.Field, "+"):
+			field.Field, field.Order = field.Field[1:], SortAsc
+		case strings.HasPrefix(part, " "):
+			// the query parser decodes an unescaped "+" as a space
+			field.Order = SortAsc
+		}
+		switch {
+		case field.Field == "":
+			fieldErrors = append(fieldErrors, FieldError{Field: "sort", Tag: "required", Message: "empty sort field"})
+		case cfg.sortFields != nil && !cfg.sortFields[field.Field]:
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   "sort",
+				Tag:     "oneof",
+				Param:   strings.Join(cfg.allowedSort, " "),
+				Message: fmt.Sprintf("cannot sort by %q", field.Field),
+			})
+		case cfg.sortFields == nil && !sortFieldPattern.MatchString(field.Field):
+			fieldErrors = append(fieldErrors, FieldError{Field: "sort", Tag: "sort", Message: fmt.Sprintf("invalid sort field %q", field.Field)})
+		default:
+			fields = append(fields, field)
+		}
+	}
+	return fields, fieldErrors
+}
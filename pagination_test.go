@@ -0,0 +1,166 @@
+package responsehelper_test
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewPagination(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		page, size int
+		total      int64
+		want       responsehelper.Pagination
+	}{
+		{"no records", 1, 10, 0, responsehelper.Pagination{CurrentPage: 1, PageSize: 10}},
+		{"exact multiple", 2, 10, 30,
+			responsehelper.Pagination{CurrentPage: 2, PageSize: 10, TotalPages: 3, TotalRecords: 30, HasNext: true, HasPrev: true}},
+		{"last page of an exact multiple", 3, 10, 30,
+			responsehelper.Pagination{CurrentPage: 3, PageSize: 10, TotalPages: 3, TotalRecords: 30, HasPrev: true}},
+		{"partial last page", 1, 10, 31,
+			responsehelper.Pagination{CurrentPage: 1, PageSize: 10, TotalPages: 4, TotalRecords: 31, HasNext: true}},
+		{"one record", 1, 10, 1, responsehelper.Pagination{CurrentPage: 1, PageSize: 10, TotalPages: 1, TotalRecords: 1}},
+		{"page past the end", 9, 10, 30,
+			responsehelper.Pagination{CurrentPage: 9, PageSize: 10, TotalPages: 3, TotalRecords: 30, HasPrev: true}},
+		{"page below 1", -4, 10, 30,
+			responsehelper.Pagination{CurrentPage: 1, PageSize: 10, TotalPages: 3, TotalRecords: 30, HasNext: true}},
+		{"zero size", 1, 0, 3,
+			responsehelper.Pagination{CurrentPage: 1, PageSize: 1, TotalPages: 3, TotalRecords: 3, HasNext: true}},
+		{"negative size", 1, -10, 3,
+			responsehelper.Pagination{CurrentPage: 1, PageSize: 1, TotalPages: 3, TotalRecords: 3, HasNext: true}},
+		{"negative total", 1, 10, -5, responsehelper.Pagination{CurrentPage: 1, PageSize: 10}},
+		{"huge total", 1, 1, math.MaxInt64,
+			responsehelper.Pagination{CurrentPage: 1, PageSize: 1, TotalPages: math.MaxInt, TotalRecords: math.MaxInt64, HasNext: true}},
+		{"huge size", 2, math.MaxInt, 5,
+			responsehelper.Pagination{CurrentPage: 2, PageSize: math.MaxInt, TotalPages: 1, TotalRecords: 5, HasPrev: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := responsehelper.NewPagination(tc.page, tc.size, tc.total); got != tc.want {
+				t.Errorf("NewPagination(%d, %d, %d) = %+v\nwant %+v", tc.page, tc.size, tc.total, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSuccessWithPaginationRecomputesPagination(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().SuccessWithPagination(c, []int{1}, responsehelper.Pagination{
+		CurrentPage:  2,
+		PageSize:     10,
+		TotalPages:   99,
+		TotalRecords: 15,
+	})
+
+	assertField(t, w, "pagination.totalPages", float64(2))
+	assertField(t, w, "pagination.hasPrev", true)
+	assertField(t, w, "pagination.hasNext", false)
+}
+
+func TestSuccessWithPaginationKeepsOtherValues(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().SuccessWithPagination(c, []int{1}, gin.H{"page": 2, "per_page": 10})
+
+	assertField(t, w, "pagination.page", float64(2))
+	assertField(t, w, "pagination.per_page", float64(10))
+}
+
+// parsePagination runs ParsePagination on a request for target.
+func parsePagination(target string, opts ...responsehelper.PaginationOption) (responsehelper.PageRequest, bool, *httptest.ResponseRecorder) {
+	c, w := newContext(http.MethodGet, target)
+	page, ok := responsehelper.ParsePagination(c, responsehelper.NewResponseHelper(), opts...)
+	return page, ok, w
+}
+
+func TestParsePagination(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		target string
+		opts   []responsehelper.PaginationOption
+		want   responsehelper.PageRequest
+	}{
+		{"defaults", "/users", nil,
+			responsehelper.PageRequest{Page: 1, PageSize: 20, Order: responsehelper.SortAsc}},
+		{"empty values", "/users?page=&pageSize=&sort=&order=", nil,
+			responsehelper.PageRequest{Page: 1, PageSize: 20, Order: responsehelper.SortAsc}},
+		{"page and size", "/users?page=3&pageSize=50", nil,
+			responsehelper.PageRequest{Page: 3, PageSize: 50, Order: responsehelper.SortAsc}},
+		{"default size", "/users", []responsehelper.PaginationOption{responsehelper.WithDefaultPageSize(5)},
+			responsehelper.PageRequest{Page: 1, PageSize: 5, Order: responsehelper.SortAsc}},
+		{"largest page", fmt.Sprintf("/users?page=%d&pageSize=100", math.MaxInt/100), nil,
+			responsehelper.PageRequest{Page: math.MaxInt / 100, PageSize: 100, Order: responsehelper.SortAsc}},
+		{"sort prefixes", "/users?sort=-createdAt,%2Bname,email&order=DESC", nil,
+			responsehelper.PageRequest{Page: 1, PageSize: 20, Order: responsehelper.SortDesc, Sort: []responsehelper.SortField{
+				{Field: "createdAt", Order: responsehelper.SortDesc},
+				{Field: "name", Order: responsehelper.SortAsc},
+				{Field: "email", Order: responsehelper.SortDesc},
+			}}},
+		{"unescaped plus", "/users?sort=+name,-age&order=desc", nil,
+			responsehelper.PageRequest{Page: 1, PageSize: 20, Order: responsehelper.SortDesc, Sort: []responsehelper.SortField{
+				{Field: "name", Order: responsehelper.SortAsc},
+				{Field: "age", Order: responsehelper.SortDesc},
+			}}},
+		{"allowed sort field", "/users?sort=name", []responsehelper.PaginationOption{responsehelper.WithSortFields("name")},
+			responsehelper.PageRequest{Page: 1, PageSize: 20, Order: responsehelper.SortAsc, Sort: []responsehelper.SortField{
+				{Field: "name", Order: responsehelper.SortAsc},
+			}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, w := parsePagination(tc.target, tc.opts...)
+
+			if !ok {
+				t.Fatalf("ParsePagination rejected %s: %s", tc.target, w.Body)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParsePagination(%s) = %+v\nwant %+v", tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePaginationRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		target string
+		opts   []responsehelper.PaginationOption
+		field  string
+		tag    string
+	}{
+		{"page not a number", "/users?page=two", nil, "page", "number"},
+		{"page zero", "/users?page=0", nil, "page", "min"},
+		{"page out of int range", "/users?page=99999999999999999999999", nil, "page", "max"},
+		{"page offset overflows", fmt.Sprintf("/users?page=%d&pageSize=100", math.MaxInt/100+1), nil, "page", "max"},
+		{"page offset overflows the default size", fmt.Sprintf("/users?page=%d", math.MaxInt/20+1), nil, "page", "max"},
+		{"size too large", "/users?pageSize=101", nil, "pageSize", "max"},
+		{"size below the max option", "/users?pageSize=11", []responsehelper.PaginationOption{responsehelper.WithMaxPageSize(10)}, "pageSize", "max"},
+		{"order", "/users?order=up", nil, "order", "oneof"},
+		{"empty sort field", "/users?sort=name,,age", nil, "sort", "required"},
+		{"sort field not allowed", "/users?sort=password", []responsehelper.PaginationOption{responsehelper.WithSortFields("name")}, "sort", "oneof"},
+		{"sort field syntax", "/users?sort=name%24", nil, "sort", "sort"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok, w := parsePagination(tc.target, tc.opts...)
+
+			if ok {
+				t.Fatalf("ParsePagination accepted %s", tc.target)
+			}
+			assertError(t, w, http.StatusBadRequest, "Invalid pagination parameters")
+			assertField(t, w, "error.errors.0.field", tc.field)
+			assertField(t, w, "error.errors.0.tag", tc.tag)
+		})
+	}
+}
+
+func TestParsePaginationReportsEveryParameter(t *testing.T) {
+	_, _, w := parsePagination(fmt.Sprintf("/users?page=%d&pageSize=500&order=up", math.MaxInt/20+1))
+
+	for i, field := range []string{"page", "pageSize", "order"} {
+		assertField(t, w, fmt.Sprintf("error.errors.%d.field", i), field)
+	}
+}
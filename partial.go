@@ -0,0 +1,28 @@
+package responsehelper
+
+import "net/http"
+
+// PartialFailure is a part of an aggregated response that could not be
+// loaded, rendered in the "partialFailures" of the success envelope, eg: a
+// downstream service of a dashboard that failed.
+type PartialFailure struct {
+	// Component names the missing part, eg: "recommendations".
+	Component string `json:"component" xml:"component" example:"recommendations"`
+	// Code identifies the failure, eg: "UPSTREAM_TIMEOUT".
+	Code string `json:"code" xml:"code" example:"UPSTREAM_TIMEOUT"`
+	// Message is the user facing message.
+	Message string `json:"message" xml:"message" example:"Recommendations are unavailable"`
+}
+
+func (r *Core) PartialSuccess(c Exchange, data interface{}, failures []PartialFailure, opts ...ResponseOption) {
+	if len(failures) == 0 {
+		r.Success(c, data, opts...)
+		return
+	}
+	r.renderSuccess(c, "PartialSuccess", http.StatusOK, SuccessEnvelope{
+		Data:            nullable(data),
+		Meta:            metaWithField(r.successMeta(c, "PartialSuccess"), "partial", true),
+		PartialFailures: failures,
+		Success:         true,
+	}, opts...)
+}
@@ -0,0 +1,67 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var dashboard = gin.H{"orders": []int{1, 2}, "profile": gin.H{"name": "arun"}}
+
+// TestPartialSuccessGolden pins the envelope with one and with several
+// failed parts.
+func TestPartialSuccessGolden(t *testing.T) {
+	for name, failures := range map[string][]responsehelper.PartialFailure{
+		"one": {
+			{Component: "recommendations", Code: "UPSTREAM_TIMEOUT", Message: "Recommendations are unavailable"},
+		},
+		"multiple": {
+			{Component: "recommendations", Code: "UPSTREAM_TIMEOUT", Message: "Recommendations are unavailable"},
+			{Component: "notifications", Code: "UPSTREAM_ERROR", Message: "Notifications are unavailable"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/dashboard")
+			c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: "/dashboard"})
+			responsehelper.NewResponseHelper().PartialSuccess(c, dashboard, failures)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", w.Code)
+			}
+			goldenBytes(t, w.Body.Bytes(), filepath.Join("testdata", "partial", name+".json"))
+		})
+	}
+}
+
+func TestPartialSuccessWithoutFailures(t *testing.T) {
+	for name, failures := range map[string][]responsehelper.PartialFailure{"nil": nil, "empty": {}} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/dashboard")
+			c.Set(responsehelper.MetaKey, gin.H{"region": "eu"})
+			responsehelper.NewResponseHelper().PartialSuccess(c, dashboard, failures, responsehelper.WithWarnings(responsehelper.Warning{Code: "STALE"}))
+			plain, want := newContext(http.MethodGet, "/dashboard")
+			plain.Set(responsehelper.MetaKey, gin.H{"region": "eu"})
+			responsehelper.NewResponseHelper().Success(plain, dashboard, responsehelper.WithWarnings(responsehelper.Warning{Code: "STALE"}))
+
+			if w.Code != want.Code || w.Body.String() != want.Body.String() {
+				t.Errorf("PartialSuccess sent %d\n%s\nSuccess sent %d\n%s", w.Code, w.Body, want.Code, want.Body)
+			}
+		})
+	}
+}
+
+func TestPartialSuccessXML(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/dashboard")
+	responsehelper.NewResponseHelper(responsehelper.WithDefaultFormat(responsehelper.FormatXML)).PartialSuccess(c, gin.H{"orders": 2}, []responsehelper.PartialFailure{
+		{Component: "recommendations", Code: "UPSTREAM_TIMEOUT", Message: "Recommendations are unavailable"},
+	})
+
+	want := "<partialFailures><partialFailure><component>recommendations</component><code>UPSTREAM_TIMEOUT</code><message>Recommendations are unavailable</message></partialFailure></partialFailures>"
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+}
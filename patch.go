@@ -0,0 +1,117 @@
+package responsehelper
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+)
+
+func (r *Core) Patched(c Exchange, resource string, data interface{}, changedFields []string, opts ...ResponseOption) {
+	fields := sortedUnique(changedFields)
+	meta := metaWithField(r.responseMeta(c), "changedFields", fields)
+	message := resource + " updated successfully"
+	if len(fields) == 0 {
+		meta = metaWithField(meta, "unchanged", true)
+		message = resource + " unchanged"
+	}
+	r.renderSuccess(c, "Patched", http.StatusOK, SuccessEnvelope{
+		Data:    nullable(data),
+		Message: message,
+		Meta:    meta,
+		Success: true,
+	}, opts...)
+}
+
+// sortedUnique returns the sorted, de-duplicated names, an empty slice
+// rather than nil so it renders as [].
+func sortedUnique(names []string) []string {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// DiffFields returns the sorted JSON paths of the members that differ
+// between the JSON of before and after, for Patched. Nested objects are
+// compared member by member, eg: "address.city", other values as a whole,
+// so a changed slice is reported once. Members are named and compared as
+// they are marshalled: json tags are honoured and pointers are followed.
+// Times are equal when they are the same instant, even in other time zones.
+// It returns nil when the two values are not JSON objects or cannot be
+// marshalled.
+//
+// Example:
+//
+//	h.responseHelper.Patched(c, "User", after, responsehelper.DiffFields(before, after))
+func DiffFields(before, after interface{}) []string {
+	beforeValue, err := JSONValue(before)
+	if err != nil {
+		return nil
+	}
+	afterValue, err := JSONValue(after)
+	if err != nil {
+		return nil
+	}
+	beforeObject, ok := beforeValue.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	afterObject, ok := afterValue.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	fields := diffObjects("", beforeObject, afterObject, []string{})
+	sort.Strings(fields)
+	return fields
+}
+
+// diffObjects appends to fields the paths, prefixed with prefix, of the
+// members differing between before and after.
+func diffObjects(prefix string, before, after map[string]interface{}, fields []string) []string {
+	for key, beforeMember := range before {
+		afterMember, ok := after[key]
+		if !ok {
+			fields = append(fields, prefix+key)
+			continue
+		}
+		beforeObject, beforeIsObject := beforeMember.(map[string]interface{})
+		afterObject, afterIsObject := afterMember.(map[string]interface{})
+		if beforeIsObject && afterIsObject {
+			fields = diffObjects(prefix+key+".", beforeObject, afterObject, fields)
+		} else if !reflect.DeepEqual(beforeMember, afterMember) && !sameInstant(beforeMember, afterMember) {
+			fields = append(fields, prefix+key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			fields = append(fields, prefix+key)
+		}
+	}
+	return fields
+}
+
+// sameInstant reports whether before and after are RFC 3339 times of the
+// same instant, eg: a time.Time read back from a database in UTC.
+func sameInstant(before, after interface{}) bool {
+	beforeText, ok := before.(string)
+	if !ok {
+		return false
+	}
+	afterText, ok := after.(string)
+	if !ok {
+		return false
+	}
+	beforeTime, err := time.Parse(time.RFC3339Nano, beforeText)
+	if err != nil {
+		return false
+	}
+	afterTime, err := time.Parse(time.RFC3339Nano, afterText)
+	return err == nil && beforeTime.Equal(afterTime)
+}
@@ -0,0 +1,127 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestPatched(t *testing.T) {
+	c, w := newContext(http.MethodPatch, "/users/42")
+	c.Set(responsehelper.MetaKey, gin.H{"region": "eu"})
+	responsehelper.NewResponseHelper().Patched(c, "User", gin.H{"id": 42, "name": "arun"}, []string{"name", "email", "name", ""})
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+	body := decodeBody(t, w)
+	if body["message"] != "User updated successfully" {
+		t.Errorf("message = %v", body["message"])
+	}
+	meta, _ := body["meta"].(map[string]interface{})
+	if !reflect.DeepEqual(meta["changedFields"], []interface{}{"email", "name"}) {
+		t.Errorf("meta.changedFields = %v, want sorted and de-duplicated", meta["changedFields"])
+	}
+	if _, ok := meta["unchanged"]; ok || meta["region"] != "eu" {
+		t.Errorf("meta = %v", meta)
+	}
+}
+
+func TestPatchedUnchanged(t *testing.T) {
+	for _, fields := range [][]string{nil, {}, {""}} {
+		c, w := newContext(http.MethodPatch, "/users/42")
+		responsehelper.NewResponseHelper().Patched(c, "User", gin.H{"id": 42}, fields)
+
+		body := decodeBody(t, w)
+		meta, _ := body["meta"].(map[string]interface{})
+		if w.Code != http.StatusOK || body["message"] != "User unchanged" {
+			t.Errorf("%q: %d %v", fields, w.Code, body["message"])
+		}
+		if changed, ok := meta["changedFields"].([]interface{}); !ok || len(changed) != 0 || meta["unchanged"] != true {
+			t.Errorf("%q: meta = %v, want changedFields [] and unchanged", fields, meta)
+		}
+	}
+}
+
+func TestPatchedKeepsTheMetaMiddleware(t *testing.T) {
+	c, w := newContext(http.MethodPatch, "/users/42")
+	c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: "/users/42"})
+	responsehelper.NewResponseHelper().Patched(c, "User", nil, []string{"name"})
+
+	meta, _ := decodeBody(t, w)["meta"].(map[string]interface{})
+	if meta["requestId"] != "req-1" || !reflect.DeepEqual(meta["changedFields"], []interface{}{"name"}) {
+		t.Errorf("meta = %v", meta)
+	}
+}
+
+type patchAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type patchUser struct {
+	Name     string        `json:"name"`
+	Email    string        `json:"email,omitempty"`
+	Password string        `json:"-"`
+	Address  patchAddress  `json:"address"`
+	Billing  *patchAddress `json:"billing"`
+	Tags     []string      `json:"tags"`
+	Updated  time.Time     `json:"updatedAt"`
+	Renamed  string
+}
+
+func TestDiffFields(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	base := patchUser{
+		Name:    "arun",
+		Address: patchAddress{City: "Kochi", Country: "IN"},
+		Billing: &patchAddress{City: "Kochi", Country: "IN"},
+		Tags:    []string{"a", "b"},
+		Updated: now,
+	}
+	for _, tt := range []struct {
+		name   string
+		change func(u *patchUser)
+		want   []string
+	}{
+		{"nothing", func(u *patchUser) {}, []string{}},
+		{"top level", func(u *patchUser) { u.Name = "aruncs" }, []string{"name"}},
+		{"json tag", func(u *patchUser) { u.Renamed = "x" }, []string{"Renamed"}},
+		{"ignored member", func(u *patchUser) { u.Password = "secret" }, []string{}},
+		{"omitempty added", func(u *patchUser) { u.Email = "arun@example.com" }, []string{"email"}},
+		{"nested struct", func(u *patchUser) { u.Address.City = "Kannur" }, []string{"address.city"}},
+		{"pointer followed", func(u *patchUser) { u.Billing = &patchAddress{City: "Kochi", Country: "DE"} }, []string{"billing.country"}},
+		{"pointer cleared", func(u *patchUser) { u.Billing = nil }, []string{"billing"}},
+		{"slice as a whole", func(u *patchUser) { u.Tags = []string{"a", "c"} }, []string{"tags"}},
+		{"time zone", func(u *patchUser) { u.Updated = now.In(time.FixedZone("IST", 5*3600+1800)) }, []string{}},
+		{"time", func(u *patchUser) { u.Updated = now.Add(time.Second) }, []string{"updatedAt"}},
+		{"sorted", func(u *patchUser) { u.Tags, u.Name, u.Address.Country = nil, "x", "DE" }, []string{"address.country", "name", "tags"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			before := base
+			after := before
+			tt.change(&after)
+
+			if got := responsehelper.DiffFields(before, &after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffFields = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffFieldsNotObjects(t *testing.T) {
+	for name, values := range map[string][2]interface{}{
+		"slices":           {[]int{1}, []int{2}},
+		"strings":          {"a", "b"},
+		"nil":              {nil, patchUser{}},
+		"not marshallable": {patchUser{}, func() {}},
+	} {
+		if got := responsehelper.DiffFields(values[0], values[1]); got != nil {
+			t.Errorf("%s: DiffFields = %v, want nil", name, got)
+		}
+	}
+}
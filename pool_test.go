@@ -0,0 +1,97 @@
+package responsehelper_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// TestPoolingLeaksNothing alternates responses with and without each member
+// from many goroutines: a pooled envelope not fully cleared would send a
+// member of an earlier response. Run it with -race.
+func TestPoolingLeaksNothing(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := fmt.Sprintf("%d-%d", g, i)
+				c, w := newContext(http.MethodGet, "/users")
+				var want string
+				switch i % 4 {
+				case 0:
+					c.Set(responsehelper.MetaKey, gin.H{"requestId": id})
+					h.SuccessWithPagination(c, []string{id}, responsehelper.NewPagination(1, 20, 1))
+					want = `{"data":["` + id + `"],"meta":{"requestId":"` + id + `"},"pagination":{"currentPage":1,"pageSize":20,"totalPages":1,"totalRecords":1,"hasNext":false,"hasPrev":false},"success":true}`
+				case 1:
+					h.Success(c, nil)
+					want = `{"data":null,"success":true}`
+				case 2:
+					c.Set(responsehelper.MetaKey, gin.H{"requestId": id})
+					h.BadRequest(c, "Invalid input", id)
+					want = `{"error":{"code":400,"details":"` + id + `","message":"Invalid input","retryable":false,"status":"BAD_REQUEST"},"meta":{"requestId":"` + id + `"},"success":false}`
+				case 3:
+					h.NotFound(c, "missing")
+					want = `{"error":{"code":404,"message":"missing","retryable":false,"status":"NOT_FOUND"},"success":false}`
+				}
+				if got := w.Body.String(); got != want {
+					t.Errorf("body =\n%s\nwant\n%s", got, want)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestWithPoolingDisabled(t *testing.T) {
+	for _, respond := range []func(h responsehelper.ResponseHelper, c *gin.Context){
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 1}) },
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "missing") },
+	} {
+		pooled, w := newContext(http.MethodGet, "/users")
+		respond(responsehelper.NewResponseHelper(), pooled)
+		unpooled, unpooledW := newContext(http.MethodGet, "/users")
+		respond(responsehelper.NewResponseHelper(responsehelper.WithPooling(false)), unpooled)
+
+		if unpooledW.Body.String() != w.Body.String() {
+			t.Errorf("without pooling the body is\n%s\nwant\n%s", unpooledW.Body, w.Body)
+		}
+	}
+}
+
+// TestPoolingSavesAnAllocation checks the envelope is not allocated per
+// response when pooled.
+func TestPoolingSavesAnAllocation(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector drops pooled values")
+	}
+	data := gin.H{"id": 1}
+	allocs := func(opts ...responsehelper.Option) float64 {
+		h := responsehelper.NewResponseHelper(opts...)
+		return testing.AllocsPerRun(100, benchServe(func(c *gin.Context) { h.Success(c, data) }))
+	}
+	if pooled, unpooled := allocs(), allocs(responsehelper.WithPooling(false)); pooled >= unpooled {
+		t.Errorf("pooled responses allocate %v times, %v without pooling", pooled, unpooled)
+	}
+}
+
+func BenchmarkPooling(b *testing.B) {
+	data := gin.H{"id": 1, "name": "arun"}
+	for _, enabled := range []bool{true, false} {
+		b.Run(fmt.Sprintf("pooling=%t", enabled), func(b *testing.B) {
+			h := responsehelper.NewResponseHelper(responsehelper.WithPooling(enabled))
+			serve := benchServe(func(c *gin.Context) { h.Success(c, data) })
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				serve()
+			}
+		})
+	}
+}
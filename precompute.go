@@ -0,0 +1,121 @@
+package responsehelper
+
+import "sync"
+
+// precomputedKey identifies an error registered with PrecomputeError.
+type precomputedKey struct {
+	status  int
+	message string
+}
+
+// errorScalars are the members of an error body that are not dynamic.
+type errorScalars struct {
+	code       int
+	errorCode  string
+	helpURL    string
+	kind       string
+	message    string
+	reason     string
+	resource   string
+	resourceID string
+	retryable  bool
+	status     string
+	typ        string
+}
+
+// precomputedBody is the JSON of a registered error, marshalled on its first
+// response from the members it had then.
+type precomputedBody struct {
+	scalars errorScalars
+	body    []byte
+}
+
+// precomputedErrors holds the errors registered with PrecomputeError.
+type precomputedErrors struct {
+	mu     sync.RWMutex
+	bodies map[precomputedKey]*precomputedBody
+}
+
+func (p *precomputedErrors) register(status int, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bodies == nil {
+		p.bodies = make(map[precomputedKey]*precomputedBody)
+	}
+	key := precomputedKey{status: status, message: message}
+	if _, ok := p.bodies[key]; !ok {
+		p.bodies[key] = nil
+	}
+}
+
+// body returns the JSON of envelope when it is a registered error, marshalling
+// and storing it when the stored one was made from other members.
+func (p *precomputedErrors) body(status int, envelope *ErrorEnvelope, marshal func(interface{}) ([]byte, error)) ([]byte, bool) {
+	key := precomputedKey{status: status, message: envelope.Error.Message}
+	p.mu.RLock()
+	stored, registered := p.bodies[key]
+	p.mu.RUnlock()
+	if !registered {
+		return nil, false
+	}
+	scalars := envelope.Error.scalars()
+	if stored != nil && stored.scalars == scalars {
+		return stored.body, true
+	}
+	body, err := marshal(envelope)
+	if err != nil {
+		return nil, false
+	}
+	p.mu.Lock()
+	p.bodies[key] = &precomputedBody{scalars: scalars, body: body}
+	p.mu.Unlock()
+	return body, true
+}
+
+// scalars returns the members of b that are not dynamic.
+func (b *ErrorBody) scalars() errorScalars {
+	return errorScalars{
+		code:       b.Code,
+		errorCode:  b.ErrorCode,
+		helpURL:    b.HelpURL,
+		kind:       b.Kind,
+		message:    b.Message,
+		reason:     b.Reason,
+		resource:   b.Resource,
+		resourceID: b.ResourceID,
+		retryable:  b.Retryable,
+		status:     b.Status,
+		typ:        b.Type,
+	}
+}
+
+// constant reports whether envelope has no member that changes from one
+// response to the next, so its JSON can be reused.
+func (envelope *ErrorEnvelope) constant() bool {
+	b := &envelope.Error
+	return envelope.Data == nil && envelope.Meta == nil && b.Details == nil && b.Errors == nil &&
+		b.ErrorID == "" && len(b.Causes) == 0 && len(b.Context) == 0 &&
+		len(b.GrantedPermissions) == 0 && len(b.RequiredPermissions) == 0
+}
+
+func (r *Core) PrecomputeError(status int, message string) {
+	r.precomputed.register(r.validErrorStatus(status, "PrecomputeError"), r.truncateMessage(message))
+}
+
+// writePrecomputedError writes the stored JSON of envelope, and reports
+// false when it has to be rendered, eg: because it has a meta or details,
+// or is not sent as JSON.
+func (r *Core) writePrecomputedError(c Exchange, status int, envelope *ErrorEnvelope) bool {
+	if !envelope.constant() {
+		return false
+	}
+	if _, ok := r.jsonpCallback(c); ok || r.responseFormat(c) != MIMEJSON || r.defaultFormat == FormatGoogle {
+		return false
+	}
+	body, ok := r.precomputed.body(status, envelope, r.marshalEnvelope)
+	if !ok {
+		return false
+	}
+	writeData(c, status, jsonContentType, body)
+	return true
+}
@@ -0,0 +1,137 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// precomputedHelper returns a helper with a precomputed 404 "missing".
+func precomputedHelper(opts ...responsehelper.Option) responsehelper.ResponseHelper {
+	h := responsehelper.NewResponseHelper(opts...)
+	h.PrecomputeError(http.StatusNotFound, "missing")
+	return h
+}
+
+func TestPrecomputeErrorSendsTheSameBody(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"NotFound": func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "missing") },
+		"help URL": func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.NotFound(c, "missing", responsehelper.WithHelpURL("https://docs.example.com/404"))
+		},
+		"other text": func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "gone") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := precomputedHelper()
+			for i := 0; i < 2; i++ {
+				c, w := newContext(http.MethodGet, "/users/42")
+				respond(h, c)
+				plain, want := newContext(http.MethodGet, "/users/42")
+				respond(responsehelper.NewResponseHelper(), plain)
+
+				if w.Body.String() != want.Body.String() {
+					t.Errorf("response %d =\n%s\nwant\n%s", i, w.Body, want.Body)
+				}
+				if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+					t.Errorf("Content-Type = %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrecomputeErrorKeepsTheDynamicMembers(t *testing.T) {
+	h := precomputedHelper()
+	warm, _ := newContext(http.MethodGet, "/users/42")
+	h.NotFound(warm, "missing")
+
+	t.Run("meta", func(t *testing.T) {
+		c, w := newContext(http.MethodGet, "/users/42")
+		c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1"})
+		h.NotFound(c, "missing")
+
+		assertError(t, w, http.StatusNotFound, "missing")
+		assertField(t, w, "meta.requestId", "req-1")
+	})
+	t.Run("details", func(t *testing.T) {
+		c, w := newContext(http.MethodGet, "/users/42")
+		h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusNotFound, Message: "missing", Details: "user 42"})
+
+		assertField(t, w, "error.details", "user 42")
+	})
+	t.Run("error code", func(t *testing.T) {
+		c, w := newContext(http.MethodGet, "/users/42")
+		h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusNotFound, Message: "missing", Code: "USER_NOT_FOUND"})
+
+		assertField(t, w, "error.errorCode", "USER_NOT_FOUND")
+	})
+	t.Run("back to the constant body", func(t *testing.T) {
+		c, w := newContext(http.MethodGet, "/users/42")
+		h.NotFound(c, "missing")
+
+		if got, want := w.Body.String(), `{"error":{"code":404,"message":"missing","retryable":false,"status":"NOT_FOUND"},"success":false}`; got != want {
+			t.Errorf("body =\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestPrecomputeErrorKeepsTheOtherMembers(t *testing.T) {
+	h := precomputedHelper()
+	h.PrecomputeError(http.StatusUnauthorized, "missing")
+	h.PrecomputeError(http.StatusNotFound, "user not found")
+	for _, tc := range []struct {
+		name    string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		{"Unauthorized", func(h responsehelper.ResponseHelper, c *gin.Context) { h.Unauthorized(c, "missing") }},
+		{"token expired", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.UnauthorizedReason(c, responsehelper.TokenExpired, "missing")
+		}},
+		{"session revoked", func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.UnauthorizedReason(c, responsehelper.SessionRevoked, "missing")
+		}},
+		{"NotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "user not found") }},
+		{"ResourceNotFound", func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "user", nil) }},
+		{"NotFound again", func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "user not found") }},
+	} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		tc.respond(h, c)
+		plain, want := newContext(http.MethodGet, "/users/42")
+		tc.respond(responsehelper.NewResponseHelper(), plain)
+
+		if w.Body.String() != want.Body.String() {
+			t.Errorf("%s: body =\n%s\nwant\n%s", tc.name, w.Body, want.Body)
+		}
+	}
+}
+
+func TestPrecomputeErrorIsNotSentInOtherFormats(t *testing.T) {
+	h := precomputedHelper(responsehelper.WithContentNegotiation(true))
+	c, w := newContext(http.MethodGet, "/users/42")
+	c.Request.Header.Set("Accept", "application/xml")
+	h.NotFound(c, "missing")
+
+	if got := w.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want XML", got)
+	}
+}
+
+func BenchmarkPrecomputeError(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		h    responsehelper.ResponseHelper
+	}{
+		{"marshalled", responsehelper.NewResponseHelper()},
+		{"precomputed", precomputedHelper()},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			serve := benchServe(func(c *gin.Context) { bench.h.NotFound(c, "missing") })
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				serve()
+			}
+		})
+	}
+}
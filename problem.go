@@ -0,0 +1,129 @@
+package responsehelper
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// problemMembers are the members defined by RFC 7807, extensions can not override them.
+var problemMembers = map[string]bool{
+	"type":     true,
+	"title":    true,
+	"status":   true,
+	"detail":   true,
+	"instance": true,
+}
+
+func (r *Core) Problem(c Exchange, status int, typ, title, detail string, extensions map[string]interface{}) {
+	if r.secondResponse(c, "Problem") {
+		return
+	}
+	status = r.validErrorStatus(status, "Problem")
+	title = r.truncateMessage(title)
+	response := sentResponse{status: status, options: responseOptions{method: "Problem"}, message: title}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	problem := gin.H{}
+	for key, value := range extensions {
+		if !problemMembers[key] {
+			problem[key] = value
+		}
+	}
+	if typ == "" {
+		typ = "about:blank"
+	}
+	problem["type"] = typ
+	problem["title"] = title
+	problem["status"] = status
+	if detail != "" {
+		problem["detail"] = detail
+	}
+	if path := requestPath(c); path != "" {
+		problem["instance"] = path
+	}
+	r.writeResponse(c, response, func(c Exchange) {
+		r.renderProblem(c, status, problem)
+	})
+}
+
+// renderProblem writes problem with the problem+json content type.
+func (r *Core) renderProblem(c Exchange, status int, problem gin.H) {
+	r.writeJSON(c, status, ProblemContentType, problem)
+}
+
+// problemFromError maps an error envelope body onto problem details. The
+// message becomes the title and string details the detail, every other
+// member of the error body is kept as an extension.
+func problemFromError(c Exchange, status int, errorBody *ErrorBody, meta interface{}) gin.H {
+	problem := gin.H{
+		"type":      "about:blank",
+		"status":    status,
+		"title":     errorBody.Message,
+		"retryable": errorBody.Retryable,
+	}
+	if path := requestPath(c); path != "" {
+		problem["instance"] = path
+	}
+	if errorBody.Type != "" {
+		problem["type"] = errorBody.Type
+	}
+	if detail, ok := errorBody.Details.(string); ok {
+		if detail != "" {
+			problem["detail"] = detail
+		}
+	} else if errorBody.Details != nil {
+		problem["details"] = errorBody.Details
+	}
+	if len(errorBody.Causes) > 0 {
+		problem["causes"] = errorBody.Causes
+	}
+	if len(errorBody.Context) > 0 {
+		problem["context"] = errorBody.Context
+	}
+	if errorBody.ErrorCode != "" {
+		problem["errorCode"] = errorBody.ErrorCode
+	}
+	if errorBody.ErrorID != "" {
+		problem["errorId"] = errorBody.ErrorID
+	}
+	if errorBody.Errors != nil {
+		problem["errors"] = errorBody.Errors
+	}
+	if len(errorBody.GrantedPermissions) > 0 {
+		problem["grantedPermissions"] = errorBody.GrantedPermissions
+	}
+	if errorBody.HelpURL != "" {
+		problem["helpUrl"] = errorBody.HelpURL
+	}
+	if errorBody.Kind != "" {
+		problem["kind"] = errorBody.Kind
+	}
+	if errorBody.Reason != "" {
+		problem["reason"] = errorBody.Reason
+	}
+	if len(errorBody.RequiredPermissions) > 0 {
+		problem["requiredPermissions"] = errorBody.RequiredPermissions
+	}
+	if errorBody.Resource != "" {
+		problem["resource"] = errorBody.Resource
+	}
+	if errorBody.ResourceID != "" {
+		problem["resourceId"] = errorBody.ResourceID
+	}
+	if meta != nil {
+		problem["meta"] = meta
+	}
+	return problem
+}
+
+// requestPath returns the path of the request, or "" when there is none.
+func requestPath(c Exchange) string {
+	if c.Request() == nil || c.Request().URL == nil {
+		return ""
+	}
+	return c.Request().URL.Path
+}
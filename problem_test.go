@@ -0,0 +1,126 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestProblemDetailsForEveryErrorHelper(t *testing.T) {
+	boom := errors.New("boom")
+	for _, tc := range []struct {
+		name   string
+		status int
+		call   func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		{"BadRequest", http.StatusBadRequest, func(h responsehelper.ResponseHelper, c *gin.Context) { h.BadRequest(c, "bad", "name is missing") }},
+		{"BadRequestDetails", http.StatusBadRequest, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.BadRequestDetails(c, "bad", map[string]string{"name": "missing"})
+		}},
+		{"AlreadyExists", http.StatusConflict, func(h responsehelper.ResponseHelper, c *gin.Context) { h.AlreadyExists(c, "user", boom) }},
+		{"Conflict", http.StatusConflict, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Conflict(c, "conflict", boom) }},
+		{"NotFound", http.StatusNotFound, func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "missing") }},
+		{"ResourceNotFound", http.StatusNotFound, func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "user", 42) }},
+		{"Unauthorized", http.StatusUnauthorized, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Unauthorized(c, "who are you") }},
+		{"Forbidden", http.StatusForbidden, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Forbidden(c, "no") }},
+		{"TooManyRequests", http.StatusTooManyRequests, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.TooManyRequests(c, "slow down", time.Minute)
+		}},
+		{"ServiceUnavailable", http.StatusServiceUnavailable, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.ServiceUnavailable(c, "down", time.Minute)
+		}},
+		{"InternalError", http.StatusInternalServerError, func(h responsehelper.ResponseHelper, c *gin.Context) { h.InternalError(c, "oops", boom) }},
+		{"Errors", http.StatusUnprocessableEntity, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{{Field: "name", Message: "required"}})
+		}},
+		{"WriteError", http.StatusTeapot, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.WriteError(c, http.StatusTeapot, responsehelper.ErrorBody{Message: "teapot", ErrorCode: "TEAPOT"})
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/42?verbose=1")
+			tc.call(responsehelper.NewResponseHelper(responsehelper.WithProblemDetails(true)), c)
+
+			if w.Code != tc.status {
+				t.Fatalf("status = %d, want %d\nbody: %s", w.Code, tc.status, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); got != responsehelper.ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, responsehelper.ProblemContentType)
+			}
+			problem := decodeBody(t, w)
+			for _, member := range []string{"type", "title", "status", "instance"} {
+				if _, ok := problem[member]; !ok {
+					t.Errorf("the problem has no %q member\nbody: %s", member, w.Body)
+				}
+			}
+			assertField(t, w, "status", tc.status)
+			assertField(t, w, "instance", "/users/42")
+			if _, ok := problem["success"]; ok {
+				t.Errorf("the problem has the envelope's success member\nbody: %s", w.Body)
+			}
+			if _, ok := problem["error"]; ok {
+				t.Errorf("the problem has the envelope's error member\nbody: %s", w.Body)
+			}
+		})
+	}
+}
+
+func TestProblemDetailsMapsTheEnvelope(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	c.Set("meta", gin.H{"requestId": "req-1"})
+	err := responsehelper.NewBadRequestError("Invalid user", "name is missing")
+	err.Code = "INVALID_USER"
+	responsehelper.NewResponseHelper(responsehelper.WithProblemDetails(true)).RespondAPIError(c, err)
+
+	assertField(t, w, "type", "about:blank")
+	assertField(t, w, "title", "Invalid user")
+	assertField(t, w, "detail", "name is missing")
+	assertField(t, w, "errorCode", "INVALID_USER")
+	assertField(t, w, "meta.requestId", "req-1")
+}
+
+func TestProblem(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/orders/7")
+	responsehelper.NewResponseHelper().Problem(c, http.StatusConflict, "https://example.com/probs/out-of-stock", "Out of stock", "Item 7 is out of stock", map[string]interface{}{
+		"sku":    "SKU-7",
+		"status": 200,
+		"title":  "overridden",
+	})
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if got := w.Header().Get("Content-Type"); got != responsehelper.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, responsehelper.ProblemContentType)
+	}
+	assertField(t, w, "type", "https://example.com/probs/out-of-stock")
+	assertField(t, w, "title", "Out of stock")
+	assertField(t, w, "status", http.StatusConflict)
+	assertField(t, w, "detail", "Item 7 is out of stock")
+	assertField(t, w, "instance", "/orders/7")
+	assertField(t, w, "sku", "SKU-7")
+}
+
+func TestProblemDefaultsTheType(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/orders/7")
+	responsehelper.NewResponseHelper().Problem(c, http.StatusNotFound, "", "Not found", "", nil)
+
+	assertField(t, w, "type", "about:blank")
+	if _, ok := decodeBody(t, w)["detail"]; ok {
+		t.Errorf("the problem has an empty detail\nbody: %s", w.Body)
+	}
+}
+
+func TestProblemDetailsLeaveSuccessAlone(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithProblemDetails(true)).Success(c, gin.H{"id": 1})
+
+	assertSuccess(t, w)
+	if got := w.Header().Get("Content-Type"); got == responsehelper.ProblemContentType {
+		t.Errorf("Content-Type = %q for a success", got)
+	}
+}
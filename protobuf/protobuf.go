@@ -0,0 +1,173 @@
+// Package protobuf registers a Protocol Buffers encoder for the
+// responsehelper envelopes.
+//
+// Importing the package registers the encoder for application/x-protobuf
+// and application/protobuf:
+//
+//	import _ "github.com/aruncs31s/responsehelper/protobuf"
+//
+// 2xx responses are a SuccessEnvelope, 4xx and 5xx responses an
+// ErrorEnvelope, see envelope.proto. The data, meta, pagination and details
+// are google.protobuf.Value with the structure of the JSON envelope.
+// Integers beyond 2^53 cannot be represented by a google.protobuf.Value, so
+// such responses are sent as JSON with a Warning header instead.
+package protobuf
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative envelope.proto
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aruncs31s/responsehelper"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// ContentType is the content type of the protobuf envelope.
+	ContentType = string(responsehelper.FormatProtobuf)
+	// AliasContentType is accepted as an alias of ContentType.
+	AliasContentType = "application/protobuf"
+)
+
+// maxExactInteger is the largest integer a google.protobuf.Value number holds exactly.
+const maxExactInteger = 1 << 53
+
+func init() {
+	responsehelper.RegisterEncoder(ContentType, Encode)
+	responsehelper.RegisterEncoder(AliasContentType, Encode)
+}
+
+// Encode writes v, a responsehelper.SuccessEnvelope or
+// responsehelper.ErrorEnvelope, to w as the matching message.
+func Encode(w io.Writer, v interface{}) error {
+	var message proto.Message
+	var err error
+	switch envelope := v.(type) {
+	case responsehelper.SuccessEnvelope:
+		message, err = successMessage(envelope)
+	case responsehelper.ErrorEnvelope:
+		message, err = errorMessage(envelope)
+	default:
+		err = fmt.Errorf("%w: %T", responsehelper.ErrUnsupportedValue, v)
+	}
+	if err != nil {
+		return err
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+func successMessage(envelope responsehelper.SuccessEnvelope) (*SuccessEnvelope, error) {
+	message := &SuccessEnvelope{Message: envelope.Message, Success: envelope.Success}
+	if envelope.Count != nil {
+		count := int64(*envelope.Count)
+		message.Count = &count
+	}
+	if envelope.Links != nil {
+		links, ok := envelope.Links.(responsehelper.Links)
+		if !ok {
+			return nil, fmt.Errorf("%w: links must be responsehelper.Links", responsehelper.ErrUnsupportedValue)
+		}
+		message.Links = links
+	}
+	var err error
+	if message.Data, err = value(envelope.Data); err != nil {
+		return nil, err
+	}
+	if message.Meta, err = value(envelope.Meta); err != nil {
+		return nil, err
+	}
+	if message.Pagination, err = value(envelope.Pagination); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func errorMessage(envelope responsehelper.ErrorEnvelope) (*ErrorEnvelope, error) {
+	body := envelope.Error
+	message := &ErrorEnvelope{
+		Error: &ErrorBody{
+			Causes:              body.Causes,
+			Code:                int32(body.Code),
+			ErrorCode:           body.ErrorCode,
+			ErrorId:             body.ErrorID,
+			GrantedPermissions:  body.GrantedPermissions,
+			HelpUrl:             body.HelpURL,
+			Message:             body.Message,
+			RequiredPermissions: body.RequiredPermissions,
+			Retryable:           body.Retryable,
+			Status:              body.Status,
+			Type:                body.Type,
+		},
+		Success: envelope.Success,
+	}
+	var err error
+	if message.Meta, err = value(envelope.Meta); err != nil {
+		return nil, err
+	}
+	if message.Error.Details, err = value(body.Details); err != nil {
+		return nil, err
+	}
+	if body.Errors != nil {
+		errors, err := value(body.Errors)
+		if err != nil {
+			return nil, err
+		}
+		if message.Error.Errors = errors.GetListValue(); message.Error.Errors == nil {
+			return nil, fmt.Errorf("%w: errors must be a list", responsehelper.ErrUnsupportedValue)
+		}
+	}
+	return message, nil
+}
+
+// value returns v as a google.protobuf.Value, nil for nil.
+func value(v interface{}) (*structpb.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	plain, err := responsehelper.JSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if plain == nil {
+		// a null data is left out like a missing one
+		return nil, nil
+	}
+	if err := checkIntegers(plain); err != nil {
+		return nil, err
+	}
+	converted, err := structpb.NewValue(plain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", responsehelper.ErrUnsupportedValue, err)
+	}
+	return converted, nil
+}
+
+// checkIntegers fails for integers a google.protobuf.Value number cannot hold exactly.
+func checkIntegers(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := checkIntegers(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := checkIntegers(item); err != nil {
+				return err
+			}
+		}
+	case int64:
+		if v > maxExactInteger || v < -maxExactInteger {
+			return fmt.Errorf("%w: integer %d exceeds 2^53", responsehelper.ErrUnsupportedValue, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package protobuf_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/protobuf"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// engine returns an engine answering /users with a page of users, /users/0
+// with validation errors and /big with an integer beyond 2^53.
+func engine() *gin.Engine {
+	h := responsehelper.NewResponseHelper(responsehelper.WithContentNegotiation(true))
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: c.Request.URL.Path})
+	})
+	e.GET("/users", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []gin.H{{"id": 1, "name": "arun", "tags": []string{"a"}}}, responsehelper.NewPagination(1, 1, 3))
+	})
+	e.GET("/users/0", func(c *gin.Context) {
+		h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{
+			{Code: "REQUIRED", Field: "name", Message: "name is required"},
+		}, responsehelper.WithHelpURL("https://docs.example.com/errors/invalid-user"))
+	})
+	e.GET("/big", func(c *gin.Context) {
+		h.Success(c, gin.H{"id": int64(math.MaxInt64)})
+	})
+	return e
+}
+
+func get(path, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+	engine().ServeHTTP(w, r)
+	return w
+}
+
+func decodeJSON(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decoding the JSON body: %v\nbody: %s", err, w.Body)
+	}
+	return envelope
+}
+
+func TestSuccessEnvelope(t *testing.T) {
+	w := get("/users", protobuf.ContentType)
+	want := decodeJSON(t, get("/users", "application/json"))
+
+	if got := w.Header().Get("Content-Type"); got != protobuf.ContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, protobuf.ContentType)
+	}
+	var envelope protobuf.SuccessEnvelope
+	if err := proto.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshalling the body: %v", err)
+	}
+	if !envelope.GetSuccess() {
+		t.Error("success = false")
+	}
+	for name, got := range map[string]interface{}{
+		"data":       envelope.GetData().AsInterface(),
+		"meta":       envelope.GetMeta().AsInterface(),
+		"pagination": envelope.GetPagination().AsInterface(),
+	} {
+		if !reflect.DeepEqual(got, want[name]) {
+			t.Errorf("%s = %v, JSON has %v", name, got, want[name])
+		}
+	}
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	w := get("/users/0", protobuf.AliasContentType)
+	want := decodeJSON(t, get("/users/0", "application/json"))
+	wantError := want["error"].(map[string]interface{})
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422", w.Code)
+	}
+	var envelope protobuf.ErrorEnvelope
+	if err := proto.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshalling the body: %v", err)
+	}
+	body := envelope.GetError()
+	for name, got := range map[string]interface{}{
+		"code":    float64(body.GetCode()),
+		"status":  body.GetStatus(),
+		"message": body.GetMessage(),
+		"helpUrl": body.GetHelpUrl(),
+		"errors":  body.GetErrors().AsSlice(),
+	} {
+		if !reflect.DeepEqual(got, wantError[name]) {
+			t.Errorf("error.%s = %v, JSON has %v", name, got, wantError[name])
+		}
+	}
+	if envelope.GetSuccess() {
+		t.Error("success = true")
+	}
+	if got := envelope.GetMeta().AsInterface(); !reflect.DeepEqual(got, want["meta"]) {
+		t.Errorf("meta = %v, JSON has %v", got, want["meta"])
+	}
+}
+
+func TestLargeIntegersFallBackToJSON(t *testing.T) {
+	w := get("/big", protobuf.ContentType)
+
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want JSON", got)
+	}
+	if w.Header().Get("Warning") == "" {
+		t.Error("no Warning header on the JSON fallback")
+	}
+	var envelope struct {
+		Data struct {
+			ID json.Number `json:"id"`
+		} `json:"data"`
+	}
+	decoder := json.NewDecoder(w.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Data.ID != "9223372036854775807" {
+		t.Errorf("data.id = %s, want it exact", envelope.Data.ID)
+	}
+}
@@ -0,0 +1,138 @@
+package responsehelper
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppliedQueryKey is the gin context key holding the AppliedQuery stored by ParsePagination.
+const AppliedQueryKey = "appliedQuery"
+
+// defaultQueryMetaDenylist are the filters never sent in "meta.query" when
+// WithQueryMetaDenylist is not used.
+var defaultQueryMetaDenylist = []string{"token", "apiKey"}
+
+// AppliedQuery is the "meta.query" object sent with WithQueryMeta, the
+// listing parameters as the handler applied them, defaults included.
+type AppliedQuery struct {
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Sort     []SortField       `json:"sort,omitempty"`
+	Order    SortOrder         `json:"order"`
+	Filters  map[string]string `json:"filters,omitempty"`
+}
+
+// WithQueryMeta adds "meta.query" to the responses of SuccessWithPagination
+// and SuccessWithCursor when the request was parsed with ParsePagination, so
+// clients can see the page, sort and filters the server applied:
+//
+//	"meta": {
+//		"query": {"page": 1, "pageSize": 20, "sort": [{"field": "createdAt", "order": "desc"}], "order": "asc", "filters": {"status": "active"}}
+//	}
+//
+// Filters are set with WithAppliedFilters, the ones named in
+// WithQueryMetaDenylist are left out.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithQueryMeta(true))
+func WithQueryMeta(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.queryMeta = enabled
+	}
+}
+
+// WithQueryMetaOnSuccess adds "meta.query" to the responses of Success as
+// well, see WithQueryMeta.
+func WithQueryMetaOnSuccess(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.queryMetaOnSuccess = enabled
+	}
+}
+
+// WithQueryMetaDenylist replaces the filters left out of "meta.query",
+// "token" and "apiKey" by default. Names are matched case-insensitively.
+func WithQueryMetaDenylist(keys ...string) Option {
+	return func(cfg *config) {
+		cfg.queryMetaDenylist = denylistSet(keys)
+	}
+}
+
+// denylistSet returns the lowercased set of keys.
+func denylistSet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = true
+	}
+	return set
+}
+
+// successMeta returns the meta of a success envelope sent by method.
+func (cfg *config) successMeta(c Exchange, method string) interface{} {
+	meta := cfg.responseMeta(c)
+	switch method {
+	case "SuccessWithPagination", "SuccessWithCursor":
+		if !cfg.queryMeta {
+			return meta
+		}
+	case "Success":
+		if !cfg.queryMeta || !cfg.queryMetaOnSuccess {
+			return meta
+		}
+	default:
+		return meta
+	}
+	stored, ok := c.Get(AppliedQueryKey)
+	if !ok {
+		return meta
+	}
+	query, ok := stored.(AppliedQuery)
+	if !ok {
+		return meta
+	}
+	query.Filters = cfg.allowedFilters(query.Filters)
+	return metaWithField(meta, "query", query)
+}
+
+// allowedFilters returns the filters not in the denylist.
+func (cfg *config) allowedFilters(filters map[string]string) map[string]string {
+	denylist := cfg.queryMetaDenylist
+	if denylist == nil {
+		denylist = denylistSet(defaultQueryMetaDenylist)
+	}
+	allowed := make(map[string]string, len(filters))
+	for key, value := range filters {
+		if !denylist[strings.ToLower(key)] {
+			allowed[key] = value
+		}
+	}
+	return allowed
+}
+
+// metaWithField returns a copy of meta with key added, like SetMetaField
+// without changing the meta of the request.
+func metaWithField(meta interface{}, key string, value interface{}) interface{} {
+	var fields map[string]interface{}
+	switch meta := meta.(type) {
+	case Meta:
+		fields = meta.Extra
+	case gin.H:
+		fields = meta
+	case map[string]interface{}:
+		fields = meta
+	case nil:
+	default:
+		return meta
+	}
+	copied := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		copied[k] = v
+	}
+	copied[key] = value
+	if typed, ok := meta.(Meta); ok {
+		typed.Extra = copied
+		return typed
+	}
+	return gin.H(copied)
+}
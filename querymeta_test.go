@@ -0,0 +1,138 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// queryMetaEngine returns an engine parsing the listing of /users with the
+// given filters, answering with a page and /user with a success.
+func queryMetaEngine(filters map[string]string, opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(opts...)
+	engine := gin.New()
+	engine.GET("/users", func(c *gin.Context) {
+		page, ok := responsehelper.ParsePagination(c, h, responsehelper.WithAppliedFilters(filters))
+		if !ok {
+			return
+		}
+		h.SuccessWithPagination(c, []string{"arun"}, responsehelper.NewPagination(page.Page, page.PageSize, 1))
+	})
+	engine.GET("/user", func(c *gin.Context) {
+		if _, ok := responsehelper.ParsePagination(c, h, responsehelper.WithAppliedFilters(filters)); ok {
+			h.Success(c, gin.H{"name": "arun"})
+		}
+	})
+	return engine
+}
+
+// metaQuery returns "meta.query" of the response, nil when there is none.
+func metaQuery(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	meta, _ := decodeBody(t, w)["meta"].(map[string]interface{})
+	query, _ := meta["query"].(map[string]interface{})
+	return query
+}
+
+func TestQueryMetaFillsInTheDefaults(t *testing.T) {
+	engine := queryMetaEngine(nil, responsehelper.WithQueryMeta(true))
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	want := map[string]interface{}{"page": float64(1), "pageSize": float64(20), "order": "asc"}
+	if got := metaQuery(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("meta.query = %v, want %v", got, want)
+	}
+}
+
+func TestQueryMetaNormalizesTheQuery(t *testing.T) {
+	engine := queryMetaEngine(map[string]string{"status": "active"}, responsehelper.WithQueryMeta(true))
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/users?page=2&pageSize=5&order=DESC&sort=name,%2Bage", nil))
+
+	want := map[string]interface{}{
+		"page":     float64(2),
+		"pageSize": float64(5),
+		"order":    "desc",
+		"sort": []interface{}{
+			map[string]interface{}{"field": "name", "order": "desc"},
+			map[string]interface{}{"field": "age", "order": "asc"},
+		},
+		"filters": map[string]interface{}{"status": "active"},
+	}
+	if got := metaQuery(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("meta.query = %v, want %v", got, want)
+	}
+}
+
+func TestQueryMetaDenylist(t *testing.T) {
+	filters := map[string]string{"status": "active", "TOKEN": "secret", "apikey": "key", "owner": "arun"}
+	for _, tc := range []struct {
+		name string
+		opts []responsehelper.Option
+		want map[string]interface{}
+	}{
+		{"default", nil, map[string]interface{}{"status": "active", "owner": "arun"}},
+		{"replaced", []responsehelper.Option{responsehelper.WithQueryMetaDenylist("Owner")},
+			map[string]interface{}{"status": "active", "TOKEN": "secret", "apikey": "key"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := queryMetaEngine(filters, append(tc.opts, responsehelper.WithQueryMeta(true))...)
+			w := serve(engine, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+			if got := metaQuery(t, w)["filters"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("meta.query.filters = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryMetaOnSuccess(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []responsehelper.Option
+		want bool
+	}{
+		{"disabled", []responsehelper.Option{responsehelper.WithQueryMetaOnSuccess(true)}, false},
+		{"paginated only", []responsehelper.Option{responsehelper.WithQueryMeta(true)}, false},
+		{"enabled", []responsehelper.Option{responsehelper.WithQueryMeta(true), responsehelper.WithQueryMetaOnSuccess(true)}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(queryMetaEngine(nil, tc.opts...), httptest.NewRequest(http.MethodGet, "/user", nil))
+
+			assertSuccess(t, w)
+			if got := metaQuery(t, w) != nil; got != tc.want {
+				t.Errorf("meta.query sent = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryMetaWithoutParsePagination(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithQueryMeta(true))
+	c, w := newContext(http.MethodGet, "/users")
+	h.SuccessWithPagination(c, []string{}, responsehelper.NewPagination(1, 20, 0))
+
+	if query := metaQuery(t, w); query != nil {
+		t.Errorf("meta.query = %v without ParsePagination", query)
+	}
+}
+
+func TestQueryMetaKeepsTheRequestMeta(t *testing.T) {
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware())
+	h := responsehelper.NewResponseHelper(responsehelper.WithQueryMeta(true))
+	engine.GET("/users", func(c *gin.Context) {
+		if page, ok := responsehelper.ParsePagination(c, h); ok {
+			h.SuccessWithPagination(c, []string{}, responsehelper.NewPagination(page.Page, page.PageSize, 0))
+		}
+	})
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set(responsehelper.RequestIDHeader, "req-1")
+	w := serve(engine, r)
+
+	assertField(t, w, "meta.requestId", "req-1")
+	assertField(t, w, "meta.query.page", float64(1))
+}
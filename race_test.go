@@ -0,0 +1,7 @@
+//go:build race
+
+package responsehelper_test
+
+func init() {
+	raceEnabled = true
+}
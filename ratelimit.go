@@ -0,0 +1,102 @@
+package responsehelper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RateLimitKey is the gin context key holding the RateLimitInfo of the request.
+	RateLimitKey = "responsehelper.rateLimit"
+
+	// RateLimitLimitHeader carries the number of requests allowed in the window.
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+	// RateLimitRemainingHeader carries the number of requests left in the window.
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader carries when the window resets.
+	RateLimitResetHeader = "X-RateLimit-Reset"
+	// RetryAfterHeader carries the seconds a client should wait before retrying.
+	RetryAfterHeader = "Retry-After"
+)
+
+// RateLimitInfo is the state of the rate limit of a client.
+type RateLimitInfo struct {
+	// Limit is the number of requests allowed in the window.
+	Limit int
+	// Remaining is the number of requests left in the window.
+	Remaining int
+	// Reset is when the window resets. The zero time leaves out X-RateLimit-Reset.
+	Reset time.Time
+}
+
+// RateLimitResetFormat is the format of the X-RateLimit-Reset header.
+type RateLimitResetFormat int
+
+const (
+	// RateLimitResetEpoch sends the unix time in seconds of the reset.
+	RateLimitResetEpoch RateLimitResetFormat = iota
+	// RateLimitResetDelta sends the seconds left until the reset.
+	RateLimitResetDelta
+)
+
+// SetRateLimit stores info for the request, so every response sent by the
+// helper carries the X-RateLimit-* headers. Call it from the rate limiter
+// middleware.
+//
+// Example:
+//
+//	responsehelper.SetRateLimit(c, responsehelper.RateLimitInfo{Limit: 100, Remaining: 42, Reset: window.End})
+func SetRateLimit(c *gin.Context, info RateLimitInfo) {
+	c.Set(RateLimitKey, info)
+}
+
+// SetRateLimitHeaders sets the X-RateLimit-* headers from info right away,
+// with the reset as unix time. Use it for responses not sent by the helper.
+func SetRateLimitHeaders(c *gin.Context, info RateLimitInfo) {
+	setRateLimitHeaderValues(exchangeOf(c), info)
+}
+
+// setRateLimitHeaderValues is SetRateLimitHeaders for an Exchange.
+func setRateLimitHeaderValues(c Exchange, info RateLimitInfo) {
+	writeRateLimitHeaders(c, info, RateLimitResetEpoch)
+}
+
+// setRateLimitHeaders sets the X-RateLimit-* headers from info, or from the
+// info set with SetRateLimit when info is nil.
+func (cfg *config) setRateLimitHeaders(c Exchange, info *RateLimitInfo) {
+	if info == nil {
+		stored, ok := c.Get(RateLimitKey)
+		if !ok {
+			return
+		}
+		typed, ok := stored.(RateLimitInfo)
+		if !ok {
+			return
+		}
+		info = &typed
+	}
+	writeRateLimitHeaders(c, *info, cfg.rateLimitReset)
+}
+
+func writeRateLimitHeaders(c Exchange, info RateLimitInfo, format RateLimitResetFormat) {
+	setHeader(c, RateLimitLimitHeader, strconv.Itoa(info.Limit))
+	setHeader(c, RateLimitRemainingHeader, strconv.Itoa(info.Remaining))
+	if info.Reset.IsZero() {
+		return
+	}
+	reset := info.Reset.Unix()
+	if format == RateLimitResetDelta {
+		reset = ceilSeconds(time.Until(info.Reset))
+	}
+	setHeader(c, RateLimitResetHeader, strconv.FormatInt(reset, 10))
+}
+
+// ceilSeconds rounds d up to whole seconds, negative durations become 0.
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,110 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func assertRateLimitHeaders(t *testing.T, w *httptest.ResponseRecorder, limit, remaining, reset string) {
+	t.Helper()
+	for header, want := range map[string]string{
+		responsehelper.RateLimitLimitHeader:     limit,
+		responsehelper.RateLimitRemainingHeader: remaining,
+		responsehelper.RateLimitResetHeader:     reset,
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestRateLimitHeadersOnEveryResponse(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		responsehelper.SetRateLimit(c, responsehelper.RateLimitInfo{Limit: 100, Remaining: 0, Reset: reset})
+	})
+	engine.GET("/ok", func(c *gin.Context) { h.Success(c, nil) })
+	engine.GET("/limited", func(c *gin.Context) { h.TooManyRequests(c, "slow down", 30*time.Second) })
+
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assertRateLimitHeaders(t, w, "100", "0", strconv.FormatInt(reset.Unix(), 10))
+
+	w = serve(engine, httptest.NewRequest(http.MethodGet, "/limited", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	assertRateLimitHeaders(t, w, "100", "0", strconv.FormatInt(reset.Unix(), 10))
+	if got := w.Header().Get(responsehelper.RetryAfterHeader); got != "30" {
+		t.Errorf("%s = %q, want %q", responsehelper.RetryAfterHeader, got, "30")
+	}
+}
+
+func TestRateLimitResetDelta(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/")
+	responsehelper.SetRateLimit(c, responsehelper.RateLimitInfo{Limit: 10, Remaining: 9, Reset: time.Now().Add(90*time.Second - time.Millisecond)})
+	responsehelper.NewResponseHelper(responsehelper.WithRateLimitResetFormat(responsehelper.RateLimitResetDelta)).Success(c, nil)
+
+	assertRateLimitHeaders(t, w, "10", "9", "90")
+}
+
+func TestRateLimitPerResponse(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/")
+	responsehelper.SetRateLimit(c, responsehelper.RateLimitInfo{Limit: 10, Remaining: 9})
+	responsehelper.NewResponseHelper().TooManyRequests(c, "slow down", 0, responsehelper.WithRateLimit(responsehelper.RateLimitInfo{Limit: 10, Remaining: 0}))
+
+	assertRateLimitHeaders(t, w, "10", "0", "")
+	if got := w.Header().Get(responsehelper.RetryAfterHeader); got != "" {
+		t.Errorf("%s = %q without a retry delay", responsehelper.RetryAfterHeader, got)
+	}
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	reset := time.Unix(1714564800, 0)
+	c, w := newContext(http.MethodGet, "/")
+	responsehelper.SetRateLimitHeaders(c, responsehelper.RateLimitInfo{Limit: 5, Remaining: 4, Reset: reset})
+
+	assertRateLimitHeaders(t, w, "5", "4", "1714564800")
+}
+
+func TestNoRateLimitHeaders(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/")
+	responsehelper.NewResponseHelper().Success(c, nil)
+
+	assertRateLimitHeaders(t, w, "", "", "")
+}
+
+func TestRateLimitHeadersOnEveryHelper(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	for name, respond := range map[string]func(c *gin.Context){
+		"Success":   func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) },
+		"Created":   func(c *gin.Context) { h.Created(c, gin.H{"id": 1}) },
+		"Paginated": func(c *gin.Context) { h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 10, 1)) },
+		"NotFound":  func(c *gin.Context) { h.NotFound(c, "missing") },
+		"Internal":  func(c *gin.Context) { h.InternalError(c, "Oops", nil) },
+		"NoContent": func(c *gin.Context) { h.NoContent(c) },
+		"Problem":   func(c *gin.Context) { h.Problem(c, http.StatusConflict, "", "Conflict", "", nil) },
+		"CSV":       func(c *gin.Context) { h.SuccessCSV(c, "users.csv", []struct{ ID int }{{1}}) },
+		"Large":     func(c *gin.Context) { h.SuccessLarge(c, []int{1, 2}) },
+		"OAuthError": func(c *gin.Context) {
+			h.OAuthError(c, responsehelper.OAuthErrorInvalidGrant, "expired", http.StatusBadRequest)
+		},
+		"WriteJSON":  func(c *gin.Context) { h.WriteJSON(c, http.StatusAccepted, gin.H{"queued": true}) },
+		"WriteError": func(c *gin.Context) { h.WriteError(c, http.StatusLocked, responsehelper.ErrorBody{Message: "Locked"}) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/")
+			responsehelper.SetRateLimit(c, responsehelper.RateLimitInfo{Limit: 100, Remaining: 7, Reset: time.Unix(1714564800, 0)})
+			respond(c)
+
+			assertRateLimitHeaders(t, w, "100", "7", "1714564800")
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package responsehelper
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// errInvalidRawJSON is reported when raw JSON data fails WithRawJSONValidation.
+var errInvalidRawJSON = errors.New("responsehelper: the raw JSON data is not valid JSON")
+
+// RawJSON marks b as JSON to send as the data as it is, eg: the body of a
+// downstream call. A plain []byte is sent as a base64 string instead.
+//
+// Example:
+//
+//	h.responseHelper.Success(c, responsehelper.RawJSON(body))
+func RawJSON(b []byte) json.RawMessage {
+	return json.RawMessage(b)
+}
+
+// WithRawJSONValidation checks that json.RawMessage data, eg: from RawJSON,
+// is valid JSON before it is sent, and sends a 500 Internal Server Error
+// when it is not. Without it the bytes are trusted, as the check scans them
+// once more.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithRawJSONValidation(true))
+func WithRawJSONValidation(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.validateRawJSON = enabled
+	}
+}
+
+// invalidRawJSON reports whether data is raw JSON failing WithRawJSONValidation.
+func (cfg *config) invalidRawJSON(data interface{}) bool {
+	raw, ok := data.(json.RawMessage)
+	return ok && cfg.validateRawJSON && len(raw) > 0 && !json.Valid(raw)
+}
+
+// rawEnvelope reports whether envelope has json.RawMessage data, which
+// marshalEnvelope splices in.
+func rawEnvelope(envelope interface{}) bool {
+	success, ok := envelope.(*SuccessEnvelope)
+	if !ok {
+		return false
+	}
+	_, ok = success.Data.(json.RawMessage)
+	return ok
+}
+
+// marshalEnvelope returns the JSON of envelope. json.RawMessage data is
+// spliced in as it is instead of being compacted and escaped by the encoder,
+// empty data is sent as null.
+func (cfg *config) marshalEnvelope(envelope interface{}) ([]byte, error) {
+	envelope = cfg.withNullMeta(envelope)
+	success, ok := envelope.(*SuccessEnvelope)
+	if !ok {
+		return cfg.marshalJSON(envelope)
+	}
+	raw, ok := success.Data.(json.RawMessage)
+	if !ok {
+		return cfg.marshalJSON(envelope)
+	}
+	if len(raw) == 0 {
+		raw = jsonNull
+	}
+	rest := *success
+	rest.Count, rest.Data = nil, nil
+	body, err := cfg.marshalJSON(&rest)
+	if err != nil {
+		return nil, err
+	}
+	// count and data are the first members, the rest always has success
+	out := make([]byte, 0, len(body)+len(raw)+32)
+	out = append(out, '{')
+	if success.Count != nil {
+		out = append(out, `"count":`...)
+		out = strconv.AppendInt(out, int64(*success.Count), 10)
+		out = append(out, ',')
+	}
+	out = append(out, `"data":`...)
+	out = append(out, raw...)
+	out = append(out, ',')
+	return append(out, body[1:]...), nil
+}
@@ -0,0 +1,126 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRawJSONIsSplicedAsItIs(t *testing.T) {
+	raw := responsehelper.RawJSON([]byte(`{"b": 1, "a": "<x>"}`))
+	for _, tc := range []struct {
+		name    string
+		opts    []responsehelper.Option
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		want    string
+	}{
+		{"Success", nil, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, raw) },
+			`{"data":{"b": 1, "a": "<x>"},"success":true}`},
+		{"meta", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			c.Set(responsehelper.MetaKey, gin.H{"requestId": "req-1"})
+			h.Success(c, raw)
+		}, `{"data":{"b": 1, "a": "<x>"},"meta":{"requestId":"req-1"},"success":true}`},
+		{"empty", nil, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, responsehelper.RawJSON(nil)) },
+			`{"data":null,"success":true}`},
+		{"null fields", []responsehelper.Option{responsehelper.WithNullFields(true)},
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, raw) },
+			`{"data":{"b": 1, "a": "<x>"},"meta":null,"success":true}`},
+		{"top-level count", []responsehelper.Option{responsehelper.WithCountPlacement(responsehelper.CountTopLevel)},
+			func(h responsehelper.ResponseHelper, c *gin.Context) {
+				h.SuccessWithCount(c, responsehelper.RawJSON([]byte(`[1,2]`)))
+			},
+			`{"data":[1,2],"success":true}`},
+		{"pagination", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, responsehelper.RawJSON([]byte(`[1, 2]`)), responsehelper.NewPagination(1, 2, 2))
+		}, `{"data":[1, 2],"pagination":{"currentPage":1,"pageSize":2,"totalPages":1,"totalRecords":2,"hasNext":false,"hasPrev":false},"success":true}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/report")
+			tc.respond(responsehelper.NewResponseHelper(tc.opts...), c)
+
+			if w.Body.String() != tc.want {
+				t.Errorf("body =\n%s\nwant\n%s", w.Body, tc.want)
+			}
+		})
+	}
+}
+
+func TestRawJSONValidation(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithRawJSONValidation(true))
+	for _, raw := range []string{`{"a":`, `{"a":1}}`, `nul`, `'a'`, `{"a":1,}`, "\xff"} {
+		c, w := newContext(http.MethodGet, "/report")
+		h.Success(c, responsehelper.RawJSON([]byte(raw)))
+
+		assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+		assertField(t, w, "error.details", "responsehelper: the raw JSON data is not valid JSON")
+	}
+}
+
+func TestRawJSONWithoutValidationIsTrusted(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/report")
+	responsehelper.NewResponseHelper().Success(c, responsehelper.RawJSON([]byte(`{"a":`)))
+
+	if want := `{"data":{"a":,"success":true}`; w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("sent %d %s, want 200 %s", w.Code, w.Body, want)
+	}
+}
+
+func FuzzRawJSON(f *testing.F) {
+	for _, seed := range []string{`{"a":1}`, `[1,2,3]`, `"text"`, `null`, ` 1 `, `{"a":`, `}`, ``} {
+		f.Add([]byte(seed))
+	}
+	h := responsehelper.NewResponseHelper(responsehelper.WithRawJSONValidation(true))
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		c, w := newContext(http.MethodGet, "/report")
+		h.Success(c, responsehelper.RawJSON(raw))
+
+		if !json.Valid(w.Body.Bytes()) {
+			t.Fatalf("sent invalid JSON %q for %q", w.Body, raw)
+		}
+		valid := len(raw) == 0 || json.Valid(raw)
+		if valid != (w.Code == http.StatusOK) {
+			t.Fatalf("status = %d for %q", w.Code, raw)
+		}
+		if len(raw) > 0 && valid {
+			var body struct{ Data json.RawMessage }
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var got, want interface{}
+			_ = json.Unmarshal(body.Data, &got)
+			_ = json.Unmarshal(raw, &want)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Fatalf("data = %s, want %s", body.Data, raw)
+			}
+		}
+	})
+}
+
+func BenchmarkRawJSON(b *testing.B) {
+	raw := []byte(`{"users":[{"id":1,"name":"arun"},{"id":2,"name":"anu"}],"total":2,"region":"eu-west-1"}`)
+	h := responsehelper.NewResponseHelper()
+	for _, bench := range []struct {
+		name    string
+		respond gin.HandlerFunc
+	}{
+		{"spliced", func(c *gin.Context) { h.Success(c, responsehelper.RawJSON(raw)) }},
+		{"decoded", func(c *gin.Context) {
+			var data interface{}
+			_ = json.Unmarshal(raw, &data)
+			h.Success(c, data)
+		}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			serve := benchServe(bench.respond)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				serve()
+			}
+		})
+	}
+}
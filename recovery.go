@@ -0,0 +1,87 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PanicLoggerFunc logs a recovered panic together with its error ID and stack.
+type PanicLoggerFunc func(c *gin.Context, errorID string, recovered interface{}, stack []byte)
+
+// RecoveryOption configures Recovery.
+type RecoveryOption func(*recoveryConfig)
+
+type recoveryConfig struct {
+	// logger receives every recovered panic.
+	logger PanicLoggerFunc
+}
+
+// WithPanicLogger replaces the default log.Printf logging of recovered panics.
+func WithPanicLogger(logger PanicLoggerFunc) RecoveryOption {
+	return func(cfg *recoveryConfig) {
+		if logger != nil {
+			cfg.logger = logger
+		}
+	}
+}
+
+// Recovery recovers panics in later handlers and sends the standard 500
+// envelope instead of gin's empty body. The panic value is only sent in debug
+// mode, the logged error ID matches the errorId of the response.
+//
+// http.ErrAbortHandler is re-panicked, and when the response was already
+// partially written the connection is closed as nothing valid can be sent.
+//
+// Example:
+//
+//	router := gin.New()
+//	router.Use(responsehelper.Recovery(responseHelper))
+func Recovery(h ResponseHelper, opts ...RecoveryOption) gin.HandlerFunc {
+	cfg := recoveryConfig{logger: logPanic}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				respondPanic(c, h, cfg.logger, recovered)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// respondPanic logs a recovered panic and sends the 500 envelope for it. It
+// must be called from the deferred function that recovered the panic, so the
+// logged stack is the one of the panic.
+func respondPanic(c *gin.Context, h ResponseHelper, logger PanicLoggerFunc, recovered interface{}) {
+	if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+		panic(recovered)
+	}
+	errorID := ErrorID(c)
+	logger(c, errorID, recovered, debug.Stack())
+	if c.Writer.Written() {
+		panic(http.ErrAbortHandler)
+	}
+	c.Abort()
+	h.InternalError(c, "Internal server error", panicError(h, recovered))
+}
+
+// panicError returns the panic value as an error when h runs in debug mode.
+func panicError(h ResponseHelper, recovered interface{}) error {
+	helper, ok := h.(*responseHelper)
+	if !ok || !helper.debugEnabled() {
+		return nil
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
+
+// logPanic is the default PanicLoggerFunc.
+func logPanic(c *gin.Context, errorID string, recovered interface{}, stack []byte) {
+	log.Printf("[responsehelper] panic recovered: errorId=%s path=%s: %v\n%s", errorID, requestPath(exchangeOf(c)), recovered, stack)
+}
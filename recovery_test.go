@@ -0,0 +1,81 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type loggedPanic struct {
+	errorID   string
+	recovered interface{}
+	stack     string
+}
+
+// panicEngine returns an engine with Recovery logging into logged, and a
+// handler panicking with value at /panic.
+func panicEngine(h responsehelper.ResponseHelper, value interface{}, logged *loggedPanic) *gin.Engine {
+	engine := gin.New()
+	engine.Use(responsehelper.Recovery(h, responsehelper.WithPanicLogger(func(c *gin.Context, errorID string, recovered interface{}, stack []byte) {
+		*logged = loggedPanic{errorID, recovered, string(stack)}
+	})))
+	engine.GET("/panic", func(c *gin.Context) { panic(value) })
+	engine.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "half")
+		panic(value)
+	})
+	return engine
+}
+
+func TestRecovery(t *testing.T) {
+	var logged loggedPanic
+	engine := panicEngine(responsehelper.NewResponseHelper(), "secret state", &logged)
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assertError(t, w, http.StatusInternalServerError, "Internal server error")
+	assertField(t, w, "error.errorId", logged.errorID)
+	if logged.recovered != "secret state" || !strings.Contains(logged.stack, "recovery_test.go") {
+		t.Errorf("logged %+v, want the panic value and its stack", logged)
+	}
+	if strings.Contains(w.Body.String(), "secret state") {
+		t.Errorf("the panic value was sent without debug mode\nbody: %s", w.Body)
+	}
+}
+
+func TestRecoveryDebugMode(t *testing.T) {
+	var logged loggedPanic
+	engine := panicEngine(responsehelper.NewResponseHelper(responsehelper.WithDebug(true)), "secret state", &logged)
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assertField(t, w, "error.details", "panic: secret state")
+}
+
+func TestRecoveryRepanicsAbortHandler(t *testing.T) {
+	var logged loggedPanic
+	engine := panicEngine(responsehelper.NewResponseHelper(), http.ErrAbortHandler, &logged)
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", recovered)
+		}
+	}()
+	serve(engine, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	t.Error("http.ErrAbortHandler was swallowed")
+}
+
+func TestRecoveryPartiallyWritten(t *testing.T) {
+	var logged loggedPanic
+	engine := panicEngine(responsehelper.NewResponseHelper(), "late", &logged)
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler to close the connection", recovered)
+		}
+		if logged.recovered != "late" {
+			t.Errorf("the panic was not logged: %+v", logged)
+		}
+	}()
+	serve(engine, httptest.NewRequest(http.MethodGet, "/partial", nil))
+}
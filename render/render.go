@@ -0,0 +1,254 @@
+// Package render produces the bytes of the responsehelper envelopes outside
+// of an HTTP exchange, eg: for a queue consumer or a cron job writing the
+// outcome of the shared service layer to a log or a queue. The envelopes are
+// rendered by a responsehelper.Core, so they honour its configuration, eg:
+// sanitization or the default format, and match the bodies of the Gin
+// handlers byte for byte. Callers do not deal with Gin: the meta and the
+// other request values are passed in the context.
+//
+// Rendering has no side effects: the response hooks, and so the metrics, the
+// audit, the error reporter and the response logging of the helper do not
+// run. The before send hooks still do, with a nil *gin.Context, as they shape
+// the envelope.
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// ResponseKind selects the envelope Render produces.
+type ResponseKind string
+
+const (
+	// KindSuccess is the envelope of Success.
+	KindSuccess ResponseKind = "success"
+	// KindCreated is the envelope of Created.
+	KindCreated ResponseKind = "created"
+	// KindPaginated is the envelope of SuccessWithPagination.
+	KindPaginated ResponseKind = "paginated"
+	// KindError is the envelope of RespondAPIError.
+	KindError ResponseKind = "error"
+	// KindValidation is the envelope of ValidationFailed.
+	KindValidation ResponseKind = "validation"
+)
+
+var (
+	// ErrUnknownKind is returned by Render for a ResponseKind it does not know.
+	ErrUnknownKind = errors.New("render: unknown response kind")
+	// ErrNotRendered is returned by Render when the helper wrote nothing, eg:
+	// with responsehelper.WithSkipOnClientGone and a canceled context.
+	ErrNotRendered = errors.New("render: the response was not rendered")
+	// ErrMarshal is returned by Render when the envelope could not be
+	// marshalled, with what was rendered instead.
+	ErrMarshal = errors.New("render: cannot marshal the envelope")
+)
+
+// RenderInput holds what the envelope of a ResponseKind is made of.
+type RenderInput struct {
+	// Data is the payload of KindSuccess, KindCreated and KindPaginated.
+	Data interface{}
+	// Pagination is the pagination of KindPaginated.
+	Pagination interface{}
+	// Error is the error of KindError. When nil, Err is sent as an internal
+	// error.
+	Error *responsehelper.APIError
+	// Err is the error of KindValidation, or of KindError without Error.
+	Err error
+	// Accept is the Accept header the format is negotiated from when the
+	// helper was created with content negotiation, eg: "application/xml".
+	Accept string
+	// Options are the per response options, eg: responsehelper.WithWarnings(...).
+	Options []responsehelper.ResponseOption
+}
+
+// Renderer renders envelopes without an HTTP exchange.
+type Renderer struct {
+	core *responsehelper.Core
+}
+
+// New creates a Renderer configured with opts, the options of
+// responsehelper.NewResponseHelper.
+//
+// Example:
+//
+//	renderer := render.New(responsehelper.WithErrorSanitization(true))
+//	body, err := renderer.Render(ctx, render.KindSuccess, render.RenderInput{Data: report})
+func New(opts ...responsehelper.Option) *Renderer {
+	return &Renderer{core: responsehelper.NewCore(opts...)}
+}
+
+// Wrap creates a Renderer rendering with the Core of helper, so the HTTP
+// handlers and the other transports share one configuration and its
+// registered codes. It panics when helper was not created by
+// responsehelper.NewResponseHelper or responsehelper.New.
+func Wrap(helper responsehelper.ResponseHelper) *Renderer {
+	core := responsehelper.CoreOf(helper)
+	if core == nil {
+		panic("render: Wrap needs a helper created by responsehelper.NewResponseHelper")
+	}
+	return &Renderer{core: core}
+}
+
+type valuesKey struct{}
+
+// WithValue returns a copy of ctx carrying value under key, like a Gin
+// handler finds values set with c.Set, eg: the locale
+// (responsehelper.LocaleKey) or the request ID (responsehelper.RequestIDKey).
+func WithValue(ctx context.Context, key string, value interface{}) context.Context {
+	parent, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	values := make(map[string]interface{}, len(parent)+1)
+	for k, v := range parent {
+		values[k] = v
+	}
+	values[key] = value
+	return context.WithValue(ctx, valuesKey{}, values)
+}
+
+// Value returns the value stored in ctx under key with WithValue, and false
+// when there is none.
+func Value(ctx context.Context, key string) (interface{}, bool) {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	value, ok := values[key]
+	return value, ok
+}
+
+// WithMeta returns a copy of ctx carrying the meta of the envelopes Render
+// produces, eg: a responsehelper.Meta with the ID of the message consumed.
+func WithMeta(ctx context.Context, meta interface{}) context.Context {
+	return WithValue(ctx, responsehelper.MetaKey, meta)
+}
+
+// MetaFromContext returns the meta stored in ctx with WithMeta, and false
+// when there is none.
+func MetaFromContext(ctx context.Context) (interface{}, bool) {
+	return Value(ctx, responsehelper.MetaKey)
+}
+
+// Render returns the envelope of kind made of in, the body the matching
+// helper method writes to an HTTP response for the same input and context
+// values.
+//
+// The error wraps ErrUnknownKind for an unknown kind, ErrNotRendered when
+// nothing was rendered, and ErrMarshal and the error of the encoder when the
+// envelope could not be marshalled, in which case the body is what was
+// rendered instead, eg: the 500 Internal Server Error envelope replacing a
+// JSON envelope.
+//
+// Example:
+//
+//	ctx = render.WithMeta(ctx, responsehelper.Meta{RequestID: msg.ID, Timestamp: time.Now().UTC()})
+//	body, err := renderer.Render(ctx, render.KindError, render.RenderInput{Error: apiErr})
+func (r *Renderer) Render(ctx context.Context, kind ResponseKind, in RenderInput) ([]byte, error) {
+	x := newExchange(ctx, in.Accept)
+	switch kind {
+	case KindSuccess:
+		r.core.Success(x, in.Data, in.Options...)
+	case KindCreated:
+		r.core.Created(x, in.Data, in.Options...)
+	case KindPaginated:
+		r.core.SuccessWithPagination(x, in.Data, in.Pagination, in.Options...)
+	case KindError:
+		apiErr := in.Error
+		if apiErr == nil {
+			apiErr = responsehelper.NewInternalError("", in.Err)
+		}
+		r.core.RespondAPIError(x, apiErr, in.Options...)
+	case KindValidation:
+		r.core.ValidationFailed(x, in.Err, in.Options...)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if !x.written {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotRendered, err)
+		}
+		return nil, ErrNotRendered
+	}
+	if x.err != nil {
+		return x.body.Bytes(), fmt.Errorf("%w: %w", ErrMarshal, x.err)
+	}
+	return x.body.Bytes(), nil
+}
+
+// exchange is the responsehelper.DetachedExchange Render collects the
+// response with. Its request only carries ctx and the Accept header.
+type exchange struct {
+	request *http.Request
+	values  map[string]interface{}
+	header  http.Header
+	body    bytes.Buffer
+	status  int
+	written bool
+	err     error
+}
+
+func newExchange(ctx context.Context, accept string) *exchange {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	x := &exchange{
+		request: (&http.Request{Method: http.MethodGet, URL: &url.URL{}, Header: http.Header{}}).WithContext(ctx),
+		values:  make(map[string]interface{}, len(values)),
+		header:  http.Header{},
+		status:  http.StatusOK,
+	}
+	for key, value := range values {
+		x.values[key] = value
+	}
+	if accept != "" {
+		x.request.Header.Set("Accept", accept)
+	}
+	return x
+}
+
+func (x *exchange) Request() *http.Request {
+	return x.request
+}
+
+func (x *exchange) Get(key string) (interface{}, bool) {
+	value, ok := x.values[key]
+	return value, ok
+}
+
+func (x *exchange) Set(key string, value interface{}) {
+	x.values[key] = value
+}
+
+func (x *exchange) Header() http.Header {
+	return x.header
+}
+
+func (x *exchange) WriteHeader(status int) {
+	if !x.written {
+		x.status, x.written = status, true
+	}
+}
+
+func (x *exchange) Write(data []byte) (int, error) {
+	x.WriteHeader(http.StatusOK)
+	return x.body.Write(data)
+}
+
+func (x *exchange) Status() int {
+	return x.status
+}
+
+func (x *exchange) Size() int {
+	if !x.written {
+		return -1
+	}
+	return x.body.Len()
+}
+
+func (x *exchange) Written() bool {
+	return x.written
+}
+
+func (x *exchange) MarshalFailed(err error) {
+	x.err = err
+}
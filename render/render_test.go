@@ -0,0 +1,235 @@
+package render_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/render"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+var meta = responsehelper.Meta{RequestID: "msg-1", Timestamp: time.Date(2024, 5, 1, 6, 30, 0, 0, time.UTC)}
+
+// parityCase renders an envelope and sends the same one from a Gin handler.
+type parityCase struct {
+	name string
+	kind render.ResponseKind
+	in   render.RenderInput
+	gin  func(h responsehelper.ResponseHelper, c *gin.Context)
+}
+
+var parityCases = []parityCase{
+	{"success", render.KindSuccess, render.RenderInput{Data: map[string]int{"id": 42}},
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, map[string]int{"id": 42}) }},
+	{"success with options", render.KindSuccess, render.RenderInput{
+		Data:    map[string]int{"id": 42},
+		Options: []responsehelper.ResponseOption{responsehelper.MetaKV("attempt", 2), responsehelper.WithWarnings(responsehelper.Warning{Code: "SLOW", Message: "The upstream was slow"})},
+	}, func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, map[string]int{"id": 42}, responsehelper.MetaKV("attempt", 2), responsehelper.WithWarnings(responsehelper.Warning{Code: "SLOW", Message: "The upstream was slow"}))
+	}},
+	{"created", render.KindCreated, render.RenderInput{Data: map[string]int{"id": 42}},
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Created(c, map[string]int{"id": 42}) }},
+	{"paginated", render.KindPaginated, render.RenderInput{Data: []int{1, 2}, Pagination: responsehelper.NewPagination(1, 2, 5)},
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+		}},
+	{"API error", render.KindError, render.RenderInput{Error: responsehelper.ErrConflict.WithCode("EMAIL_TAKEN").WithMessage("Email already registered")},
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.RespondAPIError(c, responsehelper.ErrConflict.WithCode("EMAIL_TAKEN").WithMessage("Email already registered"))
+		}},
+	{"internal error", render.KindError, render.RenderInput{Err: errors.New("db down")},
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.RespondAPIError(c, responsehelper.NewInternalError("", errors.New("db down")))
+		}},
+	{"validation", render.KindValidation, render.RenderInput{Err: errors.New("name is required")},
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.ValidationFailed(c, errors.New("name is required"))
+		}},
+	{"XML", render.KindSuccess, render.RenderInput{Data: map[string]int{"id": 42}, Accept: "application/xml"},
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, map[string]int{"id": 42}) }},
+}
+
+func TestRenderParity(t *testing.T) {
+	for name, opts := range map[string][]responsehelper.Option{
+		"default":      nil,
+		"sanitization": {responsehelper.WithErrorSanitization(true), responsehelper.WithContentNegotiation(true)},
+		"null fields":  {responsehelper.WithNullFields(true), responsehelper.WithContentNegotiation(true)},
+	} {
+		h := responsehelper.NewResponseHelper(opts...)
+		renderer := render.Wrap(h)
+		for _, tc := range parityCases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				ctx := render.WithValue(render.WithMeta(context.Background(), meta), responsehelper.ErrorIDKey, "err-1")
+				body, err := renderer.Render(ctx, tc.kind, tc.in)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+				if tc.in.Accept != "" {
+					c.Request.Header.Set("Accept", tc.in.Accept)
+				}
+				c.Set(responsehelper.MetaKey, meta)
+				c.Set(responsehelper.ErrorIDKey, "err-1")
+				tc.gin(h, c)
+
+				if string(body) != w.Body.String() {
+					t.Errorf("Render = %s\nGin sent %s", body, w.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderSharesTheCodesOfTheHelper(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	h.RegisterCode("QUOTA_EXCEEDED", http.StatusPaymentRequired, "Your quota is exhausted")
+	apiErr := responsehelper.NewAPIError(http.StatusPaymentRequired, "Your quota is exhausted", nil).WithCode("QUOTA_EXCEEDED")
+
+	body, err := render.Wrap(h).Render(context.Background(), render.KindError, render.RenderInput{Error: apiErr})
+	if err != nil || !strings.Contains(string(body), `"code":402`) || !strings.Contains(string(body), `"errorCode":"QUOTA_EXCEEDED"`) {
+		t.Errorf("Render = %s, %v", body, err)
+	}
+}
+
+// sideEffects counts the calls of the hooks, reporter, audit and logger of
+// a helper.
+type sideEffects struct {
+	mu     sync.Mutex
+	calls  []string
+	audits responsehelper.MemoryAuditSink
+}
+
+func (s *sideEffects) add(call string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+func (s *sideEffects) Enabled(context.Context, slog.Level) bool { return true }
+func (s *sideEffects) Handle(_ context.Context, record slog.Record) error {
+	s.add("log: " + record.Message)
+	return nil
+}
+func (s *sideEffects) WithAttrs([]slog.Attr) slog.Handler { return s }
+func (s *sideEffects) WithGroup(string) slog.Handler      { return s }
+
+func (s *sideEffects) options() []responsehelper.Option {
+	return []responsehelper.Option{
+		responsehelper.WithOnResponse(func(c *gin.Context, info responsehelper.ResponseInfo) { s.add("hook: " + info.Method) }),
+		responsehelper.WithErrorReporter(func(_ context.Context, err error, _ map[string]interface{}) { s.add("report: " + err.Error()) }),
+		responsehelper.WithAuditSink(&s.audits),
+		responsehelper.WithLogger(slog.New(s)),
+		responsehelper.WithSuccessLogging(true),
+	}
+}
+
+func TestRenderHasNoSideEffects(t *testing.T) {
+	var effects sideEffects
+	renderer := render.New(effects.options()...)
+	for _, tc := range parityCases {
+		if _, err := renderer.Render(context.Background(), tc.kind, tc.in); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+	}
+
+	// give the audit queue the time to record a wrong entry
+	time.Sleep(10 * time.Millisecond)
+	if len(effects.calls) != 0 || len(effects.audits.Entries()) != 0 {
+		t.Errorf("Render ran %v and audited %v", effects.calls, effects.audits.Entries())
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+	responsehelper.NewResponseHelper(effects.options()...).InternalError(c, "Oops", errors.New("db down"))
+	if len(effects.calls) != 3 {
+		t.Errorf("the Gin helper ran %v, want the hook, the reporter and the logger", effects.calls)
+	}
+}
+
+func TestRenderMarshalError(t *testing.T) {
+	var effects sideEffects
+	for name, in := range map[string]render.RenderInput{
+		"JSON": {Data: math.Inf(1)},
+		"XML":  {Data: map[string]interface{}{"callback": func() {}}, Accept: "application/xml"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, err := render.New(append(effects.options(), responsehelper.WithContentNegotiation(true))...).
+				Render(context.Background(), render.KindSuccess, in)
+
+			if !errors.Is(err, render.ErrMarshal) {
+				t.Fatalf("err = %v, want ErrMarshal", err)
+			}
+			if name == "JSON" && !strings.Contains(string(body), `"code":500`) {
+				t.Errorf("Render = %s, want the fallback 500", body)
+			}
+			if len(effects.calls) != 0 {
+				t.Errorf("Render ran %v", effects.calls)
+			}
+		})
+	}
+}
+
+func TestRenderNotRendered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body, err := render.New(responsehelper.WithSkipOnClientGone(true)).
+		Render(ctx, render.KindSuccess, render.RenderInput{Data: map[string]int{"id": 42}})
+
+	if !errors.Is(err, render.ErrNotRendered) || !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want ErrNotRendered and context.Canceled", err)
+	}
+	if body != nil {
+		t.Errorf("Render = %s, want nothing", body)
+	}
+
+	if _, err := render.New().Render(ctx, render.KindSuccess, render.RenderInput{Data: 1}); err != nil {
+		t.Errorf("err = %v, want the envelope rendered without WithSkipOnClientGone", err)
+	}
+}
+
+func TestRenderUnknownKind(t *testing.T) {
+	if _, err := render.New().Render(context.Background(), "deleted", render.RenderInput{}); !errors.Is(err, render.ErrUnknownKind) {
+		t.Errorf("err = %v, want ErrUnknownKind", err)
+	}
+}
+
+func TestRenderContextValues(t *testing.T) {
+	parent := render.WithMeta(context.Background(), meta)
+	ctx := render.WithValue(parent, responsehelper.LocaleKey, "de")
+
+	if got, ok := render.MetaFromContext(ctx); !ok || got.(responsehelper.Meta).RequestID != meta.RequestID {
+		t.Errorf("MetaFromContext = %v, %t", got, ok)
+	}
+	if _, ok := render.Value(parent, responsehelper.LocaleKey); ok {
+		t.Error("WithValue changed the parent context")
+	}
+	if _, ok := render.MetaFromContext(context.Background()); ok {
+		t.Error("MetaFromContext found a meta in an empty context")
+	}
+}
+
+func TestWrapPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Wrap did not panic for a helper without a Core")
+		}
+	}()
+	render.Wrap(nil)
+}
@@ -0,0 +1,76 @@
+package responsehelper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrorReporterFunc reports a server error to an error tracker. meta holds
+// "errorId", "status", "path", "method" and the context values listed with
+// WithReportedContextKeys.
+type ErrorReporterFunc func(ctx context.Context, err error, meta map[string]interface{})
+
+// WithErrorReporter reports every 5xx response to reporter, eg: Sentry. The
+// error passed to the helper is reported as is, even when WithErrorSanitization
+// drops it from the body. Responses without an error report their message as
+// the error. 4xx responses are never reported.
+//
+// Example:
+//
+//	responsehelper.WithErrorReporter(func(ctx context.Context, err error, meta map[string]interface{}) {
+//		sentry.CaptureException(err)
+//	})
+func WithErrorReporter(reporter ErrorReporterFunc) Option {
+	return func(cfg *config) {
+		cfg.errorReporter = reporter
+	}
+}
+
+// WithReportedContextKeys adds the values stored under keys in the gin
+// context to the meta passed to the error reporter, eg: "userId".
+func WithReportedContextKeys(keys ...string) Option {
+	return func(cfg *config) {
+		cfg.reportedContextKeys = append(cfg.reportedContextKeys, keys...)
+	}
+}
+
+// reportError passes a 5xx response to the error reporter.
+func (cfg *config) reportError(c Exchange, status int, err error, message string) {
+	if cfg.errorReporter == nil || status < http.StatusInternalServerError {
+		return
+	}
+	if err == nil {
+		err = errors.New(message)
+	}
+	cfg.report(c, status, err)
+}
+
+// report passes err, the error of a response sent with status, to
+// WithErrorReporter whatever the status.
+func (cfg *config) report(c Exchange, status int, err error) {
+	if cfg.errorReporter == nil || detached(c) {
+		return
+	}
+	meta := map[string]interface{}{
+		"errorId": errorID(c),
+		"status":  status,
+		"path":    requestPath(c),
+	}
+	ctx := context.Background()
+	if c.Request() != nil {
+		meta["method"] = c.Request().Method
+		ctx = c.Request().Context()
+	}
+	for _, key := range cfg.reportedContextKeys {
+		if value, ok := c.Get(key); ok {
+			meta[key] = value
+		}
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			cfg.warnf("error reporter panicked: %v", recovered)
+		}
+	}()
+	cfg.errorReporter(ctx, err, meta)
+}
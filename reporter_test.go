@@ -0,0 +1,80 @@
+package responsehelper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+type report struct {
+	err  error
+	meta map[string]interface{}
+}
+
+func reportingHelper(reports *[]report, opts ...responsehelper.Option) responsehelper.ResponseHelper {
+	return responsehelper.NewResponseHelper(append([]responsehelper.Option{
+		responsehelper.WithErrorReporter(func(_ context.Context, err error, meta map[string]interface{}) {
+			*reports = append(*reports, report{err, meta})
+		}),
+	}, opts...)...)
+}
+
+func TestErrorReporterGetsTheRawError(t *testing.T) {
+	var reports []report
+	h := reportingHelper(&reports, responsehelper.WithErrorSanitization(true), responsehelper.WithReportedContextKeys("userId", "tenant"))
+	raw := errors.New("pq: password authentication failed for user \"app\"")
+
+	c, w := newContext(http.MethodPost, "/users")
+	c.Set("userId", "u-7")
+	h.InternalError(c, "Could not save the user", raw)
+
+	if len(reports) != 1 {
+		t.Fatalf("%d reports, want 1", len(reports))
+	}
+	if reports[0].err != raw {
+		t.Errorf("reported %v, want the raw error", reports[0].err)
+	}
+	if strings.Contains(w.Body.String(), "pq:") {
+		t.Errorf("the raw error reached the body: %s", w.Body)
+	}
+	assertError(t, w, http.StatusInternalServerError, "Could not save the user")
+	meta := reports[0].meta
+	for key, want := range map[string]interface{}{
+		"errorId": w.Header().Get(responsehelper.ErrorIDHeader),
+		"status":  http.StatusInternalServerError,
+		"path":    "/users",
+		"method":  http.MethodPost,
+		"userId":  "u-7",
+	} {
+		if meta[key] != want {
+			t.Errorf("meta[%q] = %v, want %v", key, meta[key], want)
+		}
+	}
+	if _, ok := meta["tenant"]; ok {
+		t.Errorf("meta has the unset key tenant: %v", meta)
+	}
+}
+
+func TestErrorReporterServerErrorsOnly(t *testing.T) {
+	var reports []report
+	h := reportingHelper(&reports)
+
+	c, _ := newContext(http.MethodGet, "/users/42")
+	h.NotFound(c, "missing")
+	c, _ = newContext(http.MethodGet, "/users/42")
+	h.Conflict(c, "taken", errors.New("duplicate"))
+	if len(reports) != 0 {
+		t.Fatalf("4xx responses were reported: %+v", reports)
+	}
+
+	c, _ = newContext(http.MethodGet, "/users")
+	h.ServiceUnavailable(c, "Down for maintenance", time.Minute)
+	if len(reports) != 1 || reports[0].err.Error() != "Down for maintenance" {
+		t.Errorf("reports = %+v, want the message of the 503 as error", reports)
+	}
+}
@@ -0,0 +1,183 @@
+package responsehelper
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestBodyKey is the gin context key holding the request body captured
+	// by RequestEchoMiddleware.
+	RequestBodyKey = "requestEchoBody"
+	// MaskedValue replaces the denylisted query parameters in "meta.request".
+	MaskedValue = "[masked]"
+	// defaultEchoBodyBytes is the number of body bytes RequestEchoMiddleware
+	// captures when its limit is not positive.
+	defaultEchoBodyBytes = 1024
+	// maxEchoBodyBytes caps the number of body bytes RequestEchoMiddleware
+	// captures, whatever its limit.
+	maxEchoBodyBytes = 16 << 10
+)
+
+// defaultEchoDenylist are the query parameters masked when
+// WithRequestEchoDenylist is not used.
+var defaultEchoDenylist = []string{"token", "apiKey", "access_token", "password", "secret", "signature"}
+
+// defaultEchoHeaders are the headers echoed when WithRequestEchoHeaders is
+// not used.
+var defaultEchoHeaders = []string{"Accept", "Content-Length", "Content-Type", "User-Agent"}
+
+// RequestEcho is the "meta.request" object of the 4xx envelopes sent with
+// WithRequestEcho, what the client sent as the server saw it.
+type RequestEcho struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Query  map[string][]string `json:"query,omitempty"`
+	// Headers are the allowlisted headers, their values joined with ", ".
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the beginning of a JSON body captured by RequestEchoMiddleware.
+	Body string `json:"body,omitempty"`
+	// BodyTruncated tells that the body is longer than the part in Body.
+	BodyTruncated bool `json:"bodyTruncated,omitempty"`
+}
+
+// WithRequestEcho adds "meta.request" to the 4xx envelopes, with the method,
+// the path, the query with the parameters of WithRequestEchoDenylist masked,
+// the headers of WithRequestEchoHeaders and, with RequestEchoMiddleware, the
+// beginning of a JSON body, so client errors can be reproduced. Like
+// WithDebug it has no effect when gin runs in release mode, never enable it
+// where requests carry data clients should not see again.
+//
+// Example:
+//
+//	router.Use(responsehelper.RequestEchoMiddleware(2048))
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithRequestEcho(true))
+func WithRequestEcho(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.requestEcho = enabled
+	}
+}
+
+// WithRequestEchoDenylist replaces the query parameters masked in
+// "meta.request", "token", "apiKey", "access_token", "password", "secret"
+// and "signature" by default. Names are matched case-insensitively.
+func WithRequestEchoDenylist(params ...string) Option {
+	return func(cfg *config) {
+		cfg.requestEchoDenylist = denylistSet(params)
+	}
+}
+
+// WithRequestEchoHeaders replaces the headers echoed in "meta.request",
+// Accept, Content-Length, Content-Type and User-Agent by default.
+func WithRequestEchoHeaders(names ...string) Option {
+	return func(cfg *config) {
+		cfg.requestEchoHeaders = append([]string{}, names...)
+	}
+}
+
+// RequestEchoMiddleware captures the first limit bytes of JSON request
+// bodies for WithRequestEcho, as binding consumes the body before the
+// response is written. Only the bytes the handler reads are captured. A
+// limit that is not positive captures 1 KiB, the limit is capped at 16 KiB.
+// The middleware does nothing when gin runs in release mode.
+//
+// Example:
+//
+//	router.Use(responsehelper.RequestEchoMiddleware(2048))
+func RequestEchoMiddleware(limit int) gin.HandlerFunc {
+	if limit <= 0 {
+		limit = defaultEchoBodyBytes
+	}
+	limit = min(limit, maxEchoBodyBytes)
+	return func(c *gin.Context) {
+		if gin.Mode() != gin.ReleaseMode && c.Request.Body != nil && c.Request.Body != http.NoBody && isJSONContentType(c.ContentType()) {
+			capture := &bodyCapture{ReadCloser: c.Request.Body, limit: limit}
+			c.Request.Body = capture
+			c.Set(RequestBodyKey, capture)
+		}
+		c.Next()
+	}
+}
+
+// bodyCapture copies the first limit bytes read from a request body.
+type bodyCapture struct {
+	io.ReadCloser
+	limit     int
+	captured  []byte
+	truncated bool
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	room := b.limit - len(b.captured)
+	if n > room {
+		b.truncated = true
+	}
+	b.captured = append(b.captured, p[:min(n, room)]...)
+	return n, err
+}
+
+// isJSONContentType reports whether contentType is JSON, eg:
+// application/json or application/merge-patch+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// echoMeta returns meta with the "request" of WithRequestEcho added for 4xx
+// responses.
+func (cfg *config) echoMeta(c Exchange, status int, meta interface{}) interface{} {
+	if !cfg.requestEcho || gin.Mode() == gin.ReleaseMode || status < http.StatusBadRequest || status >= http.StatusInternalServerError || c.Request() == nil {
+		return meta
+	}
+	return metaWithField(meta, "request", cfg.requestEchoOf(c))
+}
+
+// requestEchoOf returns the RequestEcho of the request.
+func (cfg *config) requestEchoOf(c Exchange) RequestEcho {
+	echo := RequestEcho{
+		Method: c.Request().Method,
+		Path:   requestPath(c),
+	}
+	denylist := cfg.requestEchoDenylist
+	if denylist == nil {
+		denylist = denylistSet(defaultEchoDenylist)
+	}
+	if c.Request().URL != nil {
+		for name, values := range c.Request().URL.Query() {
+			if echo.Query == nil {
+				echo.Query = map[string][]string{}
+			}
+			if denylist[strings.ToLower(name)] {
+				values = []string{MaskedValue}
+			}
+			echo.Query[name] = values
+		}
+	}
+	headers := cfg.requestEchoHeaders
+	if headers == nil {
+		headers = defaultEchoHeaders
+	}
+	for _, name := range headers {
+		if values := c.Request().Header.Values(name); len(values) > 0 {
+			if echo.Headers == nil {
+				echo.Headers = map[string]string{}
+			}
+			echo.Headers[http.CanonicalHeaderKey(name)] = strings.Join(values, ", ")
+		}
+	}
+	if stored, ok := c.Get(RequestBodyKey); ok {
+		if capture, ok := stored.(*bodyCapture); ok {
+			echo.Body = string(capture.captured)
+			echo.BodyTruncated = capture.truncated
+		}
+	}
+	return echo
+}
@@ -0,0 +1,185 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// echoEngine returns an engine answering POST /users, which binds the body,
+// with a 400 and GET /fail with a 500.
+func echoEngine(limit int, opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(append([]responsehelper.Option{responsehelper.WithRequestEcho(true)}, opts...)...)
+	engine := gin.New()
+	engine.Use(responsehelper.RequestEchoMiddleware(limit))
+	engine.POST("/users", func(c *gin.Context) {
+		var user struct {
+			Name string `json:"name" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&user); err != nil {
+			h.BadRequest(c, "Invalid user", err.Error())
+			return
+		}
+		h.Created(c, user)
+	})
+	engine.GET("/fail", func(c *gin.Context) {
+		h.InternalError(c, "Oops", errors.New("db down"))
+	})
+	return engine
+}
+
+// postUsers sends body to POST target as contentType.
+func postUsers(engine http.Handler, target, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set("User-Agent", "client/1.0")
+	r.Header.Set("Authorization", "Bearer secret")
+	return serve(engine, r)
+}
+
+// echoOf returns the "meta.request" of w, nil when there is none.
+func echoOf(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	meta, _ := decodeBody(t, w)["meta"].(map[string]interface{})
+	echo, _ := meta["request"].(map[string]interface{})
+	return echo
+}
+
+func TestRequestEcho(t *testing.T) {
+	w := postUsers(echoEngine(0), "/users?page=2&tag=a&tag=b", "application/json", `{"email":"arun@example.com"}`)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body: %s", w.Code, w.Body)
+	}
+	want := map[string]interface{}{
+		"method": "POST",
+		"path":   "/users",
+		"query":  map[string]interface{}{"page": []interface{}{"2"}, "tag": []interface{}{"a", "b"}},
+		"headers": map[string]interface{}{
+			"Content-Type": "application/json",
+			"User-Agent":   "client/1.0",
+		},
+		"body": `{"email":"arun@example.com"}`,
+	}
+	if echo := echoOf(t, w); !reflect.DeepEqual(echo, want) {
+		t.Errorf("meta.request = %v, want %v", echo, want)
+	}
+}
+
+func TestRequestEchoMasksTheDenylist(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []responsehelper.Option
+		want map[string]interface{}
+	}{
+		{"default", nil, map[string]interface{}{
+			"TOKEN":        []interface{}{responsehelper.MaskedValue},
+			"access_token": []interface{}{responsehelper.MaskedValue},
+			"apikey":       []interface{}{responsehelper.MaskedValue},
+			"session":      []interface{}{"s1"},
+			"page":         []interface{}{"2"},
+		}},
+		{"replaced", []responsehelper.Option{responsehelper.WithRequestEchoDenylist("Session")}, map[string]interface{}{
+			"TOKEN":        []interface{}{"t1"},
+			"access_token": []interface{}{"a1"},
+			"apikey":       []interface{}{"k1"},
+			"session":      []interface{}{responsehelper.MaskedValue},
+			"page":         []interface{}{"2"},
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postUsers(echoEngine(0, tt.opts...), "/users?TOKEN=t1&access_token=a1&apikey=k1&session=s1&page=2", "application/json", `{}`)
+			if query := echoOf(t, w)["query"]; !reflect.DeepEqual(query, tt.want) {
+				t.Errorf("meta.request.query = %v, want %v", query, tt.want)
+			}
+			if tt.name == "default" && strings.Contains(w.Body.String(), "t1") {
+				t.Errorf("a masked value was sent: %s", w.Body)
+			}
+		})
+	}
+}
+
+func TestRequestEchoHeaders(t *testing.T) {
+	w := postUsers(echoEngine(0, responsehelper.WithRequestEchoHeaders("user-agent", "X-Missing")), "/users", "application/json", `{}`)
+
+	want := map[string]interface{}{"User-Agent": "client/1.0"}
+	if headers := echoOf(t, w)["headers"]; !reflect.DeepEqual(headers, want) {
+		t.Errorf("meta.request.headers = %v, want %v", headers, want)
+	}
+	if strings.Contains(w.Body.String(), "Bearer") {
+		t.Errorf("the Authorization header was sent: %s", w.Body)
+	}
+}
+
+func TestRequestEchoCapsTheBody(t *testing.T) {
+	body := `{"email":"` + strings.Repeat("a", 32<<10) + `"}`
+	for _, tt := range []struct {
+		limit, want int
+	}{
+		{10, 10},
+		{0, 1024},
+		{-1, 1024},
+		{1 << 20, 16 << 10},
+	} {
+		echo := echoOf(t, postUsers(echoEngine(tt.limit), "/users", "application/json", body))
+		if captured, _ := echo["body"].(string); len(captured) != tt.want || !strings.HasPrefix(body, captured) {
+			t.Errorf("limit %d: captured %d bytes, want %d", tt.limit, len(captured), tt.want)
+		}
+		if echo["bodyTruncated"] != true {
+			t.Errorf("limit %d: bodyTruncated = %v", tt.limit, echo["bodyTruncated"])
+		}
+	}
+
+	echo := echoOf(t, postUsers(echoEngine(28), "/users", "application/json", `{"email":"arun@example.com"}`))
+	if echo["body"] != `{"email":"arun@example.com"}` || echo["bodyTruncated"] != nil {
+		t.Errorf("a body of the limit: body = %v, bodyTruncated = %v", echo["body"], echo["bodyTruncated"])
+	}
+}
+
+func TestRequestEchoCapturesOnlyJSONBodies(t *testing.T) {
+	for _, tt := range []struct {
+		contentType string
+		captured    bool
+	}{
+		{"application/json; charset=utf-8", true},
+		{"application/merge-patch+json", true},
+		{"text/plain", false},
+		{"application/x-www-form-urlencoded", false},
+		{"invalid;;", false},
+	} {
+		echo := echoOf(t, postUsers(echoEngine(0), "/users", tt.contentType, `{"email":"x"}`))
+		if _, ok := echo["body"]; ok != tt.captured {
+			t.Errorf("%s: body = %v, want captured %t", tt.contentType, echo["body"], tt.captured)
+		}
+	}
+}
+
+func TestRequestEchoOnlyOn4xx(t *testing.T) {
+	engine := echoEngine(0)
+	if echo := echoOf(t, serve(engine, httptest.NewRequest(http.MethodGet, "/fail", nil))); echo != nil {
+		t.Errorf("meta.request of a 500 = %v", echo)
+	}
+	if echo := echoOf(t, postUsers(engine, "/users", "application/json", `{"name":"arun"}`)); echo != nil {
+		t.Errorf("meta.request of a 201 = %v", echo)
+	}
+}
+
+func TestRequestEchoIsOff(t *testing.T) {
+	w := postUsers(echoEngine(0, responsehelper.WithRequestEcho(false)), "/users", "application/json", `{}`)
+	if echo := echoOf(t, w); echo != nil {
+		t.Errorf("meta.request without WithRequestEcho = %v", echo)
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+	engine := echoEngine(0)
+	if echo := echoOf(t, postUsers(engine, "/users", "application/json", `{}`)); echo != nil {
+		t.Errorf("meta.request in release mode = %v", echo)
+	}
+}
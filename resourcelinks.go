@@ -0,0 +1,125 @@
+package responsehelper
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Links are the hypermedia links of a resource by relation, eg:
+// {"self": "/users/42", "orders": "/users/42/orders"}. Relative links are
+// resolved against WithBaseURL, empty links are left out.
+type Links map[string]string
+
+// LinkedResource is implemented by the items of a collection carrying their
+// own links. The success methods send each such item with a "links" member
+// added to its JSON object.
+//
+// Example:
+//
+//	func (u User) Links() responsehelper.Links {
+//		return responsehelper.Links{"self": "/users/" + strconv.Itoa(u.ID)}
+//	}
+type LinkedResource interface {
+	Links() Links
+}
+
+var linkedResourceType = reflect.TypeOf((*LinkedResource)(nil)).Elem()
+
+func (r *Core) SuccessWithLinks(c Exchange, data interface{}, links Links) {
+	envelope := SuccessEnvelope{
+		Data:    nullable(data),
+		Success: true,
+	}
+	if resolved := r.resolveLinks(links); resolved != nil {
+		envelope.Links = resolved
+	}
+	r.renderSuccess(c, "SuccessWithLinks", http.StatusOK, envelope)
+}
+
+// WithLocation sets the Location header of a Created response to the URL of
+// the new resource, and adds it to the envelope as links.self. A relative
+// location is resolved against WithBaseURL.
+//
+// Example:
+//
+//	h.responseHelper.Created(c, user, responsehelper.WithLocation("/users/"+strconv.Itoa(user.ID)))
+func WithLocation(location string) ResponseOption {
+	return func(options *responseOptions) {
+		options.location = location
+	}
+}
+
+// resolveLinks returns links with the relative ones resolved and the empty
+// ones left out, nil when none is left.
+func (cfg *config) resolveLinks(links Links) Links {
+	var resolved Links
+	for rel, link := range links {
+		if link == "" {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(Links, len(links))
+		}
+		resolved[rel] = cfg.resolveLink(link)
+	}
+	return resolved
+}
+
+// resolveLink returns link prefixed with WithBaseURL when it is relative, eg:
+// "/users/42" becomes "https://api.example.com/v1/users/42".
+func (cfg *config) resolveLink(link string) string {
+	if cfg.linkBaseURL == nil {
+		return link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.IsAbs() || parsed.Host != "" {
+		return link
+	}
+	return cfg.linkBase() + "/" + strings.TrimLeft(link, "/")
+}
+
+// linkedData returns data with the links of its LinkedResource items added,
+// data itself when it is not a collection of them.
+func (cfg *config) linkedData(data interface{}) interface{} {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return data
+	}
+	elem := value.Type().Elem()
+	if elem.Kind() != reflect.Interface && !elem.Implements(linkedResourceType) {
+		return data
+	}
+	items := make([]interface{}, value.Len())
+	linked := false
+	for i := range items {
+		var changed bool
+		items[i], changed = cfg.linkedItem(value.Index(i).Interface())
+		linked = linked || changed
+	}
+	if !linked {
+		return data
+	}
+	return items
+}
+
+// linkedItem returns item with its links added when it is a LinkedResource
+// with links, and whether it is.
+func (cfg *config) linkedItem(item interface{}) (interface{}, bool) {
+	resource, ok := item.(LinkedResource)
+	if !ok || reflect.ValueOf(resource).Kind() == reflect.Pointer && reflect.ValueOf(resource).IsNil() {
+		return item, false
+	}
+	links := cfg.resolveLinks(resource.Links())
+	if links == nil {
+		return item, false
+	}
+	object, err := JSONValue(resource)
+	fields, ok := object.(map[string]interface{})
+	if err != nil || !ok {
+		return item, false
+	}
+	fields["links"] = links
+	return fields, true
+}
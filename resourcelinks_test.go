@@ -0,0 +1,139 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+type linkedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (u linkedUser) Links() responsehelper.Links {
+	if u.ID == 0 {
+		return nil
+	}
+	return responsehelper.Links{"self": "/users/" + strconv.Itoa(u.ID), "avatar": ""}
+}
+
+func TestSuccessWithLinks(t *testing.T) {
+	links := responsehelper.Links{
+		"self":   "/users/42",
+		"orders": "users/42/orders",
+		"docs":   "https://docs.example.com/users",
+		"avatar": "",
+	}
+	for _, tc := range []struct {
+		name string
+		opts []responsehelper.Option
+		want map[string]interface{}
+	}{
+		{"relative", nil, map[string]interface{}{
+			"self":   "/users/42",
+			"orders": "users/42/orders",
+			"docs":   "https://docs.example.com/users",
+		}},
+		{"base URL", []responsehelper.Option{responsehelper.WithBaseURL("https://api.example.com/v1/")}, map[string]interface{}{
+			"self":   "https://api.example.com/v1/users/42",
+			"orders": "https://api.example.com/v1/users/42/orders",
+			"docs":   "https://docs.example.com/users",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users/42")
+			responsehelper.NewResponseHelper(tc.opts...).SuccessWithLinks(c, linkedUser{ID: 42}, links)
+
+			assertSuccess(t, w)
+			assertField(t, w, "data.id", float64(42))
+			if got := decodeBody(t, w)["links"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("links = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSuccessWithLinksLeavesOutEmptyLinks(t *testing.T) {
+	for _, links := range []responsehelper.Links{nil, {"self": ""}} {
+		c, w := newContext(http.MethodGet, "/users/42")
+		responsehelper.NewResponseHelper().SuccessWithLinks(c, map[string]int{"id": 42}, links)
+
+		if got, ok := decodeBody(t, w)["links"]; ok {
+			t.Errorf("links = %v for %v", got, links)
+		}
+	}
+}
+
+func TestCreatedAddsTheLocationAsSelf(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithBaseURL("https://api.example.com")).
+		Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Location"); got != "https://api.example.com/users/42" {
+		t.Errorf("Location = %q", got)
+	}
+	assertField(t, w, "links.self", "https://api.example.com/users/42")
+}
+
+func TestCreatedWithoutLocation(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().Created(c, map[string]int{"id": 42})
+
+	if got := w.Header().Get("Location"); got != "" {
+		t.Errorf("Location = %q without WithLocation", got)
+	}
+	if got, ok := decodeBody(t, w)["links"]; ok {
+		t.Errorf("links = %v without WithLocation", got)
+	}
+}
+
+func TestItemLinks(t *testing.T) {
+	var missing *linkedUser
+	for _, tc := range []struct {
+		name string
+		data interface{}
+		want []interface{}
+	}{
+		{"typed slice", []linkedUser{{ID: 1, Name: "arun"}, {ID: 2, Name: "anu"}}, []interface{}{
+			map[string]interface{}{"id": float64(1), "name": "arun", "links": map[string]interface{}{"self": "https://api.example.com/users/1"}},
+			map[string]interface{}{"id": float64(2), "name": "anu", "links": map[string]interface{}{"self": "https://api.example.com/users/2"}},
+		}},
+		{"mixed items", []interface{}{linkedUser{ID: 1, Name: "arun"}, "plain", missing, linkedUser{Name: "new"}}, []interface{}{
+			map[string]interface{}{"id": float64(1), "name": "arun", "links": map[string]interface{}{"self": "https://api.example.com/users/1"}},
+			"plain",
+			nil,
+			map[string]interface{}{"id": float64(0), "name": "new"},
+		}},
+		{"without links", []linkedUser{{Name: "new"}}, []interface{}{
+			map[string]interface{}{"id": float64(0), "name": "new"},
+		}},
+		{"not linked", []int{1, 2}, []interface{}{float64(1), float64(2)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			responsehelper.NewResponseHelper(responsehelper.WithBaseURL("https://api.example.com")).
+				SuccessWithPagination(c, tc.data, responsehelper.NewPagination(1, 20, 2))
+
+			if got := decodeBody(t, w)["data"]; !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("data = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestItemLinksOnSuccess(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().Success(c, []linkedUser{{ID: 7, Name: "arun"}})
+
+	assertField(t, w, "data.0.links.self", "/users/7")
+	if _, ok := lookup(w, "links"); ok {
+		t.Error("a collection of linked items got top-level links")
+	}
+}
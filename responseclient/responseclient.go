@@ -0,0 +1,140 @@
+// Package responseclient decodes the responsehelper envelopes on the client
+// side, so Go services calling an API built with responsehelper do not have
+// to declare the envelopes again.
+//
+// Example:
+//
+//	resp, err := http.Get(usersURL + "/42")
+//	...
+//	envelope, _, err := responseclient.DecodeResponse[User](resp.Body)
+//	var apiErr *responsehelper.APIError
+//	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound { ... }
+package responseclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// SuccessEnvelope is the body of a success response, with its data decoded
+// into T. The members holding values of several shapes are kept as raw JSON,
+// eg: decode Pagination into a responsehelper.Pagination or a
+// responsehelper.CursorPagination depending on the endpoint.
+type SuccessEnvelope[T any] struct {
+	// Count is set by SuccessWithCount with CountTopLevel.
+	Count *int `json:"count,omitempty"`
+	// Data is the payload of the response.
+	Data T `json:"data"`
+	// Links are set by SuccessWithLinks and by Created with WithLocation.
+	Links json.RawMessage `json:"links,omitempty"`
+	// Message is set by Deleted.
+	Message string `json:"message,omitempty"`
+	// Meta is the meta object, eg: with "requestId".
+	Meta json.RawMessage `json:"meta,omitempty"`
+	// Pagination is set by SuccessWithPagination and SuccessWithCursor.
+	Pagination json.RawMessage `json:"pagination,omitempty"`
+	// Success is true.
+	Success bool `json:"success"`
+	// Warnings are the caveats of the request, eg: an ignored filter.
+	Warnings []responsehelper.Warning `json:"warnings,omitempty"`
+}
+
+// ErrorEnvelope is the body of an error response.
+type ErrorEnvelope struct {
+	// Error describes what went wrong.
+	Error responsehelper.ErrorBody `json:"error"`
+	// Meta is the meta object, eg: with "requestId".
+	Meta json.RawMessage `json:"meta,omitempty"`
+	// Success is false.
+	Success bool `json:"success"`
+}
+
+// sentinels are the errors the APIErrors of DecodeResponse derive from, so
+// errors.Is(err, responsehelper.ErrNotFound) holds for a decoded 404.
+var sentinels = map[int]*responsehelper.APIError{
+	http.StatusBadRequest:          responsehelper.ErrBadRequest,
+	http.StatusUnauthorized:        responsehelper.ErrUnauthorized,
+	http.StatusForbidden:           responsehelper.ErrForbidden,
+	http.StatusNotFound:            responsehelper.ErrNotFound,
+	http.StatusConflict:            responsehelper.ErrConflict,
+	http.StatusInternalServerError: responsehelper.ErrInternal,
+}
+
+// DecodeResponse reads a response body and decodes it according to its
+// "success" member. The data of a success envelope is decoded into T. An
+// error envelope is returned as its ErrorBody, with a *responsehelper.APIError
+// as the error so callers can use errors.As, and errors.Is with the sentinel
+// of the status, eg: responsehelper.ErrNotFound. A body without a boolean
+// "success", sent without the envelope, is decoded into the data as a whole.
+// Unknown members are ignored.
+//
+// Example:
+//
+//	envelope, errorBody, err := responseclient.DecodeResponse[[]User](resp.Body)
+func DecodeResponse[T any](r io.Reader) (SuccessEnvelope[T], *responsehelper.ErrorBody, error) {
+	var envelope SuccessEnvelope[T]
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return envelope, nil, fmt.Errorf("responseclient: read body: %w", err)
+	}
+	var probe struct {
+		Success *bool `json:"success"`
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' || json.Unmarshal(body, &probe) != nil || probe.Success == nil {
+		// the bare body, sent without the envelope
+		if err := json.Unmarshal(body, &envelope.Data); err != nil {
+			return envelope, nil, fmt.Errorf("responseclient: decode data: %w", err)
+		}
+		envelope.Success = true
+		return envelope, nil, nil
+	}
+	if *probe.Success {
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return envelope, nil, fmt.Errorf("responseclient: decode success envelope: %w", err)
+		}
+		return envelope, nil, nil
+	}
+	var errorEnvelope ErrorEnvelope
+	if err := json.Unmarshal(body, &errorEnvelope); err != nil {
+		return envelope, nil, fmt.Errorf("responseclient: decode error envelope: %w", err)
+	}
+	return envelope, &errorEnvelope.Error, apiError(body, errorEnvelope.Error)
+}
+
+// apiError returns the APIError of an error envelope. Its "errors" are
+// decoded as FieldErrors when they are the violations of ValidationFailed.
+func apiError(body []byte, errorBody responsehelper.ErrorBody) *responsehelper.APIError {
+	apiErr := &responsehelper.APIError{Status: errorBody.Code}
+	if sentinel, ok := sentinels[errorBody.Code]; ok {
+		apiErr = sentinel.WithMessage("")
+	}
+	apiErr.Code = errorBody.ErrorCode
+	apiErr.Message = errorBody.Message
+	apiErr.Details = errorBody.Details
+	var fields struct {
+		Error struct {
+			Errors []responsehelper.FieldError `json:"errors"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &fields) == nil && isFieldErrors(fields.Error.Errors) {
+		apiErr.FieldErrors = fields.Error.Errors
+	}
+	return apiErr
+}
+
+// isFieldErrors reports whether every entry of items names a field and a
+// rule, unlike the ErrorItems of Errors.
+func isFieldErrors(items []responsehelper.FieldError) bool {
+	for _, item := range items {
+		if item.Field == "" || item.Tag == "" {
+			return false
+		}
+	}
+	return len(items) > 0
+}
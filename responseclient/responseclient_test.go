@@ -0,0 +1,179 @@
+package responseclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/responseclient"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// render returns the body respond sends with a helper configured by opts.
+func render(respond func(h responsehelper.ResponseHelper, c *gin.Context), opts ...responsehelper.Option) *strings.Reader {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users", nil)
+	c.Set(responsehelper.MetaKey, gin.H{"requestId": "req-1"})
+	respond(responsehelper.NewResponseHelper(opts...), c)
+	return strings.NewReader(w.Body.String())
+}
+
+func TestDecodeSuccess(t *testing.T) {
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Success(c, user{ID: 42, Name: "arun"}, responsehelper.WithWarnings(responsehelper.Warning{Code: "DEPRECATED", Message: "use v2"}))
+	})
+	envelope, errorBody, err := responseclient.DecodeResponse[user](body)
+
+	if err != nil || errorBody != nil {
+		t.Fatalf("DecodeResponse = %v, %v", errorBody, err)
+	}
+	if !envelope.Success || envelope.Data != (user{ID: 42, Name: "arun"}) {
+		t.Errorf("envelope = %+v", envelope)
+	}
+	if string(envelope.Meta) != `{"requestId":"req-1"}` {
+		t.Errorf("meta = %s", envelope.Meta)
+	}
+	if len(envelope.Warnings) != 1 || envelope.Warnings[0].Code != "DEPRECATED" {
+		t.Errorf("warnings = %+v", envelope.Warnings)
+	}
+}
+
+func TestDecodePagination(t *testing.T) {
+	users := []user{{1, "arun"}, {2, "anu"}}
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, users, responsehelper.NewPagination(1, 2, 5))
+	})
+	envelope, _, err := responseclient.DecodeResponse[[]user](body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(envelope.Data, users) {
+		t.Errorf("data = %+v, want %+v", envelope.Data, users)
+	}
+	var pagination responsehelper.Pagination
+	if err := json.Unmarshal(envelope.Pagination, &pagination); err != nil || pagination != responsehelper.NewPagination(1, 2, 5) {
+		t.Errorf("pagination = %+v, %v", pagination, err)
+	}
+}
+
+func TestDecodeCount(t *testing.T) {
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithCount(c, []int{1, 2, 3})
+	}, responsehelper.WithCountPlacement(responsehelper.CountTopLevel))
+	envelope, _, err := responseclient.DecodeResponse[[]int](body)
+
+	if err != nil || envelope.Count == nil || *envelope.Count != 3 {
+		t.Errorf("count = %v, %v", envelope.Count, err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.RespondAPIError(c, responsehelper.ErrNotFound.WithMessage("user not found").WithCode("USER_NOT_FOUND"))
+	})
+	envelope, errorBody, err := responseclient.DecodeResponse[user](body)
+
+	var apiErr *responsehelper.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want an *APIError", err)
+	}
+	if !errors.Is(err, responsehelper.ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = false", err)
+	}
+	if apiErr.Status != http.StatusNotFound || apiErr.Message != "user not found" || apiErr.Code != "USER_NOT_FOUND" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if errorBody == nil || errorBody.Code != http.StatusNotFound || errorBody.ErrorCode != "USER_NOT_FOUND" {
+		t.Errorf("ErrorBody = %+v", errorBody)
+	}
+	if envelope.Success {
+		t.Error("the envelope of an error is a success")
+	}
+}
+
+func TestDecodeErrorWithoutSentinel(t *testing.T) {
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.TooManyRequests(c, "Slow down", 0)
+	})
+	_, _, err := responseclient.DecodeResponse[user](body)
+
+	var apiErr *responsehelper.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests || apiErr.Message != "Slow down" {
+		t.Errorf("err = %#v", err)
+	}
+	if errors.Is(err, responsehelper.ErrBadRequest) {
+		t.Error("a 429 matched ErrBadRequest")
+	}
+}
+
+func TestDecodeFieldErrors(t *testing.T) {
+	fieldErrors := []responsehelper.FieldError{{Field: "email", Tag: "email", Message: "invalid email"}}
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusBadRequest, Message: "Validation failed", FieldErrors: fieldErrors})
+	})
+	_, _, err := responseclient.DecodeResponse[user](body)
+
+	var apiErr *responsehelper.APIError
+	if !errors.As(err, &apiErr) || !reflect.DeepEqual(apiErr.FieldErrors, fieldErrors) {
+		t.Errorf("err = %#v, want the field errors %+v", err, fieldErrors)
+	}
+}
+
+func TestDecodeErrorItemsAreNotFieldErrors(t *testing.T) {
+	body := render(func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.Errors(c, http.StatusBadRequest, []responsehelper.ErrorItem{{Message: "first"}, {Message: "second"}})
+	})
+	_, _, err := responseclient.DecodeResponse[user](body)
+
+	var apiErr *responsehelper.APIError
+	if !errors.As(err, &apiErr) || apiErr.FieldErrors != nil {
+		t.Errorf("err = %#v", err)
+	}
+}
+
+func TestDecodeBareBody(t *testing.T) {
+	for _, body := range []string{`{"id":42,"name":"arun"}`, ` {"id":42,"name":"arun","success":"yes"} `} {
+		envelope, errorBody, err := responseclient.DecodeResponse[user](strings.NewReader(body))
+
+		if err != nil || errorBody != nil || !envelope.Success || envelope.Data.ID != 42 {
+			t.Errorf("DecodeResponse(%s) = %+v, %v, %v", body, envelope, errorBody, err)
+		}
+	}
+	envelope, _, err := responseclient.DecodeResponse[[]int](strings.NewReader(`[1,2]`))
+	if err != nil || !reflect.DeepEqual(envelope.Data, []int{1, 2}) {
+		t.Errorf("DecodeResponse([1,2]) = %+v, %v", envelope, err)
+	}
+}
+
+func TestDecodeIgnoresUnknownMembers(t *testing.T) {
+	body := `{"success":true,"data":{"id":1,"extra":true},"future":{"a":1}}`
+	envelope, _, err := responseclient.DecodeResponse[user](strings.NewReader(body))
+
+	if err != nil || envelope.Data.ID != 1 {
+		t.Errorf("DecodeResponse = %+v, %v", envelope, err)
+	}
+}
+
+func TestDecodeInvalidBody(t *testing.T) {
+	for _, body := range []string{``, `{"success":true,"data":"text"}`, `not json`} {
+		if _, _, err := responseclient.DecodeResponse[user](strings.NewReader(body)); err == nil {
+			t.Errorf("DecodeResponse(%q) succeeded", body)
+		}
+	}
+}
@@ -7,7 +7,10 @@ Last Modified: 2025-11-07
 */
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +22,7 @@ type ResponseHelper interface {
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
 	//   - details: Additional details about the error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	//  responseHelper.BadRequest(c, "Invalid input", "The 'name' field is required.")
@@ -33,14 +37,40 @@ type ResponseHelper interface {
 	//		"details": "The 'name' field is required."
 	//	}
 	// }
-	BadRequest(c *gin.Context, message string, details string)
+	//
+	// Deprecated: use BadRequestDetails.
+	BadRequest(c *gin.Context, message string, details string, opts ...ResponseOption)
+
+	// BadRequestDetails sends a 400 Bad Request response with structured details
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - message: A brief message describing the error.
+	//   - details: Additional details about the error, eg: a map, slice or struct. Left out when nil.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.BadRequestDetails(c, "Invalid input", map[string]string{"name": "required"})
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    400,
+	//		"status":  "BAD_REQUEST",
+	//		"message": "Invalid input",
+	//		"details": {"name": "required"}
+	//	}
+	// }
+	BadRequestDetails(c *gin.Context, message string, details interface{}, opts ...ResponseOption)
 
 	// AlreadyExists sends a 409 Conflict response indicating resource already exists
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - resource: The name of the resource that already exists.
-	//   - err: The error that occurred.
+	//   - err: The error that occurred, may be nil.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	//  responseHelper.AlreadyExists(c, "User", err)
@@ -55,14 +85,15 @@ type ResponseHelper interface {
 	//		"details": "Error details here"
 	//	}
 	// }
-	AlreadyExists(c *gin.Context, resource string, err error)
+	AlreadyExists(c *gin.Context, resource string, err error, opts ...ResponseOption)
 
 	// Conflict sends a 409 Conflict response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
-	//   - err: The error that occurred.
+	//   - err: The error that occurred, may be nil.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	//  h.responseHelper.Conflict(c, "Resource conflict", err)
@@ -77,12 +108,13 @@ type ResponseHelper interface {
 	//		"details": "Error details here"
 	//	}
 	// }
-	Conflict(c *gin.Context, message string, err error)
+	Conflict(c *gin.Context, message string, err error, opts ...ResponseOption)
 	// NotFound sends a 404 Not Found response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	//  h.responseHelper.NotFound(c, "Resource not found")
@@ -96,13 +128,42 @@ type ResponseHelper interface {
 	//		"message": "Resource not found"
 	//	}
 	// }
-	NotFound(c *gin.Context, message string)
+	NotFound(c *gin.Context, message string, opts ...ResponseOption)
+
+	// ResourceNotFound sends a 404 Not Found response naming the resource and
+	// the identifier that was not found, so clients need not parse the message.
+	// Plain NotFound stays for free-form messages.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - resource: The kind of resource, eg: "user".
+	//   - id: The identifier that was looked up, stringified as "resourceId".
+	//     A map, eg: a composite key, is rendered as its sorted "key=value"
+	//     pairs. When nil, the message and "resourceId" leave the id out.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.ResourceNotFound(c, "user", userID)
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":       404,
+	//		"status":     "NOT_FOUND",
+	//		"message":    "user with id '42' not found",
+	//		"resource":   "user",
+	//		"resourceId": "42"
+	//	}
+	// }
+	ResourceNotFound(c *gin.Context, resource string, id interface{}, opts ...ResponseOption)
 
 	// Unauthorized sends a 401 Unauthorized response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	// h.responseHelper.Unauthorized(c, "Unauthorized access")
@@ -116,12 +177,99 @@ type ResponseHelper interface {
 	//		"message": "Unauthorized access"
 	//	}
 	// }
-	Unauthorized(c *gin.Context, message string)
+	Unauthorized(c *gin.Context, message string, opts ...ResponseOption)
+
+	// UnauthorizedWithChallenge sends a 401 Unauthorized response with a WWW-Authenticate header
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - message: A brief message describing the error.
+	//   - scheme: The authentication scheme, eg: "Bearer".
+	//   - realm: The protection space, left out when empty.
+	//   - params: Additional challenge parameters, eg: {"error": responsehelper.BearerErrorInvalidToken}.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.UnauthorizedWithChallenge(c, "Token expired", "Bearer", "api",
+	//  	map[string]string{"error": responsehelper.BearerErrorInvalidToken})
+	//
+	// Example Response Header:
+	//  WWW-Authenticate: Bearer realm="api", error="invalid_token"
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    401,
+	//		"status":  "UNAUTHORIZED",
+	//		"message": "Token expired"
+	//	}
+	// }
+	UnauthorizedWithChallenge(c *gin.Context, message, scheme, realm string, params map[string]string, opts ...ResponseOption)
+
+	// UnauthorizedReason sends a 401 Unauthorized response telling why the
+	// credentials were rejected, so clients can refresh an expired token
+	// rather than asking to log in again
+	//
+	// The reason is rendered as "error.reason" and in the Bearer challenge of
+	// the WWW-Authenticate header, with the realm of WithBearerChallenge: as
+	// error="invalid_token" with an error_description, except for
+	// TokenMissing which gets a challenge without error as RFC 6750 asks.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - reason: TokenExpired, TokenInvalid, TokenMissing or SessionRevoked.
+	//   - message: A brief message describing the error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("token-expired").
+	//
+	// Example:
+	//  h.responseHelper.UnauthorizedReason(c, responsehelper.TokenExpired, "Your session expired")
+	//
+	// Example Response Header:
+	//  WWW-Authenticate: Bearer realm="api", error="invalid_token", error_description="The access token expired"
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    401,
+	//		"status":  "UNAUTHORIZED",
+	//		"message": "Your session expired",
+	//		"reason":  "TOKEN_EXPIRED"
+	//	}
+	// }
+	UnauthorizedReason(c *gin.Context, reason AuthFailureReason, message string, opts ...ResponseOption)
+
+	// OAuthError sends the error response of an OAuth 2.0 endpoint, eg: the
+	// token endpoint, in the flat shape of RFC 6749 rather than the envelope
+	//
+	// The Cache-Control: no-store and Pragma: no-cache headers are set as the
+	// RFC requires. A 401 to a client that authenticated with the
+	// Authorization header gets a WWW-Authenticate challenge of the same
+	// scheme. An error code outside RFC 6749 and WithCustomOAuthCodes is sent
+	// as a 500 server_error with a warning.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - errCode: The error code, eg: responsehelper.OAuthErrorInvalidGrant.
+	//   - description: The error_description, left out when empty.
+	//   - status: The HTTP status, 400 for most codes and 401 for invalid_client.
+	//
+	// Example:
+	//  h.responseHelper.OAuthError(c, responsehelper.OAuthErrorInvalidGrant, "The authorization code expired", http.StatusBadRequest)
+	//
+	// Example Response Body:
+	// {
+	//	"error": "invalid_grant",
+	//	"error_description": "The authorization code expired"
+	// }
+	OAuthError(c *gin.Context, errCode string, description string, status int)
 	// Forbidden sends a 403 Forbidden response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	// h.responseHelper.Forbidden(c, "Forbidden access")
@@ -135,13 +283,100 @@ type ResponseHelper interface {
 	//		"message": "This User does not have access to the resource"
 	//	}
 	// }
-	Forbidden(c *gin.Context, message string)
+	Forbidden(c *gin.Context, message string, opts ...ResponseOption)
+
+	// ForbiddenScope sends a 403 Forbidden response listing the required permissions
+	//
+	// When the helper was created with WithBearerChallenge, the
+	// WWW-Authenticate header is set with error="insufficient_scope" and the
+	// required scope.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - message: A brief message describing the error.
+	//   - required: The permissions needed for the resource, left out when empty.
+	//   - granted: The permissions the caller has, left out when empty.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.ForbiddenScope(c, "Missing permission", []string{"billing.write"}, claims.Scopes)
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":                403,
+	//		"status":              "FORBIDDEN",
+	//		"message":             "Missing permission",
+	//		"requiredPermissions": ["billing.write"],
+	//		"grantedPermissions":  ["billing.read"]
+	//	}
+	// }
+	ForbiddenScope(c *gin.Context, message string, required []string, granted []string, opts ...ResponseOption)
+
+	// TooManyRequests sends a 429 Too Many Requests response
+	//
+	// The Retry-After header is set when retryAfter is positive, rounded up to
+	// whole seconds. The rate limit headers are set like on every response,
+	// see SetRateLimit and WithRateLimit.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - message: A brief message describing the error.
+	//   - retryAfter: How long the client should wait before retrying, 0 for no Retry-After header.
+	//   - opts: Optional per response options, eg: responsehelper.WithRateLimit(info).
+	//
+	// Example:
+	//  h.responseHelper.TooManyRequests(c, "Rate limit exceeded", 30*time.Second)
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":      429,
+	//		"status":    "TOO_MANY_REQUESTS",
+	//		"message":   "Rate limit exceeded",
+	//		"retryable": true
+	//	}
+	// }
+	TooManyRequests(c *gin.Context, message string, retryAfter time.Duration, opts ...ResponseOption)
+
+	// ServiceUnavailable sends a 503 Service Unavailable response
+	//
+	// The Retry-After header is set when retryAfter is positive, rounded up to
+	// whole seconds.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - message: A brief message describing the error.
+	//   - retryAfter: How long the client should wait before retrying, 0 for no Retry-After header.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.ServiceUnavailable(c, "Payments are temporarily unavailable", time.Minute)
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":      503,
+	//		"status":    "SERVICE_UNAVAILABLE",
+	//		"message":   "Payments are temporarily unavailable",
+	//		"errorId":   "3f1c2a9e8d4b4c1e",
+	//		"retryable": true
+	//	}
+	// }
+	ServiceUnavailable(c *gin.Context, message string, retryAfter time.Duration, opts ...ResponseOption)
 	// InternalError sends a 500 Internal Server Error response
 	//
+	// An error ID is generated (or reused from the context) and sent as
+	// "errorId" and in the X-Error-ID header.
+	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - message: A brief message describing the error.
-	//   - err: The error that occurred.
+	//   - err: The error that occurred, may be nil.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
 	//
 	// Example:
 	//  h.responseHelper.InternalError(c, "An unexpected error occurred", err)
@@ -153,16 +388,18 @@ type ResponseHelper interface {
 	//		"code":    500,
 	//		"status":  "INTERNAL_SERVER_ERROR",
 	//		"message": "An unexpected error occurred",
-	//		"details": "Error details here"
+	//		"details": "Error details here",
+	//		"errorId": "3f9a1c0b7d2e4a65"
 	//	}
 	// }
-	InternalError(c *gin.Context, message string, err error)
+	InternalError(c *gin.Context, message string, err error, opts ...ResponseOption)
 
 	// Success sends a 200 OK response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - data: The data to include in the response.
+	//   - opts: Optional per response options, eg: responsehelper.CachePublic(time.Minute) to set the Cache-Control header.
 	//
 	// Example:
 	//  h.responseHelper.Success(c, data)
@@ -175,17 +412,99 @@ type ResponseHelper interface {
 	//	},
 	//	"meta": "2023-01-01T00:00:00Z"
 	// }
-	Success(c *gin.Context, data interface{})
+	Success(c *gin.Context, data interface{}, opts ...ResponseOption)
+
+	// PartialSuccess sends a 200 OK response with the data that could be
+	// loaded and the parts that could not, eg: for an endpoint aggregating
+	// several services, so clients render what they have. The failures are
+	// listed under "partialFailures" and "meta.partial" is true. Without
+	// failures it behaves exactly like Success.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - data: The data that could be loaded.
+	//   - failures: The parts that could not be loaded.
+	//   - opts: Optional per response options, eg: responsehelper.WithWarnings(...).
+	//
+	// Example:
+	//  h.responseHelper.PartialSuccess(c, dashboard, []responsehelper.PartialFailure{{
+	//  	Component: "recommendations",
+	//  	Code:      "UPSTREAM_TIMEOUT",
+	//  	Message:   "Recommendations are unavailable",
+	//  }})
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {"orders": [...], "recommendations": null},
+	//	"meta": {"partial": true},
+	//	"partialFailures": [
+	//		{"component": "recommendations", "code": "UPSTREAM_TIMEOUT", "message": "Recommendations are unavailable"}
+	//	]
+	// }
+	PartialSuccess(c *gin.Context, data interface{}, failures []PartialFailure, opts ...ResponseOption)
+
+	// SuccessCSV sends a 200 OK response with rows as a CSV attachment
+	//
+	// The header row comes from the csv tags of the fields, then their json
+	// tags, then their names. Nested structs are flattened into dotted
+	// headers, eg: "address.city", slices and maps are rendered as JSON. For
+	// maps the header is the sorted keys of all rows. The rows are written as
+	// they are formatted, the body is never buffered.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - filename: The name of the attachment, eg: "orders.csv". No Content-Disposition is sent when empty.
+	//   - rows: A slice of structs or of maps with string keys, anything else is sent as an InternalError.
+	//
+	// Example:
+	//  h.responseHelper.SuccessCSV(c, "orders.csv", orders)
+	//
+	// Example Response Body:
+	// id,customer.name,total
+	// 1,"Doe, Jane",19.99
+	SuccessCSV(c *gin.Context, filename string, rows interface{})
+
+	// SuccessIfModified sends a 200 OK response with the data returned by
+	// dataFn, or a 304 Not Modified response without a body when the
+	// If-Modified-Since of a GET or HEAD request is not before lastModified.
+	// The Last-Modified header is set from lastModified, truncated to the
+	// second as HTTP dates have no fractions. dataFn, which may hit the
+	// database, is only called when the full response is sent, its error is
+	// sent as an InternalError, as is a nil dataFn. A zero lastModified always
+	// sends the data.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - lastModified: The time the resource last changed, eg: its updatedAt.
+	//   - dataFn: Returns the data to include in the response.
+	//   - opts: Optional per response options of the 200 OK response, eg: responsehelper.WithCache(...).
+	//
+	// Example:
+	//  h.responseHelper.SuccessIfModified(c, article.UpdatedAt, func() (interface{}, error) {
+	//  	return h.store.ArticleWithComments(c, article.ID)
+	//  })
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {
+	//		// response data here
+	//	},
+	//	"meta": "2023-01-01T00:00:00Z"
+	// }
+	SuccessIfModified(c *gin.Context, lastModified time.Time, dataFn func() (interface{}, error), opts ...ResponseOption)
 
 	// SuccessWithPagination sends a 200 OK response with pagination metadata
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - data: The data to include in the response.
-	//   - meta: The pagination metadata.
+	//   - meta: The pagination metadata, preferably a Pagination from NewPagination. A Pagination has its totals recomputed, any other value is sent as it is.
+	//   - opts: Optional per response options, eg: responsehelper.CachePrivate(time.Minute) to set the Cache-Control header.
 	//
 	// Example:
-	//  h.responseHelper.SuccessWithPagination(c, data, meta)
+	//  h.responseHelper.SuccessWithPagination(c, data, responsehelper.NewPagination(page, pageSize, total))
 	//
 	// Example Response Body:
 	// {
@@ -197,19 +516,129 @@ type ResponseHelper interface {
 	//		"currentPage": 3,
 	//		"pageSize": 10,
 	//		"totalPages": 3,
-	//		"totalRecords": 27
+	//		"totalRecords": 27,
+	//		"hasNext": false,
+	//		"hasPrev": true
+	//	}
+	// }
+	SuccessWithPagination(c *gin.Context, data interface{}, meta interface{}, opts ...ResponseOption)
+
+	// SuccessWithCursor sends a 200 OK response with cursor pagination metadata
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - data: The data to include in the response.
+	//   - cur: The cursors of the neighbouring pages, made with EncodeCursor. Empty cursors are omitted.
+	//
+	// Example:
+	//  h.responseHelper.SuccessWithCursor(c, posts, responsehelper.CursorPagination{
+	//  	NextCursor: responsehelper.EncodeCursor(last.ID),
+	//  	PageSize:   len(posts),
+	//  	HasMore:    true,
+	//  })
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": [
+	//		// response data here
+	//	],
+	//	"pagination": {
+	//		"nextCursor": "eyJpZCI6NDJ9",
+	//		"pageSize": 20,
+	//		"hasMore": true
+	//	}
+	// }
+	SuccessWithCursor(c *gin.Context, data interface{}, cur CursorPagination)
+
+	// SuccessWithCount sends a 200 OK response with the number of items of data
+	//
+	// The count is sent as "meta.count", or as "count" next to "data" with
+	// WithCountPlacement(CountTopLevel). Data that is not a slice or an
+	// array is sent like Success does.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - data: The items to include in the response, a slice, an array or a pointer to one.
+	//
+	// Example:
+	//  h.responseHelper.SuccessWithCount(c, tags)
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": [
+	//		// response data here
+	//	],
+	//	"meta": {
+	//		"count": 3
 	//	}
 	// }
-	SuccessWithPagination(c *gin.Context, data interface{}, meta interface{})
+	SuccessWithCount(c *gin.Context, data interface{})
+
+	// SuccessWithLinks sends a 200 OK response with hypermedia links
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - data: The data to include in the response.
+	//   - links: The links of the resource by relation. Relative links are resolved against WithBaseURL, empty links are left out.
+	//
+	// Example:
+	//  h.responseHelper.SuccessWithLinks(c, user, responsehelper.Links{
+	//  	"self":   "/users/42",
+	//  	"orders": "/users/42/orders",
+	//  })
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {
+	//		// response data here
+	//	},
+	//	"links": {
+	//		"orders": "https://api.example.com/v1/users/42/orders",
+	//		"self": "https://api.example.com/v1/users/42"
+	//	}
+	// }
+	SuccessWithLinks(c *gin.Context, data interface{}, links Links)
+
+	// SuccessLarge sends a 200 OK response with data streamed to the client
+	//
+	// The envelope is written while the data is encoded instead of after,
+	// a slice or an array one item at a time, so large payloads are not
+	// held in memory twice. The body is the one of Success. Other formats
+	// than JSON, and JSONP, are sent like Success does.
+	//
+	// The status is sent before the data is encoded, so an item that cannot
+	// be encoded can no longer turn the response into an error: the
+	// connection is closed instead, and the client sees a truncated body.
+	// Use Recovery rather than gin.Recovery, which keeps the connection.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - data: The data to include in the response, usually a large slice.
+	//
+	// Example:
+	//  h.responseHelper.SuccessLarge(c, events)
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": [
+	//		// response data here
+	//	]
+	// }
+	SuccessLarge(c *gin.Context, data interface{})
 
 	// Created sends a 201 Created response
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
 	//   - data: The data to include in the response.
+	//   - opts: Optional per response options, eg: WithLocation to set the Location header and links.self.
 	//
 	// Example:
-	//  responseHelper.Created(c, data)
+	//  responseHelper.Created(c, data, responsehelper.WithLocation("/users/42"))
 	//
 	// Example Response Body:
 	// {
@@ -217,11 +646,98 @@ type ResponseHelper interface {
 	//	"data": {
 	//		// response data here
 	//	},
+	//	"links": {
+	//		"self": "/users/42"
+	//	},
 	//	"meta": "2023-01-01T00:00:00Z"
 	// }
-	Created(c *gin.Context, data interface{})
+	Created(c *gin.Context, data interface{}, opts ...ResponseOption)
 
-	// Deleted sends a 204 No Content response
+	// Patched sends a 200 OK response with the updated representation of a
+	// partially updated resource, listing the members the update changed
+	// under "meta.changedFields", sorted and de-duplicated. When none
+	// changed, the meta also has "unchanged": true, so clients can skip
+	// refreshing their copy.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - resource: The name of the resource, eg: "User", used in the message.
+	//   - data: The updated resource.
+	//   - changedFields: The JSON names of the changed members, see DiffFields.
+	//   - opts: Optional per response options, eg: WithCache.
+	//
+	// Example:
+	//  h.responseHelper.Patched(c, "User", user, responsehelper.DiffFields(before, user))
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {
+	//		// the updated resource
+	//	},
+	//	"message": "User updated successfully",
+	//	"meta": {
+	//		"changedFields": ["address.city", "name"]
+	//	}
+	// }
+	Patched(c *gin.Context, resource string, data interface{}, changedFields []string, opts ...ResponseOption)
+
+	// OperationAccepted sends a 202 Accepted response for a long-running
+	// operation, with the operation as data and its status URL in the
+	// Location header.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - op: The operation, usually OperationPending.
+	//
+	// Example:
+	//  h.responseHelper.OperationAccepted(c, responsehelper.Operation{ID: id, Status: responsehelper.OperationPending, StatusURL: "/operations/" + id, CreatedAt: now, UpdatedAt: now})
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {
+	//		"id": "op_7f3a2c",
+	//		"status": "pending",
+	//		"statusUrl": "/operations/op_7f3a2c",
+	//		"progress": 0,
+	//		"createdAt": "2024-05-01T12:00:00Z",
+	//		"updatedAt": "2024-05-01T12:00:00Z"
+	//	}
+	// }
+	OperationAccepted(c *gin.Context, op Operation)
+
+	// OperationStatus sends the state of a long-running operation, for the
+	// clients polling it: a 200 OK response with the operation as data while
+	// it is pending or running and once it succeeded, with its result, or
+	// the error envelope of op.Error, 500 when nil, once it failed.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - op: The operation.
+	//
+	// Example:
+	//  h.responseHelper.OperationStatus(c, op)
+	//
+	// Example Response Body:
+	// {
+	//	"success": true,
+	//	"data": {
+	//		"id": "op_7f3a2c",
+	//		"status": "succeeded",
+	//		"statusUrl": "/operations/op_7f3a2c",
+	//		"progress": 100,
+	//		"result": {
+	//			// result data here
+	//		},
+	//		"createdAt": "2024-05-01T12:00:00Z",
+	//		"updatedAt": "2024-05-01T12:03:10Z"
+	//	}
+	// }
+	OperationStatus(c *gin.Context, op Operation)
+
+	// Deleted sends a 200 OK response with a message, or a 204 No Content
+	// response without a body with WithDeleteStatus(http.StatusNoContent)
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
@@ -238,7 +754,22 @@ type ResponseHelper interface {
 	// }
 	Deleted(c *gin.Context, message string)
 
-	// NoContent sends a 204 No Content response
+	// DeletedNoContent sends a 204 No Content response without a body for a
+	// deleted resource, whatever WithDeleteStatus says. The meta is only sent
+	// in headers, eg: the X-Request-ID of MetaMiddleware and X-API-Version.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//
+	// Example:
+	//  responseHelper.DeletedNoContent(c)
+	//
+	// Example Response Body: none
+	DeletedNoContent(c *gin.Context)
+
+	// NoContent sends a 204 No Content response without a body. The meta is
+	// only sent in headers, eg: the X-Request-ID of MetaMiddleware and
+	// X-API-Version.
 	//
 	// Parameters:
 	//   - c: The Gin context to send the response to.
@@ -246,157 +777,631 @@ type ResponseHelper interface {
 	// Example:
 	//  responseHelper.NoContent(c)
 	//
+	// Example Response Body: none
+	NoContent(c *gin.Context)
+
+	// RespondAPIError sends the error envelope described by an *APIError
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - err: The API error to render. Its Headers are set on the response.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.RespondAPIError(c, &responsehelper.APIError{
+	//  	Status:  http.StatusNotFound,
+	//  	Code:    "USER_NOT_FOUND",
+	//  	Message: "User not found",
+	//  })
+	//
 	// Example Response Body:
 	// {
-	//	"success": true,
-	//	"data":    null,
-	//	"meta":    "2023-01-01T00:00:00Z"
+	//	"success": false,
+	//	"error": {
+	//		"code":      404,
+	//		"status":    "NOT_FOUND",
+	//		"errorCode": "USER_NOT_FOUND",
+	//		"message":   "User not found"
+	//	}
 	// }
-	NoContent(c *gin.Context)
+	RespondAPIError(c *gin.Context, err *APIError, opts ...ResponseOption)
+
+	// Respond sends a 200 OK response with data when err is nil, otherwise the error response for err
+	//
+	// An *APIError anywhere in the chain of err, eg: a sentinel like
+	// ErrNotFound.WithMessage("user not found"), is sent with RespondAPIError.
+	// An error implementing StatusCoder is sent with its status, any other
+	// error as an InternalError.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - err: The error returned by the service layer, may be nil.
+	//   - data: The data sent when err is nil.
+	//   - opts: Optional per response options, applied to error responses.
+	//
+	// Example:
+	//  user, err := h.userService.Get(id)
+	//  h.responseHelper.Respond(c, err, user)
+	Respond(c *gin.Context, err error, data interface{}, opts ...ResponseOption)
+
+	// Errors sends an error response carrying several independent errors
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - statusCode: The HTTP status code, eg: http.StatusUnprocessableEntity.
+	//   - errs: The errors to report, rendered in the given order. An empty slice is treated as an internal error.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  h.responseHelper.Errors(c, http.StatusBadRequest, []responsehelper.ErrorItem{
+	//  	{Code: "REQUIRED", Field: "name", Message: "name is required"},
+	//  	{Code: "INVALID_EMAIL", Field: "email", Message: "email is not valid"},
+	//  })
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    400,
+	//		"status":  "BAD_REQUEST",
+	//		"message": "2 errors occurred",
+	//		"errors": [
+	//			{"code": "REQUIRED", "field": "name", "message": "name is required"},
+	//			{"code": "INVALID_EMAIL", "field": "email", "message": "email is not valid"}
+	//		]
+	//	}
+	// }
+	Errors(c *gin.Context, statusCode int, errs []ErrorItem, opts ...ResponseOption)
+
+	// Problem sends an RFC 7807 "application/problem+json" response
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - status: The HTTP status code.
+	//   - typ: A URI identifying the problem type, "about:blank" when empty.
+	//   - title: A short summary of the problem type.
+	//   - detail: An explanation specific to this occurrence of the problem.
+	//   - extensions: Additional members to include, eg: "errorCode".
+	//
+	// Example:
+	//  h.responseHelper.Problem(c, http.StatusForbidden, "https://example.com/probs/out-of-credit",
+	//  	"You do not have enough credit.", "Your current balance is 30, but that costs 50.",
+	//  	map[string]interface{}{"balance": 30})
+	//
+	// Example Response Body:
+	// {
+	//	"type":     "https://example.com/probs/out-of-credit",
+	//	"title":    "You do not have enough credit.",
+	//	"status":   403,
+	//	"detail":   "Your current balance is 30, but that costs 50.",
+	//	"instance": "/account/12345/msgs/abc",
+	//	"balance":  30
+	// }
+	Problem(c *gin.Context, status int, typ, title, detail string, extensions map[string]interface{})
+
+	// WriteJSON sends body as is, for the responses the other methods do not
+	// model, eg: a body with several roots or experimental members
+	//
+	// The body is not wrapped in an envelope and gets no meta, but goes
+	// through the rest of the pipeline of the helpers: the second response
+	// guard, the rate limit, timing, API version and tenant headers, the
+	// negotiated format, signing, logging and the response hooks, which
+	// report the method "WriteJSON". A status outside 200-599 is sent as 500
+	// with a warning.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - status: The HTTP status code.
+	//   - body: The body to send.
+	//   - opts: Optional per response options, eg: responsehelper.WithCache(...).
+	//
+	// Example:
+	//  h.responseHelper.WriteJSON(c, http.StatusOK, gin.H{"users": users, "groups": groups})
+	//
+	// Example Response Body:
+	// {
+	//	"users":  [...],
+	//	"groups": [...]
+	// }
+	WriteJSON(c *gin.Context, status int, body interface{}, opts ...ResponseOption)
+
+	// WriteError sends errBody in the standard error envelope, the way the
+	// error helpers do
+	//
+	// The error helpers send their responses with it, so it applies the
+	// meta, the errorId of 5xx errors, the retryable flag, the error type
+	// and help URL, the before send hooks, problem details, logging,
+	// reporting and the response hooks, which report the method
+	// "WriteError". The status and the status name of errBody are set from
+	// status when they are empty.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - status: The HTTP status code, sent as 500 when it is not 4xx or 5xx.
+	//   - errBody: The "error" object of the envelope.
+	//   - opts: Optional per response options, eg: responsehelper.Detail("field", "email").
+	//
+	// Example:
+	//  h.responseHelper.WriteError(c, http.StatusLocked, responsehelper.ErrorBody{
+	//  	Message:   "Account is locked",
+	//  	ErrorCode: "ACCOUNT_LOCKED",
+	//  })
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":      423,
+	//		"status":    "LOCKED",
+	//		"message":   "Account is locked",
+	//		"errorCode": "ACCOUNT_LOCKED",
+	//		"retryable": false
+	//	}
+	// }
+	WriteError(c *gin.Context, status int, errBody ErrorBody, opts ...ResponseOption)
+
+	// ValidationFailed sends a 400 Bad Request response describing validation errors
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - err: The error returned by c.ShouldBind*. Errors that are not
+	//     validator.ValidationErrors are sent as a plain BadRequest with the error text.
+	//   - opts: Optional per response options, eg: responsehelper.WithErrorType("user-not-found").
+	//
+	// Example:
+	//  if err := c.ShouldBindJSON(&req); err != nil {
+	//  	h.responseHelper.ValidationFailed(c, err)
+	//  	return
+	//  }
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    400,
+	//		"status":  "BAD_REQUEST",
+	//		"message": "Validation failed",
+	//		"errors": [
+	//			{"field": "items[2].name", "tag": "required", "message": "items[2].name is required"}
+	//		]
+	//	}
+	// }
+	ValidationFailed(c *gin.Context, err error, opts ...ResponseOption)
+
+	// NotFoundKey sends a 404 Not Found response with a translated message
+	//
+	// The message is resolved from key with the translator configured by
+	// WithTranslator, using the Accept-Language header or the locale stored
+	// in the context under LocaleKey. Unknown keys are sent as is.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - key: The message key, eg: "errors.user.not_found".
+	//   - args: Arguments used to format the message.
+	//
+	// Example:
+	//  h.responseHelper.NotFoundKey(c, "errors.user.not_found", userID)
+	//
+	// Example Response Body (Accept-Language: de):
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":    404,
+	//		"status":  "NOT_FOUND",
+	//		"message": "Benutzer 42 nicht gefunden"
+	//	}
+	// }
+	NotFoundKey(c *gin.Context, key string, args ...interface{})
+
+	// BadRequestKey is BadRequest with a message translated like NotFoundKey
+	//
+	// Example:
+	//  h.responseHelper.BadRequestKey(c, "errors.request.invalid", "The 'name' field is required.")
+	BadRequestKey(c *gin.Context, key string, details string, args ...interface{})
+
+	// UnauthorizedKey is Unauthorized with a message translated like NotFoundKey
+	//
+	// Example:
+	//  h.responseHelper.UnauthorizedKey(c, "errors.auth.unauthorized")
+	UnauthorizedKey(c *gin.Context, key string, args ...interface{})
+
+	// ForbiddenKey is Forbidden with a message translated like NotFoundKey
+	//
+	// Example:
+	//  h.responseHelper.ForbiddenKey(c, "errors.auth.forbidden")
+	ForbiddenKey(c *gin.Context, key string, args ...interface{})
+
+	// ConflictKey is Conflict with a message translated like NotFoundKey
+	//
+	// Example:
+	//  h.responseHelper.ConflictKey(c, "errors.user.email_taken", err, email)
+	ConflictKey(c *gin.Context, key string, err error, args ...interface{})
+
+	// InternalErrorKey is InternalError with a message translated like NotFoundKey
+	//
+	// Example:
+	//  h.responseHelper.InternalErrorKey(c, "errors.unexpected", err)
+	InternalErrorKey(c *gin.Context, key string, err error, args ...interface{})
+
+	// RegisterCode registers a business error code for RespondCode
+	//
+	// It is safe to call concurrently with RespondCode, registering a code
+	// again replaces it.
+	//
+	// Parameters:
+	//   - code: The business error code, eg: "USER_SUSPENDED".
+	//   - status: The HTTP status code sent for the code.
+	//   - defaultMessage: The message sent for the code, may contain fmt verbs.
+	//
+	// Example:
+	//  responseHelper.RegisterCode("USER_SUSPENDED", http.StatusForbidden, "User %s is suspended")
+	RegisterCode(code string, status int, defaultMessage string)
+
+	// RespondCode sends the error response registered for a business error code
+	//
+	// Unknown codes are logged and sent as a 500 Internal Server Error.
+	//
+	// Parameters:
+	//   - c: The Gin context to send the response to.
+	//   - code: The business error code registered with RegisterCode.
+	//   - args: Arguments used to format the registered message.
+	//
+	// Example:
+	//  h.responseHelper.RespondCode(c, "USER_SUSPENDED", user.Name)
+	//
+	// Example Response Body:
+	// {
+	//	"success": false,
+	//	"error": {
+	//		"code":      403,
+	//		"status":    "FORBIDDEN",
+	//		"message":   "User arun is suspended",
+	//		"errorCode": "USER_SUSPENDED"
+	//	}
+	// }
+	RespondCode(c *gin.Context, code string, args ...interface{})
+
+	// PrecomputeError registers an error response whose body is marshalled once
+	//
+	// Errors sent with the status and the message afterwards reuse the JSON of
+	// the first one instead of marshalling it again, as long as they have no
+	// meta, details, errors or other member that changes per response. Those
+	// are rendered as usual, so are 5xx errors, which carry an errorId, and
+	// responses sent in another format than JSON. It is safe to call
+	// concurrently with the other methods.
+	//
+	// Parameters:
+	//   - status: The HTTP status code of the error.
+	//   - message: The message of the error.
+	//
+	// Example:
+	//  responseHelper.PrecomputeError(http.StatusNotFound, "resource not found")
+	PrecomputeError(status int, message string)
 }
 
 // Response helper - centralizes response logic
 // The context is same in the case of all the responses , but there is no need to , group it in a struct
 // only one response per request , so there is no reuse for context.
-type responseHelper struct{}
+type responseHelper struct {
+	*Core
+}
 
-func NewResponseHelper() ResponseHelper {
-	return &responseHelper{}
+// NewResponseHelper creates a ResponseHelper, optionally customised with
+// Options. It keeps the behaviour of earlier versions, see New for the
+// recommended defaults.
+//
+// Example:
+//
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true))
+func NewResponseHelper(opts ...Option) ResponseHelper {
+	return &responseHelper{Core: NewCore(opts...)}
 }
 
-func (r *responseHelper) BadRequest(c *gin.Context, message string, details string) {
+// New creates a ResponseHelper with the recommended defaults, optionally
+// customised with Options applied after them. The defaults are:
+//
+//   - WithEmptyCollections(true)
+//
+// Example:
+//
+//	responseHelper := responsehelper.New(responsehelper.WithErrorSanitization(true))
+func New(opts ...Option) ResponseHelper {
+	return NewResponseHelper(append([]Option{WithEmptyCollections(true)}, opts...)...)
+}
 
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusBadRequest, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    400,
-			"status":  "BAD_REQUEST",
-			"message": message,
-			"details": details,
-		},
-		"meta": meta,
-	})
+func (r *Core) BadRequest(c Exchange, message string, details string, opts ...ResponseOption) {
+	opts = helperCall(opts, "BadRequest", nil)
+	r.BadRequestDetails(c, message, details, opts...)
+}
+
+func (r *Core) BadRequestDetails(c Exchange, message string, details interface{}, opts ...ResponseOption) {
+	opts = helperCall(opts, "BadRequestDetails", nil)
+	r.respondError(c, http.StatusBadRequest, ErrorBody{
+		Code:    400,
+		Status:  "BAD_REQUEST",
+		Message: message,
+		Details: details,
+	}, opts...)
 }
 
-func (r *responseHelper) AlreadyExists(c *gin.Context, resource string, err error) {
-	r.Conflict(c, resource+" already exists", err)
+func (r *Core) AlreadyExists(c Exchange, resource string, err error, opts ...ResponseOption) {
+	opts = helperCall(opts, "AlreadyExists", err)
+	r.Conflict(c, resource+" already exists", err, opts...)
 }
 
-func (r *responseHelper) Conflict(c *gin.Context, message string, err error) {
-	meta, _ := c.Get("meta")
+func (r *Core) Conflict(c Exchange, message string, err error, opts ...ResponseOption) {
+	opts = helperCall(opts, "Conflict", err)
+	errorBody := ErrorBody{
+		Code:    409,
+		Status:  "CONFLICT",
+		Message: message,
+	}
+	if details, ok := r.errorDetails(err); ok {
+		errorBody.Details = details
+	}
+	r.addErrorCauses(&errorBody, err)
+	r.respondError(c, http.StatusConflict, errorBody, opts...)
+}
 
-	c.JSON(http.StatusConflict, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    409,
-			"status":  "CONFLICT",
-			"message": message,
-			"details": err.Error(),
-		},
-		"meta": meta,
-	})
+func (r *Core) NotFound(c Exchange, message string, opts ...ResponseOption) {
+	opts = helperCall(opts, "NotFound", nil)
+	r.respondError(c, http.StatusNotFound, ErrorBody{
+		Code:    404,
+		Status:  "NOT_FOUND",
+		Message: message,
+	}, opts...)
 }
 
-func (r *responseHelper) NotFound(c *gin.Context, message string) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusNotFound, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    404,
-			"status":  "NOT_FOUND",
-			"message": message,
-		},
-		"meta": meta,
-	})
+func (r *Core) Unauthorized(c Exchange, message string, opts ...ResponseOption) {
+	opts = helperCall(opts, "Unauthorized", nil)
+	r.respondError(c, http.StatusUnauthorized, ErrorBody{
+		Code:    401,
+		Status:  "UNAUTHORIZED",
+		Message: message,
+	}, opts...)
 }
 
-func (r *responseHelper) Unauthorized(c *gin.Context, message string) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusUnauthorized, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    401,
-			"status":  "UNAUTHORIZED",
-			"message": message,
+func (r *Core) InternalError(c Exchange, message string, err error, opts ...ResponseOption) {
+	opts = helperCall(opts, "InternalError", err)
+	// There is a possibility of leaking information through error messages,
+	// so the details are dropped when sanitization is enabled.
+	envelope := ErrorEnvelope{
+		Data: jsonNull,
+		Error: ErrorBody{
+			Code:    500,
+			Status:  "INTERNAL_SERVER_ERROR",
+			Message: message,
 		},
-		"meta": meta,
-	})
+	}
+	if details, ok := r.errorDetails(err); ok {
+		envelope.Error.Details = details
+	}
+	r.addErrorCauses(&envelope.Error, err)
+	r.renderError(c, http.StatusInternalServerError, envelope, opts...)
 }
 
-func (r *responseHelper) InternalError(c *gin.Context, message string, err error) {
-	meta, _ := c.Get("meta")
-	// Check if sanitization of error is needed,
-	/*
-		1. There is a possibility of leaking information through error messages.
-	*/
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    500,
-			"status":  "INTERNAL_SERVER_ERROR",
-			"message": message,
-			"details": err.Error(), // sanitizing this in production
-		},
-		"data": nil,
-		"meta": meta,
-	})
+func (r *Core) Success(c Exchange, data interface{}, opts ...ResponseOption) {
+	r.renderSuccess(c, "Success", http.StatusOK, SuccessEnvelope{
+		Data:    nullable(data),
+		Success: true,
+	}, opts...)
 }
 
-func (r *responseHelper) Success(c *gin.Context, data interface{}) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    data,
-		"meta":    meta,
-	})
+func (r *Core) SuccessWithPagination(c Exchange, data interface{}, paginationMeta interface{}, opts ...ResponseOption) {
+	pagination := paginationValue(paginationMeta)
+	if p, ok := pagination.(Pagination); ok {
+		r.setPaginationLinks(c, p)
+		r.setCountHeaders(c, p)
+	}
+	r.renderSuccess(c, "SuccessWithPagination", http.StatusOK, SuccessEnvelope{
+		Data:       nullable(data),
+		Pagination: r.nullField(pagination),
+		Success:    true,
+	}, opts...)
 }
 
-func (r *responseHelper) SuccessWithPagination(c *gin.Context, data interface{}, paginationMeta interface{}) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"data":       data,
-		"pagination": paginationMeta,
-		"meta":       meta,
-	})
+func (r *Core) Created(c Exchange, data interface{}, opts ...ResponseOption) {
+	envelope := SuccessEnvelope{
+		Data:    nullable(data),
+		Success: true,
+	}
+	if options := newResponseOptions(opts); options.location != "" {
+		location := r.resolveLink(options.location)
+		setHeader(c, "Location", location)
+		envelope.Links = Links{"self": location}
+	}
+	r.renderSuccess(c, "Created", http.StatusCreated, envelope, opts...)
 }
 
-func (r *responseHelper) Created(c *gin.Context, data interface{}) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"data":    data,
-		"meta":    meta,
+func (r *Core) Deleted(c Exchange, message string) {
+	if r.deleteStatus == http.StatusNoContent {
+		r.renderEmpty(c, "Deleted", http.StatusNoContent)
+		return
+	}
+	r.renderSuccess(c, "Deleted", http.StatusOK, SuccessEnvelope{
+		Message: message + " deleted successfully",
+		Success: true,
 	})
 }
+func (r *Core) Forbidden(c Exchange, message string, opts ...ResponseOption) {
+	opts = helperCall(opts, "Forbidden", nil)
+	r.respondError(c, http.StatusForbidden, ErrorBody{
+		Code:    403,
+		Status:  "FORBIDDEN",
+		Message: message,
+	}, opts...)
+}
 
-func (r *responseHelper) Deleted(c *gin.Context, message string) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": message + " deleted successfully",
-		"meta":    meta,
-	})
+func (r *Core) TooManyRequests(c Exchange, message string, retryAfter time.Duration, opts ...ResponseOption) {
+	opts = helperCall(opts, "TooManyRequests", nil)
+	if retryAfter > 0 {
+		setHeader(c, RetryAfterHeader, strconv.FormatInt(ceilSeconds(retryAfter), 10))
+	}
+	r.respondError(c, http.StatusTooManyRequests, ErrorBody{
+		Code:    429,
+		Status:  "TOO_MANY_REQUESTS",
+		Message: message,
+	}, opts...)
 }
-func (r *responseHelper) Forbidden(c *gin.Context, message string) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusForbidden, gin.H{
-		"success": false,
-		"error": gin.H{
-			"code":    403,
-			"status":  "FORBIDDEN",
-			"message": message,
-		},
-		"meta": meta,
+
+func (r *Core) ServiceUnavailable(c Exchange, message string, retryAfter time.Duration, opts ...ResponseOption) {
+	opts = helperCall(opts, "ServiceUnavailable", nil)
+	if retryAfter > 0 {
+		setHeader(c, RetryAfterHeader, strconv.FormatInt(ceilSeconds(retryAfter), 10))
+	}
+	r.respondError(c, http.StatusServiceUnavailable, ErrorBody{
+		Code:    503,
+		Status:  "SERVICE_UNAVAILABLE",
+		Message: message,
+	}, opts...)
+}
+
+func (r *Core) Errors(c Exchange, statusCode int, errs []ErrorItem, opts ...ResponseOption) {
+	opts = helperCall(opts, "Errors", nil)
+	if len(errs) == 0 {
+		r.InternalError(c, "An unexpected error occurred", errors.New("responsehelper: Errors called with no errors"), opts...)
+		return
+	}
+	message := strconv.Itoa(len(errs)) + " errors occurred"
+	if len(errs) == 1 {
+		message = "1 error occurred"
+	}
+	status := r.validErrorStatus(statusCode, "Errors")
+	r.respondError(c, status, ErrorBody{
+		Code:    status,
+		Status:  statusText(status),
+		Message: message,
+		Errors:  errs,
+	}, opts...)
+}
+
+func (r *Core) DeletedNoContent(c Exchange) {
+	r.renderEmpty(c, "DeletedNoContent", http.StatusNoContent)
+}
+
+func (r *Core) NoContent(c Exchange) {
+	r.renderEmpty(c, "NoContent", http.StatusNoContent)
+}
+
+// renderEmpty writes a response without a body sent by method, eg: 204 No
+// Content or 304 Not Modified. It has no Content-Type, the meta is left to
+// the headers.
+func (r *Core) renderEmpty(c Exchange, method string, status int) {
+	if r.secondResponse(c, method) {
+		return
+	}
+	r.writeResponse(c, sentResponse{status: status, options: responseOptions{method: method}}, nil)
+}
+
+// renderSuccess adds the meta to a success envelope, unless the method set
+// it, and writes it.
+func (r *Core) renderSuccess(c Exchange, method string, status int, body SuccessEnvelope, opts ...ResponseOption) {
+	if r.secondResponse(c, method) {
+		return
+	}
+	status = r.validSuccessStatus(status, method)
+	if r.clientGone(c) {
+		r.skipWrite(c, sentResponse{status: status, options: responseOptions{method: method}})
+		return
+	}
+	envelope := r.acquireSuccessEnvelope(body)
+	defer r.releaseSuccessEnvelope(envelope)
+	if r.invalidRawJSON(envelope.Data) {
+		r.InternalError(c, "An unexpected error occurred", errInvalidRawJSON)
+		return
+	}
+	if envelope.Data != nil {
+		envelope.Data = r.fillEmptyCollections(envelope.Data)
+		if !r.jsonapiFormat(c) {
+			// JSON:API documents carry the links in the resource objects
+			envelope.Data = r.linkedData(envelope.Data)
+			var ok bool
+			if envelope.Data, ok = r.sparseData(c, method, envelope.Data); !ok {
+				return
+			}
+		}
+	}
+	if envelope.Meta == nil {
+		envelope.Meta = r.successMeta(c, method)
+	}
+	options := newResponseOptions(helperCall(opts, method, nil))
+	envelope.Meta = r.callMeta(envelope.Meta, options.meta)
+	envelope.Warnings = responseWarnings(c, options.warnings)
+	status, ok := r.runBeforeSend(c, &OutgoingResponse{Status: status, Success: envelope})
+	if !ok {
+		return
+	}
+	if !r.withinResponseLimit(c, method, envelope) {
+		return
+	}
+	r.writeResponse(c, sentResponse{status: status, options: options}, func(c Exchange) {
+		r.writeBody(c, status, envelope)
 	})
 }
 
-func (r *responseHelper) NoContent(c *gin.Context) {
-	meta, _ := c.Get("meta")
-	c.JSON(http.StatusNoContent, gin.H{
-		"success": true,
-		"data":    nil,
-		"meta":    meta,
+// respondError writes the standard error envelope around errorBody with
+// WriteError.
+func (r *Core) respondError(c Exchange, status int, errorBody ErrorBody, opts ...ResponseOption) {
+	r.WriteError(c, status, errorBody, opts...)
+}
+
+// renderError adds the meta to an error envelope and writes it, or writes
+// the equivalent problem details when WithProblemDetails is enabled.
+func (r *Core) renderError(c Exchange, status int, body ErrorEnvelope, opts ...ResponseOption) {
+	options := newResponseOptions(opts)
+	if r.secondResponse(c, options.method) {
+		return
+	}
+	envelope := r.acquireErrorEnvelope(body)
+	defer r.releaseErrorEnvelope(envelope)
+	errorBody := &envelope.Error
+	if valid := r.validErrorStatus(status, options.method); valid != status {
+		status, errorBody.Code, errorBody.Status = valid, valid, statusText(valid)
+	}
+	r.scrubDBErrors(errorBody, options.err)
+	errorBody.Message = r.truncateMessage(errorBody.Message)
+	meta := r.echoMeta(c, status, r.responseMeta(c))
+	if status >= http.StatusInternalServerError {
+		// give clients something to quote when they report a server error
+		errorID := errorID(c)
+		errorBody.ErrorID = errorID
+		setHeader(c, ErrorIDHeader, errorID)
+	}
+	if errorBody.Details == "" && !r.nullFields {
+		errorBody.Details = nil
+	}
+	errorBody.Retryable = options.isRetryable(status)
+	errorBody.Context = options.errorContext(errorBody.Context)
+	if errorType, ok := r.errorTypeURI(status, options.errorType); ok {
+		errorBody.Type = errorType
+	}
+	if helpURL := r.helpURL(status, errorBody, options.helpURL); helpURL != "" {
+		errorBody.HelpURL = helpURL
+	}
+	envelope.Meta = meta
+	sent, ok := r.runBeforeSend(c, &OutgoingResponse{Status: status, Error: envelope})
+	if !ok {
+		return
+	}
+	if sent != status && errorBody.Code == status {
+		errorBody.Code, errorBody.Status = sent, statusText(sent)
+	}
+	status, meta = sent, envelope.Meta
+	response := sentResponse{status: status, options: options, errorCode: errorBody.ErrorCode, message: errorBody.Message}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	r.writeResponse(c, response, func(c Exchange) {
+		if r.prefersHTML(c) {
+			r.writeErrorPage(c, status, errorBody)
+		} else if r.problemDetails {
+			r.renderProblem(c, status, problemFromError(c, status, errorBody, meta))
+		} else {
+			envelope.Meta = meta
+			if !r.writePrecomputedError(c, status, envelope) {
+				r.writeBody(c, status, envelope)
+			}
+		}
 	})
 }
@@ -0,0 +1,224 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newContext returns a gin context answering a request for target, and the
+// recorder of its response.
+func newContext(method, target string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, nil)
+	return c, w
+}
+
+// decodeBody decodes the JSON body of w.
+func decodeBody(t testing.TB, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return body
+}
+
+// lookup returns the member of the JSON body of w at path, its keys joined
+// with dots and array indexes given as numbers, and whether it exists.
+func lookup(w *httptest.ResponseRecorder, path string) (interface{}, bool) {
+	var value interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &value); err != nil {
+		return nil, false
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			member, ok := typed[key]
+			if !ok {
+				return nil, false
+			}
+			value = member
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			value = typed[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// assertField checks the member of the body of w at path, comparing want
+// with it as JSON so that 42 matches 42.0.
+func assertField(t testing.TB, w *httptest.ResponseRecorder, path string, want interface{}) {
+	t.Helper()
+	got, ok := lookup(w, path)
+	if !ok {
+		t.Errorf("%s is missing\nbody: %s", path, w.Body)
+		return
+	}
+	encoded, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("%s: encoding %#v: %v", path, want, err)
+	}
+	var wantJSON interface{}
+	if err := json.Unmarshal(encoded, &wantJSON); err != nil {
+		t.Fatalf("%s: decoding %s: %v", path, encoded, err)
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		t.Errorf("%s = %v, want %v\nbody: %s", path, got, wantJSON, w.Body)
+	}
+}
+
+// assertError checks that w is an error envelope with status, and with
+// message unless it is empty.
+func assertError(t testing.TB, w *httptest.ResponseRecorder, status int, message string) {
+	t.Helper()
+	if w.Code != status {
+		t.Errorf("status = %d, want %d\nbody: %s", w.Code, status, w.Body)
+	}
+	body := decodeBody(t, w)
+	if body["success"] != false {
+		t.Errorf("success = %v, want false\nbody: %s", body["success"], w.Body)
+	}
+	assertField(t, w, "error.code", status)
+	if message != "" {
+		assertField(t, w, "error.message", message)
+	}
+}
+
+// assertSuccess checks that w is a 2xx success envelope and returns its
+// data when it is an object.
+func assertSuccess(t testing.TB, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	if w.Code < 200 || w.Code > 299 {
+		t.Fatalf("status = %d, want 2xx\nbody: %s", w.Code, w.Body)
+	}
+	body := decodeBody(t, w)
+	if body["success"] != true {
+		t.Fatalf("success = %v, want true\nbody: %s", body["success"], w.Body)
+	}
+	data, _ := body["data"].(map[string]interface{})
+	return data
+}
+
+func TestErrorsKeepsTheOrder(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{
+		{Code: "REQUIRED", Field: "name", Message: "name is required"},
+		{Code: "INVALID_EMAIL", Field: "email", Message: "email is not valid"},
+		{Code: "TOO_SHORT", Field: "password", Message: "password is too short"},
+	})
+
+	assertError(t, w, http.StatusUnprocessableEntity, "3 errors occurred")
+	assertField(t, w, "error.status", "UNPROCESSABLE_ENTITY")
+	errs, _ := decodeBody(t, w)["error"].(map[string]interface{})["errors"].([]interface{})
+	wantFields := []string{"name", "email", "password"}
+	if len(errs) != len(wantFields) {
+		t.Fatalf("len(errors) = %d, want %d\nbody: %s", len(errs), len(wantFields), w.Body)
+	}
+	for i, field := range wantFields {
+		if got := errs[i].(map[string]interface{})["field"]; got != field {
+			t.Errorf("errors[%d].field = %v, want %q", i, got, field)
+		}
+	}
+}
+
+func TestErrorsSingleError(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().Errors(c, http.StatusBadRequest, []responsehelper.ErrorItem{
+		{Field: "name", Message: "name is required"},
+	})
+
+	assertError(t, w, http.StatusBadRequest, "1 error occurred")
+	assertField(t, w, "error.errors.0.message", "name is required")
+}
+
+func TestErrorsWithoutErrorsIsAnInternalError(t *testing.T) {
+	for name, errs := range map[string][]responsehelper.ErrorItem{
+		"nil":   nil,
+		"empty": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodPost, "/users")
+			responsehelper.NewResponseHelper(responsehelper.WithErrorSanitization(true)).Errors(c, http.StatusBadRequest, errs)
+
+			assertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+			if _, ok := decodeBody(t, w)["error"].(map[string]interface{})["errors"]; ok {
+				t.Errorf("the body has errors\nbody: %s", w.Body)
+			}
+		})
+	}
+}
+
+func TestErrorsInvalidStatus(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().Errors(c, http.StatusOK, []responsehelper.ErrorItem{{Message: "boom"}})
+
+	assertError(t, w, http.StatusInternalServerError, "1 error occurred")
+}
+
+func TestBadRequestDetails(t *testing.T) {
+	type violation struct {
+		Field  string `json:"field"`
+		Reason string `json:"reason"`
+	}
+	for name, tc := range map[string]struct {
+		details interface{}
+		want    interface{}
+	}{
+		"struct": {violation{Field: "email", Reason: "taken"}, map[string]interface{}{"field": "email", "reason": "taken"}},
+		"map":    {map[string]int{"min": 3}, map[string]interface{}{"min": 3}},
+		"slice":  {[]string{"a", "b"}, []string{"a", "b"}},
+		"string": {"name is missing", "name is missing"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodPost, "/users")
+			responsehelper.NewResponseHelper().BadRequestDetails(c, "Invalid user", tc.details)
+
+			assertError(t, w, http.StatusBadRequest, "Invalid user")
+			assertField(t, w, "error.details", tc.want)
+		})
+	}
+}
+
+func TestBadRequestDetailsNil(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().BadRequestDetails(c, "Invalid user", nil)
+
+	if details, ok := decodeBody(t, w)["error"].(map[string]interface{})["details"]; ok {
+		t.Errorf("error.details = %v, want no details", details)
+	}
+}
+
+func TestBadRequestStringDetails(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().BadRequest(c, "Invalid user", "name is missing")
+
+	assertError(t, w, http.StatusBadRequest, "Invalid user")
+	assertField(t, w, "error.details", "name is missing")
+
+	c, w = newContext(http.MethodPost, "/users")
+	responsehelper.NewResponseHelper().BadRequest(c, "Invalid user", "")
+	if details, ok := decodeBody(t, w)["error"].(map[string]interface{})["details"]; ok {
+		t.Errorf("error.details = %v, want no details", details)
+	}
+}
@@ -0,0 +1,221 @@
+package responsehelpertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files instead of comparing with them. It is
+// registered by this package, a test package importing it must not declare
+// its own -update flag.
+var update = flag.Bool("update", false, "rewrite the golden files of responsehelpertest.Golden")
+
+// VolatilePlaceholder replaces the volatile members in golden files.
+const VolatilePlaceholder = "<volatile>"
+
+// defaultVolatile are the members that change on every request.
+var defaultVolatile = []string{
+	"meta.timestamp",
+	"meta.requestId",
+	"meta.durationMs",
+	"meta.commit",
+	"error.errorId",
+}
+
+// GoldenOption configures Golden.
+type GoldenOption func(*goldenConfig)
+
+type goldenConfig struct {
+	// volatile are the paths of the members replaced with VolatilePlaceholder.
+	volatile []string
+	// noDefaults leaves out defaultVolatile.
+	noDefaults bool
+}
+
+// WithVolatile adds the members at paths to the volatile ones replaced with
+// VolatilePlaceholder, next to the timestamp, the request ID, the duration
+// and the error ID. Paths are written like the ones of AssertField, a "*"
+// matches every member of an object or every item of an array.
+//
+// Example:
+//
+//	responsehelpertest.Golden(t, w, "testdata/users.json", responsehelpertest.WithVolatile("data.*.createdAt"))
+func WithVolatile(paths ...string) GoldenOption {
+	return func(cfg *goldenConfig) {
+		cfg.volatile = append(cfg.volatile, paths...)
+	}
+}
+
+// WithoutDefaultVolatile leaves the members Golden replaces by default as
+// they are, so only the ones of WithVolatile are replaced.
+func WithoutDefaultVolatile() GoldenOption {
+	return func(cfg *goldenConfig) {
+		cfg.noDefaults = true
+	}
+}
+
+// Golden compares the body of the response with the golden file at path, so
+// changes of the envelope fail the test. The body is indented with its keys
+// sorted and the volatile members replaced, see WithVolatile. Run the tests
+// with -update to write the files, their directories are created.
+//
+// Example:
+//
+//	w := httptest.NewRecorder()
+//	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/404", nil))
+//	responsehelpertest.Golden(t, w, "testdata/user_not_found.json")
+func Golden(t testing.TB, recorder *httptest.ResponseRecorder, path string, opts ...GoldenOption) {
+	t.Helper()
+	cfg := &goldenConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	volatile := cfg.volatile
+	if !cfg.noDefaults {
+		volatile = append(defaultVolatile[:len(defaultVolatile):len(defaultVolatile)], volatile...)
+	}
+	got, err := canonical(recorder.Body.Bytes(), volatile)
+	if err != nil {
+		t.Fatalf("the body is not JSON: %v\nbody: %s", err, recorder.Body)
+	}
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating the directory of %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("writing %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("%s does not exist, run the test with -update to create it\nbody: %s", path, got)
+	}
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("the body differs from %s, run the test with -update to accept it\n%s", path, diffLines(string(want), string(got)))
+	}
+}
+
+// canonical returns body indented with sorted keys, the members at volatile
+// replaced.
+func canonical(body []byte, volatile []string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	for _, path := range volatile {
+		value = replaceVolatile(value, strings.Split(path, "."))
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// replaceVolatile returns value with the members at keys replaced with
+// VolatilePlaceholder. Missing members are left out.
+func replaceVolatile(value interface{}, keys []string) interface{} {
+	if len(keys) == 0 {
+		return VolatilePlaceholder
+	}
+	key, rest := keys[0], keys[1:]
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for name, member := range typed {
+			if key == "*" || key == name {
+				typed[name] = replaceVolatile(member, rest)
+			}
+		}
+	case []interface{}:
+		for i, item := range typed {
+			if key == "*" || key == strconv.Itoa(i) {
+				typed[i] = replaceVolatile(item, rest)
+			}
+		}
+	}
+	return value
+}
+
+// diffContext is the number of unchanged lines shown around the changes.
+const diffContext = 2
+
+// diffLines returns the lines of want and got that differ, prefixed with "-"
+// and "+", around a few unchanged lines.
+func diffLines(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+	type line struct {
+		prefix byte
+		text   string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+	var out strings.Builder
+	skipped := false
+	for k, l := range lines {
+		near := false
+		for n := max(k-diffContext, 0); n <= min(k+diffContext, len(lines)-1); n++ {
+			if lines[n].prefix != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			if !skipped {
+				out.WriteString("  ...\n")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		fmt.Fprintf(&out, "%c %s\n", l.prefix, l.text)
+	}
+	return out.String()
+}
@@ -0,0 +1,189 @@
+package responsehelpertest_test
+
+import (
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper/responsehelpertest"
+)
+
+// recorded returns a recorder holding body.
+func recorded(body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	w.Body.WriteString(body)
+	return w
+}
+
+// updating runs the test with -update set.
+func updating(t *testing.T) {
+	t.Helper()
+	if err := flag.Set("update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = flag.Set("update", "false") })
+}
+
+// writeGolden writes content to a golden file in a temporary directory and
+// returns its path.
+func writeGolden(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "golden.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const userNotFound = `{"success":false,"error":{"code":"NOT_FOUND","message":"user not found","errorId":"e-81f3"},` +
+	`"meta":{"timestamp":"2024-05-01T12:00:00Z","requestId":"req-1","path":"/users/7","durationMs":1.25}}`
+
+func TestGoldenUpdateCreatesTheDirectories(t *testing.T) {
+	updating(t)
+	path := filepath.Join(t.TempDir(), "testdata", "users", "not_found.json")
+
+	if ft := check(func(t testing.TB) { responsehelpertest.Golden(t, recorded(userNotFound), path) }); len(ft.failures) > 0 {
+		t.Fatalf("Golden with -update failed: %v", ft.failures)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "error": {
+    "code": "NOT_FOUND",
+    "errorId": "<volatile>",
+    "message": "user not found"
+  },
+  "meta": {
+    "durationMs": "<volatile>",
+    "path": "/users/7",
+    "requestId": "<volatile>",
+    "timestamp": "<volatile>"
+  },
+  "success": false
+}
+`
+	if string(got) != want {
+		t.Errorf("golden file =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGoldenMatchesWithOtherVolatileValues(t *testing.T) {
+	updating(t)
+	path := filepath.Join(t.TempDir(), "not_found.json")
+	responsehelpertest.Golden(t, recorded(userNotFound), path)
+	_ = flag.Set("update", "false")
+
+	other := strings.NewReplacer("req-1", "req-2", "e-81f3", "e-0000", "1.25", "9").Replace(userNotFound)
+	if ft := check(func(t testing.TB) { responsehelpertest.Golden(t, recorded(other), path) }); len(ft.failures) > 0 {
+		t.Errorf("Golden failed on volatile members only: %v", ft.failures)
+	}
+}
+
+func TestGoldenNormalizesNestedVolatileMembers(t *testing.T) {
+	path := writeGolden(t, `{
+  "data": [
+    {
+      "audit": {
+        "createdAt": "<volatile>",
+        "createdBy": "arun"
+      },
+      "id": 1
+    },
+    {
+      "audit": {
+        "createdAt": "<volatile>",
+        "createdBy": "dev"
+      },
+      "id": 2
+    }
+  ],
+  "meta": {
+    "requestId": "req-1"
+  }
+}
+`)
+	body := `{"meta":{"requestId":"req-1"},"data":[` +
+		`{"id":1,"audit":{"createdBy":"arun","createdAt":"2024-05-01T12:00:00Z"}},` +
+		`{"id":2,"audit":{"createdBy":"dev","createdAt":"2024-05-02T08:30:00Z"}}]}`
+
+	ft := check(func(t testing.TB) {
+		responsehelpertest.Golden(t, recorded(body), path,
+			responsehelpertest.WithVolatile("data.*.audit.createdAt"), responsehelpertest.WithoutDefaultVolatile())
+	})
+	if len(ft.failures) > 0 {
+		t.Errorf("Golden failed: %v", ft.failures)
+	}
+}
+
+func TestGoldenVolatilePaths(t *testing.T) {
+	body := `{"data":{"items":[{"at":1},{"at":2}],"at":3},"meta":{"timestamp":"now"}}`
+	for _, tt := range []struct {
+		name string
+		opts []responsehelpertest.GoldenOption
+		want string
+	}{
+		{"an index", []responsehelpertest.GoldenOption{responsehelpertest.WithVolatile("data.items.1.at")},
+			`{"data":{"at":3,"items":[{"at":1},{"at":"<volatile>"}]},"meta":{"timestamp":"<volatile>"}}`},
+		{"a wildcard member", []responsehelpertest.GoldenOption{responsehelpertest.WithVolatile("data.*")},
+			`{"data":{"at":"<volatile>","items":"<volatile>"},"meta":{"timestamp":"<volatile>"}}`},
+		{"a missing member", []responsehelpertest.GoldenOption{responsehelpertest.WithVolatile("data.items.5.at", "links.self")},
+			`{"data":{"at":3,"items":[{"at":1},{"at":2}]},"meta":{"timestamp":"<volatile>"}}`},
+		{"without the defaults", []responsehelpertest.GoldenOption{responsehelpertest.WithoutDefaultVolatile()},
+			`{"data":{"at":3,"items":[{"at":1},{"at":2}]},"meta":{"timestamp":"now"}}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// the golden file is written indented from the expected body
+			path := filepath.Join(t.TempDir(), "golden.json")
+			updating(t)
+			responsehelpertest.Golden(t, recorded(tt.want), path, responsehelpertest.WithoutDefaultVolatile())
+			_ = flag.Set("update", "false")
+
+			if ft := check(func(t testing.TB) { responsehelpertest.Golden(t, recorded(body), path, tt.opts...) }); len(ft.failures) > 0 {
+				t.Errorf("Golden failed: %v", ft.failures)
+			}
+		})
+	}
+}
+
+func TestGoldenSortsTheKeys(t *testing.T) {
+	path := writeGolden(t, "{\n  \"a\": 1,\n  \"b\": {\n    \"c\": \"<p>\",\n    \"d\": 12345678901234567890\n  }\n}\n")
+	body := `{"b":{"d":12345678901234567890,"c":"<p>"},"a":1}`
+	if ft := check(func(t testing.TB) { responsehelpertest.Golden(t, recorded(body), path) }); len(ft.failures) > 0 {
+		t.Errorf("Golden failed: %v", ft.failures)
+	}
+}
+
+func TestGoldenDiffsOnMismatch(t *testing.T) {
+	path := writeGolden(t, "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3,\n  \"d\": 4,\n  \"e\": 5,\n  \"f\": 6,\n  \"g\": 7\n}\n")
+	ft := check(func(t testing.TB) {
+		responsehelpertest.Golden(t, recorded(`{"a":1,"b":2,"c":3,"d":4,"e":50,"f":6,"g":7}`), path)
+	})
+	if ft.fatal || len(ft.failures) != 1 {
+		t.Fatalf("failures = %v, fatal = %t, want one error", ft.failures, ft.fatal)
+	}
+	want := "  ...\n    \"c\": 3,\n    \"d\": 4,\n-   \"e\": 5,\n+   \"e\": 50,\n    \"f\": 6,\n    \"g\": 7\n  ...\n"
+	if !strings.Contains(ft.failures[0], "run the test with -update") || !strings.HasSuffix(ft.failures[0], want) {
+		t.Errorf("failure =\n%s\nwant the diff\n%s", ft.failures[0], want)
+	}
+}
+
+func TestGoldenFailures(t *testing.T) {
+	for name, tt := range map[string]struct {
+		body, path, want string
+	}{
+		"missing file": {`{"a":1}`, filepath.Join(t.TempDir(), "missing.json"), "does not exist, run the test with -update"},
+		"not JSON":     {"plain text", writeGolden(t, "{}\n"), "the body is not JSON"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ft := check(func(t testing.TB) { responsehelpertest.Golden(t, recorded(tt.body), tt.path) })
+			if !ft.fatal || len(ft.failures) != 1 || !strings.Contains(ft.failures[0], tt.want) {
+				t.Errorf("failures = %v, fatal = %t, want %q", ft.failures, ft.fatal, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package responsehelpertest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var _ responsehelper.ResponseHelper = (*Recorder)(nil)
+
+// errNilDataFn is the error recorded for SuccessIfModified without a dataFn,
+// the one the helpers send.
+var errNilDataFn = errors.New("responsehelper: SuccessIfModified called with a nil dataFn")
+
+// Call is a response sent through a Recorder.
+type Call struct {
+	// Method is the ResponseHelper method called, eg: "NotFound".
+	Method string
+	// Status is the HTTP status the method sends.
+	Status int
+	// Message is the message of an error, or of Deleted, and the resource of
+	// Patched and ResourceNotFound.
+	Message string
+	// Key and Args are the message key and its arguments of the *Key methods.
+	Key  string
+	Args []interface{}
+	// Code is the business error code of RespondCode, RespondAPIError and
+	// WriteError, the reason of UnauthorizedReason or the error code of
+	// OAuthError.
+	Code string
+	// Details are the details of an error, the changed fields of Patched, the
+	// identifier of ResourceNotFound or the failures of PartialSuccess.
+	Details interface{}
+	// Data is the data of a success response, the rows of SuccessCSV.
+	Data interface{}
+	// Pagination is the pagination of SuccessWithPagination and SuccessWithCursor.
+	Pagination interface{}
+	// Err is the error passed to the method, if any.
+	Err error
+}
+
+// Recorder is a ResponseHelper recording the responses handlers send, so
+// handler tests can check them without decoding bodies. The zero value is
+// ready to use and writes only the status of each response, so handlers
+// checking c.Writer keep working. With Helper set, the responses are
+// written by it instead. It is safe for concurrent use.
+//
+// Example:
+//
+//	recorder := &responsehelpertest.Recorder{}
+//	handler := NewUserHandler(recorder)
+//	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+//	handler.Get(c)
+//	if !recorder.CalledOnceWith("NotFound") { ... }
+type Recorder struct {
+	// Helper writes the responses, only their status is written when nil.
+	Helper responsehelper.ResponseHelper
+
+	mu    sync.Mutex
+	calls []Call
+	codes map[string]registeredCode
+}
+
+// registeredCode is a code registered with RegisterCode.
+type registeredCode struct {
+	status  int
+	message string
+}
+
+// Calls returns the recorded calls, oldest first.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Last returns the latest recorded call, and false when there is none.
+func (r *Recorder) Last() (Call, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.calls) == 0 {
+		return Call{}, false
+	}
+	return r.calls[len(r.calls)-1], true
+}
+
+// Count returns the number of recorded calls of method.
+func (r *Recorder) Count(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, call := range r.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// CalledOnceWith reports whether exactly one response was sent and it was
+// sent with method.
+func (r *Recorder) CalledOnceWith(method string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls) == 1 && r.calls[0].Method == method
+}
+
+// Reset forgets the recorded calls, eg: between the cases of a table test.
+// Registered codes are kept.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// record records call and writes its response with write, or its status.
+func (r *Recorder) record(c *gin.Context, call Call, write func(h responsehelper.ResponseHelper)) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+	if r.Helper != nil {
+		write(r.Helper)
+		return
+	}
+	if c != nil && c.Writer != nil && !c.Writer.Written() {
+		c.Status(call.Status)
+		c.Writer.WriteHeaderNow()
+	}
+}
+
+// errorStatus returns status, or 500 when it is not an error status.
+func errorStatus(status int) int {
+	if status < 400 || status > 599 {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// Deprecated: use BadRequestDetails.
+func (r *Recorder) BadRequest(c *gin.Context, message string, details string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "BadRequest", Status: http.StatusBadRequest, Message: message, Details: details}, func(h responsehelper.ResponseHelper) {
+		h.BadRequest(c, message, details, opts...)
+	})
+}
+
+func (r *Recorder) BadRequestDetails(c *gin.Context, message string, details interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "BadRequestDetails", Status: http.StatusBadRequest, Message: message, Details: details}, func(h responsehelper.ResponseHelper) {
+		h.BadRequestDetails(c, message, details, opts...)
+	})
+}
+
+func (r *Recorder) AlreadyExists(c *gin.Context, resource string, err error, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "AlreadyExists", Status: http.StatusConflict, Message: resource + " already exists", Err: err}, func(h responsehelper.ResponseHelper) {
+		h.AlreadyExists(c, resource, err, opts...)
+	})
+}
+
+func (r *Recorder) Conflict(c *gin.Context, message string, err error, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Conflict", Status: http.StatusConflict, Message: message, Err: err}, func(h responsehelper.ResponseHelper) {
+		h.Conflict(c, message, err, opts...)
+	})
+}
+
+func (r *Recorder) NotFound(c *gin.Context, message string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "NotFound", Status: http.StatusNotFound, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.NotFound(c, message, opts...)
+	})
+}
+
+func (r *Recorder) ResourceNotFound(c *gin.Context, resource string, id interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "ResourceNotFound", Status: http.StatusNotFound, Message: resource, Details: id}, func(h responsehelper.ResponseHelper) {
+		h.ResourceNotFound(c, resource, id, opts...)
+	})
+}
+
+func (r *Recorder) Unauthorized(c *gin.Context, message string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Unauthorized", Status: http.StatusUnauthorized, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.Unauthorized(c, message, opts...)
+	})
+}
+
+func (r *Recorder) UnauthorizedWithChallenge(c *gin.Context, message, scheme, realm string, params map[string]string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "UnauthorizedWithChallenge", Status: http.StatusUnauthorized, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.UnauthorizedWithChallenge(c, message, scheme, realm, params, opts...)
+	})
+}
+
+func (r *Recorder) UnauthorizedReason(c *gin.Context, reason responsehelper.AuthFailureReason, message string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "UnauthorizedReason", Status: http.StatusUnauthorized, Message: message, Code: string(reason)}, func(h responsehelper.ResponseHelper) {
+		h.UnauthorizedReason(c, reason, message, opts...)
+	})
+}
+
+func (r *Recorder) Forbidden(c *gin.Context, message string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Forbidden", Status: http.StatusForbidden, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.Forbidden(c, message, opts...)
+	})
+}
+
+func (r *Recorder) ForbiddenScope(c *gin.Context, message string, required []string, granted []string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "ForbiddenScope", Status: http.StatusForbidden, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.ForbiddenScope(c, message, required, granted, opts...)
+	})
+}
+
+func (r *Recorder) TooManyRequests(c *gin.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "TooManyRequests", Status: http.StatusTooManyRequests, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.TooManyRequests(c, message, retryAfter, opts...)
+	})
+}
+
+func (r *Recorder) ServiceUnavailable(c *gin.Context, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "ServiceUnavailable", Status: http.StatusServiceUnavailable, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.ServiceUnavailable(c, message, retryAfter, opts...)
+	})
+}
+
+func (r *Recorder) InternalError(c *gin.Context, message string, err error, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "InternalError", Status: http.StatusInternalServerError, Message: message, Err: err}, func(h responsehelper.ResponseHelper) {
+		h.InternalError(c, message, err, opts...)
+	})
+}
+
+func (r *Recorder) Success(c *gin.Context, data interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Success", Status: http.StatusOK, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.Success(c, data, opts...)
+	})
+}
+
+func (r *Recorder) PartialSuccess(c *gin.Context, data interface{}, failures []responsehelper.PartialFailure, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "PartialSuccess", Status: http.StatusOK, Data: data, Details: failures}, func(h responsehelper.ResponseHelper) {
+		h.PartialSuccess(c, data, failures, opts...)
+	})
+}
+
+func (r *Recorder) SuccessCSV(c *gin.Context, filename string, rows interface{}) {
+	r.record(c, Call{Method: "SuccessCSV", Status: http.StatusOK, Data: rows}, func(h responsehelper.ResponseHelper) {
+		h.SuccessCSV(c, filename, rows)
+	})
+}
+
+// SuccessIfModified calls dataFn and records a 200 response with its data,
+// or the InternalError of its error or of a nil dataFn. Whether the request
+// is conditional is left to Helper, which is given the data without calling
+// dataFn again.
+func (r *Recorder) SuccessIfModified(c *gin.Context, lastModified time.Time, dataFn func() (interface{}, error), opts ...responsehelper.ResponseOption) {
+	if dataFn == nil {
+		r.InternalError(c, "An unexpected error occurred", errNilDataFn)
+		return
+	}
+	data, err := dataFn()
+	if err != nil {
+		r.InternalError(c, "An unexpected error occurred", err)
+		return
+	}
+	r.record(c, Call{Method: "SuccessIfModified", Status: http.StatusOK, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.SuccessIfModified(c, lastModified, func() (interface{}, error) {
+			return data, nil
+		}, opts...)
+	})
+}
+
+func (r *Recorder) SuccessWithPagination(c *gin.Context, data interface{}, meta interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "SuccessWithPagination", Status: http.StatusOK, Data: data, Pagination: meta}, func(h responsehelper.ResponseHelper) {
+		h.SuccessWithPagination(c, data, meta, opts...)
+	})
+}
+
+func (r *Recorder) SuccessWithCursor(c *gin.Context, data interface{}, cur responsehelper.CursorPagination) {
+	r.record(c, Call{Method: "SuccessWithCursor", Status: http.StatusOK, Data: data, Pagination: cur}, func(h responsehelper.ResponseHelper) {
+		h.SuccessWithCursor(c, data, cur)
+	})
+}
+
+func (r *Recorder) SuccessWithCount(c *gin.Context, data interface{}) {
+	r.record(c, Call{Method: "SuccessWithCount", Status: http.StatusOK, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.SuccessWithCount(c, data)
+	})
+}
+
+func (r *Recorder) SuccessWithLinks(c *gin.Context, data interface{}, links responsehelper.Links) {
+	r.record(c, Call{Method: "SuccessWithLinks", Status: http.StatusOK, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.SuccessWithLinks(c, data, links)
+	})
+}
+
+func (r *Recorder) SuccessLarge(c *gin.Context, data interface{}) {
+	r.record(c, Call{Method: "SuccessLarge", Status: http.StatusOK, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.SuccessLarge(c, data)
+	})
+}
+
+func (r *Recorder) Created(c *gin.Context, data interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Created", Status: http.StatusCreated, Data: data}, func(h responsehelper.ResponseHelper) {
+		h.Created(c, data, opts...)
+	})
+}
+
+func (r *Recorder) Patched(c *gin.Context, resource string, data interface{}, changedFields []string, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Patched", Status: http.StatusOK, Data: data, Message: resource, Details: changedFields}, func(h responsehelper.ResponseHelper) {
+		h.Patched(c, resource, data, changedFields, opts...)
+	})
+}
+
+func (r *Recorder) OperationAccepted(c *gin.Context, op responsehelper.Operation) {
+	r.record(c, Call{Method: "OperationAccepted", Status: http.StatusAccepted, Data: op}, func(h responsehelper.ResponseHelper) {
+		h.OperationAccepted(c, op)
+	})
+}
+
+// OperationStatus records a 200 OK with the operation as data, or the error
+// of a failed operation with its status, 500 when it has none.
+func (r *Recorder) OperationStatus(c *gin.Context, op responsehelper.Operation) {
+	call := Call{Method: "OperationStatus", Status: http.StatusOK, Data: op}
+	if op.Status == responsehelper.OperationFailed {
+		call = Call{Method: "OperationStatus", Status: http.StatusInternalServerError, Message: "The operation failed"}
+		if op.Error != nil {
+			if responsehelper.ValidateErrorStatus(op.Error.Code) == nil {
+				call.Status = op.Error.Code
+			}
+			call.Message, call.Code, call.Details = op.Error.Message, op.Error.ErrorCode, op.Error.Details
+		}
+	}
+	r.record(c, call, func(h responsehelper.ResponseHelper) {
+		h.OperationStatus(c, op)
+	})
+}
+
+func (r *Recorder) Deleted(c *gin.Context, message string) {
+	r.record(c, Call{Method: "Deleted", Status: http.StatusOK, Message: message}, func(h responsehelper.ResponseHelper) {
+		h.Deleted(c, message)
+	})
+}
+
+func (r *Recorder) DeletedNoContent(c *gin.Context) {
+	r.record(c, Call{Method: "DeletedNoContent", Status: http.StatusNoContent}, func(h responsehelper.ResponseHelper) {
+		h.DeletedNoContent(c)
+	})
+}
+
+func (r *Recorder) NoContent(c *gin.Context) {
+	r.record(c, Call{Method: "NoContent", Status: http.StatusNoContent}, func(h responsehelper.ResponseHelper) {
+		h.NoContent(c)
+	})
+}
+
+func (r *Recorder) RespondAPIError(c *gin.Context, err *responsehelper.APIError, opts ...responsehelper.ResponseOption) {
+	call := Call{Method: "RespondAPIError", Status: http.StatusInternalServerError}
+	if err != nil {
+		call = apiErrorCall(call.Method, err)
+	}
+	r.record(c, call, func(h responsehelper.ResponseHelper) {
+		h.RespondAPIError(c, err, opts...)
+	})
+}
+
+func (r *Recorder) Respond(c *gin.Context, err error, data interface{}, opts ...responsehelper.ResponseOption) {
+	call := Call{Method: "Respond", Status: http.StatusOK, Data: data}
+	var apiErr *responsehelper.APIError
+	var coder responsehelper.StatusCoder
+	switch {
+	case errors.As(err, &apiErr):
+		call = apiErrorCall(call.Method, apiErr)
+		call.Err = err
+	case errors.As(err, &coder):
+		call = Call{Method: call.Method, Status: errorStatus(coder.StatusCode()), Message: "An unexpected error occurred", Err: err}
+		if call.Status < http.StatusInternalServerError {
+			call.Message = err.Error()
+		}
+	case err != nil:
+		call = Call{Method: call.Method, Status: http.StatusInternalServerError, Message: "An unexpected error occurred", Err: err}
+	}
+	r.record(c, call, func(h responsehelper.ResponseHelper) {
+		h.Respond(c, err, data, opts...)
+	})
+}
+
+// apiErrorCall returns the call of method sending err.
+func apiErrorCall(method string, err *responsehelper.APIError) Call {
+	return Call{
+		Method:  method,
+		Status:  errorStatus(err.Status),
+		Message: err.Message,
+		Code:    err.Code,
+		Details: err.Details,
+		Err:     err,
+	}
+}
+
+func (r *Recorder) Errors(c *gin.Context, statusCode int, errs []responsehelper.ErrorItem, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "Errors", Status: errorStatus(statusCode), Details: errs}, func(h responsehelper.ResponseHelper) {
+		h.Errors(c, statusCode, errs, opts...)
+	})
+}
+
+func (r *Recorder) Problem(c *gin.Context, status int, typ, title, detail string, extensions map[string]interface{}) {
+	r.record(c, Call{Method: "Problem", Status: status, Message: title, Details: detail}, func(h responsehelper.ResponseHelper) {
+		h.Problem(c, status, typ, title, detail, extensions)
+	})
+}
+
+func (r *Recorder) WriteJSON(c *gin.Context, status int, body interface{}, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "WriteJSON", Status: status, Data: body}, func(h responsehelper.ResponseHelper) {
+		h.WriteJSON(c, status, body, opts...)
+	})
+}
+
+func (r *Recorder) WriteError(c *gin.Context, status int, errBody responsehelper.ErrorBody, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "WriteError", Status: status, Message: errBody.Message, Code: errBody.ErrorCode, Details: errBody.Details}, func(h responsehelper.ResponseHelper) {
+		h.WriteError(c, status, errBody, opts...)
+	})
+}
+
+func (r *Recorder) OAuthError(c *gin.Context, errCode string, description string, status int) {
+	r.record(c, Call{Method: "OAuthError", Status: status, Message: description, Code: errCode}, func(h responsehelper.ResponseHelper) {
+		h.OAuthError(c, errCode, description, status)
+	})
+}
+
+// ValidationFailed records a 400 Bad Request, the status of
+// WithValidationStatus is not known to the Recorder.
+func (r *Recorder) ValidationFailed(c *gin.Context, err error, opts ...responsehelper.ResponseOption) {
+	r.record(c, Call{Method: "ValidationFailed", Status: http.StatusBadRequest, Message: "Validation failed", Err: err}, func(h responsehelper.ResponseHelper) {
+		h.ValidationFailed(c, err, opts...)
+	})
+}
+
+func (r *Recorder) NotFoundKey(c *gin.Context, key string, args ...interface{}) {
+	r.record(c, Call{Method: "NotFoundKey", Status: http.StatusNotFound, Key: key, Args: args}, func(h responsehelper.ResponseHelper) {
+		h.NotFoundKey(c, key, args...)
+	})
+}
+
+func (r *Recorder) BadRequestKey(c *gin.Context, key string, details string, args ...interface{}) {
+	r.record(c, Call{Method: "BadRequestKey", Status: http.StatusBadRequest, Key: key, Args: args, Details: details}, func(h responsehelper.ResponseHelper) {
+		h.BadRequestKey(c, key, details, args...)
+	})
+}
+
+func (r *Recorder) UnauthorizedKey(c *gin.Context, key string, args ...interface{}) {
+	r.record(c, Call{Method: "UnauthorizedKey", Status: http.StatusUnauthorized, Key: key, Args: args}, func(h responsehelper.ResponseHelper) {
+		h.UnauthorizedKey(c, key, args...)
+	})
+}
+
+func (r *Recorder) ForbiddenKey(c *gin.Context, key string, args ...interface{}) {
+	r.record(c, Call{Method: "ForbiddenKey", Status: http.StatusForbidden, Key: key, Args: args}, func(h responsehelper.ResponseHelper) {
+		h.ForbiddenKey(c, key, args...)
+	})
+}
+
+func (r *Recorder) ConflictKey(c *gin.Context, key string, err error, args ...interface{}) {
+	r.record(c, Call{Method: "ConflictKey", Status: http.StatusConflict, Key: key, Args: args, Err: err}, func(h responsehelper.ResponseHelper) {
+		h.ConflictKey(c, key, err, args...)
+	})
+}
+
+func (r *Recorder) InternalErrorKey(c *gin.Context, key string, err error, args ...interface{}) {
+	r.record(c, Call{Method: "InternalErrorKey", Status: http.StatusInternalServerError, Key: key, Args: args, Err: err}, func(h responsehelper.ResponseHelper) {
+		h.InternalErrorKey(c, key, err, args...)
+	})
+}
+
+// RegisterCode registers code for RespondCode. It is not recorded.
+func (r *Recorder) RegisterCode(code string, status int, defaultMessage string) {
+	r.mu.Lock()
+	if r.codes == nil {
+		r.codes = make(map[string]registeredCode)
+	}
+	r.codes[code] = registeredCode{status: errorStatus(status), message: defaultMessage}
+	r.mu.Unlock()
+	if r.Helper != nil {
+		r.Helper.RegisterCode(code, status, defaultMessage)
+	}
+}
+
+func (r *Recorder) RespondCode(c *gin.Context, code string, args ...interface{}) {
+	call := Call{Method: "RespondCode", Status: http.StatusInternalServerError, Message: "An unexpected error occurred", Code: code, Args: args}
+	r.mu.Lock()
+	registered, ok := r.codes[code]
+	r.mu.Unlock()
+	if ok {
+		call.Status = registered.status
+		call.Message = registered.message
+		if len(args) > 0 {
+			call.Message = fmt.Sprintf(registered.message, args...)
+		}
+	}
+	r.record(c, call, func(h responsehelper.ResponseHelper) {
+		h.RespondCode(c, code, args...)
+	})
+}
+
+// PrecomputeError is passed to Helper, if any. It is not recorded.
+func (r *Recorder) PrecomputeError(status int, message string) {
+	if r.Helper != nil {
+		r.Helper.PrecomputeError(status, message)
+	}
+}
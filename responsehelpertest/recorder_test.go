@@ -0,0 +1,254 @@
+package responsehelpertest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/responsehelpertest"
+	"github.com/gin-gonic/gin"
+)
+
+// statusError is an error carrying its status.
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e statusError) Error() string   { return e.message }
+func (e statusError) StatusCode() int { return e.status }
+
+func TestRecorderRespondStatusCoder(t *testing.T) {
+	for _, tc := range []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{statusError{http.StatusConflict, "version mismatch"}, http.StatusConflict, "version mismatch"},
+		{statusError{http.StatusBadGateway, "upstream down"}, http.StatusBadGateway, "An unexpected error occurred"},
+		{statusError{http.StatusOK, "not an error status"}, http.StatusInternalServerError, "An unexpected error occurred"},
+	} {
+		recorder := &responsehelpertest.Recorder{}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		recorder.Respond(c, tc.err, nil)
+
+		call, _ := recorder.Last()
+		if call.Status != tc.status || call.Message != tc.message || call.Err != tc.err {
+			t.Errorf("Respond(%v) recorded %+v, want %d %q", tc.err, call, tc.status, tc.message)
+		}
+		if w.Code != tc.status {
+			t.Errorf("Respond(%v) wrote %d, want %d", tc.err, w.Code, tc.status)
+		}
+	}
+}
+
+// userHandler is a handler under test, taking its ResponseHelper.
+type userHandler struct {
+	h     responsehelper.ResponseHelper
+	users map[string]string
+}
+
+func (u userHandler) get(c *gin.Context) {
+	name, ok := u.users[c.Param("id")]
+	if !ok {
+		u.h.NotFound(c, "user not found")
+		return
+	}
+	u.h.Success(c, gin.H{"name": name})
+}
+
+// testContext returns a context for a request with the id parameter.
+func testContext(id string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/"+id, nil)
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	return c, w
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{}
+	handler := userHandler{h: recorder, users: map[string]string{"42": "arun"}}
+	for _, tc := range []struct {
+		id     string
+		method string
+		status int
+		want   responsehelpertest.Call
+	}{
+		{"42", "Success", http.StatusOK, responsehelpertest.Call{Method: "Success", Status: http.StatusOK, Data: gin.H{"name": "arun"}}},
+		{"7", "NotFound", http.StatusNotFound, responsehelpertest.Call{Method: "NotFound", Status: http.StatusNotFound, Message: "user not found"}},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			recorder.Reset()
+			c, w := testContext(tc.id)
+			handler.get(c)
+
+			if !recorder.CalledOnceWith(tc.method) {
+				t.Errorf("calls = %+v, want one %s", recorder.Calls(), tc.method)
+			}
+			if call, ok := recorder.Last(); !ok || !reflect.DeepEqual(call, tc.want) {
+				t.Errorf("Last = %+v, want %+v", call, tc.want)
+			}
+			// the zero Recorder writes the status only
+			if w.Code != tc.status || w.Body.Len() != 0 {
+				t.Errorf("wrote %d %q, want %d without a body", w.Code, w.Body, tc.status)
+			}
+		})
+	}
+}
+
+func TestRecorderWritesThroughItsHelper(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{Helper: responsehelper.NewResponseHelper()}
+	c, w := testContext("7")
+	userHandler{h: recorder}.get(c)
+
+	responsehelpertest.AssertError(t, w, http.StatusNotFound, "user not found")
+	if recorder.Count("NotFound") != 1 {
+		t.Errorf("calls = %+v", recorder.Calls())
+	}
+}
+
+func TestRecorderCountAndReset(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{}
+	if _, ok := recorder.Last(); ok {
+		t.Error("Last reported a call of an empty Recorder")
+	}
+	for i := 0; i < 3; i++ {
+		c, _ := testContext("1")
+		recorder.Conflict(c, "taken", errors.New("duplicate"))
+	}
+	c, _ := testContext("1")
+	recorder.NoContent(c)
+
+	if got := recorder.Count("Conflict"); got != 3 {
+		t.Errorf("Count(Conflict) = %d, want 3", got)
+	}
+	if recorder.CalledOnceWith("NoContent") {
+		t.Error("CalledOnceWith(NoContent) after four calls")
+	}
+	calls := recorder.Calls()
+	calls[0].Method = "changed"
+	if recorder.Calls()[0].Method != "Conflict" {
+		t.Error("Calls returned the slice of the Recorder")
+	}
+
+	recorder.RegisterCode("USER_SUSPENDED", http.StatusForbidden, "User %s is suspended")
+	recorder.Reset()
+	if len(recorder.Calls()) != 0 {
+		t.Errorf("calls after Reset = %+v", recorder.Calls())
+	}
+	c, _ = testContext("1")
+	recorder.RespondCode(c, "USER_SUSPENDED", "arun")
+	if call, _ := recorder.Last(); call.Status != http.StatusForbidden || call.Message != "User arun is suspended" {
+		t.Errorf("RespondCode after Reset recorded %+v", call)
+	}
+}
+
+func TestRecorderRespondCode(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{}
+	recorder.RegisterCode("QUOTA", http.StatusTooManyRequests, "Quota exceeded")
+	for _, tc := range []struct {
+		code    string
+		args    []interface{}
+		status  int
+		message string
+	}{
+		{"QUOTA", nil, http.StatusTooManyRequests, "Quota exceeded"},
+		{"UNKNOWN", nil, http.StatusInternalServerError, "An unexpected error occurred"},
+	} {
+		c, _ := testContext("1")
+		recorder.RespondCode(c, tc.code, tc.args...)
+
+		call, _ := recorder.Last()
+		if call.Status != tc.status || call.Message != tc.message || call.Code != tc.code {
+			t.Errorf("RespondCode(%s) recorded %+v", tc.code, call)
+		}
+	}
+}
+
+func TestRecorderRespond(t *testing.T) {
+	apiErr := responsehelper.NewAPIError(http.StatusConflict, "taken", nil)
+	for _, tc := range []struct {
+		name string
+		err  error
+		want responsehelpertest.Call
+	}{
+		{"success", nil, responsehelpertest.Call{Method: "Respond", Status: http.StatusOK, Data: "data"}},
+		{"api error", fmt.Errorf("saving: %w", apiErr),
+			responsehelpertest.Call{Method: "Respond", Status: http.StatusConflict, Message: "taken", Err: fmt.Errorf("saving: %w", apiErr)}},
+		{"other error", errors.New("db down"),
+			responsehelpertest.Call{Method: "Respond", Status: http.StatusInternalServerError, Message: "An unexpected error occurred", Err: errors.New("db down")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &responsehelpertest.Recorder{}
+			c, _ := testContext("1")
+			recorder.Respond(c, tc.err, "data")
+
+			call, _ := recorder.Last()
+			if call.Method != tc.want.Method || call.Status != tc.want.Status || call.Message != tc.want.Message ||
+				fmt.Sprint(call.Err) != fmt.Sprint(tc.want.Err) {
+				t.Errorf("recorded %+v, want %+v", call, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecorderIsSafeForConcurrentUse(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, _ := testContext("1")
+			recorder.Success(c, nil)
+			recorder.Count("Success")
+		}()
+	}
+	wg.Wait()
+
+	if got := recorder.Count("Success"); got != 20 {
+		t.Errorf("Count(Success) = %d, want 20", got)
+	}
+}
+
+func TestRecorderSuccessIfModified(t *testing.T) {
+	recorder := &responsehelpertest.Recorder{Helper: responsehelper.NewResponseHelper()}
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	c, w := testContext("42")
+	recorder.SuccessIfModified(c, updatedAt, func() (interface{}, error) { return gin.H{"name": "arun"}, nil }, responsehelper.WithRateLimit(responsehelper.RateLimitInfo{Limit: 100, Remaining: 7}))
+	if call, ok := recorder.Last(); !ok || call.Method != "SuccessIfModified" || call.Status != http.StatusOK {
+		t.Errorf("Last = %+v", call)
+	}
+	if got := w.Header().Get(responsehelper.RateLimitRemainingHeader); got != "7" {
+		t.Errorf("%s = %q", responsehelper.RateLimitRemainingHeader, got)
+	}
+
+	c, w = testContext("42")
+	recorder.SuccessIfModified(c, updatedAt, nil)
+	if call, ok := recorder.Last(); !ok || call.Method != "InternalError" || call.Err == nil {
+		t.Errorf("Last = %+v, want the InternalError of the nil dataFn", call)
+	}
+	responsehelpertest.AssertError(t, w, http.StatusInternalServerError, "An unexpected error occurred")
+}
+
+func ExampleRecorder() {
+	recorder := &responsehelpertest.Recorder{}
+	handler := userHandler{h: recorder, users: map[string]string{"42": "arun"}}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	handler.get(c)
+
+	call, _ := recorder.Last()
+	fmt.Println(recorder.CalledOnceWith("NotFound"), call.Status, call.Message)
+	// Output: true 404 user not found
+}
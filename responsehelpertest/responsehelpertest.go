@@ -0,0 +1,181 @@
+// Package responsehelpertest checks responsehelper envelopes in tests, so
+// handlers can be tested without decoding the body by hand. Failures report
+// the raw body of the response.
+//
+// Example:
+//
+//	w := httptest.NewRecorder()
+//	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+//	responsehelpertest.AssertError(t, w, http.StatusForbidden, "User arun is suspended")
+//	responsehelpertest.AssertField(t, w, "error.errorCode", "USER_SUSPENDED")
+package responsehelpertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// AssertSuccess checks that the response is a success envelope with a 2xx
+// status, and returns its data when it is a JSON object. Use DecodeData for
+// data of another shape.
+//
+// Example:
+//
+//	data := responsehelpertest.AssertSuccess(t, w)
+//	if data["name"] != "arun" { ... }
+func AssertSuccess(t testing.TB, recorder *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	envelope := decode(t, recorder)
+	if recorder.Code < 200 || recorder.Code > 299 {
+		t.Fatalf("status = %d, want 2xx\nbody: %s", recorder.Code, recorder.Body)
+	}
+	if envelope["success"] != true {
+		t.Fatalf("success = %v, want true\nbody: %s", format(envelope["success"]), recorder.Body)
+	}
+	data, _ := envelope["data"].(map[string]interface{})
+	return data
+}
+
+// AssertError checks that the response is an error envelope with status,
+// and with message unless it is empty.
+//
+// Example:
+//
+//	responsehelpertest.AssertError(t, w, http.StatusNotFound, "user not found")
+func AssertError(t testing.TB, recorder *httptest.ResponseRecorder, status int, message string) {
+	t.Helper()
+	envelope := decode(t, recorder)
+	if recorder.Code != status {
+		t.Errorf("status = %d, want %d\nbody: %s", recorder.Code, status, recorder.Body)
+	}
+	if envelope["success"] != false {
+		t.Errorf("success = %v, want false\nbody: %s", format(envelope["success"]), recorder.Body)
+	}
+	errorBody, ok := envelope["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("error = %v, want an object\nbody: %s", format(envelope["error"]), recorder.Body)
+	}
+	if code, _ := errorBody["code"].(float64); int(code) != status {
+		t.Errorf("error.code = %v, want %d\nbody: %s", format(errorBody["code"]), status, recorder.Body)
+	}
+	if message != "" && errorBody["message"] != message {
+		t.Errorf("error.message = %v, want %q\nbody: %s", format(errorBody["message"]), message, recorder.Body)
+	}
+}
+
+// AssertField checks the member of the envelope at path, its keys joined
+// with dots and array indexes given as numbers, eg: "data.items.0.id". want
+// is compared with the member as JSON, so 42 matches 42.0.
+//
+// Example:
+//
+//	responsehelpertest.AssertField(t, w, "error.errorCode", "USER_SUSPENDED")
+//	responsehelpertest.AssertField(t, w, "meta.count", 3)
+func AssertField(t testing.TB, recorder *httptest.ResponseRecorder, path string, want interface{}) {
+	t.Helper()
+	got, err := Lookup(decode(t, recorder), path)
+	if err != nil {
+		t.Errorf("%s: %v\nbody: %s", path, err, recorder.Body)
+		return
+	}
+	wantJSON, err := normalize(want)
+	if err != nil {
+		t.Fatalf("%s: want %#v cannot be encoded as JSON: %v", path, want, err)
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		t.Errorf("%s = %s, want %s\nbody: %s", path, format(got), format(wantJSON), recorder.Body)
+	}
+}
+
+// DecodeData decodes the data of the envelope into dst.
+//
+// Example:
+//
+//	var users []User
+//	responsehelpertest.DecodeData(t, w, &users)
+func DecodeData(t testing.TB, recorder *httptest.ResponseRecorder, dst interface{}) {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decoding the envelope: %v\nbody: %s", err, recorder.Body)
+	}
+	if len(envelope.Data) == 0 {
+		t.Fatalf("the envelope has no data\nbody: %s", recorder.Body)
+	}
+	if err := json.Unmarshal(envelope.Data, dst); err != nil {
+		t.Fatalf("decoding data into %T: %v\nbody: %s", dst, err, recorder.Body)
+	}
+}
+
+// Lookup returns the member of a decoded JSON value at path, see AssertField.
+func Lookup(value interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return value, nil
+	}
+	walked := ""
+	for _, key := range strings.Split(path, ".") {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			member, ok := typed[key]
+			if !ok {
+				return nil, fmt.Errorf("%s has no member %q", describe(walked), key)
+			}
+			value = member
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, fmt.Errorf("%s has %d items, no item %q", describe(walked), len(typed), key)
+			}
+			value = typed[index]
+		default:
+			return nil, fmt.Errorf("%s is %s, not an object or an array", describe(walked), format(value))
+		}
+		walked = strings.TrimPrefix(walked+"."+key, ".")
+	}
+	return value, nil
+}
+
+// decode returns the envelope of the response as a JSON object.
+func decode(t testing.TB, recorder *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("the body is not a JSON envelope: %v\nbody: %s", err, recorder.Body)
+	}
+	return envelope
+}
+
+// normalize returns v as encoding/json decodes its JSON.
+func normalize(v interface{}) (interface{}, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(body, &out)
+	return out, err
+}
+
+// format returns v as compact JSON for failure messages.
+func format(v interface{}) string {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(body)
+}
+
+// describe names the member at path in errors.
+func describe(path string) string {
+	if path == "" {
+		return "the envelope"
+	}
+	return path
+}
@@ -0,0 +1,170 @@
+package responsehelpertest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/responsehelpertest"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// fakeT records the failures of an assertion instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failures []string
+	fatal    bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+	t.fatal = true
+	runtime.Goexit()
+}
+
+// check runs assert with a fakeT and returns it once the assertion is done.
+func check(assert func(t testing.TB)) *fakeT {
+	t := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert(t)
+	}()
+	<-done
+	return t
+}
+
+// get serves a GET of path on a tiny router.
+func get(path string) *httptest.ResponseRecorder {
+	h := responsehelper.NewResponseHelper()
+	router := gin.New()
+	router.GET("/users/:id", func(c *gin.Context) {
+		if c.Param("id") != "42" {
+			h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusForbidden, Code: "USER_SUSPENDED", Message: "User arun is suspended"})
+			return
+		}
+		h.Success(c, gin.H{"id": 42, "name": "arun", "roles": []string{"admin", "dev"}})
+	})
+	router.GET("/users", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []gin.H{{"id": 1}, {"id": 2}}, responsehelper.NewPagination(1, 2, 3))
+	})
+	router.GET("/text", func(c *gin.Context) { c.String(http.StatusOK, "plain text") })
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestAssertSuccess(t *testing.T) {
+	data := responsehelpertest.AssertSuccess(t, get("/users/42"))
+	if data["name"] != "arun" {
+		t.Errorf("data = %v", data)
+	}
+	if data := responsehelpertest.AssertSuccess(t, get("/users")); data != nil {
+		t.Errorf("data of a listing = %v, want nil", data)
+	}
+
+	for path, want := range map[string]string{
+		"/users/7": "status = 403, want 2xx",
+		"/text":    "the body is not a JSON envelope",
+	} {
+		failed := check(func(t testing.TB) { responsehelpertest.AssertSuccess(t, get(path)) })
+		if !failed.fatal || len(failed.failures) != 1 || !strings.Contains(failed.failures[0], want) {
+			t.Errorf("%s: failures = %q, want %q", path, failed.failures, want)
+		}
+	}
+}
+
+func TestAssertError(t *testing.T) {
+	responsehelpertest.AssertError(t, get("/users/7"), http.StatusForbidden, "User arun is suspended")
+	responsehelpertest.AssertError(t, get("/users/7"), http.StatusForbidden, "")
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		status  int
+		message string
+		want    []string
+	}{
+		{"success", "/users/42", http.StatusNotFound, "", []string{"status = 200, want 404", "success = true, want false", "error = null, want an object"}},
+		{"status", "/users/7", http.StatusNotFound, "", []string{"status = 403, want 404", "error.code = 403, want 404"}},
+		{"message", "/users/7", http.StatusForbidden, "Forbidden", []string{`error.message = "User arun is suspended", want "Forbidden"`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := get(tc.path)
+			failed := check(func(t testing.TB) { responsehelpertest.AssertError(t, w, tc.status, tc.message) })
+
+			if len(failed.failures) != len(tc.want) {
+				t.Fatalf("failures = %q, want %q", failed.failures, tc.want)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(failed.failures[i], want) {
+					t.Errorf("failure %d = %q, want %q", i, failed.failures[i], want)
+				}
+				if !strings.Contains(failed.failures[i], "body: "+w.Body.String()) {
+					t.Errorf("failure %d does not show the body: %q", i, failed.failures[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAssertField(t *testing.T) {
+	responsehelpertest.AssertField(t, get("/users/7"), "error.errorCode", "USER_SUSPENDED")
+	responsehelpertest.AssertField(t, get("/users/42"), "data.id", 42)
+	responsehelpertest.AssertField(t, get("/users/42"), "data.roles", []string{"admin", "dev"})
+	responsehelpertest.AssertField(t, get("/users/42"), "data.roles.1", "dev")
+	responsehelpertest.AssertField(t, get("/users"), "data.1.id", 2.0)
+
+	for _, tc := range []struct {
+		path string
+		want interface{}
+		fail string
+	}{
+		{"data.id", 7, "data.id = 42, want 7"},
+		{"data.email", "", `data has no member "email"`},
+		{"data.roles.2", "", `data.roles has 2 items, no item "2"`},
+		{"data.name.first", "", `data.name is "arun", not an object or an array`},
+		{"missing", "", `the envelope has no member "missing"`},
+	} {
+		failed := check(func(t testing.TB) { responsehelpertest.AssertField(t, get("/users/42"), tc.path, tc.want) })
+		if len(failed.failures) != 1 || !strings.Contains(failed.failures[0], tc.fail) {
+			t.Errorf("%s: failures = %q, want %q", tc.path, failed.failures, tc.fail)
+		}
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	var user struct {
+		ID    int      `json:"id"`
+		Roles []string `json:"roles"`
+	}
+	responsehelpertest.DecodeData(t, get("/users/42"), &user)
+	if user.ID != 42 || len(user.Roles) != 2 {
+		t.Errorf("DecodeData = %+v", user)
+	}
+
+	var ids []struct{ ID int }
+	responsehelpertest.DecodeData(t, get("/users"), &ids)
+	if len(ids) != 2 || ids[1].ID != 2 {
+		t.Errorf("DecodeData = %+v", ids)
+	}
+
+	failed := check(func(t testing.TB) { responsehelpertest.DecodeData(t, get("/users/7"), &user) })
+	if !failed.fatal || !strings.Contains(failed.failures[0], "the envelope has no data") {
+		t.Errorf("failures = %q", failed.failures)
+	}
+}
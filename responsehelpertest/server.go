@@ -0,0 +1,162 @@
+package responsehelpertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// Server is a gin engine wired like a service using responsehelper, for
+// integration tests: MetaMiddleware, Recovery and the NoRoute and NoMethod
+// handlers are installed. Register the routes under test on Engine with
+// Helper, then send requests with Do.
+type Server struct {
+	*gin.Engine
+	// Helper is the helper of the routes, built with the Options of NewServer.
+	Helper responsehelper.ResponseHelper
+}
+
+// NewServer returns a Server whose Helper is built by responsehelper.New
+// with opts.
+//
+// Example:
+//
+//	server := responsehelpertest.NewServer()
+//	server.GET("/users/:id", NewUserHandler(server.Helper, store).GetUser)
+//	resp := server.Do(http.MethodGet, "/users/404", nil)
+//	if resp.Status() != http.StatusNotFound || resp.ErrorMessage() != "User not found" { ... }
+func NewServer(opts ...responsehelper.Option) *Server {
+	engine := gin.New()
+	helper := responsehelper.New(opts...)
+	engine.Use(responsehelper.MetaMiddleware())
+	responsehelper.Install(engine, helper)
+	return &Server{Engine: engine, Helper: helper}
+}
+
+// Do sends a request to the server and returns its response. body is sent
+// as it is when it is a string, a []byte or an io.Reader, and encoded as
+// JSON otherwise, with a Content-Type of application/json. A nil body sends
+// none. Do panics when body cannot be encoded, as the test is wrong.
+func (s *Server) Do(method, path string, body interface{}) *ParsedResponse {
+	reader, isJSON := requestBody(body)
+	request := httptest.NewRequest(method, path, reader)
+	if isJSON {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, request)
+	return Parse(recorder)
+}
+
+// requestBody returns the reader of the body of Do, and whether it is JSON
+// encoded by Do.
+func requestBody(body interface{}) (io.Reader, bool) {
+	switch typed := body.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return bytes.NewBufferString(typed), false
+	case []byte:
+		return bytes.NewBuffer(typed), false
+	case io.Reader:
+		return typed, false
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		panic(fmt.Sprintf("responsehelpertest: encoding the request body %T: %v", body, err))
+	}
+	return bytes.NewBuffer(encoded), true
+}
+
+// ParsedResponse is a response with its envelope decoded.
+type ParsedResponse struct {
+	// Recorder holds the response as written, eg: for AssertField.
+	Recorder *httptest.ResponseRecorder
+	// Envelope is the body decoded as a JSON object, nil when it is not one.
+	Envelope map[string]interface{}
+}
+
+// Parse decodes the envelope of a recorded response.
+func Parse(recorder *httptest.ResponseRecorder) *ParsedResponse {
+	response := &ParsedResponse{Recorder: recorder}
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response.Envelope)
+	return response
+}
+
+// Status returns the status code of the response.
+func (r *ParsedResponse) Status() int {
+	return r.Recorder.Code
+}
+
+// Header returns the headers of the response.
+func (r *ParsedResponse) Header() http.Header {
+	return r.Recorder.Header()
+}
+
+// Body returns the body of the response.
+func (r *ParsedResponse) Body() string {
+	return r.Recorder.Body.String()
+}
+
+// Success returns the "success" of the envelope, false when there is none.
+func (r *ParsedResponse) Success() bool {
+	success, _ := r.Envelope["success"].(bool)
+	return success
+}
+
+// ErrorMessage returns the "error.message" of an error envelope, "" for
+// other responses.
+func (r *ParsedResponse) ErrorMessage() string {
+	message, _ := r.errorField("message").(string)
+	return message
+}
+
+// ErrorCode returns the "error.errorCode" of an error envelope, "" for
+// other responses.
+func (r *ParsedResponse) ErrorCode() string {
+	code, _ := r.errorField("errorCode").(string)
+	return code
+}
+
+// Field returns the member of the envelope at path, see AssertField.
+func (r *ParsedResponse) Field(path string) (interface{}, error) {
+	if r.Envelope == nil {
+		return nil, errors.New("the body is not a JSON envelope")
+	}
+	return Lookup(r.Envelope, path)
+}
+
+// DataAs decodes the data of the envelope into dst.
+//
+// Example:
+//
+//	var user User
+//	if err := resp.DataAs(&user); err != nil { t.Fatal(err) }
+func (r *ParsedResponse) DataAs(dst interface{}) error {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), &envelope); err != nil {
+		return fmt.Errorf("decoding the envelope: %w", err)
+	}
+	if len(envelope.Data) == 0 {
+		return errors.New("the envelope has no data")
+	}
+	if err := json.Unmarshal(envelope.Data, dst); err != nil {
+		return fmt.Errorf("decoding data into %T: %w", dst, err)
+	}
+	return nil
+}
+
+// errorField returns the member key of the "error" of the envelope.
+func (r *ParsedResponse) errorField(key string) interface{} {
+	errorBody, _ := r.Envelope["error"].(map[string]interface{})
+	return errorBody[key]
+}
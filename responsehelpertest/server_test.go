@@ -0,0 +1,165 @@
+package responsehelpertest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/responsehelpertest"
+	"github.com/gin-gonic/gin"
+)
+
+type serverUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// newUserServer returns a Server with a small user API.
+func newUserServer(opts ...responsehelper.Option) *responsehelpertest.Server {
+	server := responsehelpertest.NewServer(opts...)
+	server.GET("/users/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "42":
+			server.Helper.Success(c, serverUser{ID: 42, Name: "arun"})
+		case "7":
+			server.Helper.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusForbidden, Code: "USER_SUSPENDED", Message: "User arun is suspended"})
+		default:
+			server.Helper.NotFound(c, "User not found")
+		}
+	})
+	server.POST("/users", func(c *gin.Context) {
+		var user serverUser
+		if err := c.ShouldBindJSON(&user); err != nil {
+			server.Helper.BadRequest(c, "Invalid user", err.Error())
+			return
+		}
+		server.Helper.Created(c, user)
+	})
+	return server
+}
+
+func TestServerDo(t *testing.T) {
+	resp := newUserServer().Do(http.MethodGet, "/users/42", nil)
+
+	if resp.Status() != http.StatusOK || !resp.Success() {
+		t.Fatalf("status = %d, success = %t, body: %s", resp.Status(), resp.Success(), resp.Body())
+	}
+	var user serverUser
+	if err := resp.DataAs(&user); err != nil {
+		t.Fatal(err)
+	}
+	if user != (serverUser{ID: 42, Name: "arun"}) {
+		t.Errorf("data = %+v", user)
+	}
+	if resp.ErrorMessage() != "" || resp.ErrorCode() != "" {
+		t.Errorf("error of a success = %q, %q", resp.ErrorMessage(), resp.ErrorCode())
+	}
+	if resp.Header().Get(responsehelper.RequestIDHeader) == "" {
+		t.Error("no request ID, MetaMiddleware is not installed")
+	}
+	if requestID, err := resp.Field("meta.requestId"); err != nil || requestID != resp.Header().Get(responsehelper.RequestIDHeader) {
+		t.Errorf("meta.requestId = %v, %v", requestID, err)
+	}
+}
+
+func TestServerDoErrors(t *testing.T) {
+	server := newUserServer()
+	for _, tt := range []struct {
+		method, path string
+		status       int
+		message      string
+		code         string
+	}{
+		{http.MethodGet, "/users/404", http.StatusNotFound, "User not found", ""},
+		{http.MethodGet, "/users/7", http.StatusForbidden, "User arun is suspended", "USER_SUSPENDED"},
+		{http.MethodGet, "/orders", http.StatusNotFound, "", ""},
+		{http.MethodDelete, "/users/42", http.StatusMethodNotAllowed, "", ""},
+	} {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp := server.Do(tt.method, tt.path, nil)
+			if resp.Status() != tt.status || resp.Success() {
+				t.Fatalf("status = %d, success = %t, want %d\nbody: %s", resp.Status(), resp.Success(), tt.status, resp.Body())
+			}
+			if resp.ErrorMessage() == "" || tt.message != "" && resp.ErrorMessage() != tt.message {
+				t.Errorf("message = %q, want %q", resp.ErrorMessage(), tt.message)
+			}
+			if resp.ErrorCode() != tt.code {
+				t.Errorf("code = %q, want %q", resp.ErrorCode(), tt.code)
+			}
+			if err := resp.DataAs(&serverUser{}); err == nil {
+				t.Error("DataAs decoded the data of an error")
+			}
+		})
+	}
+}
+
+func TestServerDoBodies(t *testing.T) {
+	server := newUserServer()
+	for name, body := range map[string]interface{}{
+		"encoded": serverUser{ID: 1, Name: "dev"},
+		"string":  `{"id":1,"name":"dev"}`,
+		"bytes":   []byte(`{"id":1,"name":"dev"}`),
+		"reader":  strings.NewReader(`{"id":1,"name":"dev"}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := server.Do(http.MethodPost, "/users", body)
+			var user serverUser
+			if err := resp.DataAs(&user); err != nil || user != (serverUser{ID: 1, Name: "dev"}) {
+				t.Errorf("status = %d, data = %+v, %v\nbody: %s", resp.Status(), user, err, resp.Body())
+			}
+		})
+	}
+
+	resp := server.Do(http.MethodPost, "/users", nil)
+	if resp.Status() != http.StatusBadRequest || resp.ErrorMessage() != "Invalid user" {
+		t.Errorf("without a body: status = %d, message = %q", resp.Status(), resp.ErrorMessage())
+	}
+}
+
+func TestServerDoPanicsOnAnInvalidBody(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Do sent a body it cannot encode")
+		}
+	}()
+	newUserServer().Do(http.MethodPost, "/users", make(chan int))
+}
+
+func TestNewServerUsesTheOptions(t *testing.T) {
+	server := newUserServer(responsehelper.WithErrorSanitization(true))
+	server.GET("/fail", func(c *gin.Context) {
+		server.Helper.InternalError(c, "Oops", errors.New("secret"))
+	})
+
+	resp := server.Do(http.MethodGet, "/fail", nil)
+	if details, err := resp.Field("error.details"); err == nil {
+		t.Errorf("error.details = %v with sanitization", details)
+	}
+}
+
+func TestParseWithoutAnEnvelope(t *testing.T) {
+	resp := responsehelpertest.Parse(get("/text"))
+	if resp.Envelope != nil || resp.Success() || resp.ErrorMessage() != "" {
+		t.Errorf("envelope = %v", resp.Envelope)
+	}
+	if _, err := resp.Field("data"); err == nil {
+		t.Error("Field found a member of a plain text body")
+	}
+	if err := resp.DataAs(&serverUser{}); err == nil {
+		t.Error("DataAs decoded a plain text body")
+	}
+}
+
+func ExampleServer() {
+	server := responsehelpertest.NewServer()
+	server.GET("/users/:id", func(c *gin.Context) {
+		server.Helper.NotFound(c, "User not found")
+	})
+
+	resp := server.Do(http.MethodGet, "/users/404", nil)
+	fmt.Println(resp.Status(), resp.ErrorMessage())
+	// Output: 404 User not found
+}
@@ -0,0 +1,241 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// ErrResponseTooLarge is reported when a success envelope exceeds
+// WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("responsehelper: response too large")
+
+// errOverLimit stops measuring an envelope once it exceeds
+// WithMaxResponseBytes.
+var errOverLimit = errors.New("responsehelper: over the response size limit")
+
+const (
+	// ResponseTooLargeCode is the errorCode of the error sent instead of a
+	// success envelope exceeding WithMaxResponseBytes.
+	ResponseTooLargeCode = "RESPONSE_TOO_LARGE"
+	// ResponseTruncatedCode is the code of the warning added when
+	// WithResponseTruncation cut the data.
+	ResponseTruncatedCode = "RESPONSE_TRUNCATED"
+)
+
+// WithMaxResponseBytes caps the size of the success envelopes, measured as
+// their JSON, at n bytes. A larger one is replaced by an error with the
+// errorCode RESPONSE_TOO_LARGE, 500 unless WithResponseTooLargeStatus is
+// used, whose details hold the size measured and the allowed size, and is
+// reported to WithErrorReporter with the route. The items of slice data are
+// measured one at a time and the measuring stops at the first one past the
+// cap, so the size is a lower bound for a large page. SuccessLarge is not
+// capped. Zero, the default, disables the cap.
+//
+// Example:
+//
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(10 << 20))
+func WithMaxResponseBytes(n int64) Option {
+	return func(cfg *config) {
+		cfg.maxResponseBytes = max(n, 0)
+	}
+}
+
+// WithResponseTooLargeStatus replaces 500 as the status of the error sent
+// instead of a success envelope exceeding WithMaxResponseBytes, eg: 413 or
+// 507. Statuses other than 4xx and 5xx are ignored with a warning.
+func WithResponseTooLargeStatus(status int) Option {
+	return func(cfg *config) {
+		if err := ValidateErrorStatus(status); err != nil {
+			cfg.warnf("WithResponseTooLargeStatus: %v, ignoring it", err)
+			return
+		}
+		cfg.responseTooLargeStatus = status
+	}
+}
+
+// WithResponseTruncation makes a success envelope exceeding
+// WithMaxResponseBytes whose data is a slice keep the items that fit instead
+// of being replaced by an error, with a RESPONSE_TRUNCATED warning saying how
+// many were sent. The pagination, if any, is not changed.
+func WithResponseTruncation(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.truncateResponses = enabled
+	}
+}
+
+// withinResponseLimit reports whether envelope fits WithMaxResponseBytes,
+// truncating its data with WithResponseTruncation. Otherwise it sends the
+// RESPONSE_TOO_LARGE error and reports false.
+func (r *Core) withinResponseLimit(c Exchange, method string, envelope *SuccessEnvelope) bool {
+	if r.maxResponseBytes <= 0 {
+		return true
+	}
+	size, ok := r.envelopeSize(envelope)
+	if !ok || size <= r.maxResponseBytes {
+		return true
+	}
+	if r.truncateResponses && r.truncateData(envelope) {
+		return true
+	}
+	status := r.responseTooLargeStatus
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	err := fmt.Errorf("%w: %s sent %d bytes on %s, the limit is %d", ErrResponseTooLarge, method, size, responseRoute(c), r.maxResponseBytes)
+	if status < http.StatusInternalServerError {
+		// reported even though the error is a 4xx
+		r.report(c, status, err)
+	}
+	r.respondError(c, status, ErrorBody{
+		Code:      status,
+		Status:    statusText(status),
+		Message:   "The response is too large",
+		ErrorCode: ResponseTooLargeCode,
+		Details: map[string]int64{
+			"size":    size,
+			"maxSize": r.maxResponseBytes,
+		},
+	}, helperCall(nil, method, err)...)
+	return false
+}
+
+// envelopeSize returns the size of the JSON of envelope, counted until it
+// exceeds WithMaxResponseBytes, and false when it cannot be marshalled.
+func (r *Core) envelopeSize(envelope *SuccessEnvelope) (int64, bool) {
+	w := &limitWriter{limit: r.maxResponseBytes}
+	if err := r.encodeEnvelope(w, envelope); err != nil && !errors.Is(err, errOverLimit) {
+		return 0, false
+	}
+	return w.n, true
+}
+
+// encodeEnvelope writes the JSON of envelope to w, the same bytes as
+// marshalEnvelope. The items of slice data are marshalled and written one at
+// a time, so a limitWriter stops the encoding at the first item past the
+// limit rather than after marshalling the whole page.
+func (r *Core) encodeEnvelope(w io.Writer, envelope *SuccessEnvelope) error {
+	data := reflect.ValueOf(envelope.Data)
+	if !itemByItem(data) {
+		body, err := r.marshalEnvelope(envelope)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(body)
+		return err
+	}
+	rest := *envelope
+	rest.Count, rest.Data = nil, nil
+	body, err := r.marshalEnvelope(&rest)
+	if err != nil {
+		return err
+	}
+	// count and data are the first members, the rest always has success
+	head := []byte{'{'}
+	if envelope.Count != nil {
+		head = append(head, `"count":`...)
+		head = strconv.AppendInt(head, int64(*envelope.Count), 10)
+		head = append(head, ',')
+	}
+	head = append(head, `"data":[`...)
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	for i := 0; i < data.Len(); i++ {
+		item, err := r.marshalJSON(data.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(item); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write([]byte("],")); err != nil {
+		return err
+	}
+	_, err = w.Write(body[1:])
+	return err
+}
+
+// itemByItem reports whether data is a slice marshalled as the array of its
+// items, so encodeEnvelope can write them one at a time.
+func itemByItem(data reflect.Value) bool {
+	if data.Kind() != reflect.Slice || data.IsNil() || data.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	t := data.Type()
+	return !t.Implements(jsonMarshalerType) && !t.Implements(textMarshalerType)
+}
+
+// limitWriter counts the bytes written to it without keeping them, and fails
+// with errOverLimit once they exceed limit.
+type limitWriter struct {
+	n, limit int64
+}
+
+func (w *limitWriter) Write(data []byte) (int, error) {
+	w.n += int64(len(data))
+	if w.n > w.limit {
+		return 0, errOverLimit
+	}
+	return len(data), nil
+}
+
+// truncateData cuts the slice data of envelope to the most items fitting
+// WithMaxResponseBytes along with the warning saying so, and reports false
+// when the data is not a slice or not even an empty one fits.
+func (r *Core) truncateData(envelope *SuccessEnvelope) bool {
+	data := reflect.ValueOf(envelope.Data)
+	if data.Kind() != reflect.Slice || data.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	warnings := envelope.Warnings
+	fits := func(n int) bool {
+		envelope.Data = data.Slice(0, n).Interface()
+		envelope.Warnings = append(warnings[:len(warnings):len(warnings)], Warning{
+			Code:    ResponseTruncatedCode,
+			Message: "Only the first " + strconv.Itoa(n) + " of " + strconv.Itoa(data.Len()) + " items were sent, the response exceeded the size limit",
+		})
+		size, ok := r.envelopeSize(envelope)
+		return ok && size <= r.maxResponseBytes
+	}
+	// the largest n that fits, knowing data.Len() does not
+	low, high := -1, data.Len()
+	for high-low > 1 {
+		middle := low + (high-low)/2
+		if fits(middle) {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	if low < 0 {
+		envelope.Data, envelope.Warnings = data.Interface(), warnings
+		return false
+	}
+	return fits(low)
+}
+
+// responseRoute returns the route of c, eg: "GET /users/:id", or its path
+// when it matched none.
+func responseRoute(c Exchange) string {
+	var route string
+	if gc := ginContextOf(c); gc != nil {
+		route = gc.FullPath()
+	}
+	if route == "" {
+		route = requestPath(c)
+	}
+	if c.Request() != nil {
+		route = c.Request().Method + " " + route
+	}
+	return route
+}
@@ -0,0 +1,212 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// largeItems returns n items of about 100 bytes of JSON each.
+func largeItems(n int) []map[string]string {
+	items := make([]map[string]string, n)
+	for i := range items {
+		items[i] = map[string]string{"name": strings.Repeat("x", 88)}
+	}
+	return items
+}
+
+// countedItem counts how many times it is marshalled.
+type countedItem struct {
+	marshalled *int64
+}
+
+func (i countedItem) MarshalJSON() ([]byte, error) {
+	atomic.AddInt64(i.marshalled, 1)
+	return []byte(`"` + strings.Repeat("x", 98) + `"`), nil
+}
+
+func TestMaxResponseBytesUnderTheLimit(t *testing.T) {
+	items := largeItems(10)
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(4096)).Success(c, items)
+	plain, want := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().Success(plain, items)
+
+	if w.Code != http.StatusOK || w.Body.String() != want.Body.String() {
+		t.Errorf("status = %d, body =\n%s\nwant\n%s", w.Code, w.Body, want.Body)
+	}
+}
+
+func TestMaxResponseBytesAtTheLimit(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"slice":     func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, largeItems(10)) },
+		"empty":     func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, []int{}) },
+		"nil slice": func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, []int(nil)) },
+		"map":       func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"items": largeItems(3)}) },
+		"raw JSON": func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, responsehelper.RawJSON([]byte(`[1,2,3]`)))
+		},
+		"count": func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessWithCount(c, largeItems(2)) },
+		"pagination": func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, largeItems(2), responsehelper.NewPagination(1, 2, 9))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			plain, want := newContext(http.MethodGet, "/users")
+			respond(responsehelper.NewResponseHelper(responsehelper.WithCountPlacement(responsehelper.CountTopLevel)), plain)
+			size := int64(want.Body.Len())
+
+			for limit, status := range map[int64]int{size: http.StatusOK, size - 1: http.StatusInternalServerError} {
+				c, w := newContext(http.MethodGet, "/users")
+				respond(responsehelper.NewResponseHelper(responsehelper.WithCountPlacement(responsehelper.CountTopLevel), responsehelper.WithMaxResponseBytes(limit)), c)
+				if w.Code != status {
+					t.Errorf("a %d byte envelope with a limit of %d got %d, want %d", size, limit, w.Code, status)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxResponseBytesTooLarge(t *testing.T) {
+	var reports []report
+	h := reportingHelper(&reports, responsehelper.WithMaxResponseBytes(1024))
+	engine := gin.New()
+	engine.GET("/users/:group", func(c *gin.Context) { h.Success(c, largeItems(1000)) })
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/users/admins", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+	details, _ := errorBody["details"].(map[string]interface{})
+	if errorBody["errorCode"] != responsehelper.ResponseTooLargeCode || errorBody["message"] != "The response is too large" {
+		t.Errorf("error = %v", errorBody)
+	}
+	if size, _ := details["size"].(float64); details["maxSize"] != float64(1024) || size <= 1024 || size > 1024+200 {
+		t.Errorf("details = %v, want the size measured until it passed 1024", details)
+	}
+	if len(reports) != 1 || !errors.Is(reports[0].err, responsehelper.ErrResponseTooLarge) {
+		t.Fatalf("reports = %v, want ErrResponseTooLarge", reports)
+	}
+	if !strings.Contains(reports[0].err.Error(), "GET /users/:group") {
+		t.Errorf("reported %q, want the route", reports[0].err)
+	}
+}
+
+func TestMaxResponseBytesStopsMeasuring(t *testing.T) {
+	var marshalled int64
+	items := make([]countedItem, 100000)
+	for i := range items {
+		items[i] = countedItem{&marshalled}
+	}
+	c, w := newContext(http.MethodGet, "/events")
+	responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(10_000)).Success(c, items)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if marshalled > 200 {
+		t.Errorf("%d items were marshalled to measure a 10000 byte limit, want the measuring to stop past it", marshalled)
+	}
+}
+
+func TestResponseTooLargeStatus(t *testing.T) {
+	var reports []report
+	h := reportingHelper(&reports, responsehelper.WithMaxResponseBytes(512), responsehelper.WithResponseTooLargeStatus(http.StatusRequestEntityTooLarge))
+	c, w := newContext(http.MethodGet, "/users")
+	h.Success(c, largeItems(100))
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+	if len(reports) != 1 || !errors.Is(reports[0].err, responsehelper.ErrResponseTooLarge) {
+		t.Errorf("reports = %v, want the 4xx reported too", reports)
+	}
+
+	c, w = newContext(http.MethodGet, "/users")
+	logs := &captureHandler{level: slog.LevelWarn}
+	responsehelper.NewResponseHelper(
+		responsehelper.WithLogger(slog.New(logs)),
+		responsehelper.WithMaxResponseBytes(512),
+		responsehelper.WithResponseTooLargeStatus(http.StatusOK),
+	).Success(c, largeItems(100))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("an invalid status was used: %d, want 500", w.Code)
+	}
+	if len(logs.records) == 0 || !strings.Contains(logs.records[0].Message, "WithResponseTooLargeStatus") {
+		t.Errorf("logs = %v, want a warning about the status", logs.records)
+	}
+}
+
+func TestResponseTruncation(t *testing.T) {
+	const limit = 2048
+	h := responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(limit), responsehelper.WithResponseTruncation(true))
+	c, w := newContext(http.MethodGet, "/users")
+	h.SuccessWithPagination(c, largeItems(1000), responsehelper.NewPagination(1, 1000, 5000))
+
+	if w.Code != http.StatusOK || w.Body.Len() > limit {
+		t.Fatalf("status = %d with %d bytes, want 200 within %d", w.Code, w.Body.Len(), limit)
+	}
+	var body struct {
+		Data       []map[string]string       `json:"data"`
+		Pagination responsehelper.Pagination `json:"pagination"`
+		Warnings   []responsehelper.Warning  `json:"warnings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) == 0 || len(body.Data) >= 1000 {
+		t.Fatalf("%d items sent", len(body.Data))
+	}
+	if body.Pagination.PageSize != 1000 || body.Pagination.TotalRecords != 5000 {
+		t.Errorf("pagination = %+v, want it unchanged", body.Pagination)
+	}
+	if len(body.Warnings) != 1 || body.Warnings[0].Code != responsehelper.ResponseTruncatedCode {
+		t.Fatalf("warnings = %v", body.Warnings)
+	}
+	if want := "Only the first " + strconv.Itoa(len(body.Data)) + " of 1000 items were sent"; !strings.HasPrefix(body.Warnings[0].Message, want) {
+		t.Errorf("warning = %q, want %q", body.Warnings[0].Message, want)
+	}
+
+	// one more item does not fit
+	c, more := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper().SuccessWithPagination(c, largeItems(len(body.Data)+1), body.Pagination, responsehelper.WithWarnings(body.Warnings...))
+	if more.Body.Len() <= limit {
+		t.Errorf("%d items would fit in %d bytes too", len(body.Data)+1, more.Body.Len())
+	}
+}
+
+func TestResponseTruncationFallsBackToTheError(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"map":   func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"items": largeItems(100)}) },
+		"bytes": func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, []byte(strings.Repeat("x", 2048))) },
+		"not even empty": func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, largeItems(100), responsehelper.WithWarnings(responsehelper.Warning{Message: strings.Repeat("x", 2048)}))
+		},
+	} {
+		c, w := newContext(http.MethodGet, "/users")
+		respond(responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(1024), responsehelper.WithResponseTruncation(true)), c)
+
+		if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), responsehelper.ResponseTooLargeCode) {
+			t.Errorf("%s: %d %s, want RESPONSE_TOO_LARGE", name, w.Code, w.Body)
+		}
+	}
+}
+
+func TestSuccessLargeIsNotCapped(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/export")
+	responsehelper.NewResponseHelper(responsehelper.WithMaxResponseBytes(128)).SuccessLarge(c, largeItems(100))
+
+	if w.Code != http.StatusOK || w.Body.Len() < 100*100 {
+		t.Errorf("status = %d with %d bytes, want the whole body", w.Code, w.Body.Len())
+	}
+}
@@ -0,0 +1,108 @@
+package responsehelper
+
+import "net/http"
+
+// ResponseOption customises a single response, eg: WithErrorType("user-not-found").
+type ResponseOption func(*responseOptions)
+
+// responseOptions holds the settings of a single response.
+type responseOptions struct {
+	// errorType is the slug appended to the error type base URL.
+	errorType string
+	// retryable overrides whether the error is reported as retryable.
+	retryable *bool
+	// helpURL links the error to its documentation, overriding the configured template.
+	helpURL string
+	// rateLimit is sent as rate limit headers instead of the one set with SetRateLimit.
+	rateLimit *RateLimitInfo
+	// method is the helper method sending the response, reported to the response hooks.
+	method string
+	// err is the error passed to the helper, reported to the response hooks.
+	err error
+	// location is the URL of the resource created, sent as the Location header.
+	location string
+	// cache is sent as the Cache-Control header, see WithCache.
+	cache *CachePolicy
+	// warnings are added to the "warnings" of a success envelope.
+	warnings []Warning
+	// context holds the pairs of Detail, rendered as the "context" of an error.
+	context map[string]interface{}
+	// meta holds the pairs of MetaKV, added to the meta of a success envelope.
+	meta map[string]interface{}
+	// bound is the value of BoundTo, whose json tags name the fields of ValidationFailed.
+	bound interface{}
+}
+
+// retryableStatuses are the statuses that are reported as retryable by default.
+var retryableStatuses = map[int]bool{
+	http.StatusRequestTimeout:     true,
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// newResponseOptions applies opts to a fresh responseOptions.
+func newResponseOptions(opts []ResponseOption) responseOptions {
+	var options responseOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+// WithErrorType sets the slug of the error "type" URI, eg: "user-not-found"
+// renders "https://errors.example.com/user-not-found" when the helper was
+// created with WithErrorTypeBase("https://errors.example.com").
+func WithErrorType(slug string) ResponseOption {
+	return func(options *responseOptions) {
+		options.errorType = slug
+	}
+}
+
+// Retryable overrides the "retryable" flag of an error, eg: a 409 caused by
+// a deadlock that is safe to retry.
+func Retryable(retryable bool) ResponseOption {
+	return func(options *responseOptions) {
+		options.retryable = &retryable
+	}
+}
+
+// isRetryable reports whether a client may retry a request that failed with status.
+func (options *responseOptions) isRetryable(status int) bool {
+	if options.retryable != nil {
+		return *options.retryable
+	}
+	return retryableStatuses[status]
+}
+
+// WithHelpURL links the error to a runbook or public documentation, rendered as "helpUrl".
+func WithHelpURL(url string) ResponseOption {
+	return func(options *responseOptions) {
+		options.helpURL = url
+	}
+}
+
+// WithRateLimit sets the X-RateLimit-* headers of the response from info,
+// instead of the info set with SetRateLimit.
+func WithRateLimit(info RateLimitInfo) ResponseOption {
+	return func(options *responseOptions) {
+		options.rateLimit = &info
+	}
+}
+
+// helperCall records the helper method and error of a response for the
+// response hooks. The first recorded call wins, so a helper calling another
+// helper, eg: BadRequest calling BadRequestDetails, is reported by its own name.
+func helperCall(opts []ResponseOption, method string, err error) []ResponseOption {
+	return append(opts[:len(opts):len(opts)], func(options *responseOptions) {
+		if options.method == "" {
+			options.method = method
+		}
+		if options.err == nil {
+			options.err = err
+		}
+	})
+}
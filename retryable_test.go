@@ -0,0 +1,56 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestRetryableDefaults(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	for status, want := range map[int]bool{
+		http.StatusBadRequest:          false,
+		http.StatusNotFound:            false,
+		http.StatusRequestTimeout:      true,
+		http.StatusConflict:            false,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: false,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+	} {
+		c, w := newContext(http.MethodGet, "/users")
+		h.RespondAPIError(c, responsehelper.NewAPIError(status, "boom", nil))
+
+		assertField(t, w, "error.retryable", want)
+	}
+}
+
+func TestRetryableOnEveryHelper(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	c, w := newContext(http.MethodGet, "/users")
+	h.TooManyRequests(c, "slow down", 30*time.Second)
+
+	assertField(t, w, "error.retryable", true)
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want %q", got, "30")
+	}
+
+	c, w = newContext(http.MethodGet, "/users")
+	h.NotFound(c, "missing")
+	assertField(t, w, "error.retryable", false)
+}
+
+func TestRetryableOverride(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	c, w := newContext(http.MethodPost, "/transfers")
+	h.Conflict(c, "deadlock detected", errors.New("deadlock"), responsehelper.Retryable(true))
+	assertField(t, w, "error.retryable", true)
+
+	c, w = newContext(http.MethodPost, "/transfers")
+	h.ServiceUnavailable(c, "gone for good", 0, responsehelper.Retryable(false))
+	assertField(t, w, "error.retryable", false)
+}
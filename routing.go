@@ -0,0 +1,47 @@
+package responsehelper
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoRouteHandler sends the standard 404 envelope for paths without a route.
+//
+// Example:
+//
+//	router.NoRoute(responsehelper.NoRouteHandler(responseHelper))
+func NoRouteHandler(h ResponseHelper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.NotFound(c, "Route not found")
+	}
+}
+
+// NoMethodHandler sends the standard 405 envelope for routes that exist with
+// other methods. The Allow header set by gin is kept. gin only calls it when
+// HandleMethodNotAllowed is enabled on the engine.
+//
+// Example:
+//
+//	router.HandleMethodNotAllowed = true
+//	router.NoMethod(responsehelper.NoMethodHandler(responseHelper))
+func NoMethodHandler(h ResponseHelper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.RespondAPIError(c, NewAPIError(http.StatusMethodNotAllowed, "Method not allowed", nil))
+	}
+}
+
+// Install wires Recovery, NoRouteHandler and NoMethodHandler into engine, so
+// every response of the engine uses the standard envelope. It enables
+// HandleMethodNotAllowed.
+//
+// Example:
+//
+//	router := gin.New()
+//	responsehelper.Install(router, responseHelper)
+func Install(engine *gin.Engine, h ResponseHelper) {
+	engine.HandleMethodNotAllowed = true
+	engine.Use(Recovery(h))
+	engine.NoRoute(NoRouteHandler(h))
+	engine.NoMethod(NoMethodHandler(h))
+}
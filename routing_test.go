@@ -0,0 +1,42 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func routingEngine() *gin.Engine {
+	engine := gin.New()
+	responsehelper.Install(engine, responsehelper.NewResponseHelper())
+	engine.GET("/users", func(c *gin.Context) {})
+	engine.POST("/users", func(c *gin.Context) {})
+	engine.GET("/panic", func(c *gin.Context) { panic("boom") })
+	return engine
+}
+
+func TestNoRouteHandler(t *testing.T) {
+	w := serve(routingEngine(), httptest.NewRequest(http.MethodGet, "/nothing/here", nil))
+
+	assertError(t, w, http.StatusNotFound, "Route not found")
+	assertField(t, w, "error.status", "NOT_FOUND")
+}
+
+func TestNoMethodHandler(t *testing.T) {
+	w := serve(routingEngine(), httptest.NewRequest(http.MethodDelete, "/users", nil))
+
+	assertError(t, w, http.StatusMethodNotAllowed, "Method not allowed")
+	assertField(t, w, "error.status", "METHOD_NOT_ALLOWED")
+	if got := w.Header().Get("Allow"); got != "GET, POST" {
+		t.Errorf("Allow = %q, want %q", got, "GET, POST")
+	}
+}
+
+func TestInstallRecovers(t *testing.T) {
+	w := serve(routingEngine(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assertError(t, w, http.StatusInternalServerError, "Internal server error")
+}
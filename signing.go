@@ -0,0 +1,120 @@
+package responsehelper
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// DefaultSignatureHeader carries the signature of WithResponseSigning when no
+// header name is given.
+const DefaultSignatureHeader = "X-Signature-256"
+
+// signaturePrefix names the algorithm of a signature, like GitHub webhooks.
+const signaturePrefix = "sha256="
+
+// WithResponseSigning sets headerName, DefaultSignatureHeader when empty, on
+// the responses carrying a body to "sha256=" followed by the hex HMAC-SHA256
+// of the exact body bytes with secret, so consumers can check the body was
+// not altered, eg: the partners receiving callbacks. Streamed responses,
+// SuccessCSV and SuccessLarge, and responses without a body are not signed.
+// An empty secret disables signing.
+//
+// To rotate the secret, sign with the new one while consumers verify with
+// both, see VerifySignature, then retire the old one.
+//
+// Example:
+//
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithResponseSigning([]byte(os.Getenv("SIGNING_SECRET")), "X-Signature-256"))
+func WithResponseSigning(secret []byte, headerName string) Option {
+	return func(cfg *config) {
+		if headerName == "" {
+			headerName = DefaultSignatureHeader
+		}
+		cfg.signingSecret = append([]byte(nil), secret...)
+		cfg.signingHeader = headerName
+	}
+}
+
+// Sign returns the signature WithResponseSigning sets for body: "sha256="
+// followed by the hex HMAC-SHA256 of body with secret.
+func Sign(body, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature, the header set by
+// WithResponseSigning, is the signature of body with one of secrets, so
+// consumers keep accepting responses while the secret is rotated. The
+// comparison takes constant time.
+//
+// Example:
+//
+//	ok := responsehelper.VerifySignature(body, resp.Header.Get("X-Signature-256"), newSecret, oldSecret)
+func VerifySignature(body []byte, signature string, secrets ...[]byte) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	for _, secret := range secrets {
+		if len(secret) > 0 && hmac.Equal([]byte(Sign(body, secret)), []byte(signature)) {
+			return true
+		}
+	}
+	return false
+}
+
+// signBody runs write, which writes a whole response body to the Exchange
+// it is given, and signs the body with WithResponseSigning before it is sent.
+func (cfg *config) signBody(c Exchange, write func(c Exchange)) {
+	if len(cfg.signingSecret) == 0 {
+		write(c)
+		return
+	}
+	writer := &signingWriter{Exchange: c}
+	write(writer)
+	if writer.body.Len() > 0 {
+		c.Header().Set(cfg.signingHeader, Sign(writer.body.Bytes(), cfg.signingSecret))
+	}
+	if writer.status != 0 {
+		c.WriteHeader(writer.status)
+	}
+	if writer.body.Len() > 0 {
+		_, _ = c.Write(writer.body.Bytes())
+	}
+}
+
+// signingWriter holds back the body, and so the headers, until it is signed.
+type signingWriter struct {
+	Exchange
+	body   bytes.Buffer
+	status int
+}
+
+func (w *signingWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *signingWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *signingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *signingWriter) Status() int {
+	if w.status != 0 {
+		return w.status
+	}
+	return w.Exchange.Status()
+}
+
+// Flush is held back with the body.
+func (w *signingWriter) Flush() {}
@@ -0,0 +1,137 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var signingSecret = []byte("s3cret")
+
+func TestResponseSigning(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    []responsehelper.Option
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		status  int
+	}{
+		{"Success", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, gin.H{"id": 42, "name": "arun"})
+		}, http.StatusOK},
+		{"Created", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Created(c, gin.H{"id": 42}, responsehelper.WithLocation("/users/42"))
+		}, http.StatusCreated},
+		{"WriteJSON", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.WriteJSON(c, http.StatusAccepted, gin.H{"queued": true})
+		}, http.StatusAccepted},
+		{"NotFound", nil, func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "User not found") }, http.StatusNotFound},
+		{"precomputed", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.PrecomputeError(http.StatusNotFound, "missing")
+			h.NotFound(c, "missing")
+		}, http.StatusNotFound},
+		{"InternalError", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.InternalError(c, "Oops", errors.New("db down"))
+		}, http.StatusInternalServerError},
+		{"Problem", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Problem(c, http.StatusConflict, "", "Conflict", "Already taken", nil)
+		}, http.StatusConflict},
+		{"OAuthError", nil, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.OAuthError(c, responsehelper.OAuthErrorInvalidGrant, "Expired", http.StatusBadRequest)
+		}, http.StatusBadRequest},
+		{"XML", []responsehelper.Option{responsehelper.WithDefaultFormat(responsehelper.FormatXML)}, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Success(c, gin.H{"id": 42})
+		}, http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := responsehelper.NewResponseHelper(append(tt.opts, responsehelper.WithResponseSigning(signingSecret, ""))...)
+			c, w := newContext(http.MethodGet, "/users/42")
+			tt.respond(h, c)
+
+			if w.Code != tt.status || w.Body.Len() == 0 {
+				t.Fatalf("status = %d, body: %q, want %d with a body", w.Code, w.Body, tt.status)
+			}
+			signature := w.Header().Get(responsehelper.DefaultSignatureHeader)
+			if want := responsehelper.Sign(w.Body.Bytes(), signingSecret); signature != want {
+				t.Errorf("%s = %q, want %q, the signature of the body sent", responsehelper.DefaultSignatureHeader, signature, want)
+			}
+			if !responsehelper.VerifySignature(w.Body.Bytes(), signature, signingSecret) {
+				t.Errorf("VerifySignature rejected %q", signature)
+			}
+		})
+	}
+}
+
+func TestResponseSigningHeaderName(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithResponseSigning(signingSecret, "X-Hub-Signature-256")).Success(c, gin.H{"id": 42})
+
+	if got := w.Header().Get("X-Hub-Signature-256"); got != responsehelper.Sign(w.Body.Bytes(), signingSecret) {
+		t.Errorf("X-Hub-Signature-256 = %q", got)
+	}
+	if got := w.Header().Get(responsehelper.DefaultSignatureHeader); got != "" {
+		t.Errorf("%s = %q with another header name", responsehelper.DefaultSignatureHeader, got)
+	}
+}
+
+func TestResponseSigningSkipped(t *testing.T) {
+	for name, tt := range map[string]struct {
+		secret  []byte
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+	}{
+		"empty secret": {nil, func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 42}) }},
+		"no content":   {signingSecret, func(h responsehelper.ResponseHelper, c *gin.Context) { h.NoContent(c) }},
+		"SuccessLarge": {signingSecret, func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessLarge(c, []int{1, 2, 3}) }},
+		"SuccessCSV": {signingSecret, func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			tt.respond(responsehelper.NewResponseHelper(responsehelper.WithResponseSigning(tt.secret, "")), c)
+
+			if got, ok := w.Header()[responsehelper.DefaultSignatureHeader]; ok {
+				t.Errorf("%s = %q, want no signature", responsehelper.DefaultSignatureHeader, got)
+			}
+		})
+	}
+}
+
+func TestResponseSigningCopiesTheSecret(t *testing.T) {
+	secret := []byte("s3cret")
+	h := responsehelper.NewResponseHelper(responsehelper.WithResponseSigning(secret, ""))
+	secret[0] = 'x'
+
+	c, w := newContext(http.MethodGet, "/users/42")
+	h.Success(c, gin.H{"id": 42})
+	if !responsehelper.VerifySignature(w.Body.Bytes(), w.Header().Get(responsehelper.DefaultSignatureHeader), signingSecret) {
+		t.Error("changing the secret after WithResponseSigning changed the signature")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"success":true}`)
+	oldSecret, newSecret := []byte("old"), []byte("new")
+	signature := responsehelper.Sign(body, oldSecret)
+
+	for name, tt := range map[string]struct {
+		body      []byte
+		signature string
+		secrets   [][]byte
+		want      bool
+	}{
+		"rotating":          {body, signature, [][]byte{newSecret, oldSecret}, true},
+		"retired secret":    {body, signature, [][]byte{newSecret}, false},
+		"altered body":      {[]byte(`{"success":false}`), signature, [][]byte{oldSecret}, false},
+		"no prefix":         {body, signature[len("sha256="):], [][]byte{oldSecret}, false},
+		"empty secret":      {body, responsehelper.Sign(body, nil), [][]byte{nil}, false},
+		"no secrets":        {body, signature, nil, false},
+		"another signature": {body, "sha256=00", [][]byte{oldSecret}, false},
+	} {
+		if got := responsehelper.VerifySignature(tt.body, tt.signature, tt.secrets...); got != tt.want {
+			t.Errorf("%s: VerifySignature = %t, want %t", name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package responsehelper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+)
+
+// ellipsis ends the messages truncated by WithMaxMessageLength.
+const ellipsis = "…"
+
+// ErrInvalidStatus is wrapped by the errors of ValidateErrorStatus and
+// ValidateSuccessStatus.
+var ErrInvalidStatus = errors.New("responsehelper: invalid status")
+
+// ValidateErrorStatus returns an error wrapping ErrInvalidStatus unless
+// status is a 4xx or 5xx status, the ones the error helpers send. The
+// helpers send 500 Internal Server Error instead of an invalid status and log
+// a warning, use it to check a status before passing it, eg: one read from a
+// configuration.
+//
+// Example:
+//
+//	if err := responsehelper.ValidateErrorStatus(cfg.Status); err != nil {
+//		return err
+//	}
+func ValidateErrorStatus(status int) error {
+	if status < http.StatusBadRequest || status > 599 {
+		return fmt.Errorf("%w: %d is not an error status (400-599)", ErrInvalidStatus, status)
+	}
+	return nil
+}
+
+// ValidateSuccessStatus returns an error wrapping ErrInvalidStatus unless
+// status is a 2xx status, the ones the success helpers send.
+func ValidateSuccessStatus(status int) error {
+	if status < http.StatusOK || status > 299 {
+		return fmt.Errorf("%w: %d is not a success status (200-299)", ErrInvalidStatus, status)
+	}
+	return nil
+}
+
+// WithMaxMessageLength truncates the messages of the error responses longer
+// than max characters, ending them with an ellipsis, eg: error texts passed
+// through as messages. A max below 1, the default, keeps them whole.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithMaxMessageLength(200))
+func WithMaxMessageLength(max int) Option {
+	return func(cfg *config) {
+		cfg.maxMessageLength = max
+	}
+}
+
+// validErrorStatus returns status when it is an error status, and 500 with
+// a warning otherwise.
+func (cfg *config) validErrorStatus(status int, method string) int {
+	if err := ValidateErrorStatus(status); err != nil {
+		cfg.warnf("%s: %v, sending 500 instead", method, err)
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// validSuccessStatus returns status when it is a success status, and 200
+// with a warning otherwise.
+func (cfg *config) validSuccessStatus(status int, method string) int {
+	if err := ValidateSuccessStatus(status); err != nil {
+		cfg.warnf("%s: %v, sending 200 instead", method, err)
+		return http.StatusOK
+	}
+	return status
+}
+
+// truncateMessage returns message cut to the length of WithMaxMessageLength.
+func (cfg *config) truncateMessage(message string) string {
+	if cfg.maxMessageLength < 1 || utf8.RuneCountInString(message) <= cfg.maxMessageLength {
+		return message
+	}
+	kept := 0
+	for i := range message {
+		if kept == cfg.maxMessageLength-1 {
+			return message[:i] + ellipsis
+		}
+		kept++
+	}
+	return message
+}
@@ -0,0 +1,136 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidateStatus(t *testing.T) {
+	for _, tt := range []struct {
+		status         int
+		error, success bool
+	}{
+		{-1, false, false},
+		{0, false, false},
+		{42, false, false},
+		{199, false, false},
+		{200, false, true},
+		{204, false, true},
+		{299, false, true},
+		{300, false, false},
+		{399, false, false},
+		{400, true, false},
+		{499, true, false},
+		{500, true, false},
+		{599, true, false},
+		{600, false, false},
+	} {
+		if err := responsehelper.ValidateErrorStatus(tt.status); (err == nil) != tt.error || err != nil && !errors.Is(err, responsehelper.ErrInvalidStatus) {
+			t.Errorf("ValidateErrorStatus(%d) = %v", tt.status, err)
+		}
+		if err := responsehelper.ValidateSuccessStatus(tt.status); (err == nil) != tt.success || err != nil && !errors.Is(err, responsehelper.ErrInvalidStatus) {
+			t.Errorf("ValidateSuccessStatus(%d) = %v", tt.status, err)
+		}
+	}
+}
+
+func TestInvalidErrorStatusSends500(t *testing.T) {
+	respond := map[string]func(h responsehelper.ResponseHelper, c *gin.Context, status int){
+		"Errors": func(h responsehelper.ResponseHelper, c *gin.Context, status int) {
+			h.Errors(c, status, []responsehelper.ErrorItem{{Code: "INVALID", Message: "invalid"}})
+		},
+		"WriteError": func(h responsehelper.ResponseHelper, c *gin.Context, status int) {
+			h.WriteError(c, status, responsehelper.ErrorBody{Message: "invalid"})
+		},
+		"RespondAPIError": func(h responsehelper.ResponseHelper, c *gin.Context, status int) {
+			h.RespondAPIError(c, &responsehelper.APIError{Status: status, Message: "invalid"})
+		},
+		"RespondCode": func(h responsehelper.ResponseHelper, c *gin.Context, status int) {
+			h.RegisterCode("INVALID", status, "invalid")
+			h.RespondCode(c, "INVALID")
+		},
+	}
+	for name, respond := range respond {
+		for _, tt := range []struct {
+			status, want int
+			warned       bool
+		}{
+			{42, http.StatusInternalServerError, true},
+			{http.StatusOK, http.StatusInternalServerError, true},
+			{399, http.StatusInternalServerError, true},
+			{600, http.StatusInternalServerError, true},
+			{http.StatusBadRequest, http.StatusBadRequest, false},
+			{599, 599, false},
+		} {
+			logs := &captureHandler{level: slog.LevelWarn}
+			c, w := newContext(http.MethodGet, "/users")
+			respond(responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(logs))), c, tt.status)
+
+			if w.Code != tt.want {
+				t.Errorf("%s(%d): status = %d, want %d", name, tt.status, w.Code, tt.want)
+			}
+			assertField(t, w, "error.code", float64(tt.want))
+			warned := false
+			for _, record := range logs.records {
+				warned = warned || strings.Contains(record.Message, "is not an error status")
+			}
+			if warned != tt.warned {
+				t.Errorf("%s(%d): warned = %t, want %t", name, tt.status, warned, tt.warned)
+			}
+		}
+	}
+}
+
+func TestInvalidProblemStatusSends500(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(&captureHandler{}))).
+		Problem(c, http.StatusOK, "", "Broken", "", nil)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if status := decodeBody(t, w)["status"]; status != float64(http.StatusInternalServerError) {
+		t.Errorf("status member = %v, want 500", status)
+	}
+}
+
+func TestMaxMessageLength(t *testing.T) {
+	for _, tt := range []struct {
+		max           int
+		message, want string
+	}{
+		{0, "a message nobody truncates", "a message nobody truncates"},
+		{-1, "a message nobody truncates", "a message nobody truncates"},
+		{5, "abcdefgh", "abcd…"},
+		{5, "abcde", "abcde"},
+		{5, "abcdef", "abcd…"},
+		{1, "abc", "…"},
+		{4, "ÄÖÜßé", "ÄÖÜ…"},
+		{3, "日本語", "日本語"},
+		{3, "", ""},
+	} {
+		c, w := newContext(http.MethodGet, "/users")
+		responsehelper.NewResponseHelper(responsehelper.WithMaxMessageLength(tt.max)).BadRequest(c, tt.message, "")
+		assertField(t, w, "error.message", tt.want)
+	}
+}
+
+func TestMaxMessageLengthOfOtherErrors(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithMaxMessageLength(6))
+
+	c, w := newContext(http.MethodGet, "/users")
+	h.RespondAPIError(c, &responsehelper.APIError{Status: http.StatusConflict, Message: "Already taken"})
+	assertField(t, w, "error.message", "Alrea…")
+
+	c, w = newContext(http.MethodGet, "/users")
+	h.Problem(c, http.StatusConflict, "", "Already taken", "", nil)
+	if title := decodeBody(t, w)["title"]; title != "Alrea…" {
+		t.Errorf("title = %v, want %q", title, "Alrea…")
+	}
+}
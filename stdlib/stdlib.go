@@ -0,0 +1,391 @@
+// Package stdlib sends the responsehelper envelopes from plain net/http
+// handlers, eg: webhooks or pprof wrappers, so they match the Gin handlers
+// byte for byte.
+package stdlib
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+// Responder has the method set of responsehelper.ResponseHelper for
+// net/http handlers. It renders with a responsehelper.Core, so the
+// configuration, eg: sanitization or problem details, and the bodies are the
+// same as for Gin.
+type Responder struct {
+	core *responsehelper.Core
+}
+
+// NewResponder creates a Responder configured with opts, the options of
+// responsehelper.NewResponseHelper.
+//
+// Example:
+//
+//	responder := stdlib.NewResponder(responsehelper.WithErrorSanitization(true))
+//	http.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+//		responder.Success(w, r, data)
+//	})
+func NewResponder(opts ...responsehelper.Option) *Responder {
+	return &Responder{core: responsehelper.NewCore(opts...)}
+}
+
+// Wrap creates a Responder rendering with the Core of helper, so Gin and
+// net/http handlers share one configuration, its registered codes and hooks.
+// It panics when helper was not created by responsehelper.NewResponseHelper
+// or responsehelper.New.
+func Wrap(helper responsehelper.ResponseHelper) *Responder {
+	core := responsehelper.CoreOf(helper)
+	if core == nil {
+		panic("stdlib: Wrap needs a helper created by responsehelper.NewResponseHelper")
+	}
+	return &Responder{core: core}
+}
+
+type valuesKey struct{}
+
+// WithValue returns a copy of ctx in which the Responder finds value under
+// key, like a Gin handler finds values set with c.Set. Use it to provide the
+// meta (responsehelper.MetaKey), the request ID (responsehelper.RequestIDKey)
+// or the rate limit (responsehelper.RateLimitKey).
+//
+// Example:
+//
+//	r = r.WithContext(stdlib.WithValue(r.Context(), responsehelper.RequestIDKey, requestID))
+func WithValue(ctx context.Context, key string, value interface{}) context.Context {
+	parent, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	values := make(map[string]interface{}, len(parent)+1)
+	for k, v := range parent {
+		values[k] = v
+	}
+	values[key] = value
+	return context.WithValue(ctx, valuesKey{}, values)
+}
+
+// Value returns the value stored under key with WithValue, and false when
+// there is none.
+func Value(ctx context.Context, key string) (interface{}, bool) {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	value, ok := values[key]
+	return value, ok
+}
+
+// WithMeta returns a copy of ctx in which the Responder finds the meta sent
+// with every response.
+func WithMeta(ctx context.Context, meta interface{}) context.Context {
+	return WithValue(ctx, responsehelper.MetaKey, meta)
+}
+
+// exchange is the responsehelper.Exchange of a net/http request. The values
+// set while a response is written are its own, the ones of the request
+// context are read through.
+type exchange struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	values map[string]interface{}
+	status int
+	size   int
+}
+
+// exchange returns the Exchange the Core renders to for w and r.
+func (s *Responder) exchange(w http.ResponseWriter, r *http.Request) *exchange {
+	return &exchange{w: w, r: r, status: http.StatusOK, size: -1}
+}
+
+func (x *exchange) Request() *http.Request {
+	return x.r
+}
+
+func (x *exchange) Get(key string) (interface{}, bool) {
+	if value, ok := x.values[key]; ok {
+		return value, true
+	}
+	if x.r == nil {
+		return nil, false
+	}
+	return Value(x.r.Context(), key)
+}
+
+func (x *exchange) Set(key string, value interface{}) {
+	if x.values == nil {
+		x.values = make(map[string]interface{})
+	}
+	x.values[key] = value
+}
+
+func (x *exchange) Header() http.Header {
+	return x.w.Header()
+}
+
+func (x *exchange) WriteHeader(status int) {
+	if x.Written() {
+		return
+	}
+	x.status, x.size = status, 0
+	x.w.WriteHeader(status)
+}
+
+func (x *exchange) Write(data []byte) (int, error) {
+	x.WriteHeader(http.StatusOK)
+	n, err := x.w.Write(data)
+	x.size += n
+	return n, err
+}
+
+func (x *exchange) Flush() {
+	if flusher, ok := x.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (x *exchange) Status() int {
+	return x.status
+}
+
+func (x *exchange) Size() int {
+	return x.size
+}
+
+func (x *exchange) Written() bool {
+	return x.size >= 0
+}
+
+// BadRequest sends a 400 Bad Request response with string details.
+//
+// Deprecated: use BadRequestDetails.
+func (s *Responder) BadRequest(w http.ResponseWriter, r *http.Request, message string, details string, opts ...responsehelper.ResponseOption) {
+	s.core.BadRequest(s.exchange(w, r), message, details, opts...)
+}
+
+// BadRequestDetails sends a 400 Bad Request response with structured details.
+func (s *Responder) BadRequestDetails(w http.ResponseWriter, r *http.Request, message string, details interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.BadRequestDetails(s.exchange(w, r), message, details, opts...)
+}
+
+// AlreadyExists sends a 409 Conflict response for a resource that already exists.
+func (s *Responder) AlreadyExists(w http.ResponseWriter, r *http.Request, resource string, err error, opts ...responsehelper.ResponseOption) {
+	s.core.AlreadyExists(s.exchange(w, r), resource, err, opts...)
+}
+
+// Conflict sends a 409 Conflict response.
+func (s *Responder) Conflict(w http.ResponseWriter, r *http.Request, message string, err error, opts ...responsehelper.ResponseOption) {
+	s.core.Conflict(s.exchange(w, r), message, err, opts...)
+}
+
+// NotFound sends a 404 Not Found response.
+func (s *Responder) NotFound(w http.ResponseWriter, r *http.Request, message string, opts ...responsehelper.ResponseOption) {
+	s.core.NotFound(s.exchange(w, r), message, opts...)
+}
+
+// ResourceNotFound sends a 404 Not Found response with the resource and its identifier.
+func (s *Responder) ResourceNotFound(w http.ResponseWriter, r *http.Request, resource string, id interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.ResourceNotFound(s.exchange(w, r), resource, id, opts...)
+}
+
+// Unauthorized sends a 401 Unauthorized response.
+func (s *Responder) Unauthorized(w http.ResponseWriter, r *http.Request, message string, opts ...responsehelper.ResponseOption) {
+	s.core.Unauthorized(s.exchange(w, r), message, opts...)
+}
+
+// UnauthorizedWithChallenge sends a 401 Unauthorized response with a WWW-Authenticate challenge.
+func (s *Responder) UnauthorizedWithChallenge(w http.ResponseWriter, r *http.Request, message, scheme, realm string, params map[string]string, opts ...responsehelper.ResponseOption) {
+	s.core.UnauthorizedWithChallenge(s.exchange(w, r), message, scheme, realm, params, opts...)
+}
+
+// UnauthorizedReason sends a 401 Unauthorized response telling why the credentials were rejected.
+func (s *Responder) UnauthorizedReason(w http.ResponseWriter, r *http.Request, reason responsehelper.AuthFailureReason, message string, opts ...responsehelper.ResponseOption) {
+	s.core.UnauthorizedReason(s.exchange(w, r), reason, message, opts...)
+}
+
+// Forbidden sends a 403 Forbidden response.
+func (s *Responder) Forbidden(w http.ResponseWriter, r *http.Request, message string, opts ...responsehelper.ResponseOption) {
+	s.core.Forbidden(s.exchange(w, r), message, opts...)
+}
+
+// ForbiddenScope sends a 403 Forbidden response with the required and granted permissions.
+func (s *Responder) ForbiddenScope(w http.ResponseWriter, r *http.Request, message string, required []string, granted []string, opts ...responsehelper.ResponseOption) {
+	s.core.ForbiddenScope(s.exchange(w, r), message, required, granted, opts...)
+}
+
+// TooManyRequests sends a 429 Too Many Requests response.
+func (s *Responder) TooManyRequests(w http.ResponseWriter, r *http.Request, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) {
+	s.core.TooManyRequests(s.exchange(w, r), message, retryAfter, opts...)
+}
+
+// ServiceUnavailable sends a 503 Service Unavailable response.
+func (s *Responder) ServiceUnavailable(w http.ResponseWriter, r *http.Request, message string, retryAfter time.Duration, opts ...responsehelper.ResponseOption) {
+	s.core.ServiceUnavailable(s.exchange(w, r), message, retryAfter, opts...)
+}
+
+// InternalError sends a 500 Internal Server Error response.
+func (s *Responder) InternalError(w http.ResponseWriter, r *http.Request, message string, err error, opts ...responsehelper.ResponseOption) {
+	s.core.InternalError(s.exchange(w, r), message, err, opts...)
+}
+
+// Success sends a 200 OK response with data.
+func (s *Responder) Success(w http.ResponseWriter, r *http.Request, data interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.Success(s.exchange(w, r), data, opts...)
+}
+
+// PartialSuccess sends a 200 OK response with the data and the parts that could not be loaded.
+func (s *Responder) PartialSuccess(w http.ResponseWriter, r *http.Request, data interface{}, failures []responsehelper.PartialFailure, opts ...responsehelper.ResponseOption) {
+	s.core.PartialSuccess(s.exchange(w, r), data, failures, opts...)
+}
+
+// SuccessCSV sends a 200 OK response with rows as a CSV attachment.
+func (s *Responder) SuccessCSV(w http.ResponseWriter, r *http.Request, filename string, rows interface{}) {
+	s.core.SuccessCSV(s.exchange(w, r), filename, rows)
+}
+
+// SuccessIfModified sends a 200 OK response with the data of dataFn, or a
+// 304 Not Modified response when If-Modified-Since is not before lastModified.
+func (s *Responder) SuccessIfModified(w http.ResponseWriter, r *http.Request, lastModified time.Time, dataFn func() (interface{}, error), opts ...responsehelper.ResponseOption) {
+	s.core.SuccessIfModified(s.exchange(w, r), lastModified, dataFn, opts...)
+}
+
+// SuccessWithPagination sends a 200 OK response with data and pagination metadata.
+func (s *Responder) SuccessWithPagination(w http.ResponseWriter, r *http.Request, data interface{}, meta interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.SuccessWithPagination(s.exchange(w, r), data, meta, opts...)
+}
+
+// SuccessWithCursor sends a 200 OK response with data and cursor pagination metadata.
+func (s *Responder) SuccessWithCursor(w http.ResponseWriter, r *http.Request, data interface{}, cur responsehelper.CursorPagination) {
+	s.core.SuccessWithCursor(s.exchange(w, r), data, cur)
+}
+
+// SuccessWithCount sends a 200 OK response with data and its number of items.
+func (s *Responder) SuccessWithCount(w http.ResponseWriter, r *http.Request, data interface{}) {
+	s.core.SuccessWithCount(s.exchange(w, r), data)
+}
+
+// SuccessLarge sends a 200 OK response with data streamed to the client.
+func (s *Responder) SuccessLarge(w http.ResponseWriter, r *http.Request, data interface{}) {
+	s.core.SuccessLarge(s.exchange(w, r), data)
+}
+
+// SuccessWithLinks sends a 200 OK response with data and hypermedia links.
+func (s *Responder) SuccessWithLinks(w http.ResponseWriter, r *http.Request, data interface{}, links responsehelper.Links) {
+	s.core.SuccessWithLinks(s.exchange(w, r), data, links)
+}
+
+// Created sends a 201 Created response with data.
+func (s *Responder) Created(w http.ResponseWriter, r *http.Request, data interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.Created(s.exchange(w, r), data, opts...)
+}
+
+// Patched sends a 200 OK response with the updated resource and the changed fields.
+func (s *Responder) Patched(w http.ResponseWriter, r *http.Request, resource string, data interface{}, changedFields []string, opts ...responsehelper.ResponseOption) {
+	s.core.Patched(s.exchange(w, r), resource, data, changedFields, opts...)
+}
+
+// OperationAccepted sends a 202 Accepted response with a long-running operation.
+func (s *Responder) OperationAccepted(w http.ResponseWriter, r *http.Request, op responsehelper.Operation) {
+	s.core.OperationAccepted(s.exchange(w, r), op)
+}
+
+// OperationStatus sends the state of a long-running operation.
+func (s *Responder) OperationStatus(w http.ResponseWriter, r *http.Request, op responsehelper.Operation) {
+	s.core.OperationStatus(s.exchange(w, r), op)
+}
+
+// Deleted sends the response for a deleted resource, see WithDeleteStatus.
+func (s *Responder) Deleted(w http.ResponseWriter, r *http.Request, message string) {
+	s.core.Deleted(s.exchange(w, r), message)
+}
+
+// DeletedNoContent sends a 204 No Content response for a deleted resource.
+func (s *Responder) DeletedNoContent(w http.ResponseWriter, r *http.Request) {
+	s.core.DeletedNoContent(s.exchange(w, r))
+}
+
+// NoContent sends a 204 No Content response.
+func (s *Responder) NoContent(w http.ResponseWriter, r *http.Request) {
+	s.core.NoContent(s.exchange(w, r))
+}
+
+// RespondAPIError sends the error response described by err.
+func (s *Responder) RespondAPIError(w http.ResponseWriter, r *http.Request, err *responsehelper.APIError, opts ...responsehelper.ResponseOption) {
+	s.core.RespondAPIError(s.exchange(w, r), err, opts...)
+}
+
+// Respond sends data when err is nil and the error response for err otherwise.
+func (s *Responder) Respond(w http.ResponseWriter, r *http.Request, err error, data interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.Respond(s.exchange(w, r), err, data, opts...)
+}
+
+// Errors sends an error response listing several errors.
+func (s *Responder) Errors(w http.ResponseWriter, r *http.Request, statusCode int, errs []responsehelper.ErrorItem, opts ...responsehelper.ResponseOption) {
+	s.core.Errors(s.exchange(w, r), statusCode, errs, opts...)
+}
+
+// Problem sends an RFC 7807 problem details response.
+func (s *Responder) Problem(w http.ResponseWriter, r *http.Request, status int, typ, title, detail string, extensions map[string]interface{}) {
+	s.core.Problem(s.exchange(w, r), status, typ, title, detail, extensions)
+}
+
+// WriteJSON sends body as is, without the envelope.
+func (s *Responder) WriteJSON(w http.ResponseWriter, r *http.Request, status int, body interface{}, opts ...responsehelper.ResponseOption) {
+	s.core.WriteJSON(s.exchange(w, r), status, body, opts...)
+}
+
+// WriteError sends errBody in the standard error envelope.
+func (s *Responder) WriteError(w http.ResponseWriter, r *http.Request, status int, errBody responsehelper.ErrorBody, opts ...responsehelper.ResponseOption) {
+	s.core.WriteError(s.exchange(w, r), status, errBody, opts...)
+}
+
+// OAuthError sends an RFC 6749 error response, eg: of a token endpoint.
+func (s *Responder) OAuthError(w http.ResponseWriter, r *http.Request, errCode string, description string, status int) {
+	s.core.OAuthError(s.exchange(w, r), errCode, description, status)
+}
+
+// ValidationFailed sends the validation errors of err.
+func (s *Responder) ValidationFailed(w http.ResponseWriter, r *http.Request, err error, opts ...responsehelper.ResponseOption) {
+	s.core.ValidationFailed(s.exchange(w, r), err, opts...)
+}
+
+// NotFoundKey sends a 404 Not Found response with a translated message.
+func (s *Responder) NotFoundKey(w http.ResponseWriter, r *http.Request, key string, args ...interface{}) {
+	s.core.NotFoundKey(s.exchange(w, r), key, args...)
+}
+
+// BadRequestKey sends a 400 Bad Request response with a translated message.
+func (s *Responder) BadRequestKey(w http.ResponseWriter, r *http.Request, key string, details string, args ...interface{}) {
+	s.core.BadRequestKey(s.exchange(w, r), key, details, args...)
+}
+
+// UnauthorizedKey sends a 401 Unauthorized response with a translated message.
+func (s *Responder) UnauthorizedKey(w http.ResponseWriter, r *http.Request, key string, args ...interface{}) {
+	s.core.UnauthorizedKey(s.exchange(w, r), key, args...)
+}
+
+// ForbiddenKey sends a 403 Forbidden response with a translated message.
+func (s *Responder) ForbiddenKey(w http.ResponseWriter, r *http.Request, key string, args ...interface{}) {
+	s.core.ForbiddenKey(s.exchange(w, r), key, args...)
+}
+
+// ConflictKey sends a 409 Conflict response with a translated message.
+func (s *Responder) ConflictKey(w http.ResponseWriter, r *http.Request, key string, err error, args ...interface{}) {
+	s.core.ConflictKey(s.exchange(w, r), key, err, args...)
+}
+
+// InternalErrorKey sends a 500 Internal Server Error response with a translated message.
+func (s *Responder) InternalErrorKey(w http.ResponseWriter, r *http.Request, key string, err error, args ...interface{}) {
+	s.core.InternalErrorKey(s.exchange(w, r), key, err, args...)
+}
+
+// RegisterCode registers a business error code for RespondCode.
+func (s *Responder) RegisterCode(code string, status int, defaultMessage string) {
+	s.core.RegisterCode(code, status, defaultMessage)
+}
+
+// PrecomputeError registers an error response whose body is marshalled once.
+func (s *Responder) PrecomputeError(status int, message string) {
+	s.core.PrecomputeError(status, message)
+}
+
+// RespondCode sends the error response registered for code.
+func (s *Responder) RespondCode(w http.ResponseWriter, r *http.Request, code string, args ...interface{}) {
+	s.core.RespondCode(s.exchange(w, r), code, args...)
+}
@@ -0,0 +1,228 @@
+package stdlib_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/stdlib"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// parityCall sends the same response through the Gin helper and the Responder.
+type parityCall struct {
+	name      string
+	gin       func(h responsehelper.ResponseHelper, c *gin.Context)
+	responder func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request)
+}
+
+var parityCalls = []parityCall{
+	{"BadRequest",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.BadRequest(c, "Invalid input", "name is required")
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.BadRequest(w, r, "Invalid input", "name is required")
+		}},
+	{"ResourceNotFound",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.ResourceNotFound(c, "user", 42) },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.ResourceNotFound(w, r, "user", 42)
+		}},
+	{"UnauthorizedReason",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.UnauthorizedReason(c, responsehelper.TokenExpired, "Your session expired")
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.UnauthorizedReason(w, r, responsehelper.TokenExpired, "Your session expired")
+		}},
+	{"TooManyRequests",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.TooManyRequests(c, "Slow down", 30*time.Second)
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.TooManyRequests(w, r, "Slow down", 30*time.Second)
+		}},
+	{"InternalError",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.InternalError(c, "Oops", errors.New("db down"))
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.InternalError(w, r, "Oops", errors.New("db down"))
+		}},
+	{"Success",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, map[string]int{"id": 42}) },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.Success(w, r, map[string]int{"id": 42})
+		}},
+	{"Created",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Created(c, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.Created(w, r, map[string]int{"id": 42}, responsehelper.WithLocation("/users/42"))
+		}},
+	{"SuccessWithPagination",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.SuccessWithPagination(w, r, []int{1, 2}, responsehelper.NewPagination(1, 2, 5))
+		}},
+	{"SuccessLarge",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessLarge(c, []int{1, 2, 3}) },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.SuccessLarge(w, r, []int{1, 2, 3})
+		}},
+	{"SuccessCSV",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.SuccessCSV(w, r, "users.csv", []map[string]string{{"name": "arun"}})
+		}},
+	{"NoContent",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.NoContent(c) },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) { s.NoContent(w, r) }},
+	{"Deleted",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.Deleted(c, "User") },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) { s.Deleted(w, r, "User") }},
+	{"DeletedNoContent",
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.DeletedNoContent(c) },
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) { s.DeletedNoContent(w, r) }},
+	{"Problem",
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Problem(c, http.StatusConflict, "", "Conflict", "Already taken", map[string]interface{}{"field": "email"})
+		},
+		func(s *stdlib.Responder, w http.ResponseWriter, r *http.Request) {
+			s.Problem(w, r, http.StatusConflict, "", "Conflict", "Already taken", map[string]interface{}{"field": "email"})
+		}},
+}
+
+// TestParityWithGin sends every response through Gin and net/http with the
+// same configuration and meta: the statuses, headers and bodies must match
+// byte for byte.
+func TestParityWithGin(t *testing.T) {
+	meta := responsehelper.Meta{RequestID: "req-1", Path: "/users/42"}
+	for name, opts := range map[string][]responsehelper.Option{
+		"default":   nil,
+		"sanitized": {responsehelper.WithErrorSanitization(true), responsehelper.WithAPIVersion("1.4.2", "")},
+		"delete204": {responsehelper.WithDeleteStatus(http.StatusNoContent)},
+		"xml": {
+			responsehelper.WithContentNegotiation(true),
+			responsehelper.WithDefaultFormat(responsehelper.FormatXML),
+		},
+	} {
+		helper := responsehelper.NewResponseHelper(opts...)
+		responder := stdlib.Wrap(helper)
+		for _, call := range parityCalls {
+			t.Run(name+"/"+call.name, func(t *testing.T) {
+				ginRecorder := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(ginRecorder)
+				c.Request = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+				c.Set(responsehelper.MetaKey, meta)
+				c.Set(responsehelper.ErrorIDKey, "e1")
+				call.gin(helper, c)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+				ctx := stdlib.WithMeta(r.Context(), meta)
+				ctx = stdlib.WithValue(ctx, responsehelper.ErrorIDKey, "e1")
+				call.responder(responder, w, r.WithContext(ctx))
+
+				if w.Code != ginRecorder.Code {
+					t.Errorf("status = %d, Gin sent %d", w.Code, ginRecorder.Code)
+				}
+				if !reflect.DeepEqual(w.Header(), ginRecorder.Header()) {
+					t.Errorf("headers = %v, Gin sent %v", w.Header(), ginRecorder.Header())
+				}
+				if w.Body.String() != ginRecorder.Body.String() {
+					t.Errorf("body =\n%s\nGin sent\n%s", w.Body, ginRecorder.Body)
+				}
+			})
+		}
+	}
+}
+
+// errorBody is the error of an envelope.
+type errorBody struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details"`
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) errorBody {
+	t.Helper()
+	var body struct {
+		Error errorBody `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return body.Error
+}
+
+// foreignHelper is a ResponseHelper not created by the package.
+type foreignHelper struct {
+	responsehelper.ResponseHelper
+}
+
+func TestNewResponderUsesTheOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	stdlib.NewResponder(responsehelper.WithErrorSanitization(true)).
+		InternalError(w, httptest.NewRequest(http.MethodPost, "/webhook", nil), "Oops", errors.New("secret"))
+
+	body := decodeError(t, w)
+	if w.Code != http.StatusInternalServerError || body.Message != "Oops" {
+		t.Errorf("got %d %q, want the internal error", w.Code, body.Message)
+	}
+	if body.Details != nil {
+		t.Errorf("error.details = %v with sanitization", body.Details)
+	}
+}
+
+func TestWrapSharesTheCodes(t *testing.T) {
+	helper := responsehelper.NewResponseHelper()
+	helper.RegisterCode("USER_SUSPENDED", http.StatusForbidden, "User %s is suspended")
+
+	w := httptest.NewRecorder()
+	stdlib.Wrap(helper).RespondCode(w, httptest.NewRequest(http.MethodGet, "/users/arun", nil), "USER_SUSPENDED", "arun")
+
+	if body := decodeError(t, w); w.Code != http.StatusForbidden || body.Message != "User arun is suspended" {
+		t.Errorf("got %d %q, want the registered code", w.Code, body.Message)
+	}
+}
+
+func TestWrapNeedsACore(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Wrap accepted a helper without a Core")
+		}
+	}()
+	stdlib.Wrap(foreignHelper{})
+}
+
+func TestValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	parent := stdlib.WithValue(r.Context(), responsehelper.LocaleKey, "de")
+	ctx := stdlib.WithValue(parent, responsehelper.RequestIDKey, "req-1")
+
+	if value, ok := stdlib.Value(ctx, responsehelper.LocaleKey); !ok || value != "de" {
+		t.Errorf("Value(%s) = %v, %t", responsehelper.LocaleKey, value, ok)
+	}
+	if _, ok := stdlib.Value(parent, responsehelper.RequestIDKey); ok {
+		t.Errorf("WithValue changed the parent context")
+	}
+	if _, ok := stdlib.Value(r.Context(), responsehelper.LocaleKey); ok {
+		t.Errorf("Value found a key in a context without values")
+	}
+}
@@ -0,0 +1,82 @@
+package responsehelper
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantIDHeader carries the tenant of the request with WithTenantHeader.
+const TenantIDHeader = "X-Tenant-ID"
+
+// WithTenantFromContext adds "tenantId" to the meta of every envelope, read
+// from the gin context key, eg: set by an authentication middleware, so
+// client bug reports name the tenant the logs are filtered by. Requests
+// without the key, or with an empty value, get no tenantId. A tenantId
+// already set with SetMetaField is kept.
+//
+// Example:
+//
+//	responseHelper := responsehelper.NewResponseHelper(
+//		responsehelper.WithTenantFromContext("tenantID"),
+//		responsehelper.WithTenantHeader(true),
+//	)
+func WithTenantFromContext(key string) Option {
+	return func(cfg *config) {
+		cfg.tenantKey = key
+	}
+}
+
+// WithTenantHeader also sends the tenant of WithTenantFromContext in the
+// X-Tenant-ID header, on every response the helper sends.
+func WithTenantHeader(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.tenantHeader = enabled
+	}
+}
+
+// tenantID returns the tenant of the request, and false when it has none.
+func (cfg *config) tenantID(c Exchange) (string, bool) {
+	if cfg.tenantKey == "" {
+		return "", false
+	}
+	value, ok := c.Get(cfg.tenantKey)
+	if !ok || value == nil {
+		return "", false
+	}
+	tenant := fmt.Sprint(value)
+	return tenant, tenant != ""
+}
+
+// tenantMeta returns meta with the tenantId of WithTenantFromContext added.
+func (cfg *config) tenantMeta(c Exchange, meta interface{}) interface{} {
+	tenant, ok := cfg.tenantID(c)
+	if !ok {
+		return meta
+	}
+	switch typed := meta.(type) {
+	case Meta:
+		if _, set := typed.Extra["tenantId"]; set {
+			return meta
+		}
+	case gin.H:
+		if _, set := typed["tenantId"]; set {
+			return meta
+		}
+	case map[string]interface{}:
+		if _, set := typed["tenantId"]; set {
+			return meta
+		}
+	}
+	return metaWithField(meta, "tenantId", tenant)
+}
+
+// setTenantHeader sets the X-Tenant-ID header with WithTenantHeader.
+func (cfg *config) setTenantHeader(c Exchange) {
+	if !cfg.tenantHeader {
+		return
+	}
+	if tenant, ok := cfg.tenantID(c); ok {
+		setHeader(c, TenantIDHeader, tenant)
+	}
+}
@@ -0,0 +1,113 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// tenantMeta sends respond with the tenant stored under "tenantID" when it
+// is not nil, and returns the meta of the body and the X-Tenant-ID header.
+func tenantMeta(t *testing.T, tenant interface{}, setup func(c *gin.Context), respond func(h responsehelper.ResponseHelper, c *gin.Context), opts ...responsehelper.Option) (map[string]interface{}, string) {
+	t.Helper()
+	h := responsehelper.NewResponseHelper(append([]responsehelper.Option{responsehelper.WithTenantFromContext("tenantID")}, opts...)...)
+	c, w := newContext(http.MethodGet, "/users/42")
+	if tenant != nil {
+		c.Set("tenantID", tenant)
+	}
+	if setup != nil {
+		setup(c)
+	}
+	respond(h, c)
+	meta, _ := decodeBody(t, w)["meta"].(map[string]interface{})
+	return meta, w.Header().Get(responsehelper.TenantIDHeader)
+}
+
+func tenantSuccess(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, gin.H{"id": 42}) }
+
+func TestTenantInTheMeta(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"success": tenantSuccess,
+		"pagination": func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 1, 1))
+		},
+		"error": func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "User not found") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			meta, header := tenantMeta(t, "acme", nil, respond)
+			if meta["tenantId"] != "acme" {
+				t.Errorf("meta = %v, want tenantId acme", meta)
+			}
+			if header != "" {
+				t.Errorf("%s = %q without WithTenantHeader", responsehelper.TenantIDHeader, header)
+			}
+		})
+	}
+}
+
+func TestTenantStringified(t *testing.T) {
+	if meta, _ := tenantMeta(t, 42, nil, tenantSuccess); meta["tenantId"] != "42" {
+		t.Errorf("meta = %v, want tenantId \"42\"", meta)
+	}
+}
+
+func TestTenantAbsent(t *testing.T) {
+	for name, tenant := range map[string]interface{}{"unset": nil, "empty": ""} {
+		t.Run(name, func(t *testing.T) {
+			meta, header := tenantMeta(t, tenant, nil, tenantSuccess, responsehelper.WithTenantHeader(true))
+			if _, ok := meta["tenantId"]; ok {
+				t.Errorf("meta = %v, want no tenantId", meta)
+			}
+			if header != "" {
+				t.Errorf("%s = %q", responsehelper.TenantIDHeader, header)
+			}
+		})
+	}
+
+	c, w := newContext(http.MethodGet, "/users/42")
+	c.Set("tenantID", "acme")
+	responsehelper.NewResponseHelper().Success(c, gin.H{"id": 42})
+	if _, ok := decodeBody(t, w)["meta"]; ok {
+		t.Errorf("the tenant was sent without WithTenantFromContext: %s", w.Body)
+	}
+}
+
+func TestTenantHeader(t *testing.T) {
+	for name, respond := range map[string]func(h responsehelper.ResponseHelper, c *gin.Context){
+		"success": tenantSuccess,
+		"error":   func(h responsehelper.ResponseHelper, c *gin.Context) { h.NotFound(c, "User not found") },
+	} {
+		if _, header := tenantMeta(t, "acme", nil, respond, responsehelper.WithTenantHeader(true)); header != "acme" {
+			t.Errorf("%s: %s = %q, want acme", name, responsehelper.TenantIDHeader, header)
+		}
+	}
+}
+
+func TestTenantWithTheMetaMiddleware(t *testing.T) {
+	stored := responsehelper.Meta{RequestID: "req-1", Path: "/users/42", Extra: map[string]interface{}{"region": "eu"}}
+	meta, _ := tenantMeta(t, "acme", func(c *gin.Context) { c.Set(responsehelper.MetaKey, stored) }, tenantSuccess)
+
+	for key, want := range map[string]interface{}{"requestId": "req-1", "path": "/users/42", "region": "eu", "tenantId": "acme"} {
+		if meta[key] != want {
+			t.Errorf("meta[%q] = %v, want %v", key, meta[key], want)
+		}
+	}
+	if _, ok := stored.Extra["tenantId"]; ok {
+		t.Error("the tenant was added to the Meta stored by the middleware")
+	}
+}
+
+func TestTenantKeepsTheOneSet(t *testing.T) {
+	for name, setup := range map[string]func(c *gin.Context){
+		"SetMetaField": func(c *gin.Context) { responsehelper.SetMetaField(c, "tenantId", "parent") },
+		"Meta": func(c *gin.Context) {
+			c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Extra: map[string]interface{}{"tenantId": "parent"}})
+		},
+	} {
+		if meta, _ := tenantMeta(t, "acme", setup, tenantSuccess); meta["tenantId"] != "parent" {
+			t.Errorf("%s: meta = %v, want the tenantId set kept", name, meta)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package responsehelper
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimedOutKey is the gin context key set to true once Timeout sent its response.
+const TimedOutKey = "responsehelper.timedOut"
+
+// TimeoutOption configures Timeout.
+type TimeoutOption func(*timeoutConfig)
+
+type timeoutConfig struct {
+	// status is sent when the deadline expires.
+	status int
+	// message is the error message sent when the deadline expires.
+	message string
+}
+
+// WithTimeoutStatus replaces 504 Gateway Timeout as the status sent when the
+// deadline expires, eg: http.StatusRequestTimeout.
+func WithTimeoutStatus(status int) TimeoutOption {
+	return func(cfg *timeoutConfig) {
+		cfg.status = status
+	}
+}
+
+// WithTimeoutMessage replaces the error message sent when the deadline expires.
+func WithTimeoutMessage(message string) TimeoutOption {
+	return func(cfg *timeoutConfig) {
+		cfg.message = message
+	}
+}
+
+// Timeout runs the later handlers with a request context that expires after
+// d. When the deadline expires before the handlers wrote a response, the 504
+// envelope is sent right away, TimedOutKey is set and later writes of the
+// handlers are discarded with http.ErrHandlerTimeout.
+//
+// The handlers keep running until they return, so they should stop once
+// c.Request.Context() is done.
+//
+// Example:
+//
+//	router.Use(responsehelper.Timeout(responseHelper, 5*time.Second))
+func Timeout(h ResponseHelper, d time.Duration, opts ...TimeoutOption) gin.HandlerFunc {
+	cfg := timeoutConfig{status: http.StatusGatewayTimeout, message: "The request timed out"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, header: c.Writer.Header().Clone()}
+		c.Writer = writer
+		finished := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				finished <- recover()
+			}()
+			c.Next()
+		}()
+
+		var recovered interface{}
+		timedOut := false
+		select {
+		case recovered = <-finished:
+		case <-ctx.Done():
+			timedOut = writer.timeout(func() {
+				c.Set(TimedOutKey, true)
+				timeoutContext := c.Copy()
+				timeoutContext.Writer = writer.ResponseWriter
+				h.RespondAPIError(timeoutContext, NewAPIError(cfg.status, cfg.message, nil))
+				writer.ResponseWriter.Flush()
+			})
+			recovered = <-finished
+		}
+		writer.finish()
+		c.Writer = writer.ResponseWriter
+		if recovered == nil {
+			return
+		}
+		if timedOut {
+			log.Printf("[responsehelper] WARNING: handler panicked after the request timed out: %v", recovered)
+			return
+		}
+		panic(recovered)
+	}
+}
+
+// TimedOut reports whether Timeout already sent the timeout response.
+func TimedOut(c *gin.Context) bool {
+	return timedOut(exchangeOf(c))
+}
+
+// timedOut is TimedOut for an Exchange.
+func timedOut(c Exchange) bool {
+	return getBool(c, TimedOutKey)
+}
+
+// timeoutWriter guards the response against the handlers writing while, or
+// after, Timeout sends the timeout response. The handlers write their headers
+// into their own map, which is copied to the response on their first write.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu          sync.Mutex
+	header      http.Header
+	timedOut    bool
+	wroteHeader bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.commitHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.commitHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.commitHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.commitHeader()
+	w.ResponseWriter.Flush()
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ResponseWriter.Status()
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ResponseWriter.Size()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.timedOut || w.ResponseWriter.Written()
+}
+
+// timeout discards all later writes and sends the timeout response with
+// respond, holding the lock so the handlers cannot look at the response while
+// it is written. It reports false, without calling respond, when the handlers
+// already wrote the response.
+func (w *timeoutWriter) timeout(respond func()) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.ResponseWriter.Written() {
+		return false
+	}
+	w.timedOut = true
+	respond()
+	return true
+}
+
+// finish copies the headers of handlers that set headers without writing a body.
+func (w *timeoutWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.commitHeader()
+	}
+}
+
+// commitHeader copies the headers of the handlers to the response once.
+func (w *timeoutWriter) commitHeader() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+}
@@ -0,0 +1,75 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutEngine returns an engine with a 50ms Timeout, answering /slow well
+// after the deadline and /fast right away.
+func timeoutEngine(late chan<- bool, opts ...responsehelper.TimeoutOption) *gin.Engine {
+	h := responsehelper.NewResponseHelper()
+	engine := gin.New()
+	engine.Use(responsehelper.Timeout(h, 50*time.Millisecond, opts...))
+	engine.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		time.Sleep(50 * time.Millisecond)
+		c.Header("X-Late", "1")
+		h.Success(c, gin.H{"late": true})
+		late <- responsehelper.TimedOut(c)
+	})
+	engine.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "1")
+		h.Success(c, gin.H{"fast": true})
+	})
+	return engine
+}
+
+func TestTimeoutSlowHandler(t *testing.T) {
+	late := make(chan bool, 1)
+	w := serve(timeoutEngine(late), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assertError(t, w, http.StatusGatewayTimeout, "The request timed out")
+	if !<-late {
+		t.Error("TimedOut = false in the late handler")
+	}
+	if w.Header().Get("X-Late") != "" {
+		t.Error("the header of the late handler was sent")
+	}
+}
+
+func TestTimeoutStatus(t *testing.T) {
+	late := make(chan bool, 1)
+	w := serve(timeoutEngine(late, responsehelper.WithTimeoutStatus(http.StatusRequestTimeout), responsehelper.WithTimeoutMessage("Too slow")),
+		httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assertError(t, w, http.StatusRequestTimeout, "Too slow")
+	<-late
+}
+
+func TestTimeoutInTime(t *testing.T) {
+	w := serve(timeoutEngine(nil), httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	assertSuccess(t, w)
+	assertField(t, w, "data.fast", true)
+	if w.Header().Get("X-Handler") != "1" {
+		t.Error("the header of the handler was lost")
+	}
+}
+
+func TestTimeoutRepanics(t *testing.T) {
+	engine := gin.New()
+	engine.Use(responsehelper.Timeout(responsehelper.NewResponseHelper(), time.Second))
+	engine.GET("/panic", func(c *gin.Context) { panic("boom") })
+	defer func() {
+		if recovered := recover(); recovered != "boom" {
+			t.Errorf("recovered %v, want the panic of the handler", recovered)
+		}
+	}()
+	serve(engine, httptest.NewRequest(http.MethodGet, "/panic", nil))
+}
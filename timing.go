@@ -0,0 +1,143 @@
+package responsehelper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ServerTimingHeader carries the time spent on the request (W3C Server Timing).
+	ServerTimingHeader = "Server-Timing"
+	// ResponseTimeHeader carries the time spent on the request with
+	// WithResponseTimeHeader, eg: "12.345ms".
+	ResponseTimeHeader = "X-Response-Time"
+	// DurationKey is the gin context key holding the time.Duration spent on
+	// the request, measured once when the response is written.
+	DurationKey = "responseDuration"
+)
+
+// WithRequestDuration reports how long the server took in "meta.durationMs"
+// and in a "Server-Timing: app;dur=12.3" header, measured from MetaMiddleware
+// to the moment the response is written. Streaming responses, eg: SuccessCSV,
+// are measured until their first byte, the header says so with
+// desc="time to first byte". Responses to requests that did not go through
+// MetaMiddleware are not measured.
+//
+// Example:
+//
+//	router.Use(responsehelper.MetaMiddleware())
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithRequestDuration(true))
+func WithRequestDuration(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.requestDuration = enabled
+	}
+}
+
+// responseTime is the header set by WithResponseTimeHeader.
+type responseTime struct {
+	header string
+	unit   time.Duration
+	// suffix names unit after the number, eg: "ms".
+	suffix string
+}
+
+// responseTimeUnits are the suffixes of the units of WithResponseTimeHeader.
+var responseTimeUnits = map[time.Duration]string{
+	time.Nanosecond:  "ns",
+	time.Microsecond: "us",
+	time.Millisecond: "ms",
+	time.Second:      "s",
+}
+
+// WithResponseTimeHeader sets the header name, X-Response-Time when empty,
+// to the time spent on the request on every response, 204 included, eg:
+// "X-Response-Time: 12.345ms". The time is written in unit, one of
+// time.Nanosecond, time.Microsecond, time.Millisecond or time.Second, with
+// three decimals; other units fall back to milliseconds. It is measured from
+// MetaMiddleware like WithRequestDuration, streaming responses until their
+// first byte, and the header is left out for requests that did not go
+// through the middleware.
+//
+// Example:
+//
+//	router.Use(responsehelper.MetaMiddleware())
+//	responseHelper := responsehelper.NewResponseHelper(responsehelper.WithResponseTimeHeader("", time.Millisecond))
+func WithResponseTimeHeader(name string, unit time.Duration) Option {
+	return func(cfg *config) {
+		if name == "" {
+			name = ResponseTimeHeader
+		}
+		suffix, ok := responseTimeUnits[unit]
+		if !ok {
+			unit, suffix = time.Millisecond, "ms"
+		}
+		cfg.responseTime = &responseTime{header: name, unit: unit, suffix: suffix}
+	}
+}
+
+// RequestDuration returns the time spent on the request, as reported in the
+// response. It reports false before the response is written and without
+// WithRequestDuration or WithResponseTimeHeader.
+func RequestDuration(c *gin.Context) (time.Duration, bool) {
+	return requestDuration(exchangeOf(c))
+}
+
+// requestDuration is RequestDuration for an Exchange.
+func requestDuration(c Exchange) (time.Duration, bool) {
+	stored, _ := c.Get(DurationKey)
+	duration, ok := stored.(time.Duration)
+	return duration, ok
+}
+
+// measureDuration returns the time spent on the request. It is measured on
+// the first call and stored under DurationKey, so the meta, the header and
+// the hooks report the same value.
+func (cfg *config) measureDuration(c Exchange) (time.Duration, bool) {
+	if !cfg.requestDuration {
+		return 0, false
+	}
+	return elapsed(c)
+}
+
+// elapsed returns the time spent on the request, measured once like
+// measureDuration whatever the options.
+func elapsed(c Exchange) (time.Duration, bool) {
+	if duration, ok := requestDuration(c); ok {
+		return duration, true
+	}
+	meta, ok := getMeta(c)
+	if !ok || meta.clock == nil {
+		return 0, false
+	}
+	duration := max(meta.clock().Sub(meta.started), 0)
+	c.Set(DurationKey, duration)
+	return duration, true
+}
+
+// setTimingHeaders sets the Server-Timing and the response time headers of
+// the response. firstByte marks responses streamed after the headers.
+func (cfg *config) setTimingHeaders(c Exchange, firstByte bool) {
+	if cfg.responseTime != nil {
+		if duration, ok := elapsed(c); ok {
+			// truncated to the third decimal, like "meta.durationMs"
+			duration = duration.Truncate(cfg.responseTime.unit / 1000)
+			setHeader(c, cfg.responseTime.header, strconv.FormatFloat(float64(duration)/float64(cfg.responseTime.unit), 'f', 3, 64)+cfg.responseTime.suffix)
+		}
+	}
+	duration, ok := cfg.measureDuration(c)
+	if !ok {
+		return
+	}
+	value := "app;dur=" + strconv.FormatFloat(durationMs(duration), 'f', -1, 64)
+	if firstByte {
+		value += `;desc="time to first byte"`
+	}
+	c.Header().Add(ServerTimingHeader, value)
+}
+
+// durationMs returns d in milliseconds, with microsecond precision.
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
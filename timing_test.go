@@ -0,0 +1,168 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// timingEngine returns an engine measured by a clock advancing 12.3ms on
+// every reading, answering /ok with a success, /fail with an error, /empty
+// with a 204 and /csv with a streamed CSV.
+func timingEngine(opts ...responsehelper.Option) *gin.Engine {
+	h := responsehelper.NewResponseHelper(opts...)
+	engine := gin.New()
+	engine.Use(responsehelper.MetaMiddleware(responsehelper.WithMetaClock(steppingClock(metaNow, 12300*time.Microsecond))))
+	engine.GET("/ok", func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) })
+	engine.GET("/fail", func(c *gin.Context) { h.NotFound(c, "missing") })
+	engine.GET("/empty", func(c *gin.Context) { h.NoContent(c) })
+	engine.GET("/csv", func(c *gin.Context) {
+		h.SuccessCSV(c, "users.csv", []map[string]string{{"name": "arun"}})
+	})
+	return engine
+}
+
+func TestRequestDuration(t *testing.T) {
+	engine := timingEngine(responsehelper.WithRequestDuration(true))
+	for _, path := range []string{"/ok", "/fail"} {
+		w := serve(engine, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assertField(t, w, "meta.durationMs", 12.3)
+		if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "app;dur=12.3" {
+			t.Errorf("%s: %s = %q, want %q", path, responsehelper.ServerTimingHeader, got, "app;dur=12.3")
+		}
+	}
+}
+
+func TestRequestDurationWithoutBody(t *testing.T) {
+	w := serve(timingEngine(responsehelper.WithRequestDuration(true)), httptest.NewRequest(http.MethodGet, "/empty", nil))
+
+	if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "app;dur=12.3" {
+		t.Errorf("%s = %q, want %q", responsehelper.ServerTimingHeader, got, "app;dur=12.3")
+	}
+}
+
+func TestRequestDurationOfAStream(t *testing.T) {
+	w := serve(timingEngine(responsehelper.WithRequestDuration(true)), httptest.NewRequest(http.MethodGet, "/csv", nil))
+
+	want := `app;dur=12.3;desc="time to first byte"`
+	if got := w.Header().Get(responsehelper.ServerTimingHeader); got != want {
+		t.Errorf("%s = %q, want %q", responsehelper.ServerTimingHeader, got, want)
+	}
+}
+
+func TestRequestDurationIsMeasuredOnce(t *testing.T) {
+	var hooked, stored time.Duration
+	engine := timingEngine(
+		responsehelper.WithRequestDuration(true),
+		responsehelper.WithResponseTimeHeader("", time.Millisecond),
+		responsehelper.WithOnResponse(func(c *gin.Context, info responsehelper.ResponseInfo) {
+			hooked = info.Duration
+			stored, _ = responsehelper.RequestDuration(c)
+		}),
+	)
+	w := serve(engine, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	// every reading of the clock would add 12.3ms
+	assertField(t, w, "meta.durationMs", 12.3)
+	if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "app;dur=12.3" {
+		t.Errorf("%s = %q", responsehelper.ServerTimingHeader, got)
+	}
+	if got := w.Header().Get(responsehelper.ResponseTimeHeader); got != "12.300ms" {
+		t.Errorf("%s = %q", responsehelper.ResponseTimeHeader, got)
+	}
+	if hooked != 12300*time.Microsecond || stored != hooked {
+		t.Errorf("hook got %v, RequestDuration %v, want 12.3ms", hooked, stored)
+	}
+}
+
+func TestRequestDurationDisabled(t *testing.T) {
+	w := serve(timingEngine(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "" {
+		t.Errorf("%s = %q without WithRequestDuration", responsehelper.ServerTimingHeader, got)
+	}
+	if got, ok := lookup(w, "meta.durationMs"); ok {
+		t.Errorf("meta.durationMs = %v without WithRequestDuration", got)
+	}
+}
+
+func TestRequestDurationWithoutMetaMiddleware(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/ok")
+	responsehelper.NewResponseHelper(responsehelper.WithRequestDuration(true)).Success(c, nil)
+
+	if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "" {
+		t.Errorf("%s = %q without MetaMiddleware", responsehelper.ServerTimingHeader, got)
+	}
+	if _, ok := responsehelper.RequestDuration(c); ok {
+		t.Error("RequestDuration reported a duration without MetaMiddleware")
+	}
+}
+
+func TestResponseTimeHeader(t *testing.T) {
+	engine := timingEngine(responsehelper.WithResponseTimeHeader("", time.Millisecond))
+	for _, path := range []string{"/ok", "/fail", "/empty", "/csv"} {
+		w := serve(engine, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if got := w.Header().Get(responsehelper.ResponseTimeHeader); got != "12.300ms" {
+			t.Errorf("%s: %s = %q, want %q", path, responsehelper.ResponseTimeHeader, got, "12.300ms")
+		}
+		if got := w.Header().Get(responsehelper.ServerTimingHeader); got != "" {
+			t.Errorf("%s: %s = %q without WithRequestDuration", path, responsehelper.ServerTimingHeader, got)
+		}
+	}
+}
+
+func TestResponseTimeHeaderFormatting(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		unit time.Duration
+		step time.Duration
+		want string
+	}{
+		{"milliseconds", time.Millisecond, 12345678 * time.Nanosecond, "12.345ms"},
+		{"microseconds", time.Microsecond, 12345678 * time.Nanosecond, "12345.678us"},
+		{"nanoseconds", time.Nanosecond, 1500 * time.Nanosecond, "1500.000ns"},
+		{"seconds", time.Second, 1234567 * time.Microsecond, "1.234s"},
+		{"other unit", time.Minute, 12345678 * time.Nanosecond, "12.345ms"},
+		{"zero", time.Millisecond, 0, "0.000ms"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := responsehelper.NewResponseHelper(responsehelper.WithResponseTimeHeader("X-Elapsed", tt.unit))
+			engine := gin.New()
+			engine.Use(responsehelper.MetaMiddleware(responsehelper.WithMetaClock(steppingClock(metaNow, tt.step))))
+			engine.GET("/ok", func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) })
+
+			w := serve(engine, httptest.NewRequest(http.MethodGet, "/ok", nil))
+			if got := w.Header().Get("X-Elapsed"); got != tt.want {
+				t.Errorf("X-Elapsed = %q, want %q", got, tt.want)
+			}
+			if got := w.Header().Get(responsehelper.ResponseTimeHeader); got != "" {
+				t.Errorf("%s = %q with another header name", responsehelper.ResponseTimeHeader, got)
+			}
+		})
+	}
+}
+
+func TestResponseTimeHeaderMatchesTheDuration(t *testing.T) {
+	w := serve(timingEngine(responsehelper.WithRequestDuration(true), responsehelper.WithResponseTimeHeader("", time.Millisecond)),
+		httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	assertField(t, w, "meta.durationMs", 12.3)
+	if got := w.Header().Get(responsehelper.ResponseTimeHeader); got != "12.300ms" {
+		t.Errorf("%s = %q, want %q", responsehelper.ResponseTimeHeader, got, "12.300ms")
+	}
+}
+
+func TestResponseTimeHeaderWithoutMiddleware(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/ok")
+	responsehelper.NewResponseHelper(responsehelper.WithResponseTimeHeader("", time.Millisecond)).Success(c, gin.H{"id": 1})
+
+	if got, ok := w.Header()[responsehelper.ResponseTimeHeader]; ok {
+		t.Errorf("%s = %q without MetaMiddleware", responsehelper.ResponseTimeHeader, got)
+	}
+}
@@ -0,0 +1,77 @@
+package responsehelper
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// TotalCountHeader carries the total number of records of a listing.
+	TotalCountHeader = "X-Total-Count"
+	// TotalPagesHeader carries the number of pages of a listing.
+	TotalPagesHeader = "X-Total-Pages"
+	// ExposeHeadersHeader lists the response headers browsers let scripts read (CORS).
+	ExposeHeadersHeader = "Access-Control-Expose-Headers"
+)
+
+// WithCountHeaders sets the X-Total-Count and X-Total-Pages headers of the
+// responses of SuccessWithPagination sent with a Pagination, next to the
+// "pagination" of the body, for clients reading the totals from headers.
+// With expose, the headers are added to Access-Control-Expose-Headers as
+// browsers hide them from cross-origin scripts otherwise.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithCountHeaders(true, true))
+func WithCountHeaders(enabled, expose bool) Option {
+	return func(cfg *config) {
+		cfg.countHeaders = enabled
+		cfg.exposeCountHeaders = enabled && expose
+	}
+}
+
+// SetCountHeaders sets the X-Total-Count and X-Total-Pages headers of p, eg:
+// for listings sent without the envelope. Use ExposeHeaders to let
+// cross-origin scripts read them.
+//
+// Example:
+//
+//	responsehelper.SetCountHeaders(c, responsehelper.NewPagination(page, size, total))
+//	c.JSON(http.StatusOK, users)
+func SetCountHeaders(c *gin.Context, p Pagination) {
+	setCountHeaderValues(exchangeOf(c), p)
+}
+
+// setCountHeaderValues is SetCountHeaders for an Exchange.
+func setCountHeaderValues(c Exchange, p Pagination) {
+	setHeader(c, TotalCountHeader, strconv.FormatInt(p.TotalRecords, 10))
+	setHeader(c, TotalPagesHeader, strconv.Itoa(p.TotalPages))
+}
+
+// ExposeHeaders adds names to the Access-Control-Expose-Headers header of the
+// response, keeping the names already listed, eg: by a CORS middleware.
+//
+// Example:
+//
+//	responsehelper.ExposeHeaders(c, responsehelper.TotalCountHeader, responsehelper.TotalPagesHeader)
+func ExposeHeaders(c *gin.Context, names ...string) {
+	exposeHeaders(exchangeOf(c), names...)
+}
+
+// exposeHeaders is ExposeHeaders for an Exchange.
+func exposeHeaders(c Exchange, names ...string) {
+	addHeaderList(c.Header(), ExposeHeadersHeader, names)
+}
+
+// setCountHeaders sets the count headers of a paginated response.
+func (cfg *config) setCountHeaders(c Exchange, p Pagination) {
+	if !cfg.countHeaders {
+		return
+	}
+	setCountHeaderValues(c, p)
+	if cfg.exposeCountHeaders {
+		exposeHeaders(c, TotalCountHeader, TotalPagesHeader)
+		addVary(c, "Origin")
+	}
+}
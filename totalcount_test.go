@@ -0,0 +1,82 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+)
+
+func TestCountHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   []responsehelper.Option
+		count  string
+		pages  string
+		expose string
+	}{
+		{"disabled", nil, "", "", ""},
+		{"enabled", []responsehelper.Option{responsehelper.WithCountHeaders(true, false)}, "45", "3", ""},
+		{"exposed", []responsehelper.Option{responsehelper.WithCountHeaders(true, true)}, "45", "3", "X-Total-Count, X-Total-Pages"},
+		{"expose without headers", []responsehelper.Option{responsehelper.WithCountHeaders(false, true)}, "", "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			responsehelper.NewResponseHelper(tc.opts...).
+				SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(2, 20, 45))
+
+			for header, want := range map[string]string{
+				responsehelper.TotalCountHeader:    tc.count,
+				responsehelper.TotalPagesHeader:    tc.pages,
+				responsehelper.ExposeHeadersHeader: tc.expose,
+			} {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+			assertField(t, w, "pagination.totalRecords", float64(45))
+			assertField(t, w, "pagination.totalPages", float64(3))
+		})
+	}
+}
+
+func TestCountHeadersKeepTheExposedHeaders(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	c.Header(responsehelper.ExposeHeadersHeader, "X-Request-ID, x-total-count")
+	responsehelper.NewResponseHelper(responsehelper.WithCountHeaders(true, true)).
+		SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 20, 1))
+
+	if got, want := w.Header().Get(responsehelper.ExposeHeadersHeader), "X-Request-ID, x-total-count, X-Total-Pages"; got != want {
+		t.Errorf("%s = %q, want %q", responsehelper.ExposeHeadersHeader, got, want)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want %q", got, "Origin")
+	}
+}
+
+func TestCountHeadersNeedATypedPagination(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithCountHeaders(true, true)).
+		SuccessWithPagination(c, []int{1}, map[string]int{"total": 45})
+
+	if got := w.Header().Get(responsehelper.TotalCountHeader); got != "" {
+		t.Errorf("%s = %q for an untyped pagination", responsehelper.TotalCountHeader, got)
+	}
+}
+
+func TestSetCountHeaders(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.SetCountHeaders(c, responsehelper.NewPagination(1, 10, 95))
+	responsehelper.ExposeHeaders(c, responsehelper.TotalCountHeader, responsehelper.TotalPagesHeader)
+	c.JSON(http.StatusOK, []int{1})
+
+	if got := w.Header().Get(responsehelper.TotalCountHeader); got != "95" {
+		t.Errorf("%s = %q, want %q", responsehelper.TotalCountHeader, got, "95")
+	}
+	if got := w.Header().Get(responsehelper.TotalPagesHeader); got != "10" {
+		t.Errorf("%s = %q, want %q", responsehelper.TotalPagesHeader, got, "10")
+	}
+	if got := w.Header().Get(responsehelper.ExposeHeadersHeader); got != "X-Total-Count, X-Total-Pages" {
+		t.Errorf("%s = %q", responsehelper.ExposeHeadersHeader, got)
+	}
+}
@@ -0,0 +1,75 @@
+package responsehelper
+
+import "github.com/gin-gonic/gin"
+
+// Envelope is the body OK and CreatedT send for data of type T. It documents
+// the SuccessEnvelope of a response with a concrete type, eg: for the
+// annotations of an OpenAPI generator, and is rendered the same.
+//
+// Example:
+//
+//	// @Success 200 {object} responsehelper.Envelope[User]
+//	func (h *UserHandler) Get(c *gin.Context) {
+//		responsehelper.OK(h.responseHelper, c, user)
+//	}
+type Envelope[T any] struct {
+	// Data is the payload of the response.
+	Data T `json:"data"`
+	// Links are set by Created with WithLocation.
+	Links Links `json:"links,omitempty"`
+	// Meta is set by MetaMiddleware.
+	Meta *Meta `json:"meta,omitempty"`
+	// Success is always true.
+	Success bool `json:"success"`
+	// Warnings are the caveats of WithWarnings and AddWarning.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// PaginatedEnvelope is the body PaginatedT sends for a page of items of type
+// T, see Envelope.
+//
+// Example:
+//
+//	// @Success 200 {object} responsehelper.PaginatedEnvelope[User]
+type PaginatedEnvelope[T any] struct {
+	// Data are the items of the page.
+	Data []T `json:"data"`
+	// Meta is set by MetaMiddleware.
+	Meta *Meta `json:"meta,omitempty"`
+	// Pagination describes the page.
+	Pagination Pagination `json:"pagination"`
+	// Success is always true.
+	Success bool `json:"success"`
+	// Warnings are the caveats of WithWarnings and AddWarning.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// OK sends data with 200 OK like h.Success, checking its type at compile
+// time. The body is an Envelope[T].
+//
+// Example:
+//
+//	responsehelper.OK(h.responseHelper, c, user)
+func OK[T any](h ResponseHelper, c *gin.Context, data T, opts ...ResponseOption) {
+	h.Success(c, data, opts...)
+}
+
+// CreatedT sends data with 201 Created like h.Created, checking its type at
+// compile time. The body is an Envelope[T].
+//
+// Example:
+//
+//	responsehelper.CreatedT(h.responseHelper, c, user, responsehelper.WithLocation("/users/"+strconv.Itoa(user.ID)))
+func CreatedT[T any](h ResponseHelper, c *gin.Context, data T, opts ...ResponseOption) {
+	h.Created(c, data, opts...)
+}
+
+// PaginatedT sends a page of items with 200 OK like h.SuccessWithPagination,
+// checking their type at compile time. The body is a PaginatedEnvelope[T].
+//
+// Example:
+//
+//	responsehelper.PaginatedT(h.responseHelper, c, users, responsehelper.NewPagination(page, size, total))
+func PaginatedT[T any](h ResponseHelper, c *gin.Context, items []T, pagination Pagination, opts ...ResponseOption) {
+	h.SuccessWithPagination(c, items, pagination, opts...)
+}
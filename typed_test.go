@@ -0,0 +1,160 @@
+package responsehelper_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+type typedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// sameBody sends a response through the generic helper and the interface
+// method it wraps, and fails when the statuses, headers or bodies differ.
+func sameBody(t *testing.T, typed, untyped func(h responsehelper.ResponseHelper, c *gin.Context)) *httptest.ResponseRecorder {
+	t.Helper()
+	h := responsehelper.NewResponseHelper()
+	meta := responsehelper.Meta{RequestID: "req-1", Path: "/users"}
+
+	c, w := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.MetaKey, meta)
+	typed(h, c)
+	c, want := newContext(http.MethodGet, "/users")
+	c.Set(responsehelper.MetaKey, meta)
+	untyped(h, c)
+
+	if w.Code != want.Code || !reflect.DeepEqual(w.Header(), want.Header()) || w.Body.String() != want.Body.String() {
+		t.Errorf("generic helper sent %d %v\n%s\ninterface method sent %d %v\n%s", w.Code, w.Header(), w.Body, want.Code, want.Header(), want.Body)
+	}
+	return w
+}
+
+// decodeEnvelope decodes the body of w into an Envelope[T].
+func decodeEnvelope[T any](t *testing.T, w *httptest.ResponseRecorder) responsehelper.Envelope[T] {
+	t.Helper()
+	var envelope responsehelper.Envelope[T]
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decoding the body: %v\nbody: %s", err, w.Body)
+	}
+	return envelope
+}
+
+func TestOK(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		user := typedUser{ID: 42, Name: "arun"}
+		w := sameBody(t,
+			func(h responsehelper.ResponseHelper, c *gin.Context) { responsehelper.OK(h, c, user) },
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, user) })
+
+		envelope := decodeEnvelope[typedUser](t, w)
+		if !envelope.Success || envelope.Data != user || envelope.Meta == nil || envelope.Meta.RequestID != "req-1" {
+			t.Errorf("envelope = %+v", envelope)
+		}
+	})
+	t.Run("pointer", func(t *testing.T) {
+		user := &typedUser{ID: 42, Name: "arun"}
+		w := sameBody(t,
+			func(h responsehelper.ResponseHelper, c *gin.Context) { responsehelper.OK(h, c, user) },
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, user) })
+
+		if envelope := decodeEnvelope[*typedUser](t, w); envelope.Data == nil || *envelope.Data != *user {
+			t.Errorf("envelope = %+v", envelope)
+		}
+	})
+	t.Run("slice", func(t *testing.T) {
+		users := []typedUser{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+		w := sameBody(t,
+			func(h responsehelper.ResponseHelper, c *gin.Context) { responsehelper.OK(h, c, users) },
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, users) })
+
+		if envelope := decodeEnvelope[[]typedUser](t, w); !reflect.DeepEqual(envelope.Data, users) {
+			t.Errorf("data = %v, want %v", envelope.Data, users)
+		}
+	})
+	t.Run("map", func(t *testing.T) {
+		counts := map[string]int{"active": 3, "suspended": 1}
+		w := sameBody(t,
+			func(h responsehelper.ResponseHelper, c *gin.Context) { responsehelper.OK(h, c, counts) },
+			func(h responsehelper.ResponseHelper, c *gin.Context) { h.Success(c, counts) })
+
+		if envelope := decodeEnvelope[map[string]int](t, w); !reflect.DeepEqual(envelope.Data, counts) {
+			t.Errorf("data = %v, want %v", envelope.Data, counts)
+		}
+	})
+	t.Run("options", func(t *testing.T) {
+		sameBody(t,
+			func(h responsehelper.ResponseHelper, c *gin.Context) {
+				responsehelper.OK(h, c, typedUser{ID: 1}, responsehelper.WithWarnings(responsehelper.Warning{Code: "STALE"}))
+			},
+			func(h responsehelper.ResponseHelper, c *gin.Context) {
+				h.Success(c, typedUser{ID: 1}, responsehelper.WithWarnings(responsehelper.Warning{Code: "STALE"}))
+			})
+	})
+}
+
+func TestCreatedT(t *testing.T) {
+	user := typedUser{ID: 42, Name: "arun"}
+	w := sameBody(t,
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			responsehelper.CreatedT(h, c, user, responsehelper.WithLocation("/users/42"))
+		},
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			h.Created(c, user, responsehelper.WithLocation("/users/42"))
+		})
+
+	if w.Code != http.StatusCreated || w.Header().Get("Location") != "/users/42" {
+		t.Errorf("status = %d, Location = %q", w.Code, w.Header().Get("Location"))
+	}
+	if envelope := decodeEnvelope[typedUser](t, w); envelope.Data != user {
+		t.Errorf("data = %+v, want %+v", envelope.Data, user)
+	}
+}
+
+func TestPaginatedT(t *testing.T) {
+	users := []typedUser{{ID: 3, Name: "c"}, {ID: 4, Name: "d"}}
+	pagination := responsehelper.NewPagination(2, 2, 5)
+	w := sameBody(t,
+		func(h responsehelper.ResponseHelper, c *gin.Context) {
+			responsehelper.PaginatedT(h, c, users, pagination)
+		},
+		func(h responsehelper.ResponseHelper, c *gin.Context) { h.SuccessWithPagination(c, users, pagination) })
+
+	var envelope responsehelper.PaginatedEnvelope[typedUser]
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if !envelope.Success || !reflect.DeepEqual(envelope.Data, users) || envelope.Pagination != pagination {
+		t.Errorf("envelope = %+v", envelope)
+	}
+}
+
+// The Envelope of a concrete type documents the response of a handler for
+// the annotations of an OpenAPI generator, eg: swag.
+func ExampleOK() {
+	h := responsehelper.NewResponseHelper()
+
+	// @Summary  Get a user
+	// @Success  200  {object}  responsehelper.Envelope[typedUser]
+	// @Router   /users/{id} [get]
+	getUser := func(c *gin.Context) {
+		responsehelper.OK(h, c, typedUser{ID: 42, Name: "arun"})
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	getUser(c)
+
+	var envelope responsehelper.Envelope[typedUser]
+	_ = json.Unmarshal(w.Body.Bytes(), &envelope)
+	fmt.Println(w.Code, envelope.Data.Name)
+	// Output: 200 arun
+}
@@ -0,0 +1,263 @@
+package responsehelper
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single failed validation rule, eg: from a
+// `binding:"required"` tag.
+type FieldError struct {
+	// Field is the path of the field in the request, eg: "items[2].name".
+	Field string `json:"field" example:"items[2].name"`
+	// Tag is the validation rule that failed, eg: "required".
+	Tag string `json:"tag" example:"min"`
+	// Param is the parameter of the rule, eg: "3" for "min=3".
+	Param string `json:"param,omitempty" example:"3"`
+	// Message is a human readable description of the failure.
+	Message string `json:"message" example:"name must be at least 3 characters"`
+}
+
+// FieldErrors converts validator.ValidationErrors (as returned by gin's
+// binding) into FieldErrors. It reports false when err does not contain
+// validation errors.
+//
+// The fields are named by their json tag, eg: "items[2].name". Pass obj, the
+// value that was validated, eg: &req, to use its json tags exactly. Without
+// it the Go names are lower camel cased, eg: "Items[2].Name" becomes
+// "items[2].name", which matches the usual tags. Names already resolved by a
+// validator with RegisterJSONTagNames are kept as they are.
+func FieldErrors(err error, obj ...interface{}) ([]FieldError, bool) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil, false
+	}
+	var root reflect.Type
+	if len(obj) > 0 && obj[0] != nil {
+		root = reflect.TypeOf(obj[0])
+	}
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		field := jsonFieldPath(fe, root)
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   field,
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: validationMessage(field, fe.Tag(), fe.Param()),
+		})
+	}
+	return fieldErrors, true
+}
+
+// UseJSONFieldNames makes gin's default validator report fields by their
+// json tag instead of the Go struct field name.
+//
+// Deprecated: FieldErrors and ValidationFailed name the fields by their json
+// tag without changing gin's validator.
+func UseJSONFieldNames() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		RegisterJSONTagNames(v)
+	}
+}
+
+// RegisterJSONTagNames makes v report fields by their json tag.
+func RegisterJSONTagNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// BoundTo names the fields of ValidationFailed by the json tags of obj, the
+// value the request was bound into, instead of lower camel casing their Go
+// names. BindJSON, BindQuery and BindUri pass it.
+//
+// Example:
+//
+//	if err := c.ShouldBindJSON(&req); err != nil {
+//		h.responseHelper.ValidationFailed(c, err, responsehelper.BoundTo(&req))
+//		return
+//	}
+func BoundTo(obj interface{}) ResponseOption {
+	return func(options *responseOptions) {
+		options.bound = obj
+	}
+}
+
+func (r *Core) ValidationFailed(c Exchange, err error, opts ...ResponseOption) {
+	opts = helperCall(opts, "ValidationFailed", err)
+	fieldErrors, ok := FieldErrors(err, newResponseOptions(opts).bound)
+	if !ok {
+		details := ""
+		if err != nil {
+			details = err.Error()
+		}
+		r.BadRequest(c, "Invalid request", details, opts...)
+		return
+	}
+	status := r.validationStatus
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	r.respondError(c, status, ErrorBody{
+		Code:    status,
+		Status:  statusText(status),
+		Message: "Validation failed",
+		Errors:  fieldErrors,
+	}, opts...)
+}
+
+// fieldPath strips the top level struct name from a validator namespace,
+// eg: "CreateOrder.items[2].name" -> "items[2].name".
+func fieldPath(namespace string) string {
+	if _, path, found := strings.Cut(namespace, "."); found {
+		return path
+	}
+	return namespace
+}
+
+// jsonFieldPath returns the path of the field of fe named by the json tags of
+// root, the type that was validated, and lower camel cased from the point
+// where root does not describe the field, eg: when it is nil.
+func jsonFieldPath(fe validator.FieldError, root reflect.Type) string {
+	structNamespace := fe.StructNamespace()
+	if namespace := fe.Namespace(); namespace != structNamespace {
+		// the validator names the fields itself, eg: with RegisterJSONTagNames
+		return fieldPath(namespace)
+	}
+	if !strings.Contains(structNamespace, ".") {
+		return lowerCamel(structNamespace)
+	}
+	t := derefType(root)
+	var path strings.Builder
+	for _, segment := range namespaceSegments(fieldPath(structNamespace)) {
+		name, indexes := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, indexes = segment[:i], segment[i:]
+		}
+		jsonName := lowerCamel(name)
+		var field reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			field, found = t.FieldByName(name)
+		}
+		t = nil
+		if found {
+			tagName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.Anonymous && tagName == "" {
+				// encoding/json inlines the fields of embedded structs
+				jsonName = ""
+			} else if tagName != "" && tagName != "-" {
+				jsonName = tagName
+			} else {
+				jsonName = name
+			}
+			t = derefType(field.Type)
+			for n := strings.Count(indexes, "["); n > 0 && t != nil; n-- {
+				switch t.Kind() {
+				case reflect.Slice, reflect.Array, reflect.Map:
+					t = derefType(t.Elem())
+				default:
+					t = nil
+				}
+			}
+		}
+		if jsonName == "" && indexes == "" {
+			continue
+		}
+		if path.Len() > 0 && jsonName != "" {
+			path.WriteByte('.')
+		}
+		path.WriteString(jsonName)
+		path.WriteString(indexes)
+	}
+	return path.String()
+}
+
+// namespaceSegments splits a validator namespace at its dots, leaving the
+// dots inside map keys, eg: "Labels[a.b]", alone.
+func namespaceSegments(namespace string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i := 0; i < len(namespace); i++ {
+		switch namespace[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				segments = append(segments, namespace[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, namespace[start:])
+}
+
+// derefType returns the type t points to, t itself when it is not a pointer.
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// lowerCamel lower cases the leading capitals of a Go name the way json tags
+// usually are, eg: "Name" -> "name", "ID" -> "id", "URLPath" -> "urlPath".
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// validationMessage returns a readable message for the common validation tags.
+func validationMessage(field, tag, param string) string {
+	switch tag {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "url", "uri", "http_url":
+		return field + " must be a valid URL"
+	case "uuid", "uuid4":
+		return field + " must be a valid UUID"
+	case "min", "gte":
+		return field + " must be at least " + param
+	case "max", "lte":
+		return field + " must be at most " + param
+	case "gt":
+		return field + " must be greater than " + param
+	case "lt":
+		return field + " must be less than " + param
+	case "len":
+		return field + " must have a length of " + param
+	case "oneof":
+		return field + " must be one of [" + param + "]"
+	case "eqfield":
+		return field + " must be equal to " + param
+	case "numeric", "number":
+		return field + " must be a number"
+	}
+	if param != "" {
+		return field + " failed on the '" + tag + "=" + param + "' rule"
+	}
+	return field + " failed on the '" + tag + "' rule"
+}
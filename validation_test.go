@@ -0,0 +1,172 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type orderItem struct {
+	Name     string `json:"name" binding:"required"`
+	Quantity int    `json:"qty" binding:"min=1"`
+}
+
+type shippingAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type auditFields struct {
+	CreatedBy string `json:"created_by" binding:"required"`
+}
+
+type createOrder struct {
+	auditFields
+	Customer string          `json:"customer_name" binding:"required"`
+	Address  shippingAddress `json:"shipping_address"`
+	Items    []orderItem     `json:"items" binding:"dive"`
+}
+
+// orderBody fails on the customer, the city, the creator, the quantity of
+// the first item and the name of the third one.
+const orderBody = `{"items":[{"name":"pen","qty":0},{"name":"ink","qty":1},{"qty":2}]}`
+
+// bindOrder binds orderBody with gin's validator and returns the context,
+// the recorder and the bind error.
+func bindOrder(t *testing.T) (*gin.Context, *httptest.ResponseRecorder, *createOrder, error) {
+	t.Helper()
+	c, w := newContext(http.MethodPost, "/orders")
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(orderBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+	var req createOrder
+	err := c.ShouldBindJSON(&req)
+	if err == nil {
+		t.Fatal("ShouldBindJSON succeeded, want validation errors")
+	}
+	return c, w, &req, err
+}
+
+func fieldNames(fieldErrors []responsehelper.FieldError) map[string]string {
+	names := map[string]string{}
+	for _, fe := range fieldErrors {
+		names[fe.Field] = fe.Tag
+	}
+	return names
+}
+
+func TestFieldErrorsUsesTheJSONTags(t *testing.T) {
+	_, _, req, err := bindOrder(t)
+	fieldErrors, ok := responsehelper.FieldErrors(err, req)
+	if !ok {
+		t.Fatalf("FieldErrors(%v) reported no validation errors", err)
+	}
+	want := map[string]string{
+		"created_by":            "required",
+		"customer_name":         "required",
+		"shipping_address.city": "required",
+		"items[0].qty":          "min",
+		"items[2].name":         "required",
+	}
+	if got := fieldNames(fieldErrors); len(got) != len(want) {
+		t.Fatalf("fields = %v, want %v", got, want)
+	} else {
+		for field, tag := range want {
+			if got[field] != tag {
+				t.Errorf("fields[%q] = %q, want %q (fields: %v)", field, got[field], tag, got)
+			}
+		}
+	}
+	for _, fe := range fieldErrors {
+		if fe.Field == "items[2].name" && fe.Message != "items[2].name is required" {
+			t.Errorf("message = %q, want %q", fe.Message, "items[2].name is required")
+		}
+		if fe.Field == "items[0].qty" && fe.Param != "1" {
+			t.Errorf("param = %q, want %q", fe.Param, "1")
+		}
+	}
+}
+
+func TestFieldErrorsWithoutTheValue(t *testing.T) {
+	_, _, _, err := bindOrder(t)
+	fieldErrors, _ := responsehelper.FieldErrors(err)
+	got := fieldNames(fieldErrors)
+	for _, field := range []string{"customer", "address.city", "items[0].quantity", "items[2].name"} {
+		if _, ok := got[field]; !ok {
+			t.Errorf("no error for %q (fields: %v)", field, got)
+		}
+	}
+}
+
+func TestFieldErrorsLeavesGinsValidatorAlone(t *testing.T) {
+	_, _, req, err := bindOrder(t)
+	responsehelper.FieldErrors(err, req)
+
+	_, _, _, err = bindOrder(t)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		t.Fatalf("err = %v, want validator.ValidationErrors", err)
+	}
+	for _, fe := range validationErrors {
+		if fe.Namespace() != fe.StructNamespace() {
+			t.Errorf("Namespace() = %q, want the Go names %q", fe.Namespace(), fe.StructNamespace())
+		}
+	}
+}
+
+func TestFieldErrorsKeepsNamesOfTheValidator(t *testing.T) {
+	v := validator.New()
+	v.SetTagName("binding")
+	responsehelper.RegisterJSONTagNames(v)
+	err := v.Struct(createOrder{Customer: "arun", auditFields: auditFields{CreatedBy: "arun"}, Address: shippingAddress{City: "Kochi"}, Items: []orderItem{{Name: "pen", Quantity: 1}, {Quantity: 1}}})
+	fieldErrors, ok := responsehelper.FieldErrors(err)
+	if !ok || len(fieldErrors) != 1 {
+		t.Fatalf("FieldErrors(%v) = %v, %v, want one error", err, fieldErrors, ok)
+	}
+	if fieldErrors[0].Field != "items[1].name" {
+		t.Errorf("field = %q, want %q", fieldErrors[0].Field, "items[1].name")
+	}
+}
+
+func TestFieldErrorsOtherErrors(t *testing.T) {
+	if fieldErrors, ok := responsehelper.FieldErrors(errors.New("boom")); ok || fieldErrors != nil {
+		t.Errorf("FieldErrors(boom) = %v, %v, want nil, false", fieldErrors, ok)
+	}
+	if _, ok := responsehelper.FieldErrors(nil); ok {
+		t.Error("FieldErrors(nil) reported validation errors")
+	}
+}
+
+func TestValidationFailed(t *testing.T) {
+	c, w, req, err := bindOrder(t)
+	responsehelper.NewResponseHelper().ValidationFailed(c, err, responsehelper.BoundTo(req))
+
+	assertError(t, w, http.StatusBadRequest, "Validation failed")
+	errs, _ := decodeBody(t, w)["error"].(map[string]interface{})["errors"].([]interface{})
+	if len(errs) != 5 {
+		t.Fatalf("len(errors) = %d, want 5\nbody: %s", len(errs), w.Body)
+	}
+	if !strings.Contains(w.Body.String(), `"field":"items[2].name"`) {
+		t.Errorf("body has no error for items[2].name\nbody: %s", w.Body)
+	}
+}
+
+func TestValidationFailedStatus(t *testing.T) {
+	c, w, _, err := bindOrder(t)
+	responsehelper.NewResponseHelper(responsehelper.WithValidationStatus(http.StatusUnprocessableEntity)).ValidationFailed(c, err)
+
+	assertError(t, w, http.StatusUnprocessableEntity, "Validation failed")
+	assertField(t, w, "error.status", "UNPROCESSABLE_ENTITY")
+}
+
+func TestValidationFailedOtherErrors(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/orders")
+	responsehelper.NewResponseHelper().ValidationFailed(c, errors.New("unexpected content type"))
+
+	assertError(t, w, http.StatusBadRequest, "Invalid request")
+	assertField(t, w, "error.details", "unexpected content type")
+}
@@ -0,0 +1,62 @@
+package responsehelper
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VaryHeader lists the request headers the response depends on, so caches
+// keep a representation per value of them.
+const VaryHeader = "Vary"
+
+// AddVary adds names to the Vary header of the response, keeping the names
+// already listed, eg: by a middleware. The helper adds the request headers
+// it consults itself: Accept with WithContentNegotiation or an error page,
+// User-Agent with an error page, Accept-Language when a Catalog translates a
+// message and Origin when WithCountHeaders exposes the count headers. Use it
+// for the headers a handler or middleware picks the representation by.
+//
+// Example:
+//
+//	responsehelper.AddVary(c, "X-API-Version")
+func AddVary(c *gin.Context, names ...string) {
+	addVary(exchangeOf(c), names...)
+}
+
+// addVary is AddVary for an Exchange.
+func addVary(c Exchange, names ...string) {
+	addHeaderList(c.Header(), VaryHeader, names)
+}
+
+// addHeaderList adds names to the comma-separated list of header key,
+// without duplicates. Nothing is added to a list holding "*".
+func addHeaderList(header http.Header, key string, names []string) {
+	var list []string
+	listed := map[string]bool{}
+	for _, value := range header.Values(key) {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" && !listed[textproto.CanonicalMIMEHeaderKey(name)] {
+				listed[textproto.CanonicalMIMEHeaderKey(name)] = true
+				list = append(list, name)
+			}
+		}
+	}
+	if listed["*"] {
+		// every header is listed already
+		return
+	}
+	added := false
+	for _, name := range names {
+		if !listed[textproto.CanonicalMIMEHeaderKey(name)] {
+			listed[textproto.CanonicalMIMEHeaderKey(name)] = true
+			list = append(list, name)
+			added = true
+		}
+	}
+	if added {
+		header.Set(key, strings.Join(list, ", "))
+	}
+}
@@ -0,0 +1,64 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestVary(t *testing.T) {
+	negotiation := responsehelper.WithContentNegotiation(true)
+	translator := responsehelper.WithTranslator(catalog.Translate)
+	countHeaders := responsehelper.WithCountHeaders(true, true)
+	notFound := func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.NotFoundKey(c, "errors.user.not_found", "arun")
+	}
+	page := func(h responsehelper.ResponseHelper, c *gin.Context) {
+		h.SuccessWithPagination(c, []int{1}, responsehelper.NewPagination(1, 20, 1))
+	}
+	for _, tt := range []struct {
+		name    string
+		opts    []responsehelper.Option
+		set     string
+		respond func(h responsehelper.ResponseHelper, c *gin.Context)
+		want    string
+	}{
+		{"nothing consulted", nil, "", page, ""},
+		{"negotiation", []responsehelper.Option{negotiation}, "", page, "Accept"},
+		{"translation", []responsehelper.Option{translator}, "", notFound, "Accept-Language"},
+		{"count headers", []responsehelper.Option{countHeaders}, "", page, "Origin"},
+		{"negotiation and translation", []responsehelper.Option{negotiation, translator}, "", notFound, "Accept-Language, Accept"},
+		{"negotiation and count headers", []responsehelper.Option{negotiation, countHeaders}, "", page, "Origin, Accept"},
+		{"translator not consulted", []responsehelper.Option{negotiation, translator}, "", page, "Accept"},
+		{"set by middleware", []responsehelper.Option{negotiation, countHeaders}, "X-API-Version", page, "X-API-Version, Origin, Accept"},
+		{"duplicates", []responsehelper.Option{negotiation, translator}, "accept, Accept-Language", notFound, "accept, Accept-Language"},
+		{"everything", []responsehelper.Option{negotiation, countHeaders}, "*", page, "*"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			if tt.set != "" {
+				c.Header("Vary", tt.set)
+			}
+			tt.respond(responsehelper.NewResponseHelper(tt.opts...), c)
+
+			if got := w.Header().Values("Vary"); len(got) > 1 || w.Header().Get("Vary") != tt.want {
+				t.Errorf("Vary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	c.Writer.Header().Add("Vary", "Origin")
+	c.Writer.Header().Add("Vary", "Accept-Encoding, origin")
+	responsehelper.AddVary(c, "X-API-Version", "accept-encoding", "X-API-Version")
+	responsehelper.NewResponseHelper(responsehelper.WithContentNegotiation(true)).Success(c, 1)
+
+	want := "Origin, Accept-Encoding, X-API-Version, Accept"
+	if got := w.Header().Values("Vary"); len(got) != 1 || got[0] != want {
+		t.Errorf("Vary = %q, want %q", got, want)
+	}
+}
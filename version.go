@@ -0,0 +1,91 @@
+package responsehelper
+
+import "github.com/gin-gonic/gin"
+
+// APIVersionHeader carries the API version set with WithAPIVersion.
+const APIVersionHeader = "X-API-Version"
+
+// apiVersion is the version of WithAPIVersion, computed once per helper.
+type apiVersion struct {
+	version string
+	// fields are the meta members sent, withCommit adds the commit in debug mode.
+	fields     map[string]interface{}
+	withCommit map[string]interface{}
+}
+
+// WithAPIVersion sends version in "meta.version" of every envelope and in
+// the X-API-Version header of every response, errors and 204 included, so
+// clients can tell which deploy answered. In debug mode the commit is sent as
+// "meta.commit" as well. A meta set by MetaMiddleware keeps the version of
+// WithMetaVersion, if any.
+//
+// Example:
+//
+//	responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("1.4.2", buildCommit))
+func WithAPIVersion(version, commit string) Option {
+	return func(cfg *config) {
+		if version == "" {
+			cfg.apiVersion = nil
+			return
+		}
+		v := &apiVersion{
+			version: version,
+			fields:  map[string]interface{}{"version": version},
+		}
+		v.withCommit = v.fields
+		if commit != "" {
+			v.withCommit = map[string]interface{}{"version": version, "commit": commit}
+		}
+		cfg.apiVersion = v
+	}
+}
+
+// setAPIVersionHeader sets the X-API-Version header of the response.
+func (cfg *config) setAPIVersionHeader(c Exchange) {
+	if cfg.apiVersion != nil {
+		setHeader(c, APIVersionHeader, cfg.apiVersion.version)
+	}
+}
+
+// versionedMeta returns meta with the members of WithAPIVersion added.
+// Members already set are kept.
+func (cfg *config) versionedMeta(meta interface{}) interface{} {
+	if cfg.apiVersion == nil {
+		return meta
+	}
+	fields := cfg.apiVersion.fields
+	if cfg.debugEnabled() {
+		fields = cfg.apiVersion.withCommit
+	}
+	switch typed := meta.(type) {
+	case Meta:
+		if typed.Version == "" {
+			typed.Version = cfg.apiVersion.version
+		}
+		if commit, ok := fields["commit"]; ok {
+			if _, set := typed.Extra["commit"]; !set {
+				return metaWithField(typed, "commit", commit)
+			}
+		}
+		return typed
+	case gin.H:
+		return withMissingFields(typed, fields)
+	case map[string]interface{}:
+		return withMissingFields(typed, fields)
+	case nil:
+		return gin.H(fields)
+	}
+	return meta
+}
+
+// withMissingFields returns a copy of meta with the fields it does not have.
+func withMissingFields(meta map[string]interface{}, fields map[string]interface{}) gin.H {
+	copied := make(gin.H, len(meta)+len(fields))
+	for key, value := range fields {
+		copied[key] = value
+	}
+	for key, value := range meta {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,88 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIVersion(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("1.4.2", "abc123"))
+	for name, respond := range map[string]func(c *gin.Context){
+		"Success":       func(c *gin.Context) { h.Success(c, gin.H{"id": 1}) },
+		"BadRequest":    func(c *gin.Context) { h.BadRequest(c, "Invalid input", "name is required") },
+		"InternalError": func(c *gin.Context) { h.InternalError(c, "Oops", errors.New("db down")) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			respond(c)
+
+			if got := w.Header().Get(responsehelper.APIVersionHeader); got != "1.4.2" {
+				t.Errorf("%s = %q, want %q", responsehelper.APIVersionHeader, got, "1.4.2")
+			}
+			assertField(t, w, "meta.version", "1.4.2")
+			if commit, ok := lookup(w, "meta.commit"); ok {
+				t.Errorf("meta.commit = %v without debug", commit)
+			}
+		})
+	}
+}
+
+func TestAPIVersionOnNoContent(t *testing.T) {
+	c, w := newContext(http.MethodDelete, "/users/42")
+	responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("1.4.2", "abc123")).NoContent(c)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get(responsehelper.APIVersionHeader); got != "1.4.2" {
+		t.Errorf("%s = %q, want %q", responsehelper.APIVersionHeader, got, "1.4.2")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want none", w.Body)
+	}
+}
+
+func TestAPIVersionCommitInDebug(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("1.4.2", "abc123"), responsehelper.WithDebug(true)).
+		Success(c, nil)
+
+	assertField(t, w, "meta.version", "1.4.2")
+	assertField(t, w, "meta.commit", "abc123")
+}
+
+func TestAPIVersionKeepsTheMetaVersion(t *testing.T) {
+	h := responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("1.4.2", "abc123"), responsehelper.WithDebug(true))
+	for name, meta := range map[string]interface{}{
+		"typed": responsehelper.Meta{Version: "v1", Extra: map[string]interface{}{"commit": "set"}},
+		"map":   gin.H{"version": "v1", "commit": "set"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/users")
+			c.Set(responsehelper.MetaKey, meta)
+			h.Success(c, nil)
+
+			assertField(t, w, "meta.version", "v1")
+			assertField(t, w, "meta.commit", "set")
+			if got := w.Header().Get(responsehelper.APIVersionHeader); got != "1.4.2" {
+				t.Errorf("%s = %q, want %q", responsehelper.APIVersionHeader, got, "1.4.2")
+			}
+		})
+	}
+}
+
+func TestWithoutAPIVersion(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/users")
+	responsehelper.NewResponseHelper(responsehelper.WithAPIVersion("", "abc123")).Success(c, nil)
+
+	if got := w.Header().Get(responsehelper.APIVersionHeader); got != "" {
+		t.Errorf("%s = %q without a version", responsehelper.APIVersionHeader, got)
+	}
+	if version, ok := lookup(w, "meta.version"); ok {
+		t.Errorf("meta.version = %v without a version", version)
+	}
+}
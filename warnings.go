@@ -0,0 +1,67 @@
+package responsehelper
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// WarningsKey is the gin context key holding the warnings added with
+	// AddWarning.
+	WarningsKey = "responsehelper.warnings"
+	// WarningsHeader carries the number of warnings of a success response,
+	// so proxies can flag them without reading the body.
+	WarningsHeader = "X-API-Warnings"
+)
+
+// Warning is a caveat of a request that succeeded, rendered in the
+// "warnings" of the success envelope, eg: a filter that was ignored.
+type Warning struct {
+	// Code identifies the kind of warning, eg: "FILTER_IGNORED".
+	Code string `json:"code" xml:"code" example:"FILTER_IGNORED"`
+	// Message is the user facing message.
+	Message string `json:"message" xml:"message" example:"The filter \"color\" is not supported and was ignored"`
+}
+
+// WithWarnings adds warnings to the "warnings" of a success response.
+//
+// Example:
+//
+//	h.responseHelper.Success(c, users, responsehelper.WithWarnings(responsehelper.Warning{
+//		Code:    "FILTER_IGNORED",
+//		Message: "The filter on lastLogin is not supported and was ignored",
+//	}))
+func WithWarnings(ws ...Warning) ResponseOption {
+	return func(options *responseOptions) {
+		options.warnings = append(options.warnings, ws...)
+	}
+}
+
+// AddWarning adds w to the warnings of the success response of c, eg: from a
+// function deep in the call stack of the handler. They are rendered before
+// the ones of WithWarnings.
+//
+// Example:
+//
+//	responsehelper.AddWarning(c, responsehelper.Warning{Code: "FIELD_DEPRECATED", Message: "fullName is deprecated, use name"})
+func AddWarning(c *gin.Context, w Warning) {
+	warnings, _ := c.Get(WarningsKey)
+	stored, _ := warnings.([]Warning)
+	c.Set(WarningsKey, append(stored, w))
+}
+
+// responseWarnings returns the warnings added with AddWarning followed by
+// extra, and sets WarningsHeader when there are some.
+func responseWarnings(c Exchange, extra []Warning) []Warning {
+	stored, _ := c.Get(WarningsKey)
+	warnings, _ := stored.([]Warning)
+	if len(extra) > 0 {
+		warnings = append(warnings[:len(warnings):len(warnings)], extra...)
+	}
+	if len(warnings) == 0 {
+		return nil
+	}
+	setHeader(c, WarningsHeader, strconv.Itoa(len(warnings)))
+	return warnings
+}
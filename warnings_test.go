@@ -0,0 +1,110 @@
+package responsehelper_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	filterIgnored   = responsehelper.Warning{Code: "FILTER_IGNORED", Message: "The filter on color was ignored"}
+	fieldDeprecated = responsehelper.Warning{Code: "FIELD_DEPRECATED", Message: "fullName is deprecated, use name"}
+	limitCapped     = responsehelper.Warning{Code: "LIMIT_CAPPED", Message: "limit was capped to 100"}
+)
+
+// warningsOf returns the "warnings" of the envelope of w, nil when it has none.
+func warningsOf(t *testing.T, w *httptest.ResponseRecorder) []interface{} {
+	t.Helper()
+	warnings, _ := decodeBody(t, w)["warnings"].([]interface{})
+	return warnings
+}
+
+// warningJSON returns w as decoded from an envelope.
+func warningJSON(w responsehelper.Warning) interface{} {
+	return map[string]interface{}{"code": w.Code, "message": w.Message}
+}
+
+// loadProducts stands for a function deep in the call stack of a handler.
+func loadProducts(c *gin.Context) []string {
+	responsehelper.AddWarning(c, filterIgnored)
+	responsehelper.AddWarning(c, fieldDeprecated)
+	return []string{"lamp"}
+}
+
+func TestWithWarnings(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/products")
+	responsehelper.NewResponseHelper().Success(c, []string{"lamp"}, responsehelper.WithWarnings(filterIgnored), responsehelper.WithWarnings(limitCapped))
+
+	want := []interface{}{warningJSON(filterIgnored), warningJSON(limitCapped)}
+	if got := warningsOf(t, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+	if got := w.Header().Get(responsehelper.WarningsHeader); got != "2" {
+		t.Errorf("%s = %q, want 2", responsehelper.WarningsHeader, got)
+	}
+}
+
+func TestAddWarning(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+	for _, tt := range []struct {
+		name string
+		opts []responsehelper.ResponseOption
+		want []interface{}
+	}{
+		{"context only", nil, []interface{}{warningJSON(filterIgnored), warningJSON(fieldDeprecated)}},
+		{"context then option", []responsehelper.ResponseOption{responsehelper.WithWarnings(limitCapped)},
+			[]interface{}{warningJSON(filterIgnored), warningJSON(fieldDeprecated), warningJSON(limitCapped)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newContext(http.MethodGet, "/products")
+			h.Success(c, loadProducts(c), tt.opts...)
+
+			if got := warningsOf(t, w); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("warnings = %v, want %v", got, tt.want)
+			}
+			if got, want := w.Header().Get(responsehelper.WarningsHeader), strconv.Itoa(len(tt.want)); got != want {
+				t.Errorf("%s = %q, want %s", responsehelper.WarningsHeader, got, want)
+			}
+		})
+	}
+}
+
+func TestWarningsOfOtherResponses(t *testing.T) {
+	h := responsehelper.NewResponseHelper()
+
+	c, w := newContext(http.MethodGet, "/products")
+	h.SuccessLarge(c, loadProducts(c))
+	if got := warningsOf(t, w); len(got) != 2 || w.Header().Get(responsehelper.WarningsHeader) != "2" {
+		t.Errorf("SuccessLarge: warnings = %v, %s = %q", got, responsehelper.WarningsHeader, w.Header().Get(responsehelper.WarningsHeader))
+	}
+
+	c, w = newContext(http.MethodGet, "/products")
+	h.SuccessWithPagination(c, loadProducts(c), responsehelper.NewPagination(1, 20, 1), responsehelper.WithWarnings(limitCapped))
+	if got := warningsOf(t, w); len(got) != 3 {
+		t.Errorf("SuccessWithPagination: warnings = %v", got)
+	}
+
+	c, w = newContext(http.MethodGet, "/products")
+	loadProducts(c)
+	h.NotFound(c, "missing")
+	if got := warningsOf(t, w); got != nil || w.Header().Get(responsehelper.WarningsHeader) != "" {
+		t.Errorf("an error has the warnings %v", got)
+	}
+}
+
+func TestNoWarnings(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/products")
+	responsehelper.NewResponseHelper().Success(c, []string{"lamp"}, responsehelper.WithWarnings())
+
+	if _, ok := decodeBody(t, w)["warnings"]; ok {
+		t.Errorf("warnings sent without any: %s", w.Body)
+	}
+	if got, ok := w.Header()[responsehelper.WarningsHeader]; ok {
+		t.Errorf("%s = %q without warnings", responsehelper.WarningsHeader, got)
+	}
+}
@@ -0,0 +1,96 @@
+package responsehelper
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerFunc is a handler that returns its result instead of writing a
+// response, see Wrap.
+type HandlerFunc func(c *gin.Context) (interface{}, error)
+
+// WrapOption configures Wrap and WrapCreated.
+type WrapOption func(*wrapConfig)
+
+type wrapConfig struct {
+	// noContentOnNil sends 204 No Content when the handler returns no data.
+	noContentOnNil bool
+	// logger receives the panics recovered from the handler.
+	logger PanicLoggerFunc
+}
+
+// WithNoContentOnNil sends 204 No Content instead of a null "data" when the
+// handler returns nil data and a nil error.
+func WithNoContentOnNil(enabled bool) WrapOption {
+	return func(cfg *wrapConfig) {
+		cfg.noContentOnNil = enabled
+	}
+}
+
+// WithWrapPanicLogger replaces the default log.Printf logging of the panics
+// recovered from the handler.
+func WithWrapPanicLogger(logger PanicLoggerFunc) WrapOption {
+	return func(cfg *wrapConfig) {
+		if logger != nil {
+			cfg.logger = logger
+		}
+	}
+}
+
+// Wrap turns fn into a gin handler that sends the result of fn: data is sent
+// with Success when the error is nil, otherwise the error is sent like
+// Respond does, eg: with the status of a StatusCoder. A panic in fn is
+// recovered into the standard 500 envelope, like Recovery does.
+//
+// Example:
+//
+//	router.GET("/users/:id", responsehelper.Wrap(responseHelper, func(c *gin.Context) (interface{}, error) {
+//		return userService.Get(c.Param("id"))
+//	}))
+func Wrap(h ResponseHelper, fn HandlerFunc, opts ...WrapOption) gin.HandlerFunc {
+	return wrap(h, fn, func(c *gin.Context, data interface{}) {
+		h.Success(c, data)
+	}, opts)
+}
+
+// WrapCreated is Wrap for handlers creating a resource, data is sent with
+// Created.
+//
+// Example:
+//
+//	router.POST("/users", responsehelper.WrapCreated(responseHelper, func(c *gin.Context) (interface{}, error) {
+//		var user User
+//		if !responsehelper.BindJSON(c, responseHelper, &user) {
+//			return nil, nil
+//		}
+//		return userService.Create(user)
+//	}))
+func WrapCreated(h ResponseHelper, fn HandlerFunc, opts ...WrapOption) gin.HandlerFunc {
+	return wrap(h, fn, func(c *gin.Context, data interface{}) {
+		h.Created(c, data)
+	}, opts)
+}
+
+func wrap(h ResponseHelper, fn HandlerFunc, success func(*gin.Context, interface{}), opts []WrapOption) gin.HandlerFunc {
+	cfg := wrapConfig{logger: logPanic}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				respondPanic(c, h, cfg.logger, recovered)
+			}
+		}()
+		data, err := fn(c)
+		switch {
+		case c.Writer.Written():
+			// the handler already sent a response, eg: BindJSON rejected the body
+		case err != nil:
+			h.Respond(c, err, nil)
+		case data == nil && cfg.noContentOnNil:
+			h.NoContent(c)
+		default:
+			success(c, data)
+		}
+	}
+}
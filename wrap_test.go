@@ -0,0 +1,114 @@
+package responsehelper_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// statusError is an error of another library carrying its status.
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e statusError) Error() string   { return e.message }
+func (e statusError) StatusCode() int { return e.status }
+
+func wrapEngine(handler gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.POST("/users", handler)
+	return engine
+}
+
+func TestWrap(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		fn      responsehelper.HandlerFunc
+		opts    []responsehelper.WrapOption
+		status  int
+		message string
+	}{
+		{"data", func(c *gin.Context) (interface{}, error) {
+			return map[string]int{"id": 42}, nil
+		}, nil, http.StatusOK, ""},
+		{"nil data", func(c *gin.Context) (interface{}, error) {
+			return nil, nil
+		}, nil, http.StatusOK, ""},
+		{"nil data without content", func(c *gin.Context) (interface{}, error) {
+			return nil, nil
+		}, []responsehelper.WrapOption{responsehelper.WithNoContentOnNil(true)}, http.StatusNoContent, ""},
+		{"mapped error", func(c *gin.Context) (interface{}, error) {
+			return nil, responsehelper.ErrNotFound.WithMessage("User not found")
+		}, nil, http.StatusNotFound, "User not found"},
+		{"status coder", func(c *gin.Context) (interface{}, error) {
+			return nil, statusError{http.StatusPaymentRequired, "Plan expired"}
+		}, nil, http.StatusPaymentRequired, "Plan expired"},
+		{"5xx status coder", func(c *gin.Context) (interface{}, error) {
+			return nil, statusError{http.StatusBadGateway, "upstream at 10.0.0.3 refused"}
+		}, nil, http.StatusBadGateway, "An unexpected error occurred"},
+		{"unmapped error", func(c *gin.Context) (interface{}, error) {
+			return nil, errors.New("pq: connection refused")
+		}, nil, http.StatusInternalServerError, "An unexpected error occurred"},
+		{"panic", func(c *gin.Context) (interface{}, error) {
+			panic("boom")
+		}, []responsehelper.WrapOption{responsehelper.WithWrapPanicLogger(func(*gin.Context, string, interface{}, []byte) {})},
+			http.StatusInternalServerError, "Internal server error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := responsehelper.Wrap(responsehelper.NewResponseHelper(), tc.fn, tc.opts...)
+			w := serve(wrapEngine(handler), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+			switch {
+			case tc.status == http.StatusNoContent:
+				if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+					t.Errorf("got %d %q, want an empty 204", w.Code, w.Body)
+				}
+			case tc.message == "":
+				assertSuccess(t, w)
+			default:
+				assertError(t, w, tc.status, tc.message)
+			}
+		})
+	}
+}
+
+func TestWrapNilData(t *testing.T) {
+	handler := responsehelper.Wrap(responsehelper.NewResponseHelper(), func(c *gin.Context) (interface{}, error) {
+		return nil, nil
+	})
+	w := serve(wrapEngine(handler), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	body := decodeBody(t, w)
+	if data, ok := body["data"]; !ok || data != nil {
+		t.Errorf("data = %v, %t, want null", data, ok)
+	}
+}
+
+func TestWrapCreated(t *testing.T) {
+	handler := responsehelper.WrapCreated(responsehelper.NewResponseHelper(), func(c *gin.Context) (interface{}, error) {
+		return map[string]int{"id": 42}, nil
+	})
+	w := serve(wrapEngine(handler), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want 201", w.Code)
+	}
+	assertSuccess(t, w)
+	assertField(t, w, "data.id", float64(42))
+}
+
+func TestWrapKeepsTheResponseOfTheHandler(t *testing.T) {
+	helper := responsehelper.NewResponseHelper()
+	handler := responsehelper.Wrap(helper, func(c *gin.Context) (interface{}, error) {
+		helper.BadRequest(c, "Invalid input", "name is required")
+		return nil, nil
+	})
+	w := serve(wrapEngine(handler), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	assertError(t, w, http.StatusBadRequest, "Invalid input")
+}
@@ -0,0 +1,97 @@
+package responsehelper
+
+import "net/http"
+
+func (r *Core) WriteJSON(c Exchange, status int, body interface{}, opts ...ResponseOption) {
+	options := newResponseOptions(helperCall(opts, "WriteJSON", nil))
+	if r.secondResponse(c, options.method) {
+		return
+	}
+	if status < http.StatusOK || status > 599 {
+		r.warnf("%s: %d is not a response status (200-599), sending 500 instead", options.method, status)
+		status = http.StatusInternalServerError
+	}
+	response := sentResponse{status: status, options: options}
+	if r.clientGone(c) {
+		r.skipWrite(c, response)
+		return
+	}
+	r.writeResponse(c, response, func(c Exchange) {
+		r.writeBody(c, status, body)
+	})
+}
+
+func (r *Core) WriteError(c Exchange, status int, errBody ErrorBody, opts ...ResponseOption) {
+	if errBody.Code == 0 {
+		errBody.Code = status
+	}
+	if errBody.Status == "" {
+		errBody.Status = statusText(status)
+	}
+	r.renderError(c, status, ErrorEnvelope{Error: errBody}, helperCall(opts, "WriteError", nil)...)
+}
+
+// sentResponse describes a response to writeResponse.
+type sentResponse struct {
+	status int
+	// options are the options of the response, with the helper method and
+	// error recorded by helperCall.
+	options responseOptions
+	// errorCode and message are the error code and message of an error
+	// response, audited, reported and logged.
+	errorCode string
+	message   string
+	// streamed responses are written while they are produced, so they are
+	// not signed and their timing headers report the time to the first byte.
+	streamed bool
+}
+
+// info returns the ResponseInfo of the response for the response hooks.
+func (response sentResponse) info() ResponseInfo {
+	return ResponseInfo{
+		Status:    response.status,
+		Method:    response.options.method,
+		ErrorCode: response.errorCode,
+		Err:       response.options.err,
+	}
+}
+
+// writeResponse is the last step of every helper: it sets the headers of the
+// response and writes it with write, only its status when write is nil, then
+// audits, reports and logs it and runs the response hooks.
+func (r *Core) writeResponse(c Exchange, response sentResponse, write func(c Exchange)) {
+	status, options := response.status, response.options
+	r.setRateLimitHeaders(c, options.rateLimit)
+	r.setTimingHeaders(c, response.streamed)
+	r.setAPIVersionHeader(c)
+	r.setTenantHeader(c)
+	if status >= http.StatusBadRequest {
+		setErrorCacheHeader(c, options.cache)
+	} else {
+		r.setCacheHeader(c, options.cache)
+	}
+	written := c.Size()
+	c.Set(RespondedKey, true)
+	switch {
+	case write == nil:
+		c.WriteHeader(status)
+	case response.streamed:
+		write(c)
+	default:
+		r.signBody(c, write)
+	}
+	r.recordAudit(c, status, response.errorCode, response.message)
+	r.reportError(c, status, options.err, response.message)
+	r.logResponse(c, status, response.message, options.err)
+	r.runResponseHooks(c, response.info(), written)
+}
+
+// skipWrite skips a response with WithSkipOnClientGone. Its hooks still run
+// and its errors are still reported and logged.
+func (r *Core) skipWrite(c Exchange, response sentResponse) {
+	if response.status >= http.StatusBadRequest {
+		r.reportError(c, response.status, response.options.err, response.message)
+		r.logResponse(c, response.status, response.message, response.options.err)
+	}
+	r.skipResponse(c, response.info())
+}
@@ -0,0 +1,128 @@
+package responsehelper_test
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/gin-gonic/gin"
+)
+
+// writeHelper returns a helper sending the tenant and API version headers
+// and recording its response hooks.
+func writeHelper(recorder *hookRecorder, opts ...responsehelper.Option) responsehelper.ResponseHelper {
+	return responsehelper.NewResponseHelper(append([]responsehelper.Option{
+		responsehelper.WithOnResponse(recorder.hook),
+		responsehelper.WithTenantFromContext("tenantID"),
+		responsehelper.WithTenantHeader(true),
+		responsehelper.WithAPIVersion("2.3.0", ""),
+	}, opts...)...)
+}
+
+func TestWriteJSON(t *testing.T) {
+	var recorder hookRecorder
+	c, w := newContext(http.MethodGet, "/dashboard")
+	c.Set("tenantID", "acme")
+	writeHelper(&recorder).WriteJSON(c, http.StatusOK, gin.H{"users": []int{1, 2}, "groups": []string{"admins"}},
+		responsehelper.WithRateLimit(responsehelper.RateLimitInfo{Limit: 100, Remaining: 99}))
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"groups":["admins"],"users":[1,2]}` {
+		t.Errorf("status = %d, body: %s, want the body without an envelope", w.Code, w.Body)
+	}
+	for name, want := range map[string]string{
+		responsehelper.TenantIDHeader:   "acme",
+		responsehelper.APIVersionHeader: "2.3.0",
+		"X-RateLimit-Limit":             "100",
+		"X-RateLimit-Remaining":         "99",
+		"Content-Type":                  "application/json; charset=utf-8",
+	} {
+		if got := w.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if len(recorder.calls) != 1 || recorder.calls[0].Method != "WriteJSON" || recorder.calls[0].Status != http.StatusOK || recorder.calls[0].BytesWritten != w.Body.Len() {
+		t.Errorf("hook calls = %+v", recorder.calls)
+	}
+}
+
+func TestWriteJSONSecondResponse(t *testing.T) {
+	var recorder hookRecorder
+	logs := &captureHandler{level: slog.LevelWarn}
+	h := writeHelper(&recorder, responsehelper.WithLogger(slog.New(logs)))
+	c, w := newContext(http.MethodGet, "/dashboard")
+	h.WriteJSON(c, http.StatusAccepted, gin.H{"first": true})
+	h.WriteJSON(c, http.StatusOK, gin.H{"second": true})
+
+	if w.Code != http.StatusAccepted || w.Body.String() != `{"first":true}` {
+		t.Errorf("status = %d, body: %s, want the first response only", w.Code, w.Body)
+	}
+	if len(recorder.calls) != 1 || len(logs.records) != 1 {
+		t.Errorf("%d hook calls and %d warnings, want 1 and 1", len(recorder.calls), len(logs.records))
+	}
+}
+
+func TestWriteJSONInvalidStatus(t *testing.T) {
+	logs := &captureHandler{level: slog.LevelWarn}
+	c, w := newContext(http.MethodGet, "/dashboard")
+	responsehelper.NewResponseHelper(responsehelper.WithLogger(slog.New(logs))).WriteJSON(c, 99, gin.H{"ok": true})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if len(logs.records) == 0 {
+		t.Error("no warning logged")
+	}
+}
+
+func TestWriteJSONNegotiatedFormat(t *testing.T) {
+	c, w := newContext(http.MethodGet, "/dashboard")
+	c.Request.Header.Set("Accept", "application/xml")
+	responsehelper.NewResponseHelper(responsehelper.WithContentNegotiation(true)).WriteJSON(c, http.StatusOK, gin.H{"users": 2})
+
+	if got := w.Header().Get("Content-Type"); got != "application/xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q, body: %s", got, w.Body)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	var recorder hookRecorder
+	var sent string
+	h := writeHelper(&recorder, responsehelper.WithBeforeSend(func(c *gin.Context, response *responsehelper.OutgoingResponse) error {
+		sent = response.Error.Error.ErrorCode
+		return nil
+	}))
+	c, w := newContext(http.MethodPost, "/login")
+	c.Set("tenantID", "acme")
+	h.WriteError(c, http.StatusLocked, responsehelper.ErrorBody{Message: "Account is locked", ErrorCode: "ACCOUNT_LOCKED"})
+
+	if w.Code != http.StatusLocked || w.Header().Get(responsehelper.TenantIDHeader) != "acme" {
+		t.Errorf("status = %d, %s = %q", w.Code, responsehelper.TenantIDHeader, w.Header().Get(responsehelper.TenantIDHeader))
+	}
+	body := decodeBody(t, w)
+	errorBody, _ := body["error"].(map[string]interface{})
+	for key, want := range map[string]interface{}{"code": float64(http.StatusLocked), "status": "LOCKED", "message": "Account is locked", "errorCode": "ACCOUNT_LOCKED"} {
+		if errorBody[key] != want {
+			t.Errorf("error[%q] = %v, want %v", key, errorBody[key], want)
+		}
+	}
+	if meta, _ := body["meta"].(map[string]interface{}); meta["tenantId"] != "acme" || meta["version"] != "2.3.0" {
+		t.Errorf("meta = %v, want the tenant and the API version", body["meta"])
+	}
+	if sent != "ACCOUNT_LOCKED" {
+		t.Errorf("the before send hook got the errorCode %q", sent)
+	}
+	if len(recorder.calls) != 1 || recorder.calls[0].Method != "WriteError" || recorder.calls[0].ErrorCode != "ACCOUNT_LOCKED" {
+		t.Errorf("hook calls = %+v", recorder.calls)
+	}
+}
+
+func TestWriteErrorKeepsCodeAndStatus(t *testing.T) {
+	c, w := newContext(http.MethodPost, "/login")
+	responsehelper.NewResponseHelper().WriteError(c, http.StatusLocked, responsehelper.ErrorBody{Code: 4231, Status: "ACCOUNT_LOCKED", Message: "Account is locked"})
+
+	errorBody, _ := decodeBody(t, w)["error"].(map[string]interface{})
+	if errorBody["code"] != float64(4231) || errorBody["status"] != "ACCOUNT_LOCKED" {
+		t.Errorf("error = %v, want the code and status given kept", errorBody)
+	}
+}
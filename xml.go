@@ -0,0 +1,231 @@
+package responsehelper
+
+import (
+	"encoding/xml"
+	"sort"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// xmlRoot is the root element of the XML envelopes.
+var xmlRoot = xml.StartElement{Name: xml.Name{Local: "response"}}
+
+// xmlSuccessEnvelope is SuccessEnvelope with the fields left out when empty
+// typed as interfaces, see xmlErrorBody.
+type xmlSuccessEnvelope struct {
+	Count           *int        `xml:"count,omitempty"`
+	Data            interface{} `xml:"data,omitempty"`
+	Links           interface{} `xml:"links,omitempty"`
+	Message         string      `xml:"message,omitempty"`
+	Meta            interface{} `xml:"meta,omitempty"`
+	Pagination      interface{} `xml:"pagination,omitempty"`
+	PartialFailures interface{} `xml:"partialFailures>partialFailure,omitempty"`
+	Success         bool        `xml:"success"`
+	Warnings        interface{} `xml:"warnings>warning,omitempty"`
+}
+
+// MarshalXML renders the envelope the way the JSON one is rendered: maps
+// become elements named after their keys, sorted, and slices become
+// repeated <item> elements. The root element is always <response>.
+func (e SuccessEnvelope) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	out := xmlSuccessEnvelope{
+		Count:   e.Count,
+		Message: e.Message,
+		Success: e.Success,
+	}
+	if len(e.PartialFailures) > 0 {
+		out.PartialFailures = e.PartialFailures
+	}
+	if len(e.Warnings) > 0 {
+		out.Warnings = e.Warnings
+	}
+	var err error
+	if out.Data, err = xmlValue(e.Data); err != nil {
+		return err
+	}
+	if out.Links, err = xmlValue(e.Links); err != nil {
+		return err
+	}
+	if out.Meta, err = xmlValue(e.Meta); err != nil {
+		return err
+	}
+	if out.Pagination, err = xmlValue(e.Pagination); err != nil {
+		return err
+	}
+	return enc.EncodeElement(out, xmlRoot)
+}
+
+// MarshalXML renders the envelope the way the JSON one is rendered. The
+// root element is always <response>.
+func (e ErrorEnvelope) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	type envelope ErrorEnvelope
+	out := envelope(e)
+	var err error
+	if out.Meta, err = xmlValue(e.Meta); err != nil {
+		return err
+	}
+	return enc.EncodeElement(out, xmlRoot)
+}
+
+// xmlErrorBody is ErrorBody with the fields left out when empty typed as
+// interfaces, as encoding/xml writes the parent element of empty slices.
+type xmlErrorBody struct {
+	Causes              interface{} `xml:"causes>cause,omitempty"`
+	Code                int         `xml:"code"`
+	Context             interface{} `xml:"context,omitempty"`
+	Details             interface{} `xml:"details,omitempty"`
+	ErrorCode           string      `xml:"errorCode,omitempty"`
+	ErrorID             string      `xml:"errorId,omitempty"`
+	Errors              interface{} `xml:"errors>error,omitempty"`
+	GrantedPermissions  interface{} `xml:"grantedPermissions>permission,omitempty"`
+	HelpURL             string      `xml:"helpUrl,omitempty"`
+	Kind                string      `xml:"kind,omitempty"`
+	Message             string      `xml:"message"`
+	RequiredPermissions interface{} `xml:"requiredPermissions>permission,omitempty"`
+	Resource            string      `xml:"resource,omitempty"`
+	ResourceID          string      `xml:"resourceId,omitempty"`
+	Retryable           bool        `xml:"retryable"`
+	Status              string      `xml:"status"`
+	Type                string      `xml:"type,omitempty"`
+}
+
+// MarshalXML renders the error like the JSON one is rendered, the entries of
+// "errors" become repeated <error> elements.
+func (b ErrorBody) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	out := xmlErrorBody{
+		Causes:              xmlStrings(b.Causes),
+		Code:                b.Code,
+		ErrorCode:           b.ErrorCode,
+		ErrorID:             b.ErrorID,
+		GrantedPermissions:  xmlStrings(b.GrantedPermissions),
+		HelpURL:             b.HelpURL,
+		Kind:                b.Kind,
+		Message:             b.Message,
+		RequiredPermissions: xmlStrings(b.RequiredPermissions),
+		Resource:            b.Resource,
+		ResourceID:          b.ResourceID,
+		Retryable:           b.Retryable,
+		Status:              b.Status,
+		Type:                b.Type,
+	}
+	var err error
+	if len(b.Context) > 0 {
+		if out.Context, err = xmlValue(b.Context); err != nil {
+			return err
+		}
+	}
+	if out.Details, err = xmlValue(b.Details); err != nil {
+		return err
+	}
+	if out.Errors, err = xmlValue(b.Errors); err != nil {
+		return err
+	}
+	if node, ok := out.Errors.(xmlNode); ok {
+		if items, ok := node.value.([]interface{}); ok {
+			// let the errors>error tag name the entries
+			nodes := make([]xmlNode, len(items))
+			for i, item := range items {
+				nodes[i] = xmlNode{value: item}
+			}
+			out.Errors = nodes
+		}
+	}
+	return enc.EncodeElement(out, start)
+}
+
+// xmlStrings returns nil for an empty slice, so the field is left out.
+func xmlStrings(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// xmlValue returns v in a form encoding/xml can render. Values implementing
+// xml.Marshaler are kept, any other value is rendered like its JSON.
+func xmlValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	// gin.H renders as <map>, so it is rendered like any other map
+	if _, ok := v.(gin.H); !ok {
+		if _, ok := v.(xml.Marshaler); ok {
+			return v, nil
+		}
+	}
+	value, err := JSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return xmlNode{value: value}, nil
+}
+
+// xmlNode renders a decoded JSON value as XML.
+type xmlNode struct {
+	value interface{}
+}
+
+func (n xmlNode) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	switch value := n.value.(type) {
+	case map[string]interface{}:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := xml.StartElement{Name: xml.Name{Local: key}}
+			if !validXMLName(key) {
+				child = xml.StartElement{
+					Name: xml.Name{Local: "entry"},
+					Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+				}
+			}
+			if err := enc.EncodeElement(xmlNode{value: value[key]}, child); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case []interface{}:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range value {
+			if err := enc.EncodeElement(xmlNode{value: item}, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	case nil:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	default:
+		return enc.EncodeElement(value, start)
+	}
+}
+
+// validXMLName reports whether name can be used as an element name.
+func validXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, ch := range name {
+		if unicode.IsLetter(ch) || ch == '_' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(ch) || ch == '-' || ch == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
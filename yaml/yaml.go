@@ -0,0 +1,37 @@
+// Package yaml registers a YAML encoder for the responsehelper envelopes.
+//
+// Importing the package registers the encoder for application/yaml and
+// application/x-yaml:
+//
+//	import _ "github.com/aruncs31s/responsehelper/yaml"
+package yaml
+
+import (
+	"io"
+
+	"github.com/aruncs31s/responsehelper"
+	goyaml "github.com/goccy/go-yaml"
+)
+
+const (
+	// ContentType is the content type of the YAML envelope.
+	ContentType = string(responsehelper.FormatYAML)
+	// LegacyContentType is accepted as an alias of ContentType.
+	LegacyContentType = "application/x-yaml"
+)
+
+func init() {
+	responsehelper.RegisterEncoder(ContentType, Encode)
+	responsehelper.RegisterEncoder(LegacyContentType, Encode)
+}
+
+// Encode writes v to w as a single YAML document with the structure of its
+// JSON: the keys are the JSON names, sorted like in the JSON envelope, and
+// values implementing json.Marshaler are encoded like their JSON.
+func Encode(w io.Writer, v interface{}) error {
+	value, err := responsehelper.JSONValue(v)
+	if err != nil {
+		return err
+	}
+	return goyaml.NewEncoder(w).Encode(value)
+}
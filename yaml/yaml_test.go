@@ -0,0 +1,132 @@
+package yaml_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aruncs31s/responsehelper"
+	"github.com/aruncs31s/responsehelper/yaml"
+	"github.com/gin-gonic/gin"
+	goyaml "github.com/goccy/go-yaml"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// engine returns an engine answering /users with a page of users and
+// /users/0 with validation errors.
+func engine() *gin.Engine {
+	h := responsehelper.NewResponseHelper(
+		responsehelper.WithContentNegotiation(true),
+		responsehelper.WithFormatQueryParam("format"),
+	)
+	e := gin.New()
+	e.Use(func(c *gin.Context) {
+		c.Set(responsehelper.MetaKey, responsehelper.Meta{RequestID: "req-1", Path: c.Request.URL.Path})
+	})
+	e.GET("/users", func(c *gin.Context) {
+		h.SuccessWithPagination(c, []gin.H{{"id": 1, "name": "arun: admin"}}, responsehelper.NewPagination(1, 1, 3))
+	})
+	e.GET("/users/0", func(c *gin.Context) {
+		h.Errors(c, http.StatusUnprocessableEntity, []responsehelper.ErrorItem{
+			{Code: "REQUIRED", Field: "name", Message: "name is required"},
+		})
+	})
+	return e
+}
+
+func get(target, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	engine().ServeHTTP(w, r)
+	return w
+}
+
+// normalize returns v as the values of its JSON.
+func normalize(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// topLevelKeys returns the top-level keys of body in order, JSON being YAML.
+func topLevelKeys(t *testing.T, body []byte) []string {
+	t.Helper()
+	var ordered yamlOrder
+	if err := goyaml.Unmarshal(body, &ordered); err != nil {
+		t.Fatal(err)
+	}
+	return ordered.keys
+}
+
+// yamlOrder records the keys of a mapping in order.
+type yamlOrder struct {
+	keys []string
+}
+
+func (o *yamlOrder) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var items goyaml.MapSlice
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+	for _, item := range items {
+		o.keys = append(o.keys, item.Key.(string))
+	}
+	return nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{"pagination", "/users", yaml.ContentType},
+		{"error", "/users/0", yaml.LegacyContentType},
+		{"query", "/users?format=yaml", "application/json"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := get(tc.target, tc.accept)
+			want := get(strings.TrimSuffix(tc.target, "?format=yaml"), "application/json")
+
+			if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/") || !strings.Contains(w.Header().Get("Content-Type"), "yaml") {
+				t.Errorf("Content-Type = %q, want YAML", w.Header().Get("Content-Type"))
+			}
+			if w.Code != want.Code {
+				t.Errorf("status = %d, JSON sent %d", w.Code, want.Code)
+			}
+			if strings.Count(w.Body.String(), "\n---") > 0 {
+				t.Errorf("the body has several documents:\n%s", w.Body)
+			}
+			var got interface{}
+			if err := goyaml.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decoding the YAML body: %v\n%s", err, w.Body)
+			}
+			var wantValue interface{}
+			if err := json.Unmarshal(want.Body.Bytes(), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(normalize(t, got), wantValue) {
+				t.Errorf("envelope = %v\nJSON sent %v", got, wantValue)
+			}
+			if keys, wantKeys := topLevelKeys(t, w.Body.Bytes()), topLevelKeys(t, want.Body.Bytes()); !reflect.DeepEqual(keys, wantKeys) {
+				t.Errorf("top-level keys = %v, JSON has %v", keys, wantKeys)
+			}
+		})
+	}
+}